@@ -20,6 +20,8 @@ import (
 	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -28,11 +30,13 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	clog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -42,16 +46,20 @@ import (
 	argorolloutsv1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
 
+	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
 	"github.com/numaproj/numaplane/internal/controller/config"
 	"github.com/numaproj/numaplane/internal/controller/isbservicerollout"
 	"github.com/numaproj/numaplane/internal/controller/monovertexrollout"
 	"github.com/numaproj/numaplane/internal/controller/numaflowcontroller"
 	"github.com/numaproj/numaplane/internal/controller/numaflowcontrollerrollout"
 	"github.com/numaproj/numaplane/internal/controller/pipelinerollout"
+	"github.com/numaproj/numaplane/internal/controller/ppnd"
 	"github.com/numaproj/numaplane/internal/util/kubernetes"
 	"github.com/numaproj/numaplane/internal/util/logger"
 	"github.com/numaproj/numaplane/internal/util/metrics"
+	webhookv1alpha1 "github.com/numaproj/numaplane/internal/webhook/v1alpha1"
 	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	apiv1beta1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1beta1"
 )
 
 var (
@@ -66,6 +74,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(apiv1.AddToScheme(scheme))
+	utilruntime.Must(apiv1beta1.AddToScheme(scheme))
 
 	utilruntime.Must(numaflowv1.AddToScheme(scheme))
 
@@ -78,6 +87,7 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var enableWebhooks bool
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -87,6 +97,8 @@ func main() {
 		"If set the metrics endpoint is served securely")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"If set, the admission and conversion webhooks are served, which requires a serving certificate (see config/webhook-cert-manager)")
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
@@ -124,10 +136,21 @@ func main() {
 			BindAddress:   metricsAddr,
 			SecureServing: secureMetrics,
 			TLSOpts:       tlsOpts,
+			// serve the USDE Config in effect for a namespace, for debugging
+			ExtraHandlers: map[string]http.Handler{
+				"/debug/usde-config": config.USDEConfigHandler(),
+			},
 		},
 		Cache: cache.Options{
 			SyncPeriod: &syncPeriod,
 		},
+		// Secrets (such as the ones referenced by a Rollout's valuesFrom) are read from the API server rather than cached, so that
+		// the data of every Secret in the cluster isn't held in memory
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: []client.Object{&corev1.Secret{}},
+			},
+		},
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
@@ -226,6 +249,21 @@ func main() {
 		numaLogger.Fatal(err, "Unable to set up NumaflowController controller")
 	}
 
+	// once this instance is the leader, rehydrate the state kept in memory from the Rollout Statuses before reconciliation begins
+	if err := mgr.Add(ctlrcommon.NewRehydrationRunnable(mgr.GetAPIReader(),
+		ppnd.GetPauseModule().Rehydrate,
+		pipelineRolloutReconciler.Rehydrate,
+		numaflowControllerRolloutReconciler.Rehydrate,
+		isbServiceRolloutReconciler.Rehydrate,
+		monoVertexRolloutReconciler.Rehydrate,
+	)); err != nil {
+		numaLogger.Fatal(err, "Unable to set up rehydration")
+	}
+
+	if enableWebhooks {
+		setupWebhooks(mgr)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -242,6 +280,22 @@ func main() {
 	}
 }
 
+func setupWebhooks(mgr ctrl.Manager) {
+	for kind, setup := range map[string]func(ctrl.Manager) error{
+		"PipelineRollout":           webhookv1alpha1.SetupPipelineRolloutWebhookWithManager,
+		"MonoVertexRollout":         webhookv1alpha1.SetupMonoVertexRolloutWebhookWithManager,
+		"ISBServiceRollout":         webhookv1alpha1.SetupISBServiceRolloutWebhookWithManager,
+		"NumaflowControllerRollout": webhookv1alpha1.SetupNumaflowControllerRolloutWebhookWithManager,
+		"NumaflowController":        webhookv1alpha1.SetupNumaflowControllerWebhookWithManager,
+		"PipelineTemplate":          webhookv1alpha1.SetupPipelineTemplateWebhookWithManager,
+		"MonoVertexTemplate":        webhookv1alpha1.SetupMonoVertexTemplateWebhookWithManager,
+	} {
+		if err := setup(mgr); err != nil {
+			numaLogger.Fatal(err, fmt.Sprintf("Unable to set up %s webhook", kind))
+		}
+	}
+}
+
 func loadConfigs() {
 
 	configManager := config.GetConfigManagerInstance()
@@ -73,6 +73,9 @@ const (
 	// LabelValueNamespaceConfig is the label value used to identify the user's namespace-level ConfigMap
 	LabelValueNamespaceConfig = "namespace-level-config"
 
+	// NamespaceConfigMapUSDEKey is the key in the namespace-level ConfigMap whose value overrides the USDE Config for the namespace
+	NamespaceConfigMapUSDEKey = "usde"
+
 	// LabelKeyISBServiceRONameForPipeline is the label key used to identify the ISBServiceRollout that a Pipeline is associated with
 	LabelKeyISBServiceRONameForPipeline = KeyNumaplanePrefix + "isbsvc-name" // TODO: this is still named "isbsvc-name" instead of "isbsvc-rollout-name" - consider deprecating this and creating a separate label for isbsvc-rollout-name?
 
@@ -106,6 +109,10 @@ const (
 	// after an upgrade.
 	LabelValueUpgradeRecyclable UpgradeState = "recyclable"
 
+	// LabelValueUpgradeStandby is the label value indicating that the resource managed by a NumaRollout was previously promoted,
+	// and is kept scaled to zero during the post-promotion analysis of the newly promoted resource, in case it needs to be revived
+	LabelValueUpgradeStandby UpgradeState = "standby"
+
 	// LabelValueProgressiveSuccess is the value used for the Label `LabelKeyUpgradeStateReason` when `LabelKeyUpgradeState`="recyclable" due to Progressive child succeeding
 	LabelValueProgressiveSuccess UpgradeStateReason = "progressive-success"
 
@@ -116,6 +123,14 @@ const (
 	// replaced after having failed
 	LabelValueProgressiveReplacedFailed UpgradeStateReason = "progressive-replaced-failed"
 
+	// LabelValueProgressiveRolledBack is the value used for the Label `LabelKeyUpgradeStateReason` when `LabelKeyUpgradeState`="recyclable" due to Progressive child having failed
+	// and been rolled back automatically
+	LabelValueProgressiveRolledBack UpgradeStateReason = "progressive-rolled-back"
+
+	// LabelValuePostPromotionRolledBack is the value used for the Label `LabelKeyUpgradeStateReason` when a promoted child failed its post-promotion analysis:
+	// the failed child is labeled "recyclable" and the previous child, which was revived, is labeled "promoted"
+	LabelValuePostPromotionRolledBack UpgradeStateReason = "post-promotion-rolled-back"
+
 	// LabelValueDeleteRecreateChild is the value used for the Label `LabelKeyUpgradeStateReason` when `LabelKeyUpgradeState`="recyclable" due to a child being deleted and recreated
 	LabelValueDeleteRecreateChild UpgradeStateReason = "delete-recreate"
 
@@ -157,6 +172,51 @@ const (
 
 	AnnotationKeyForceDrainFailureStartTime = KeyNumaplanePrefix + "force-drain-failure-start-time"
 
+	// AnnotationKeyPauseTimeoutForceDrain is annotated on a pipeline which didn't pause within its pause timeout for the "pause-and-drain" strategy
+	// and whose PauseTimeoutPolicy is "ForceDrain"; the value is the time at which the force drain was requested
+	// (the spec applied to drain it is the PipelineRollout's definition if the Pipeline itself is being updated, otherwise its own spec)
+	AnnotationKeyPauseTimeoutForceDrain = KeyNumaplanePrefix + "pause-timeout-force-drain"
+
+	// AnnotationKeyApproveUpgrade is annotated on a Rollout to approve its "upgrading" child during a progressive upgrade which requires manual approval;
+	// the value is the name of the "upgrading" child being approved
+	AnnotationKeyApproveUpgrade = KeyNumaplanePrefix + "approve-upgrade"
+
+	// AnnotationKeyApprovedBy can optionally be annotated on a Rollout along with AnnotationKeyApproveUpgrade to record who gave the approval
+	AnnotationKeyApprovedBy = KeyNumaplanePrefix + "approved-by"
+
+	// AnnotationKeyRollbackToRevision is annotated on a Rollout to deploy the child definition and Riders recorded in the given revision
+	// in place of those in the Rollout spec; the rollback remains in effect until the annotation is removed, which the
+	// RolledBackToRevision condition of the Rollout Status indicates
+	AnnotationKeyRollbackToRevision = KeyNumaplanePrefix + "rollback-to-revision"
+
+	// AnnotationKeyAbortUpgrade is annotated on a Rollout to abort its in-flight progressive upgrade, restoring the "promoted" child;
+	// the value must be the name of the "upgrading" child
+	AnnotationKeyAbortUpgrade = KeyNumaplanePrefix + "abort-upgrade"
+
+	// AnnotationKeyRetryUpgrade is annotated on a Rollout to replace its failed "upgrading" child with a new one of the same spec;
+	// the value must be the name of the failed "upgrading" child
+	AnnotationKeyRetryUpgrade = KeyNumaplanePrefix + "retry-upgrade"
+
+	// AnnotationKeySkipAnalysis is annotated on a Rollout to skip the Analysis of its "upgrading" child, while still requiring the basic assessment;
+	// the value must be the name of the "upgrading" child
+	AnnotationKeySkipAnalysis = KeyNumaplanePrefix + "skip-analysis"
+
+	// AnnotationKeyActionRequestedBy can optionally be annotated on a Rollout along with one of the upgrade action annotations to record who requested it
+	AnnotationKeyActionRequestedBy = KeyNumaplanePrefix + "action-requested-by"
+
+	// AnnotationKeyRequirePlanApproval is annotated on a Rollout with the value "true" to require its upgrade plan to be approved before
+	// an upgrade is started (this is equivalent to setting "spec.strategy.requireApprovalOfPlan")
+	AnnotationKeyRequirePlanApproval = KeyNumaplanePrefix + "require-plan-approval"
+
+	// AnnotationKeyApprovePlan is annotated on a Rollout to approve the upgrade plan in its Status;
+	// the value is the definitionHash of the plan, so that the approval doesn't carry over to a plan for a different child definition
+	// (AnnotationKeyApprovedBy can optionally be annotated along with it to record who gave the approval)
+	AnnotationKeyApprovePlan = KeyNumaplanePrefix + "approve-plan"
+
+	// AnnotationKeyTemplatedUpgradeState is annotated on a child to record the upgrade state its definition was templated with
+	// (see TemplateUpgradeState)
+	AnnotationKeyTemplatedUpgradeState = KeyNumaplanePrefix + "templated-upgrade-state"
+
 	// NumaplaneSystemNamespace is the namespace where the Numaplane Controller is deployed
 	NumaplaneSystemNamespace = "numaplane-system"
 
@@ -176,6 +236,31 @@ const (
 
 	// TemplateMonoVertexNamespace can be used as a templated argument in a MonoVertexRollout's mvtx spec
 	TemplateMonoVertexNamespace = ".monovertex-namespace"
+
+	// TemplateISBServiceName can be used as a templated argument in an ISBServiceRollout's isbsvc spec, as well as in a
+	// PipelineRollout's pipeline spec, where it's the name of the InterStepBufferService which the pipeline uses
+	TemplateISBServiceName = ".isbsvc-name"
+
+	// TemplateISBServiceNamespace can be used as a templated argument in an ISBServiceRollout's isbsvc spec
+	TemplateISBServiceNamespace = ".isbsvc-namespace"
+
+	// The following can be used as templated arguments in the child definition and Riders of any Rollout
+
+	// TemplateRolloutLabels is the map of the Rollout's labels, e.g. `{{index .rollout-labels "team"}}`
+	TemplateRolloutLabels = ".rollout-labels"
+
+	// TemplateRolloutAnnotations is the map of the Rollout's annotations
+	TemplateRolloutAnnotations = ".rollout-annotations"
+
+	// TemplateChildIndex is the index which the child's name ends with (taken from the Rollout's NameCount when it was created)
+	TemplateChildIndex = ".child-index"
+
+	// TemplateUpgradeState is the upgrade state which the child was created in, either "promoted" or "in-progress": this stays
+	// the same when an "in-progress" child is promoted, so that its definition doesn't change as a result
+	TemplateUpgradeState = ".upgrade-state"
+
+	// TemplateValues is the map of the values from the ConfigMaps and Secrets referenced by the Rollout's ValuesFrom, e.g. `{{.values.region}}`
+	TemplateValues = ".values"
 )
 
 var (
@@ -91,6 +91,20 @@ func (mgr *InProgressStrategyMgr) synchronize(ctx context.Context, rollout clien
 	}
 }
 
+// Rehydrate restores the in-memory values from the Statuses of the Rollouts, so that a newly started (or newly elected) Numaplane
+// has them before any Rollout is reconciled: any value which has already been set in memory is newer and is kept
+func (mgr *InProgressStrategyMgr) Rehydrate(ctx context.Context, rollouts []client.Object) {
+	for _, rollout := range rollouts {
+		namespacedName := k8stypes.NamespacedName{Namespace: rollout.GetNamespace(), Name: rollout.GetName()}
+		if found, _ := mgr.Store.GetStrategy(namespacedName); found {
+			continue
+		}
+		if crDefinedStrategy := mgr.getRolloutStrategy(ctx, rollout); crDefinedStrategy != nil {
+			mgr.Store.SetStrategy(namespacedName, *crDefinedStrategy)
+		}
+	}
+}
+
 // store in both memory and the Resource itself
 func (mgr *InProgressStrategyMgr) SetStrategy(ctx context.Context, rollout client.Object, upgradeStrategy apiv1.UpgradeStrategy) {
 	namespacedName := k8stypes.NamespacedName{Namespace: rollout.GetNamespace(), Name: rollout.GetName()}
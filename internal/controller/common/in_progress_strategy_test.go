@@ -93,3 +93,42 @@ func Test_inProgressStrategyMgr_getStrategy(t *testing.T) {
 		})
 	}
 }
+
+func Test_inProgressStrategyMgr_rehydrate(t *testing.T) {
+
+	progressiveStrategy := apiv1.UpgradeStrategyProgressive
+	ppndStrategy := apiv1.UpgradeStrategyPPND
+
+	rolloutStrategies := map[string]*apiv1.UpgradeStrategy{
+		"in-progress":     &progressiveStrategy,
+		"already-set":     &ppndStrategy,
+		"not-in-progress": nil,
+	}
+	inProgressStrategyMgr := NewInProgressStrategyMgr(
+		// getRolloutStrategy function:
+		func(ctx context.Context, rollout client.Object) *apiv1.UpgradeStrategy {
+			return rolloutStrategies[rollout.GetName()]
+		},
+		// setRolloutStrategy function:
+		func(ctx context.Context, rollout client.Object, strategy apiv1.UpgradeStrategy) {},
+	)
+	// a value which has already been set in memory is newer than the one in the Rollout Status
+	inProgressStrategyMgr.Store.SetStrategy(k8stypes.NamespacedName{Namespace: "default", Name: "already-set"}, apiv1.UpgradeStrategyNoOp)
+
+	rollouts := []client.Object{}
+	for name := range rolloutStrategies {
+		rollouts = append(rollouts, &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}})
+	}
+	inProgressStrategyMgr.Rehydrate(context.Background(), rollouts)
+
+	found, strategy := inProgressStrategyMgr.Store.GetStrategy(k8stypes.NamespacedName{Namespace: "default", Name: "in-progress"})
+	assert.True(t, found)
+	assert.Equal(t, progressiveStrategy, strategy)
+
+	found, strategy = inProgressStrategyMgr.Store.GetStrategy(k8stypes.NamespacedName{Namespace: "default", Name: "already-set"})
+	assert.True(t, found)
+	assert.Equal(t, apiv1.UpgradeStrategyNoOp, strategy)
+
+	found, _ = inProgressStrategyMgr.Store.GetStrategy(k8stypes.NamespacedName{Namespace: "default", Name: "not-in-progress"})
+	assert.False(t, found)
+}
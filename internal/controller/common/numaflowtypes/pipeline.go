@@ -91,13 +91,39 @@ func GetPipelinesForRollout(ctx context.Context, c client.Client, pipelineRollou
 
 }
 
-func GetPipelineSpecFromRollout(
+// GetPipelineTemplateArguments returns the arguments for templating the definition of a Pipeline (and its Riders)
+func GetPipelineTemplateArguments(
+	ctx context.Context,
+	c client.Client,
+	pipelineRollout *apiv1.PipelineRollout,
 	pipelineName string,
+	isbsvcName string,
+	upgradeState common.UpgradeState,
+) (map[string]interface{}, error) {
+	args, err := ctlrcommon.GetCommonTemplateArguments(ctx, c, pipelineRollout, pipelineName, upgradeState)
+	if err != nil {
+		return nil, err
+	}
+	args[common.TemplatePipelineName] = pipelineName
+	args[common.TemplatePipelineNamespace] = pipelineRollout.Namespace
+	args[common.TemplateISBServiceName] = isbsvcName
+	return args, nil
+}
+
+// GetPipelineSpecFromRollout returns the PipelineRollout's pipeline spec, templated for the existing pipeline
+func GetPipelineSpecFromRollout(
+	ctx context.Context,
+	c client.Client,
+	pipeline *unstructured.Unstructured,
 	pipelineRollout *apiv1.PipelineRollout,
 ) (map[string]interface{}, error) {
-	args := map[string]interface{}{
-		common.TemplatePipelineName:      pipelineName,
-		common.TemplatePipelineNamespace: pipelineRollout.Namespace,
+	isbsvcName, err := GetPipelineISBSVCName(pipeline)
+	if err != nil {
+		return nil, err
+	}
+	args, err := GetPipelineTemplateArguments(ctx, c, pipelineRollout, pipeline.GetName(), isbsvcName, ctlrcommon.GetTemplatedUpgradeState(pipeline))
+	if err != nil {
+		return nil, err
 	}
 
 	return util.ResolveTemplatedSpec(pipelineRollout.Spec.Pipeline.Spec, args)
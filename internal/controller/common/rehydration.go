@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/numaproj/numaplane/internal/util/logger"
+)
+
+// Some of the state which Numaplane keeps about Rollouts (e.g. their in-progress upgrade strategies and PPND pause requests) is kept
+// in memory and persisted in their Statuses. Whenever Numaplane starts leading (after a restart or a change of leader), that state is
+// rehydrated from the Statuses before any Rollout is reconciled. (Numaplane exits when it stops leading, so the state in memory never
+// outlives the leadership it was built up under.)
+
+// RehydrateFunc restores some state kept in memory from the Rollouts it was persisted in
+type RehydrateFunc func(ctx context.Context, c client.Reader) error
+
+var (
+	rehydrated     = make(chan struct{})
+	rehydratedOnce sync.Once
+)
+
+// NewRehydrationRunnable returns a Runnable which rehydrates all of the state kept in memory once this instance is elected leader,
+// and then lets reconciliation begin
+func NewRehydrationRunnable(c client.Reader, rehydrateFuncs ...RehydrateFunc) manager.Runnable {
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		numaLogger := logger.GetBaseLogger().WithName("rehydration")
+		for _, rehydrate := range rehydrateFuncs {
+			if err := rehydrate(ctx, c); err != nil {
+				return fmt.Errorf("failed to rehydrate state from Rollout Statuses: %w", err)
+			}
+		}
+		numaLogger.Info("rehydrated state from Rollout Statuses")
+		MarkRehydrated()
+		return nil
+	})
+}
+
+// MarkRehydrated lets reconciliation begin
+func MarkRehydrated() {
+	rehydratedOnce.Do(func() {
+		close(rehydrated)
+	})
+}
+
+// WaitForRehydration blocks until the state kept in memory has been rehydrated, so that reconciliation can begin
+func WaitForRehydration(ctx context.Context) error {
+	select {
+	case <-rehydrated:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,439 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revisions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/common"
+	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// DefaultRevisionHistoryLimit is the number of revisions retained if the Rollout doesn't specify a RevisionHistoryLimit
+const DefaultRevisionHistoryLimit int32 = 10
+
+// Outcome describes what became of the child deployed for a revision
+type Outcome string
+
+const (
+	OutcomeInProgress   Outcome = "InProgress"
+	OutcomePromoted     Outcome = "Promoted"
+	OutcomeFailed       Outcome = "Failed"
+	OutcomeDiscontinued Outcome = "Discontinued"
+)
+
+// Revision is the data stored in the ControllerRevision for each revision of a Rollout's child
+type Revision struct {
+	// ChildName is the name of the child which was deployed for this revision
+	ChildName string `json:"childName"`
+	// ChildDefinition is the child definition (metadata and spec) from the Rollout at the time of this revision
+	ChildDefinition map[string]interface{} `json:"childDefinition"`
+	// Riders are the Riders from the Rollout at the time of this revision
+	Riders []interface{} `json:"riders,omitempty"`
+	// Outcome is what became of the child
+	Outcome Outcome `json:"outcome"`
+}
+
+// RevisionRolloutObject is a Rollout whose revisions can be recorded
+type RevisionRolloutObject interface {
+	ctlrcommon.RolloutObject
+
+	GetRevisionHistoryLimit() *int32
+}
+
+// return the Rollout GVK along with the name of the field in the Rollout spec which holds the child definition
+func rolloutKindInfo(rolloutObject RevisionRolloutObject) (schema.GroupVersionKind, string, error) {
+	switch rolloutObject.(type) {
+	case *apiv1.PipelineRollout:
+		return apiv1.PipelineRolloutGroupVersionKind, "pipeline", nil
+	case *apiv1.MonoVertexRollout:
+		return apiv1.MonoVertexRolloutGroupVersionKind, "monoVertex", nil
+	case *apiv1.ISBServiceRollout:
+		return apiv1.ISBServiceRolloutGroupVersionKind, "interStepBufferService", nil
+	default:
+		return schema.GroupVersionKind{}, "", fmt.Errorf("revision history is not supported for rollout type %T", rolloutObject)
+	}
+}
+
+// create a Revision from the current Rollout spec
+func newRevisionFromRollout(rolloutObject RevisionRolloutObject, childName string, outcome Outcome) (*Revision, error) {
+	_, childField, err := rolloutKindInfo(rolloutObject)
+	if err != nil {
+		return nil, err
+	}
+	asMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(rolloutObject)
+	if err != nil {
+		return nil, err
+	}
+	childDefinition, _, err := unstructured.NestedMap(asMap, "spec", childField)
+	if err != nil {
+		return nil, err
+	}
+	riders, _, err := unstructured.NestedSlice(asMap, "spec", "riders")
+	if err != nil {
+		return nil, err
+	}
+	return &Revision{
+		ChildName:       childName,
+		ChildDefinition: childDefinition,
+		Riders:          riders,
+		Outcome:         outcome,
+	}, nil
+}
+
+// determine if the two Revisions were made from the same child definition and Riders
+func sameDefinition(a, b *Revision) (bool, error) {
+	aBytes, err := json.Marshal(Revision{ChildDefinition: a.ChildDefinition, Riders: a.Riders})
+	if err != nil {
+		return false, err
+	}
+	bBytes, err := json.Marshal(Revision{ChildDefinition: b.ChildDefinition, Riders: b.Riders})
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(aBytes, bBytes), nil
+}
+
+// DecodeRevision returns the Revision stored in a ControllerRevision
+func DecodeRevision(controllerRevision *appsv1.ControllerRevision) (*Revision, error) {
+	revision := &Revision{}
+	if err := json.Unmarshal(controllerRevision.Data.Raw, revision); err != nil {
+		return nil, fmt.Errorf("failed to decode ControllerRevision %s: %w", controllerRevision.GetName(), err)
+	}
+	return revision, nil
+}
+
+// ListRevisions returns the ControllerRevisions owned by the Rollout, sorted from oldest to newest
+func ListRevisions(ctx context.Context, c client.Client, rolloutObject RevisionRolloutObject) ([]appsv1.ControllerRevision, error) {
+	rolloutMeta := rolloutObject.GetRolloutObjectMeta()
+	list := &appsv1.ControllerRevisionList{}
+	if err := c.List(ctx, list, client.InNamespace(rolloutMeta.GetNamespace()), client.MatchingLabels{common.LabelKeyParentRollout: rolloutMeta.GetName()}); err != nil {
+		return nil, fmt.Errorf("failed to list ControllerRevisions for rollout %s/%s: %w", rolloutMeta.GetNamespace(), rolloutMeta.GetName(), err)
+	}
+
+	revisions := []appsv1.ControllerRevision{}
+	for _, controllerRevision := range list.Items {
+		if metav1.IsControlledBy(&controllerRevision, rolloutMeta) {
+			revisions = append(revisions, controllerRevision)
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	return revisions, nil
+}
+
+// GetRevision returns the Revision with the given revision number
+func GetRevision(ctx context.Context, c client.Client, rolloutObject RevisionRolloutObject, revisionNumber int64) (*Revision, error) {
+	revisions, err := ListRevisions(ctx, c, rolloutObject)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		if revisions[i].Revision == revisionNumber {
+			return DecodeRevision(&revisions[i])
+		}
+	}
+	return nil, fmt.Errorf("revision %d not found for rollout %s/%s", revisionNumber, rolloutObject.GetRolloutObjectMeta().GetNamespace(), rolloutObject.GetRolloutObjectMeta().GetName())
+}
+
+/*
+RecordRevision records the current Rollout child definition and Riders along with the resulting child and its outcome.
+If the most recent revision was recorded for the same child and definition, only its outcome is updated.
+Revisions beyond the Rollout's RevisionHistoryLimit are deleted, oldest first.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - c: the client used for interacting with the Kubernetes API.
+  - rolloutObject: the Rollout whose revision is recorded
+  - childName: the name of the child deployed for this revision
+  - outcome: what became of the child
+
+Returns:
+  - An error if any issues occur during processing.
+*/
+func RecordRevision(ctx context.Context, c client.Client, rolloutObject RevisionRolloutObject, childName string, outcome Outcome) error {
+	numaLogger := logger.FromContext(ctx)
+
+	// while a Rollout is held back from upgrading to a new template generation, its child definition isn't its own: it's the definition
+	// of its latest revision, or else of its child, in which the values from any Secrets in its valuesFrom are substituted
+	if templatedRollout, ok := rolloutObject.(interface{ GetTemplateStatus() *apiv1.TemplateStatus }); ok {
+		if templateStatus := templatedRollout.GetTemplateStatus(); templateStatus != nil && templateStatus.PendingGeneration != 0 {
+			numaLogger.WithValues("childName", childName).Debug("not recording a revision while waiting to upgrade to a new template generation")
+			return nil
+		}
+	}
+
+	revision, err := newRevisionFromRollout(rolloutObject, childName, outcome)
+	if err != nil {
+		return err
+	}
+
+	existingRevisions, err := ListRevisions(ctx, c, rolloutObject)
+	if err != nil {
+		return err
+	}
+
+	nextRevisionNumber := int64(1)
+	if len(existingRevisions) > 0 {
+		latest := &existingRevisions[len(existingRevisions)-1]
+		nextRevisionNumber = latest.Revision + 1
+
+		latestRevision, err := DecodeRevision(latest)
+		if err != nil {
+			return err
+		}
+		same, err := sameDefinition(latestRevision, revision)
+		if err != nil {
+			return err
+		}
+		if latestRevision.ChildName == childName && same {
+			return updateOutcome(ctx, c, latest, latestRevision, outcome)
+		}
+	}
+
+	controllerRevision, err := makeControllerRevision(rolloutObject, revision, nextRevisionNumber)
+	if err != nil {
+		return err
+	}
+	if err := c.Create(ctx, controllerRevision); err != nil {
+		return fmt.Errorf("failed to create ControllerRevision %s: %w", controllerRevision.GetName(), err)
+	}
+	numaLogger.WithValues("revision", nextRevisionNumber, "childName", childName, "outcome", outcome).Debug("recorded new revision")
+
+	return pruneRevisions(ctx, c, rolloutObject, append(existingRevisions, *controllerRevision))
+}
+
+// UpdateRevisionOutcome updates the outcome of the most recent revision recorded for the given child, if there is one
+func UpdateRevisionOutcome(ctx context.Context, c client.Client, rolloutObject RevisionRolloutObject, childName string, outcome Outcome) error {
+	existingRevisions, err := ListRevisions(ctx, c, rolloutObject)
+	if err != nil {
+		return err
+	}
+	for i := len(existingRevisions) - 1; i >= 0; i-- {
+		revision, err := DecodeRevision(&existingRevisions[i])
+		if err != nil {
+			return err
+		}
+		if revision.ChildName == childName {
+			return updateOutcome(ctx, c, &existingRevisions[i], revision, outcome)
+		}
+	}
+	return nil
+}
+
+/*
+ApplyRollbackToRevision checks the Rollout for the "rollback-to-revision" annotation, and if present, replaces the child definition
+and Riders of the in-memory Rollout spec with those recorded in the given revision, so that the revision is deployed through the normal
+upgrade process. Note that the Rollout spec must not be written back to the cluster after this.
+The rollback remains in effect until the annotation is removed, which the RolledBackToRevision condition and RollbackToRevision
+field of the Rollout Status make visible. If the revision is invalid or isn't retained, the condition records that and the spec
+is deployed as is.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - c: the client used for interacting with the Kubernetes API.
+  - rolloutObject: the Rollout (must be a pointer to the Rollout type)
+
+Returns:
+  - A boolean indicating whether a rollback is in effect.
+  - An error if any issues occur during processing.
+*/
+func ApplyRollbackToRevision(ctx context.Context, c client.Client, rolloutObject RevisionRolloutObject) (bool, error) {
+	numaLogger := logger.FromContext(ctx)
+	status := rolloutObject.GetRolloutStatus()
+	generation := rolloutObject.GetRolloutObjectMeta().GetGeneration()
+
+	value := rolloutObject.GetRolloutObjectMeta().GetAnnotations()[common.AnnotationKeyRollbackToRevision]
+	if value == "" {
+		status.ClearRollbackToRevision()
+		return false, nil
+	}
+	revisionNumber, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		msg := fmt.Sprintf("invalid value %q for annotation %s, deploying the spec", value, common.AnnotationKeyRollbackToRevision)
+		numaLogger.Warn(msg)
+		status.MarkRollbackToRevisionFailed("InvalidRevision", msg, generation)
+		return false, nil
+	}
+
+	existingRevisions, err := ListRevisions(ctx, c, rolloutObject)
+	if err != nil {
+		return false, err
+	}
+	var revision *Revision
+	for i := range existingRevisions {
+		if existingRevisions[i].Revision == revisionNumber {
+			if revision, err = DecodeRevision(&existingRevisions[i]); err != nil {
+				return false, err
+			}
+			break
+		}
+	}
+	if revision == nil {
+		msg := fmt.Sprintf("revision %d requested by annotation %s is not retained, deploying the spec", revisionNumber, common.AnnotationKeyRollbackToRevision)
+		numaLogger.Warn(msg)
+		status.MarkRollbackToRevisionFailed("RevisionNotFound", msg, generation)
+		return false, nil
+	}
+
+	if err := applyRevision(rolloutObject, revision); err != nil {
+		return false, fmt.Errorf("failed to apply revision %d to rollout: %w", revisionNumber, err)
+	}
+	status.MarkRolledBackToRevision(revisionNumber, generation)
+
+	numaLogger.WithValues("revision", revisionNumber).Debug("rolling back to revision")
+	return true, nil
+}
+
+/*
+ApplyAutoRollback checks whether the current generation of the Rollout was rolled back after a failed progressive upgrade, and if so,
+replaces the child definition and Riders of the in-memory Rollout spec with those of the most recent revision whose child was promoted.
+This keeps the effective spec at the last known-good one until the Rollout spec changes or the upgrade is retried.
+If no promoted revision is retained (e.g. RevisionHistoryLimit is 0), the spec is left as is: the RolledBack condition still prevents
+a new upgrade from starting, so the "promoted" child is held in place.
+Note that the Rollout spec must not be written back to the cluster after this.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - c: the client used for interacting with the Kubernetes API.
+  - rolloutObject: the Rollout (must be a pointer to the Rollout type)
+
+Returns:
+  - A boolean indicating whether the spec was reverted.
+  - An error if any issues occur during processing.
+*/
+func ApplyAutoRollback(ctx context.Context, c client.Client, rolloutObject RevisionRolloutObject) (bool, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	condition := rolloutObject.GetRolloutStatus().GetCondition(apiv1.ConditionRolledBack)
+	if condition == nil || condition.Status != metav1.ConditionTrue || condition.ObservedGeneration != rolloutObject.GetRolloutObjectMeta().GetGeneration() {
+		return false, nil
+	}
+
+	existingRevisions, err := ListRevisions(ctx, c, rolloutObject)
+	if err != nil {
+		return false, err
+	}
+	for i := len(existingRevisions) - 1; i >= 0; i-- {
+		revision, err := DecodeRevision(&existingRevisions[i])
+		if err != nil {
+			return false, err
+		}
+		if revision.Outcome != OutcomePromoted {
+			continue
+		}
+		if err := applyRevision(rolloutObject, revision); err != nil {
+			return false, fmt.Errorf("failed to apply revision %d to rollout: %w", existingRevisions[i].Revision, err)
+		}
+		numaLogger.WithValues("revision", existingRevisions[i].Revision).Debug("rollout was rolled back, using the last promoted revision")
+		return true, nil
+	}
+
+	numaLogger.Debug("rollout was rolled back but no promoted revision is retained, holding the promoted child")
+	return false, nil
+}
+
+// replace the child definition and Riders of the in-memory Rollout spec with those of the revision
+func applyRevision(rolloutObject RevisionRolloutObject, revision *Revision) error {
+	_, childField, err := rolloutKindInfo(rolloutObject)
+	if err != nil {
+		return err
+	}
+	asMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(rolloutObject)
+	if err != nil {
+		return err
+	}
+	if err := unstructured.SetNestedMap(asMap, revision.ChildDefinition, "spec", childField); err != nil {
+		return err
+	}
+	if len(revision.Riders) > 0 {
+		if err := unstructured.SetNestedSlice(asMap, revision.Riders, "spec", "riders"); err != nil {
+			return err
+		}
+	} else {
+		unstructured.RemoveNestedField(asMap, "spec", "riders")
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(asMap, rolloutObject)
+}
+
+func updateOutcome(ctx context.Context, c client.Client, controllerRevision *appsv1.ControllerRevision, revision *Revision, outcome Outcome) error {
+	if revision.Outcome == outcome {
+		return nil
+	}
+	revision.Outcome = outcome
+	raw, err := json.Marshal(revision)
+	if err != nil {
+		return err
+	}
+	controllerRevision.Data = runtime.RawExtension{Raw: raw}
+	if err := c.Update(ctx, controllerRevision); err != nil {
+		return fmt.Errorf("failed to update ControllerRevision %s: %w", controllerRevision.GetName(), err)
+	}
+	logger.FromContext(ctx).WithValues("revision", controllerRevision.Revision, "childName", revision.ChildName, "outcome", outcome).Debug("updated revision outcome")
+	return nil
+}
+
+func makeControllerRevision(rolloutObject RevisionRolloutObject, revision *Revision, revisionNumber int64) (*appsv1.ControllerRevision, error) {
+	gvk, _, err := rolloutKindInfo(rolloutObject)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(revision)
+	if err != nil {
+		return nil, err
+	}
+	rolloutMeta := rolloutObject.GetRolloutObjectMeta()
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-%s-%d", strings.ToLower(gvk.Kind), rolloutMeta.GetName(), revisionNumber),
+			Namespace:       rolloutMeta.GetNamespace(),
+			Labels:          map[string]string{common.LabelKeyParentRollout: rolloutMeta.GetName()},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(rolloutMeta, gvk)},
+		},
+		Data:     runtime.RawExtension{Raw: raw},
+		Revision: revisionNumber,
+	}, nil
+}
+
+// delete the oldest revisions beyond the Rollout's RevisionHistoryLimit
+// (revisions are expected to be sorted from oldest to newest)
+func pruneRevisions(ctx context.Context, c client.Client, rolloutObject RevisionRolloutObject, revisions []appsv1.ControllerRevision) error {
+	limit := DefaultRevisionHistoryLimit
+	if rolloutObject.GetRevisionHistoryLimit() != nil {
+		limit = *rolloutObject.GetRevisionHistoryLimit()
+	}
+	for i := 0; i < len(revisions)-int(limit); i++ {
+		if err := c.Delete(ctx, &revisions[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete ControllerRevision %s: %w", revisions[i].GetName(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package revisions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func newMonoVertexRollout(image string, historyLimit *int32) *apiv1.MonoVertexRollout {
+	return &apiv1.MonoVertexRollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test-namespace",
+			UID:       "test-uid",
+		},
+		Spec: apiv1.MonoVertexRolloutSpec{
+			MonoVertex: apiv1.MonoVertex{
+				Spec: runtime.RawExtension{Raw: []byte(`{"source":{"udsource":{"container":{"image":"` + image + `"}}}}`)},
+			},
+			RevisionHistoryLimit: historyLimit,
+		},
+	}
+}
+
+func Test_RecordRevision(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, appsv1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	limit := int32(2)
+	rollout := newMonoVertexRollout("image:v1", &limit)
+
+	// recording the same child and definition again only updates the outcome
+	assert.NoError(t, RecordRevision(ctx, fakeClient, rollout, "test-0", OutcomeInProgress))
+	assert.NoError(t, RecordRevision(ctx, fakeClient, rollout, "test-0", OutcomePromoted))
+	revisions, err := ListRevisions(ctx, fakeClient, rollout)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 1)
+	assert.Equal(t, "monovertexrollout-test-1", revisions[0].Name)
+	assert.Equal(t, "test", revisions[0].Labels[common.LabelKeyParentRollout])
+	revision, err := DecodeRevision(&revisions[0])
+	assert.NoError(t, err)
+	assert.Equal(t, OutcomePromoted, revision.Outcome)
+
+	// a new definition creates a new revision
+	rollout = newMonoVertexRollout("image:v2", &limit)
+	assert.NoError(t, RecordRevision(ctx, fakeClient, rollout, "test-1", OutcomeInProgress))
+	assert.NoError(t, UpdateRevisionOutcome(ctx, fakeClient, rollout, "test-1", OutcomeFailed))
+	revision, err = GetRevision(ctx, fakeClient, rollout, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "test-1", revision.ChildName)
+	assert.Equal(t, OutcomeFailed, revision.Outcome)
+
+	// revisions beyond the limit are pruned, oldest first
+	rollout = newMonoVertexRollout("image:v3", &limit)
+	assert.NoError(t, RecordRevision(ctx, fakeClient, rollout, "test-2", OutcomeInProgress))
+	revisions, err = ListRevisions(ctx, fakeClient, rollout)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, int64(2), revisions[0].Revision)
+	assert.Equal(t, int64(3), revisions[1].Revision)
+
+	// nothing is recorded while the Rollout is waiting to upgrade to a new template generation, since its child definition isn't its own
+	rollout = newMonoVertexRollout("image:v4", &limit)
+	rollout.Status.Template = &apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 1, PendingGeneration: 2}
+	assert.NoError(t, RecordRevision(ctx, fakeClient, rollout, "test-3", OutcomePromoted))
+	revisions, err = ListRevisions(ctx, fakeClient, rollout)
+	assert.NoError(t, err)
+	assert.Len(t, revisions, 2)
+	assert.Equal(t, int64(3), revisions[1].Revision)
+}
+
+func Test_ApplyRollbackToRevision(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, appsv1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	assert.NoError(t, RecordRevision(ctx, fakeClient, newMonoVertexRollout("image:v1", nil), "test-0", OutcomePromoted))
+
+	rollout := newMonoVertexRollout("image:v2", nil)
+
+	// no annotation
+	rolledBack, err := ApplyRollbackToRevision(ctx, fakeClient, rollout)
+	assert.NoError(t, err)
+	assert.False(t, rolledBack)
+	assert.Nil(t, rollout.Status.GetCondition(apiv1.ConditionRolledBackToRevision))
+
+	// unknown revision: the spec is deployed and the condition says why
+	rollout.Annotations = map[string]string{common.AnnotationKeyRollbackToRevision: "5"}
+	rolledBack, err = ApplyRollbackToRevision(ctx, fakeClient, rollout)
+	assert.NoError(t, err)
+	assert.False(t, rolledBack)
+	condition := rollout.Status.GetCondition(apiv1.ConditionRolledBackToRevision)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "RevisionNotFound", condition.Reason)
+	assert.Contains(t, string(rollout.Spec.MonoVertex.Spec.Raw), "image:v2")
+
+	// invalid revision
+	rollout.Annotations = map[string]string{common.AnnotationKeyRollbackToRevision: "latest"}
+	rolledBack, err = ApplyRollbackToRevision(ctx, fakeClient, rollout)
+	assert.NoError(t, err)
+	assert.False(t, rolledBack)
+	assert.Equal(t, "InvalidRevision", rollout.Status.GetCondition(apiv1.ConditionRolledBackToRevision).Reason)
+
+	rollout.Annotations = map[string]string{common.AnnotationKeyRollbackToRevision: "1"}
+	rolledBack, err = ApplyRollbackToRevision(ctx, fakeClient, rollout)
+	assert.NoError(t, err)
+	assert.True(t, rolledBack)
+	assert.Contains(t, string(rollout.Spec.MonoVertex.Spec.Raw), "image:v1")
+	assert.Equal(t, metav1.ConditionTrue, rollout.Status.GetCondition(apiv1.ConditionRolledBackToRevision).Status)
+	assert.Equal(t, int64(1), *rollout.Status.RollbackToRevision)
+
+	// once the annotation is removed, the rollback is no longer recorded
+	rollout.Annotations = nil
+	rolledBack, err = ApplyRollbackToRevision(ctx, fakeClient, rollout)
+	assert.NoError(t, err)
+	assert.False(t, rolledBack)
+	assert.Nil(t, rollout.Status.GetCondition(apiv1.ConditionRolledBackToRevision))
+	assert.Nil(t, rollout.Status.RollbackToRevision)
+}
+
+func Test_ApplyAutoRollback(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, appsv1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	rollout := newMonoVertexRollout("image:v3", nil)
+	rollout.Generation = 3
+
+	// no revisions retained: the spec is left as is
+	rollout.Status.MarkRolledBack("failed", 3)
+	reverted, err := ApplyAutoRollback(ctx, fakeClient, rollout)
+	assert.NoError(t, err)
+	assert.False(t, reverted)
+
+	assert.NoError(t, RecordRevision(ctx, fakeClient, newMonoVertexRollout("image:v1", nil), "test-0", OutcomePromoted))
+	assert.NoError(t, RecordRevision(ctx, fakeClient, newMonoVertexRollout("image:v2", nil), "test-1", OutcomePromoted))
+	assert.NoError(t, RecordRevision(ctx, fakeClient, newMonoVertexRollout("image:v3", nil), "test-2", OutcomeFailed))
+
+	// not rolled back
+	rollout.Status.ClearRolledBack(3)
+	reverted, err = ApplyAutoRollback(ctx, fakeClient, rollout)
+	assert.NoError(t, err)
+	assert.False(t, reverted)
+
+	// rolled back for a previous generation
+	rollout.Status.MarkRolledBack("failed", 2)
+	reverted, err = ApplyAutoRollback(ctx, fakeClient, rollout)
+	assert.NoError(t, err)
+	assert.False(t, reverted)
+	assert.Contains(t, string(rollout.Spec.MonoVertex.Spec.Raw), "image:v3")
+
+	// rolled back for the current generation: the last promoted revision is used
+	rollout.Status.MarkRolledBack("failed", 3)
+	reverted, err = ApplyAutoRollback(ctx, fakeClient, rollout)
+	assert.NoError(t, err)
+	assert.True(t, reverted)
+	assert.Contains(t, string(rollout.Spec.MonoVertex.Spec.Raw), "image:v2")
+}
@@ -60,9 +60,9 @@ func UpdateRidersInK8S(
 
 	numaLogger.WithValues(
 		"child", child.GetName(),
-		"rider additions", riderAdditions,
-		"rider modifications", riderModifications,
-		"rider deletions", riderDeletions).Debug("updating riders")
+		"rider additions", kubernetes.ExtractResourceNames(&riderAdditions),
+		"rider modifications", kubernetes.ExtractResourceNames(&riderModifications),
+		"rider deletions", kubernetes.ExtractResourceNames(&riderDeletions)).Debug("updating riders")
 
 	// Create new Resources
 	for _, rider := range riderAdditions.Items {
@@ -155,29 +155,36 @@ func GetRidersFromK8S(ctx context.Context, namespace string, ridersList []apiv1.
 
 // Verify that the Riders' Groups/Kinds are permitted for deployment
 func VerifyRidersPermitted(riders []Rider) bool {
+	unpermittedKinds, err := GetUnpermittedRiderKinds(riders)
+	return err == nil && len(unpermittedKinds) == 0
+}
+
+// GetUnpermittedRiderKinds returns the Groups/Kinds of the Riders which aren't permitted for deployment, as "Kind.Group"
+func GetUnpermittedRiderKinds(riders []Rider) ([]string, error) {
 
 	// get global configmap
 	globalConfig, err := config.GetConfigManagerInstance().GetConfig()
 	if err != nil {
-		return false
+		return nil, err
 	}
 
 	// parse permitted Riders and create resource filter
 	riderRules, err := kubernetes.ParseResourceFilter(globalConfig.PermittedRiders)
 	if err != nil {
-		return false
+		return nil, err
 	}
 	resourcesFilter := &kubernetes.ResourceFilter{
 		IncludedResources: riderRules,
 	}
 
 	// check that each rider is a permitted Group/Kind set in the Numplane controller config
+	unpermittedKinds := []string{}
 	for _, rider := range riders {
 		gvk := rider.Definition.GroupVersionKind()
 		if resourcesFilter.IsExcludedResource(gvk.Group, gvk.Kind, "") {
-			return false
+			unpermittedKinds = append(unpermittedKinds, gvk.GroupKind().String())
 		}
 	}
 
-	return true
+	return unpermittedKinds, nil
 }
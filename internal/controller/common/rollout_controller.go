@@ -31,7 +31,7 @@ type RolloutController interface {
 	// The child name is what's used for templating the Rider definition, while the `child` is really only used by the PipelineRolloutReconciler
 	// in the case of "per-vertex" Riders. In this case, it's necessary to use the existing child's name to template in order to effectively compare whether the
 	// Rider has changed, but use the latest child definition to derive the current list of Vertices that need Riders.
-	GetDesiredRiders(rolloutObject RolloutObject, childName string, child *unstructured.Unstructured) ([]riders.Rider, error)
+	GetDesiredRiders(ctx context.Context, rolloutObject RolloutObject, childName string, child *unstructured.Unstructured) ([]riders.Rider, error)
 
 	// GetExistingRiders gets the list of Riders that already exists, either for the Promoted child or the Upgrading child depending on the value of "upgrading"
 	GetExistingRiders(ctx context.Context, rolloutObject RolloutObject, upgrading bool) (unstructured.UnstructuredList, error)
@@ -40,7 +40,7 @@ type RolloutController interface {
 	SetCurrentRiderList(ctx context.Context, rolloutObject RolloutObject, riders []riders.Rider)
 
 	// GetTemplateArguments is the map of Arguments used for templating the child definition
-	GetTemplateArguments(child *unstructured.Unstructured) map[string]interface{}
+	GetTemplateArguments(ctx context.Context, rolloutObject RolloutObject, child *unstructured.Unstructured) (map[string]interface{}, error)
 }
 
 // Garbage Collect all recyclable children; return true if we've deleted all that are recyclable
@@ -245,7 +245,7 @@ func CreateRidersForNewChild(
 ) error {
 
 	// create definitions for riders by templating what's defined in the Rollout definition with the child definition
-	newRiders, err := controller.GetDesiredRiders(rolloutObject, child.GetName(), child)
+	newRiders, err := controller.GetDesiredRiders(ctx, rolloutObject, child.GetName(), child)
 	if err != nil {
 		return fmt.Errorf("error getting desired Riders for child %s: %s", child.GetName(), err)
 	}
@@ -43,13 +43,24 @@ type RolloutObject interface {
 
 // assume child name is "<rolloutname>-<number>"
 func GetRolloutParentName(childName string) (string, error) {
+	parentName, _, err := splitChildName(childName)
+	return parentName, err
+}
+
+// GetChildIndex returns the number which the child's name ends with, assuming the child name is "<rolloutname>-<number>"
+func GetChildIndex(childName string) (int, error) {
+	_, index, err := splitChildName(childName)
+	return index, err
+}
+
+func splitChildName(childName string) (string, int, error) {
 
 	index := strings.LastIndex(childName, "-")
 	if index > 0 && index < len(childName)-1 {
-		_, err := strconv.Atoi(childName[index+1:])
+		childIndex, err := strconv.Atoi(childName[index+1:])
 		if err == nil {
-			return childName[:index], nil
+			return childName[:index], childIndex, nil
 		}
 	}
-	return "", fmt.Errorf("unexpected child name %q doesn't end with '-<number>'", childName)
+	return "", 0, fmt.Errorf("unexpected child name %q doesn't end with '-<number>'", childName)
 }
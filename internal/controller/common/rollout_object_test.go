@@ -48,3 +48,28 @@ func TestGetRolloutParentName(t *testing.T) {
 		})
 	}
 }
+
+func TestGetChildIndex(t *testing.T) {
+	tests := []struct {
+		childName     string
+		expectedIndex int
+		expectError   bool
+	}{
+		{"parent-123", 123, false},
+		{"my-parent-0", 0, false},
+		{"parent-child", 0, true},
+		{"parent-", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("childName=%s", tt.childName), func(t *testing.T) {
+			index, err := GetChildIndex(tt.childName)
+			if (err != nil) != tt.expectError {
+				t.Errorf("expected error: %v, got: %v", tt.expectError, err)
+			}
+			if index != tt.expectedIndex {
+				t.Errorf("expected index: %d, got: %d", tt.expectedIndex, index)
+			}
+		})
+	}
+}
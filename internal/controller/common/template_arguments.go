@@ -0,0 +1,88 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+)
+
+// GetCommonTemplateArguments returns the template arguments which are available for the child of any kind of Rollout, including
+// the values from the ConfigMaps and Secrets it references: each controller adds its own (such as the child's name) to these
+func GetCommonTemplateArguments(ctx context.Context, c client.Client, rolloutObject ValuesFromRolloutObject, childName string,
+	upgradeState common.UpgradeState) (map[string]interface{}, error) {
+	values, err := GetTemplateValues(ctx, c, rolloutObject)
+	if err != nil {
+		return nil, err
+	}
+	rolloutMeta := rolloutObject.GetRolloutObjectMeta()
+	args := map[string]interface{}{
+		common.TemplateRolloutLabels:      rolloutMeta.Labels,
+		common.TemplateRolloutAnnotations: rolloutMeta.Annotations,
+		common.TemplateUpgradeState:       string(upgradeState),
+		common.TemplateValues:             values,
+	}
+	// children are always named "<rolloutname>-<number>" by Numaplane
+	if index, err := GetChildIndex(childName); err == nil {
+		args[common.TemplateChildIndex] = index
+	}
+	return args, nil
+}
+
+// GetTemplatedUpgradeState returns the upgrade state which the child's definition was templated with
+// (or its current upgrade state, if it was created before this was recorded)
+func GetTemplatedUpgradeState(child *unstructured.Unstructured) common.UpgradeState {
+	if upgradeState, found := child.GetAnnotations()[common.AnnotationKeyTemplatedUpgradeState]; found {
+		return common.UpgradeState(upgradeState)
+	}
+	return common.UpgradeState(child.GetLabels()[common.LabelKeyUpgradeState])
+}
+
+// SetTemplatedUpgradeState records the upgrade state which the child's definition was templated with
+func SetTemplatedUpgradeState(child *unstructured.Unstructured, upgradeState common.UpgradeState) {
+	annotations := child.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[common.AnnotationKeyTemplatedUpgradeState] = string(upgradeState)
+	child.SetAnnotations(annotations)
+}
+
+// ResolveTemplatedUpgradeState returns the upgrade state to template the definition of the named child with:
+// if the child already exists, it's the one which its definition was templated with, so that the definition doesn't change when
+// the child is promoted; otherwise, it's the upgrade state which the child is being created in
+func ResolveTemplatedUpgradeState(ctx context.Context, c client.Client, rolloutObject RolloutObject, childName string, upgradeState common.UpgradeState) (common.UpgradeState, error) {
+	existingChild, err := kubernetes.GetResource(ctx, c, rolloutObject.GetChildGVK(),
+		k8stypes.NamespacedName{Namespace: rolloutObject.GetRolloutObjectMeta().GetNamespace(), Name: childName})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return upgradeState, nil
+		}
+		return "", err
+	}
+	if templatedUpgradeState := GetTemplatedUpgradeState(existingChild); templatedUpgradeState != "" {
+		return templatedUpgradeState, nil
+	}
+	return upgradeState, nil
+}
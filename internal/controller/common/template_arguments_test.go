@@ -0,0 +1,66 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func TestGetCommonTemplateArguments(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: "default"}, Data: map[string]string{"region": "us-west-2"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+
+	rollout := &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: "default",
+		Labels: map[string]string{"team": "a"}, Annotations: map[string]string{"owner": "b"}},
+		Spec: apiv1.PipelineRolloutSpec{ValuesFrom: []apiv1.ValuesFromSource{{ConfigMapRef: &apiv1.ValuesFromReference{Name: "env"}}}}}
+
+	args, err := GetCommonTemplateArguments(ctx, c, rollout, "my-pipeline-3", common.LabelValueUpgradeInProgress)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		common.TemplateRolloutLabels:      map[string]string{"team": "a"},
+		common.TemplateRolloutAnnotations: map[string]string{"owner": "b"},
+		common.TemplateChildIndex:         3,
+		common.TemplateUpgradeState:       "in-progress",
+		common.TemplateValues:             map[string]string{"region": "us-west-2"},
+	}, args)
+}
+
+func TestResolveTemplatedUpgradeState(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, numaflowv1.AddToScheme(scheme))
+
+	// the child was created "in-progress" and has since been promoted
+	pipeline := &numaflowv1.Pipeline{ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline-0", Namespace: "default",
+		Labels:      map[string]string{common.LabelKeyUpgradeState: string(common.LabelValueUpgradePromoted)},
+		Annotations: map[string]string{common.AnnotationKeyTemplatedUpgradeState: string(common.LabelValueUpgradeInProgress)}}}
+	// this child was created before the templated upgrade state was recorded
+	oldPipeline := &numaflowv1.Pipeline{ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline-1", Namespace: "default",
+		Labels: map[string]string{common.LabelKeyUpgradeState: string(common.LabelValueUpgradePromoted)}}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pipeline, oldPipeline).Build()
+	rollout := &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: "default"}}
+
+	upgradeState, err := ResolveTemplatedUpgradeState(ctx, c, rollout, "my-pipeline-0", common.LabelValueUpgradePromoted)
+	assert.NoError(t, err)
+	assert.Equal(t, common.LabelValueUpgradeInProgress, upgradeState)
+
+	upgradeState, err = ResolveTemplatedUpgradeState(ctx, c, rollout, "my-pipeline-1", common.LabelValueUpgradeInProgress)
+	assert.NoError(t, err)
+	assert.Equal(t, common.LabelValueUpgradePromoted, upgradeState)
+
+	upgradeState, err = ResolveTemplatedUpgradeState(ctx, c, rollout, "my-pipeline-2", common.LabelValueUpgradeInProgress)
+	assert.NoError(t, err)
+	assert.Equal(t, common.LabelValueUpgradeInProgress, upgradeState)
+}
@@ -0,0 +1,638 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package templates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/numaproj/numaplane/internal/common"
+	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
+	"github.com/numaproj/numaplane/internal/controller/common/revisions"
+	"github.com/numaproj/numaplane/internal/util"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// TemplateObject is a template which defines the child of the Rollouts referencing it
+type TemplateObject interface {
+	client.Object
+
+	GetTemplateSpec() *apiv1.TemplateSpec
+}
+
+// TemplatedRolloutObject is a Rollout whose child can be defined by a template
+type TemplatedRolloutObject interface {
+	revisions.RevisionRolloutObject
+
+	GetTemplateRef() *apiv1.TemplateReference
+	GetTemplateStatus() *apiv1.TemplateStatus
+	SetTemplateStatus(status *apiv1.TemplateStatus)
+}
+
+// a template parameter referenced as "{{.params.<name>}}" (allowing for whitespace inside the braces)
+var parameterRegex = regexp.MustCompile(`\{\{\s*\.params\.([a-zA-Z_][a-zA-Z0-9_]*)\s*\}\}`)
+
+// a string which consists of nothing but a reference to a template parameter
+var wholeParameterRegex = regexp.MustCompile(`^` + parameterRegex.String() + `$`)
+
+// a template action, which can reference a parameter as one of its arguments, e.g. "{{ .params.image | upper }}"
+var templateActionRegex = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+
+// a reference to a template parameter inside an action
+var parameterReferenceRegex = regexp.MustCompile(`\.params\.([a-zA-Z_][a-zA-Z0-9_]*)`)
+
+// NewTemplateForRollout returns an empty template of the kind which the Rollout can reference
+func NewTemplateForRollout(rolloutObject TemplatedRolloutObject) (TemplateObject, error) {
+	switch rolloutObject.(type) {
+	case *apiv1.PipelineRollout:
+		return &apiv1.PipelineTemplate{}, nil
+	case *apiv1.MonoVertexRollout:
+		return &apiv1.MonoVertexTemplate{}, nil
+	default:
+		return nil, fmt.Errorf("templates are not supported for rollout type %T", rolloutObject)
+	}
+}
+
+// return the templated child metadata and spec of the template
+func templatedChild(template TemplateObject) (apiv1.Metadata, runtime.RawExtension, error) {
+	switch typedTemplate := template.(type) {
+	case *apiv1.PipelineTemplate:
+		return typedTemplate.Spec.Pipeline.Metadata, typedTemplate.Spec.Pipeline.Spec, nil
+	case *apiv1.MonoVertexTemplate:
+		return typedTemplate.Spec.MonoVertex.Metadata, typedTemplate.Spec.MonoVertex.Spec, nil
+	default:
+		return apiv1.Metadata{}, runtime.RawExtension{}, fmt.Errorf("unsupported template type %T", template)
+	}
+}
+
+// set the child metadata and spec of the in-memory Rollout
+func setChildDefinition(rolloutObject TemplatedRolloutObject, metadata apiv1.Metadata, spec runtime.RawExtension) error {
+	switch typedRollout := rolloutObject.(type) {
+	case *apiv1.PipelineRollout:
+		typedRollout.Spec.Pipeline = apiv1.Pipeline{Metadata: metadata, Spec: spec}
+	case *apiv1.MonoVertexRollout:
+		typedRollout.Spec.MonoVertex = apiv1.MonoVertex{Metadata: metadata, Spec: spec}
+	default:
+		return fmt.Errorf("templates are not supported for rollout type %T", rolloutObject)
+	}
+	return nil
+}
+
+// return the child metadata of the Rollout, which is added to the metadata of the template
+func childMetadata(rolloutObject TemplatedRolloutObject) apiv1.Metadata {
+	switch typedRollout := rolloutObject.(type) {
+	case *apiv1.PipelineRollout:
+		return typedRollout.Spec.Pipeline.Metadata
+	case *apiv1.MonoVertexRollout:
+		return typedRollout.Spec.MonoVertex.Metadata
+	default:
+		return apiv1.Metadata{}
+	}
+}
+
+// ListReferencingRollouts returns the Rollouts in the template's namespace which reference the template
+func ListReferencingRollouts(ctx context.Context, c client.Client, template TemplateObject) ([]TemplatedRolloutObject, error) {
+	rolloutObjects := []TemplatedRolloutObject{}
+	switch template.(type) {
+	case *apiv1.PipelineTemplate:
+		list := &apiv1.PipelineRolloutList{}
+		if err := c.List(ctx, list, client.InNamespace(template.GetNamespace())); err != nil {
+			return nil, fmt.Errorf("failed to list PipelineRollouts: %w", err)
+		}
+		for i := range list.Items {
+			rolloutObjects = append(rolloutObjects, &list.Items[i])
+		}
+	case *apiv1.MonoVertexTemplate:
+		list := &apiv1.MonoVertexRolloutList{}
+		if err := c.List(ctx, list, client.InNamespace(template.GetNamespace())); err != nil {
+			return nil, fmt.Errorf("failed to list MonoVertexRollouts: %w", err)
+		}
+		for i := range list.Items {
+			rolloutObjects = append(rolloutObjects, &list.Items[i])
+		}
+	default:
+		return nil, fmt.Errorf("unsupported template type %T", template)
+	}
+
+	referencing := []TemplatedRolloutObject{}
+	for _, rolloutObject := range rolloutObjects {
+		if templateRef := rolloutObject.GetTemplateRef(); templateRef != nil && templateRef.Name == template.GetName() {
+			referencing = append(referencing, rolloutObject)
+		}
+	}
+	return referencing, nil
+}
+
+// ReferencingRolloutRequests returns the reconcile requests for the Rollouts which reference the template, so they're reconciled
+// when it changes
+func ReferencingRolloutRequests(ctx context.Context, c client.Client, template TemplateObject) []reconcile.Request {
+	referencing, err := ListReferencingRollouts(ctx, c, template)
+	if err != nil {
+		logger.FromContext(ctx).WithValues("template", fmt.Sprintf("%s/%s", template.GetNamespace(), template.GetName())).
+			Error(err, "failed to list the Rollouts referencing the template")
+		return nil
+	}
+	reqs := make([]reconcile.Request, 0, len(referencing))
+	for _, rolloutObject := range referencing {
+		rolloutMeta := rolloutObject.GetRolloutObjectMeta()
+		reqs = append(reqs, reconcile.Request{NamespacedName: k8stypes.NamespacedName{Namespace: rolloutMeta.GetNamespace(), Name: rolloutMeta.GetName()}})
+	}
+	return reqs
+}
+
+// GetParameterReferences returns the names of the parameters referenced by the template's child definition, sorted and without duplicates
+func GetParameterReferences(template TemplateObject) ([]string, error) {
+	metadata, spec, err := templatedChild(template)
+	if err != nil {
+		return nil, err
+	}
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	found := map[string]struct{}{}
+	for _, data := range [][]byte{metadataBytes, spec.Raw} {
+		for _, action := range templateActionRegex.FindAll(data, -1) {
+			for _, match := range parameterReferenceRegex.FindAllSubmatch(action, -1) {
+				found[string(match[1])] = struct{}{}
+			}
+		}
+	}
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// ParseParameterValue converts the string value of a parameter to its declared type
+func ParseParameterValue(parameter apiv1.TemplateParameter, value string) (interface{}, error) {
+	switch parameter.Type {
+	case apiv1.TemplateParameterTypeInteger:
+		return strconv.ParseInt(value, 10, 64)
+	case apiv1.TemplateParameterTypeNumber:
+		return strconv.ParseFloat(value, 64)
+	case apiv1.TemplateParameterTypeBoolean:
+		return strconv.ParseBool(value)
+	case apiv1.TemplateParameterTypeString, "":
+		return value, nil
+	default:
+		return nil, fmt.Errorf("unsupported parameter type %q", parameter.Type)
+	}
+}
+
+// get the typed value of each of the template's parameters, from the values supplied or else the defaults
+func parameterValues(templateSpec *apiv1.TemplateSpec, values map[string]string) (map[string]interface{}, error) {
+	declared := map[string]apiv1.TemplateParameter{}
+	for _, parameter := range templateSpec.Parameters {
+		declared[parameter.Name] = parameter
+	}
+	for name := range values {
+		if _, found := declared[name]; !found {
+			return nil, fmt.Errorf("value supplied for unknown parameter %q", name)
+		}
+	}
+
+	typedValues := map[string]interface{}{}
+	for _, parameter := range templateSpec.Parameters {
+		value, found := values[parameter.Name]
+		if !found {
+			if parameter.Default == nil {
+				return nil, fmt.Errorf("no value supplied for parameter %q, which has no default", parameter.Name)
+			}
+			value = *parameter.Default
+		}
+		typedValue, err := ParseParameterValue(parameter, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q for %s parameter %q: %w", value, parameter.Type, parameter.Name, err)
+		}
+		typedValues[parameter.Name] = typedValue
+	}
+	return typedValues, nil
+}
+
+// substitute the parameters in a string: if the string is nothing but a parameter reference, the parameter's typed value is returned
+func substituteParametersInString(value string, params map[string]interface{}) (interface{}, error) {
+	if match := wholeParameterRegex.FindStringSubmatch(value); match != nil {
+		typedValue, found := params[match[1]]
+		if !found {
+			return nil, fmt.Errorf("undeclared parameter %q", match[1])
+		}
+		return typedValue, nil
+	}
+
+	var err error
+	substituted := parameterRegex.ReplaceAllStringFunc(value, func(reference string) string {
+		name := parameterRegex.FindStringSubmatch(reference)[1]
+		typedValue, found := params[name]
+		if !found {
+			err = fmt.Errorf("undeclared parameter %q", name)
+			return reference
+		}
+		return fmt.Sprint(typedValue)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// a parameter referenced as an argument of an action is substituted with a constant, which is evaluated along with the
+	// rest of the action when the child is created
+	substituted = templateActionRegex.ReplaceAllStringFunc(substituted, func(action string) string {
+		return parameterReferenceRegex.ReplaceAllStringFunc(action, func(reference string) string {
+			name := parameterReferenceRegex.FindStringSubmatch(reference)[1]
+			typedValue, found := params[name]
+			if !found {
+				err = fmt.Errorf("undeclared parameter %q", name)
+				return reference
+			}
+			if stringValue, isString := typedValue.(string); isString {
+				return strconv.Quote(stringValue)
+			}
+			return fmt.Sprint(typedValue)
+		})
+	})
+	return substituted, err
+}
+
+// substitute the parameters in every string value of the unmarshaled JSON (map keys are left as is)
+func substituteParameters(value interface{}, params map[string]interface{}) (interface{}, error) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, elem := range typedValue {
+			substituted, err := substituteParameters(elem, params)
+			if err != nil {
+				return nil, err
+			}
+			typedValue[key] = substituted
+		}
+		return typedValue, nil
+	case []interface{}:
+		for i, elem := range typedValue {
+			substituted, err := substituteParameters(elem, params)
+			if err != nil {
+				return nil, err
+			}
+			typedValue[i] = substituted
+		}
+		return typedValue, nil
+	case string:
+		return substituteParametersInString(typedValue, params)
+	default:
+		return value, nil
+	}
+}
+
+func substituteMetadataParameters(metadata map[string]string, params map[string]interface{}) (map[string]string, error) {
+	if metadata == nil {
+		return nil, nil
+	}
+	substituted := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		result, err := substituteParametersInString(value, params)
+		if err != nil {
+			return nil, err
+		}
+		substituted[key] = util.NormalizeTemplateVariables(fmt.Sprint(result))
+	}
+	return substituted, nil
+}
+
+func mergeMetadata(base, overrides map[string]string) map[string]string {
+	if len(base) == 0 && len(overrides) == 0 {
+		return nil
+	}
+	merged := map[string]string{}
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	return merged
+}
+
+/*
+ResolveTemplate substitutes the parameter values into the template's child definition. Other template variables, such as
+{{.pipeline-name}}, are normalized and left to be resolved when the child is created, just like in a Rollout's inline child definition.
+
+Parameters:
+  - template: the template
+  - values: the values of the template's parameters: parameters without a value use their default
+  - metadata: the child metadata from the Rollout, which is added to the template's (taking precedence)
+
+Returns:
+  - The resolved child metadata and spec
+  - An error if a value is missing or invalid, or if the template references an undeclared parameter
+*/
+func ResolveTemplate(template TemplateObject, values map[string]string, metadata apiv1.Metadata) (apiv1.Metadata, runtime.RawExtension, error) {
+	templateMetadata, templateSpec, err := templatedChild(template)
+	if err != nil {
+		return apiv1.Metadata{}, runtime.RawExtension{}, err
+	}
+	params, err := parameterValues(template.GetTemplateSpec(), values)
+	if err != nil {
+		return apiv1.Metadata{}, runtime.RawExtension{}, err
+	}
+
+	labels, err := substituteMetadataParameters(templateMetadata.Labels, params)
+	if err != nil {
+		return apiv1.Metadata{}, runtime.RawExtension{}, fmt.Errorf("failed to resolve labels: %w", err)
+	}
+	annotations, err := substituteMetadataParameters(templateMetadata.Annotations, params)
+	if err != nil {
+		return apiv1.Metadata{}, runtime.RawExtension{}, fmt.Errorf("failed to resolve annotations: %w", err)
+	}
+	resolvedMetadata := apiv1.Metadata{
+		Labels:      mergeMetadata(labels, metadata.Labels),
+		Annotations: mergeMetadata(annotations, metadata.Annotations),
+	}
+
+	var spec interface{}
+	if err := json.Unmarshal(templateSpec.Raw, &spec); err != nil {
+		return apiv1.Metadata{}, runtime.RawExtension{}, fmt.Errorf("failed to unmarshal template spec: %w", err)
+	}
+	spec, err = substituteParameters(spec, params)
+	if err != nil {
+		return apiv1.Metadata{}, runtime.RawExtension{}, fmt.Errorf("failed to resolve spec: %w", err)
+	}
+	specBytes, err := json.Marshal(spec)
+	if err != nil {
+		return apiv1.Metadata{}, runtime.RawExtension{}, err
+	}
+	return resolvedMetadata, runtime.RawExtension{Raw: []byte(util.NormalizeTemplateVariables(string(specBytes)))}, nil
+}
+
+// get the template referenced by the Rollout
+func getTemplate(ctx context.Context, c client.Client, rolloutObject TemplatedRolloutObject) (TemplateObject, error) {
+	template, err := NewTemplateForRollout(rolloutObject)
+	if err != nil {
+		return nil, err
+	}
+	key := k8stypes.NamespacedName{Namespace: rolloutObject.GetRolloutObjectMeta().GetNamespace(), Name: rolloutObject.GetTemplateRef().Name}
+	if err := c.Get(ctx, key, template); err != nil {
+		return nil, fmt.Errorf("failed to get template %s: %w", key, err)
+	}
+	return template, nil
+}
+
+// ResolveTemplateRef replaces the child definition of the in-memory Rollout with the one defined by the current generation of its
+// template, if it references one. This is for reading the child definition of a Rollout outside of its own reconciliation: it doesn't
+// take the template's MaxConcurrentUpgrades into account. Note that the Rollout spec must not be written back to the cluster after this.
+func ResolveTemplateRef(ctx context.Context, c client.Client, rolloutObject TemplatedRolloutObject) error {
+	templateRef := rolloutObject.GetTemplateRef()
+	if templateRef == nil {
+		return nil
+	}
+	template, err := getTemplate(ctx, c, rolloutObject)
+	if err != nil {
+		return err
+	}
+	metadata, spec, err := ResolveTemplate(template, templateRef.Values, childMetadata(rolloutObject))
+	if err != nil {
+		return fmt.Errorf("failed to resolve template %s: %w", templateRef.Name, err)
+	}
+	return setChildDefinition(rolloutObject, metadata, spec)
+}
+
+/*
+ApplyTemplate replaces the child definition of the in-memory Rollout with the one defined by its template, if it references one,
+and records the template generation in the Rollout's status.
+A newer template generation is only applied if fewer than the template's MaxConcurrentUpgrades Rollouts referencing it are being
+upgraded to it: otherwise the Rollout's child remains defined by its latest revision (or by its promoted child, if it has no revision)
+until it's the Rollout's turn.
+Note that the Rollout spec must not be written back to the cluster after this.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - c: the client used for interacting with the Kubernetes API.
+  - rolloutObject: the Rollout (must be a pointer to the Rollout type)
+
+Returns:
+  - A boolean indicating whether the Rollout is waiting to be upgraded to a newer template generation, in which case it should be requeued.
+  - An error if any issues occur during processing.
+*/
+func ApplyTemplate(ctx context.Context, c client.Client, rolloutObject TemplatedRolloutObject) (bool, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	templateRef := rolloutObject.GetTemplateRef()
+	if templateRef == nil {
+		rolloutObject.SetTemplateStatus(nil)
+		return false, nil
+	}
+	template, err := getTemplate(ctx, c, rolloutObject)
+	if err != nil {
+		return false, err
+	}
+	generation := template.GetGeneration()
+
+	// a new template reference is applied right away, as is the generation which is already applied
+	templateStatus := rolloutObject.GetTemplateStatus()
+	upgrade := templateStatus == nil || templateStatus.Name != templateRef.Name || templateStatus.AppliedGeneration == 0 ||
+		templateStatus.AppliedGeneration == generation
+	if !upgrade {
+		upgrade, err = upgradeSlots.acquire(ctx, c, rolloutObject, template)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if !upgrade {
+		applied, err := applyLatestRevision(ctx, c, rolloutObject)
+		if err != nil {
+			return false, err
+		}
+		// without a revision (e.g. if RevisionHistoryLimit is 0), the current child definition is the promoted child's
+		if !applied {
+			if applied, err = applyPromotedChild(ctx, c, rolloutObject); err != nil {
+				return false, err
+			}
+		}
+		if applied {
+			numaLogger.WithValues("template", templateRef.Name, "appliedGeneration", templateStatus.AppliedGeneration, "pendingGeneration", generation).
+				Debug("waiting to upgrade to the new template generation since MaxConcurrentUpgrades has been reached")
+			templateStatus = templateStatus.DeepCopy()
+			templateStatus.PendingGeneration = generation
+			rolloutObject.SetTemplateStatus(templateStatus)
+			return true, nil
+		}
+		numaLogger.WithValues("template", templateRef.Name).Debug("no child to keep the current definition of, so deploying the new template generation")
+	}
+
+	metadata, spec, err := ResolveTemplate(template, templateRef.Values, childMetadata(rolloutObject))
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve template %s: %w", templateRef.Name, err)
+	}
+	if err := setChildDefinition(rolloutObject, metadata, spec); err != nil {
+		return false, err
+	}
+	rolloutObject.SetTemplateStatus(&apiv1.TemplateStatus{Name: templateRef.Name, AppliedGeneration: generation})
+	return false, nil
+}
+
+// replace the child definition of the in-memory Rollout with the one recorded in its latest revision, returning false if there isn't one
+func applyLatestRevision(ctx context.Context, c client.Client, rolloutObject TemplatedRolloutObject) (bool, error) {
+	controllerRevisions, err := revisions.ListRevisions(ctx, c, rolloutObject)
+	if err != nil {
+		return false, err
+	}
+	if len(controllerRevisions) == 0 {
+		return false, nil
+	}
+	revision, err := revisions.DecodeRevision(&controllerRevisions[len(controllerRevisions)-1])
+	if err != nil {
+		return false, err
+	}
+
+	childDefinition := struct {
+		Metadata apiv1.Metadata       `json:"metadata,omitempty"`
+		Spec     runtime.RawExtension `json:"spec"`
+	}{}
+	definitionBytes, err := json.Marshal(revision.ChildDefinition)
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(definitionBytes, &childDefinition); err != nil {
+		return false, fmt.Errorf("failed to decode the child definition of the latest revision: %w", err)
+	}
+	return true, setChildDefinition(rolloutObject, childDefinition.Metadata, childDefinition.Spec)
+}
+
+// replace the child definition of the in-memory Rollout with the definition of its promoted child, returning false if there isn't one
+func applyPromotedChild(ctx context.Context, c client.Client, rolloutObject TemplatedRolloutObject) (bool, error) {
+	child, err := ctlrcommon.FindMostCurrentChildOfUpgradeState(ctx, rolloutObject, common.LabelValueUpgradePromoted, nil, false, c)
+	if err != nil {
+		return false, err
+	}
+	if child == nil {
+		return false, nil
+	}
+	spec, found := child.Object["spec"]
+	if !found {
+		return false, nil
+	}
+	specBytes, err := json.Marshal(spec)
+	if err != nil {
+		return false, err
+	}
+	// the labels and annotations which Numaplane sets on the child aren't part of its definition
+	metadata := apiv1.Metadata{Labels: withoutNumaplaneKeys(child.GetLabels()), Annotations: withoutNumaplaneKeys(child.GetAnnotations())}
+	return true, setChildDefinition(rolloutObject, metadata, runtime.RawExtension{Raw: specBytes})
+}
+
+func withoutNumaplaneKeys(m map[string]string) map[string]string {
+	var filtered map[string]string
+	for key, value := range m {
+		if strings.HasPrefix(key, common.KeyNumaplanePrefix) {
+			continue
+		}
+		if filtered == nil {
+			filtered = map[string]string{}
+		}
+		filtered[key] = value
+	}
+	return filtered
+}
+
+// isUpgrading determines if the Rollout is still being upgraded to the template generation it's been granted.
+// A failed Rollout isn't: it may not recover without the user's intervention, and it mustn't hold back the others indefinitely.
+func isUpgrading(rolloutObject TemplatedRolloutObject) bool {
+	status := rolloutObject.GetRolloutStatus()
+	if status.Phase == apiv1.PhaseFailed {
+		return false
+	}
+	return status.Phase != apiv1.PhaseDeployed || status.UpgradeInProgress != ""
+}
+
+// slotTracker tracks which Rollouts have been allowed to upgrade to the latest generation of each template. The Rollouts' statuses
+// record this too, but they're only updated at the end of a reconciliation, and the Rollouts are reconciled concurrently.
+type slotTracker struct {
+	lock sync.Mutex
+	// key is "<template kind>/<namespace>/<template name>"
+	granted map[string]*generationSlots
+}
+
+// generationSlots holds the names of the Rollouts which have been allowed to upgrade to a template generation
+type generationSlots struct {
+	generation int64
+	rollouts   map[string]struct{}
+}
+
+var upgradeSlots = &slotTracker{granted: map[string]*generationSlots{}}
+
+// acquire determines if the Rollout can upgrade to the template's generation without exceeding its MaxConcurrentUpgrades
+func (tracker *slotTracker) acquire(ctx context.Context, c client.Client, rolloutObject TemplatedRolloutObject, template TemplateObject) (bool, error) {
+	maxConcurrentUpgrades := template.GetTemplateSpec().MaxConcurrentUpgrades
+	if maxConcurrentUpgrades == nil {
+		return true, nil
+	}
+
+	referencing, err := ListReferencingRollouts(ctx, c, template)
+	if err != nil {
+		return false, err
+	}
+
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	generation := template.GetGeneration()
+	key := fmt.Sprintf("%T/%s/%s", template, template.GetNamespace(), template.GetName())
+	slots := tracker.granted[key]
+	if slots == nil || slots.generation != generation {
+		// slots for older generations are no longer needed
+		slots = &generationSlots{generation: generation, rollouts: map[string]struct{}{}}
+		tracker.granted[key] = slots
+	}
+
+	rolloutName := rolloutObject.GetRolloutObjectMeta().GetName()
+	if _, found := slots.rollouts[rolloutName]; found {
+		return true, nil
+	}
+
+	// a Rollout which has finished upgrading to the generation releases its slot
+	for _, referencingRollout := range referencing {
+		status := referencingRollout.GetTemplateStatus()
+		if status == nil || status.AppliedGeneration != generation {
+			continue
+		}
+		name := referencingRollout.GetRolloutObjectMeta().GetName()
+		if isUpgrading(referencingRollout) {
+			slots.rollouts[name] = struct{}{}
+		} else {
+			delete(slots.rollouts, name)
+		}
+	}
+
+	if int32(len(slots.rollouts)) >= *maxConcurrentUpgrades {
+		return false, nil
+	}
+	slots.rollouts[rolloutName] = struct{}{}
+	return true, nil
+}
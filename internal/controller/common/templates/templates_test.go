@@ -0,0 +1,235 @@
+package templates
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/controller/common/revisions"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+const testNamespace = "test-namespace"
+
+func newMonoVertexTemplate(generation int64, maxConcurrentUpgrades *int32) *apiv1.MonoVertexTemplate {
+	defaultImage := "my-source:v1"
+	return &apiv1.MonoVertexTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-template", Namespace: testNamespace, Generation: generation},
+		Spec: apiv1.MonoVertexTemplateSpec{
+			TemplateSpec: apiv1.TemplateSpec{
+				Parameters: []apiv1.TemplateParameter{
+					{Name: "image", Default: &defaultImage},
+					{Name: "maxReplicas", Type: apiv1.TemplateParameterTypeInteger},
+				},
+				MaxConcurrentUpgrades: maxConcurrentUpgrades,
+			},
+			MonoVertex: apiv1.MonoVertex{
+				Metadata: apiv1.Metadata{Labels: map[string]string{"image": "{{.params.image}}", "team": "a"}},
+				Spec: runtime.RawExtension{Raw: []byte(`{"scale":{"max":"{{ .params.maxReplicas }}"},` +
+					`"source":{"udsource":{"container":{"image":"{{.params.image}}","args":["{{ .monovertex-name }}","{{.params.maxReplicas}}x"]}}}}`)},
+			},
+		},
+	}
+}
+
+func newMonoVertexRollout(name string, values map[string]string, templateStatus *apiv1.TemplateStatus) *apiv1.MonoVertexRollout {
+	monoVertexRollout := &apiv1.MonoVertexRollout{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, UID: k8stypes.UID(name)},
+		Spec: apiv1.MonoVertexRolloutSpec{
+			MonoVertex:  apiv1.MonoVertex{Metadata: apiv1.Metadata{Labels: map[string]string{"team": "b"}}},
+			TemplateRef: &apiv1.TemplateReference{Name: "my-template", Values: values},
+		},
+		Status: apiv1.MonoVertexRolloutStatus{Template: templateStatus},
+	}
+	monoVertexRollout.Status.Phase = apiv1.PhaseDeployed
+	return monoVertexRollout
+}
+
+func newFakeClient(t *testing.T, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, apiv1.AddToScheme(scheme))
+	assert.NoError(t, appsv1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func Test_ResolveTemplate(t *testing.T) {
+	template := newMonoVertexTemplate(1, nil)
+
+	metadata, spec, err := ResolveTemplate(template, map[string]string{"maxReplicas": "5"}, apiv1.Metadata{Labels: map[string]string{"team": "b"}})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"image": "my-source:v1", "team": "b"}, metadata.Labels)
+	// a parameter which makes up an entire field is substituted with its type, while other template variables are normalized and kept
+	assert.JSONEq(t, `{"scale":{"max":5},"source":{"udsource":{"container":{"image":"my-source:v1","args":["{{.monovertex-name}}","5x"]}}}}`,
+		string(spec.Raw))
+
+	// a parameter which is an argument of an action is substituted with a constant, leaving the action to be evaluated with the child
+	withActions := template.DeepCopy()
+	withActions.Spec.MonoVertex.Spec = runtime.RawExtension{Raw: []byte(`{"scale":{"max":"{{ add .params.maxReplicas 1 }}"},` +
+		`"source":{"udsource":{"container":{"image":"{{ .params.image | replace \"v1\" .upgrade-state }}"}}}}`)}
+	_, spec, err = ResolveTemplate(withActions, map[string]string{"maxReplicas": "5"}, apiv1.Metadata{})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"scale":{"max":"{{ add 5 1 }}"},"source":{"udsource":{"container":{"image":"{{ \"my-source:v1\" | replace \"v1\" .upgrade-state }}"}}}}`,
+		string(spec.Raw))
+	references, err := GetParameterReferences(withActions)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"image", "maxReplicas"}, references)
+
+	_, _, err = ResolveTemplate(template, map[string]string{}, apiv1.Metadata{})
+	assert.ErrorContains(t, err, `no value supplied for parameter "maxReplicas"`)
+
+	_, _, err = ResolveTemplate(template, map[string]string{"maxReplicas": "five"}, apiv1.Metadata{})
+	assert.ErrorContains(t, err, `invalid value "five" for integer parameter "maxReplicas"`)
+
+	_, _, err = ResolveTemplate(template, map[string]string{"maxReplicas": "5", "minReplicas": "1"}, apiv1.Metadata{})
+	assert.ErrorContains(t, err, `unknown parameter "minReplicas"`)
+
+	template.Spec.MonoVertex.Spec = runtime.RawExtension{Raw: []byte(`{"source":{"udsource":{"container":{"image":"{{.params.tag}}"}}}}`)}
+	_, _, err = ResolveTemplate(template, map[string]string{"maxReplicas": "5"}, apiv1.Metadata{})
+	assert.ErrorContains(t, err, `undeclared parameter "tag"`)
+
+	references, err = GetParameterReferences(template)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"image", "tag"}, references)
+}
+
+func Test_ApplyTemplate(t *testing.T) {
+	ctx := context.Background()
+	maxConcurrentUpgrades := int32(1)
+	values := map[string]string{"maxReplicas": "5"}
+
+	// the rollouts are all using generation 1 of the template, which has since been updated to generation 2
+	template := newMonoVertexTemplate(2, &maxConcurrentUpgrades)
+	template.Spec.Parameters[0].Default = nil
+	rollout1 := newMonoVertexRollout("rollout-1", map[string]string{"maxReplicas": "5", "image": "my-source:v2"},
+		&apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 1})
+	rollout2 := newMonoVertexRollout("rollout-2", map[string]string{"maxReplicas": "5", "image": "my-source:v2"},
+		&apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 1})
+	newRollout := newMonoVertexRollout("rollout-3", map[string]string{"maxReplicas": "5", "image": "my-source:v2"}, nil)
+	fakeClient := newFakeClient(t, template, rollout1, rollout2, newRollout)
+
+	// rollout-2 has a revision recorded for generation 1
+	revisionRollout := rollout2.DeepCopy()
+	oldTemplate := newMonoVertexTemplate(1, nil)
+	revisionRollout.Spec.MonoVertex.Metadata, revisionRollout.Spec.MonoVertex.Spec, _ = ResolveTemplate(oldTemplate, values, revisionRollout.Spec.MonoVertex.Metadata)
+	assert.NoError(t, revisions.RecordRevision(ctx, fakeClient, revisionRollout, "rollout-2-0", revisions.OutcomePromoted))
+
+	// a Rollout which isn't using the template yet isn't held back
+	waiting, err := ApplyTemplate(ctx, fakeClient, newRollout)
+	assert.NoError(t, err)
+	assert.False(t, waiting)
+	assert.Equal(t, &apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 2}, newRollout.Status.Template)
+
+	// rollout-1 takes the only upgrade slot
+	waiting, err = ApplyTemplate(ctx, fakeClient, rollout1)
+	assert.NoError(t, err)
+	assert.False(t, waiting)
+	assert.Equal(t, int64(2), rollout1.Status.Template.AppliedGeneration)
+	assert.Contains(t, string(rollout1.Spec.MonoVertex.Spec.Raw), "my-source:v2")
+
+	// so rollout-2 keeps the child definition of its latest revision
+	waiting, err = ApplyTemplate(ctx, fakeClient, rollout2)
+	assert.NoError(t, err)
+	assert.True(t, waiting)
+	assert.Equal(t, &apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 1, PendingGeneration: 2}, rollout2.Status.Template)
+	assert.Equal(t, revisionRollout.Spec.MonoVertex.Metadata, rollout2.Spec.MonoVertex.Metadata)
+	assert.JSONEq(t, string(revisionRollout.Spec.MonoVertex.Spec.Raw), string(rollout2.Spec.MonoVertex.Spec.Raw))
+
+	// once rollout-1 has finished upgrading, rollout-2 can be upgraded
+	upgradedRollout1 := &apiv1.MonoVertexRollout{}
+	assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(rollout1), upgradedRollout1))
+	upgradedRollout1.Status.Template = &apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 2}
+	assert.NoError(t, fakeClient.Update(ctx, upgradedRollout1))
+	rollout2 = newMonoVertexRollout("rollout-2", map[string]string{"maxReplicas": "5", "image": "my-source:v2"},
+		&apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 1})
+	waiting, err = ApplyTemplate(ctx, fakeClient, rollout2)
+	assert.NoError(t, err)
+	assert.False(t, waiting)
+	assert.Equal(t, &apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 2}, rollout2.Status.Template)
+}
+
+func Test_ApplyTemplate_withoutRevision(t *testing.T) {
+	ctx := context.Background()
+	maxConcurrentUpgrades := int32(1)
+
+	template := newMonoVertexTemplate(2, &maxConcurrentUpgrades)
+	rollout1 := newMonoVertexRollout("rollout-1", map[string]string{"maxReplicas": "5"}, &apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 1})
+	rollout1.Status.Phase = apiv1.PhasePending
+	rollout2 := newMonoVertexRollout("rollout-2", map[string]string{"maxReplicas": "5"}, &apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 1})
+	upgradeSlots.granted = map[string]*generationSlots{}
+
+	// rollout-2 has no revision, but has a promoted child
+	promotedChild := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "numaflow.numaproj.io/v1alpha1",
+		"kind":       "MonoVertex",
+		"metadata": map[string]any{
+			"name":      "rollout-2-0",
+			"namespace": testNamespace,
+			"labels": map[string]any{
+				common.LabelKeyParentRollout: "rollout-2",
+				common.LabelKeyUpgradeState:  string(common.LabelValueUpgradePromoted),
+				"team":                       "b",
+			},
+		},
+		"spec": map[string]any{"source": map[string]any{"udsource": map[string]any{"container": map[string]any{"image": "my-source:v1"}}}},
+	}}
+	fakeClient := newFakeClient(t, template, rollout1, rollout2, promotedChild)
+
+	// rollout-1 takes the only upgrade slot
+	waiting, err := ApplyTemplate(ctx, fakeClient, rollout1)
+	assert.NoError(t, err)
+	assert.False(t, waiting)
+	upgradingRollout1 := &apiv1.MonoVertexRollout{}
+	assert.NoError(t, fakeClient.Get(ctx, client.ObjectKeyFromObject(rollout1), upgradingRollout1))
+	upgradingRollout1.Status = rollout1.Status
+	assert.NoError(t, fakeClient.Update(ctx, upgradingRollout1))
+
+	// so rollout-2 is held back with the definition of its promoted child, since it has no revision
+	waiting, err = ApplyTemplate(ctx, fakeClient, rollout2)
+	assert.NoError(t, err)
+	assert.True(t, waiting)
+	assert.Equal(t, &apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 1, PendingGeneration: 2}, rollout2.Status.Template)
+	assert.Equal(t, map[string]string{"team": "b"}, rollout2.Spec.MonoVertex.Metadata.Labels)
+	assert.JSONEq(t, `{"source":{"udsource":{"container":{"image":"my-source:v1"}}}}`, string(rollout2.Spec.MonoVertex.Spec.Raw))
+
+	// once rollout-1 has failed, it no longer holds its slot, so rollout-2 can be upgraded
+	upgradingRollout1.Status.Phase = apiv1.PhaseFailed
+	assert.NoError(t, fakeClient.Update(ctx, upgradingRollout1))
+	rollout2 = newMonoVertexRollout("rollout-2", map[string]string{"maxReplicas": "5"}, &apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 1})
+	upgradeSlots.granted = map[string]*generationSlots{}
+	waiting, err = ApplyTemplate(ctx, fakeClient, rollout2)
+	assert.NoError(t, err)
+	assert.False(t, waiting)
+	assert.Equal(t, &apiv1.TemplateStatus{Name: "my-template", AppliedGeneration: 2}, rollout2.Status.Template)
+}
+
+func Test_isUpgrading(t *testing.T) {
+	testCases := []struct {
+		name              string
+		phase             apiv1.Phase
+		upgradeInProgress apiv1.UpgradeStrategy
+		expected          bool
+	}{
+		{name: "deployed", phase: apiv1.PhaseDeployed, expected: false},
+		{name: "deployed with an upgrade in progress", phase: apiv1.PhaseDeployed, upgradeInProgress: apiv1.UpgradeStrategyProgressive, expected: true},
+		{name: "pending", phase: apiv1.PhasePending, expected: true},
+		{name: "failed", phase: apiv1.PhaseFailed, expected: false},
+		{name: "failed with an upgrade in progress", phase: apiv1.PhaseFailed, upgradeInProgress: apiv1.UpgradeStrategyProgressive, expected: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rollout := newMonoVertexRollout("rollout", nil, nil)
+			rollout.Status.Phase = tc.phase
+			rollout.Status.UpgradeInProgress = tc.upgradeInProgress
+			assert.Equal(t, tc.expected, isUpgrading(rollout))
+		})
+	}
+}
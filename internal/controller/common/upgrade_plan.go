@@ -0,0 +1,257 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/usde"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// UpgradePlanApprovalRequired determines if the Rollout requires its upgrade plan to be approved before an upgrade is started,
+// either because its spec requires it or because it's annotated to require it
+func UpgradePlanApprovalRequired(rolloutObject RolloutObject, requiredBySpec bool) bool {
+	if requiredBySpec {
+		return true
+	}
+	required, _ := strconv.ParseBool(rolloutObject.GetRolloutObjectMeta().GetAnnotations()[common.AnnotationKeyRequirePlanApproval])
+	return required
+}
+
+/*
+NewUpgradePlan describes how the existing child will be upgraded to the new definition.
+
+Parameters:
+  - newDef: the new definition of the child.
+  - existingDef: the existing definition of the child.
+  - upgradeStrategy: the upgrade strategy which will be used, as determined by the USDE.
+  - recreate: whether the child will be deleted and recreated.
+  - riderAdditions, riderModifications, riderDeletions: the Riders which will be added, modified, and deleted.
+  - nextChildName: the name of the next child which would be created, for the Progressive strategy.
+  - pipelinesToPause: the Pipelines which will be paused, for the PPND strategy.
+
+Returns:
+  - the UpgradePlan
+  - An error if any issues occur during processing.
+*/
+func NewUpgradePlan(
+	rolloutObject RolloutObject,
+	newDef, existingDef *unstructured.Unstructured,
+	upgradeStrategy apiv1.UpgradeStrategy,
+	recreate bool,
+	riderAdditions, riderModifications, riderDeletions unstructured.UnstructuredList,
+	nextChildName string,
+	pipelinesToPause []string,
+) (*apiv1.UpgradePlan, error) {
+	recreateFields, dataLossFields, progressiveFields, err := usde.ChangedSpecFields(newDef, existingDef)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &apiv1.UpgradePlan{
+		Generation:         rolloutObject.GetRolloutObjectMeta().Generation,
+		Strategy:           upgradeStrategy,
+		Recreate:           recreate,
+		RecreateFields:     recreateFields,
+		DataLossFields:     dataLossFields,
+		ProgressiveFields:  progressiveFields,
+		RiderAdditions:     getKindsAndNames(riderAdditions),
+		RiderModifications: getKindsAndNames(riderModifications),
+		RiderDeletions:     getKindsAndNames(riderDeletions),
+		PipelinesToPause:   pipelinesToPause,
+		ComputedTime:       metav1.NewTime(time.Now()),
+	}
+
+	existingChild := fmt.Sprintf("%s/%s", existingDef.GetKind(), existingDef.GetName())
+	switch {
+	case upgradeStrategy == apiv1.UpgradeStrategyProgressive:
+		// a new child is created and, if it succeeds, the existing one is recycled
+		plan.ChildrenToCreate = []string{fmt.Sprintf("%s/%s", existingDef.GetKind(), nextChildName)}
+		plan.ChildrenToRecycle = []string{existingChild}
+	case recreate:
+		plan.ChildrenToRecycle = []string{existingChild}
+		plan.ChildrenToCreate = []string{existingChild}
+	default:
+		plan.ChildrenToUpdate = []string{existingChild}
+	}
+
+	return plan, nil
+}
+
+// definitionHashLength is the number of hex characters of the sha256 hash kept in the DefinitionHash of an UpgradePlan
+const definitionHashLength = 16
+
+/*
+UpgradeDefinitionHash identifies the computed definition of the child and the Riders to change, which an UpgradePlan is made for.
+Only the fields which describe the desired state are included, so that the hash doesn't change as the existing objects are updated.
+
+Parameters:
+  - newDef: the new definition of the child.
+  - riderAdditions, riderModifications, riderDeletions: the Riders which will be added, modified, and deleted.
+
+Returns:
+  - the hash
+  - An error if any issues occur during processing.
+*/
+func UpgradeDefinitionHash(newDef *unstructured.Unstructured, riderAdditions, riderModifications, riderDeletions unstructured.UnstructuredList) (string, error) {
+	definition := map[string]interface{}{
+		"spec":               newDef.Object["spec"],
+		"riderAdditions":     riderDefinitions(riderAdditions),
+		"riderModifications": riderDefinitions(riderModifications),
+		"riderDeletions":     getKindsAndNames(riderDeletions),
+	}
+	// the keys of maps are sorted when marshaled, so this is deterministic
+	asBytes, err := json.Marshal(definition)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.Sum256(asBytes)
+	return hex.EncodeToString(hash[:])[:definitionHashLength], nil
+}
+
+// riderDefinitions returns the desired state of each Rider: its kind and name, and its content other than metadata and status
+func riderDefinitions(ulist unstructured.UnstructuredList) []map[string]interface{} {
+	definitions := make([]map[string]interface{}, 0, len(ulist.Items))
+	for _, u := range ulist.Items {
+		definition := map[string]interface{}{"kind": u.GetKind(), "name": u.GetName()}
+		for key, value := range u.Object {
+			if key != "metadata" && key != "status" && key != "kind" {
+				definition[key] = value
+			}
+		}
+		definitions = append(definitions, definition)
+	}
+	return definitions
+}
+
+/*
+ProcessUpgradePlan publishes the UpgradePlan for the current definition of the child in the Status of the Rollout, if it hasn't
+been yet, and determines if it's been approved.
+The plan is approved by annotating the Rollout with "numaplane.numaproj.io/approve-plan" set to the DefinitionHash of the plan.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - rolloutObject: the rollout object.
+  - definitionHash: the UpgradeDefinitionHash of the new definition of the child and its Riders.
+  - makePlan: the function which computes the UpgradePlan for the new definition.
+  - recorder: the recorder used to emit Events for the plan.
+
+Returns:
+  - Whether the plan has been approved, so the upgrade can be started.
+  - An error if any issues occur during processing.
+*/
+func ProcessUpgradePlan(
+	ctx context.Context,
+	rolloutObject RolloutObject,
+	definitionHash string,
+	makePlan func() (*apiv1.UpgradePlan, error),
+	recorder record.EventRecorder,
+) (bool, error) {
+	numaLogger := logger.FromContext(ctx)
+	status := rolloutObject.GetRolloutStatus()
+	generation := rolloutObject.GetRolloutObjectMeta().Generation
+
+	// the plan is recomputed (and requires approval again) if the child definition changed, even without a new generation
+	plan := status.UpgradePlan
+	if plan == nil || plan.Generation != generation || plan.DefinitionHash != definitionHash {
+		var err error
+		plan, err = makePlan()
+		if err != nil {
+			return false, err
+		}
+		plan.DefinitionHash = definitionHash
+		status.UpgradePlan = plan
+		numaLogger.WithValues("plan", plan).Info("computed upgrade plan, which requires approval")
+		recordUpgradePlanEvent(rolloutObject, recorder, "UpgradePlanComputed", summarizeUpgradePlan(plan))
+	}
+
+	if plan.Approved {
+		return true, nil
+	}
+
+	annotations := rolloutObject.GetRolloutObjectMeta().GetAnnotations()
+	if annotations[common.AnnotationKeyApprovePlan] == definitionHash {
+		plan.Approved = true
+		plan.ApprovedBy = annotations[common.AnnotationKeyApprovedBy]
+		message := fmt.Sprintf("upgrade plan %s for generation %d approved", definitionHash, generation)
+		if plan.ApprovedBy != "" {
+			message = fmt.Sprintf("%s by %s", message, plan.ApprovedBy)
+		}
+		status.MarkUpgradePlanApproved(message, generation)
+		numaLogger.Info(message)
+		recordUpgradePlanEvent(rolloutObject, recorder, "UpgradePlanApproved", message)
+		return true, nil
+	}
+
+	status.MarkUpgradePlanPendingApproval(fmt.Sprintf("annotate with %s=%s to approve the upgrade plan", common.AnnotationKeyApprovePlan, definitionHash), generation)
+	return false, nil
+}
+
+// summarizeUpgradePlan describes the UpgradePlan in a single line
+func summarizeUpgradePlan(plan *apiv1.UpgradePlan) string {
+	summary := []string{fmt.Sprintf("upgrade plan %s for generation %d: strategy %s", plan.DefinitionHash, plan.Generation, plan.Strategy)}
+	if len(plan.PipelinesToPause) > 0 {
+		summary = append(summary, fmt.Sprintf("%d pipeline(s) to pause", len(plan.PipelinesToPause)))
+	}
+	if len(plan.ChildrenToCreate) > 0 {
+		summary = append(summary, fmt.Sprintf("create %s", strings.Join(plan.ChildrenToCreate, ",")))
+	}
+	if len(plan.ChildrenToUpdate) > 0 {
+		summary = append(summary, fmt.Sprintf("update %s", strings.Join(plan.ChildrenToUpdate, ",")))
+	}
+	if len(plan.ChildrenToRecycle) > 0 {
+		summary = append(summary, fmt.Sprintf("recycle %s", strings.Join(plan.ChildrenToRecycle, ",")))
+	}
+	riderChanges := len(plan.RiderAdditions) + len(plan.RiderModifications) + len(plan.RiderDeletions)
+	if riderChanges > 0 {
+		summary = append(summary, fmt.Sprintf("%d rider change(s)", riderChanges))
+	}
+	return strings.Join(summary, "; ")
+}
+
+func recordUpgradePlanEvent(rolloutObject RolloutObject, recorder record.EventRecorder, reason, message string) {
+	if eventObject, ok := rolloutObject.(runtime.Object); ok && recorder != nil {
+		recorder.Event(eventObject, corev1.EventTypeNormal, reason, message)
+	}
+}
+
+func getKindsAndNames(ulist unstructured.UnstructuredList) []string {
+	kindsAndNames := make([]string, 0, len(ulist.Items))
+	for _, u := range ulist.Items {
+		kindsAndNames = append(kindsAndNames, fmt.Sprintf("%s/%s", u.GetKind(), u.GetName()))
+	}
+	if len(kindsAndNames) == 0 {
+		return nil
+	}
+	return kindsAndNames
+}
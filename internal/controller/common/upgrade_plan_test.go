@@ -0,0 +1,141 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func TestUpgradePlanApprovalRequired(t *testing.T) {
+	rollout := &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: "default"}}
+	assert.False(t, UpgradePlanApprovalRequired(rollout, false))
+	assert.True(t, UpgradePlanApprovalRequired(rollout, true))
+
+	rollout.Annotations = map[string]string{common.AnnotationKeyRequirePlanApproval: "true"}
+	assert.True(t, UpgradePlanApprovalRequired(rollout, false))
+}
+
+func TestNewUpgradePlan(t *testing.T) {
+	rollout := &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: "default", Generation: 3}}
+	existingDef := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"lifecycle": map[string]interface{}{}}}}
+	existingDef.SetKind("Pipeline")
+	existingDef.SetName("my-pipeline-0")
+	newDef := existingDef.DeepCopy()
+
+	rider := unstructured.Unstructured{}
+	rider.SetKind("ConfigMap")
+	rider.SetName("my-configmap")
+
+	plan, err := NewUpgradePlan(rollout, newDef, existingDef, apiv1.UpgradeStrategyProgressive, false,
+		unstructured.UnstructuredList{Items: []unstructured.Unstructured{rider}}, unstructured.UnstructuredList{}, unstructured.UnstructuredList{}, "my-pipeline-1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), plan.Generation)
+	assert.Equal(t, []string{"Pipeline/my-pipeline-1"}, plan.ChildrenToCreate)
+	assert.Equal(t, []string{"Pipeline/my-pipeline-0"}, plan.ChildrenToRecycle)
+	assert.Equal(t, []string{"ConfigMap/my-configmap"}, plan.RiderAdditions)
+	assert.Nil(t, plan.RiderDeletions)
+
+	plan, err = NewUpgradePlan(rollout, newDef, existingDef, apiv1.UpgradeStrategyPPND, false,
+		unstructured.UnstructuredList{}, unstructured.UnstructuredList{}, unstructured.UnstructuredList{}, "my-pipeline-1", []string{"my-pipeline-0"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Pipeline/my-pipeline-0"}, plan.ChildrenToUpdate)
+	assert.Equal(t, []string{"my-pipeline-0"}, plan.PipelinesToPause)
+	assert.Nil(t, plan.ChildrenToCreate)
+}
+
+func TestUpgradeDefinitionHash(t *testing.T) {
+	newDef := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"version": "1.4.0"}}}
+	rider := unstructured.Unstructured{Object: map[string]interface{}{"data": map[string]interface{}{"key": "value"}}}
+	rider.SetKind("ConfigMap")
+	rider.SetName("my-configmap")
+	riders := unstructured.UnstructuredList{Items: []unstructured.Unstructured{rider}}
+
+	hash, err := UpgradeDefinitionHash(newDef, riders, unstructured.UnstructuredList{}, unstructured.UnstructuredList{})
+	assert.NoError(t, err)
+	assert.Len(t, hash, definitionHashLength)
+
+	// metadata which doesn't describe the desired state doesn't change the hash
+	newDef.SetResourceVersion("12345")
+	riders.Items[0].SetResourceVersion("678")
+	sameHash, err := UpgradeDefinitionHash(newDef, riders, unstructured.UnstructuredList{}, unstructured.UnstructuredList{})
+	assert.NoError(t, err)
+	assert.Equal(t, hash, sameHash)
+
+	// a change to the child or a Rider does
+	riders.Items[0].Object["data"] = map[string]interface{}{"key": "other-value"}
+	riderHash, err := UpgradeDefinitionHash(newDef, riders, unstructured.UnstructuredList{}, unstructured.UnstructuredList{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, riderHash)
+
+	newDef.Object["spec"] = map[string]interface{}{"version": "1.5.0"}
+	specHash, err := UpgradeDefinitionHash(newDef, riders, unstructured.UnstructuredList{}, unstructured.UnstructuredList{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, riderHash, specHash)
+}
+
+func TestProcessUpgradePlan(t *testing.T) {
+	ctx := context.Background()
+	recorder := record.NewFakeRecorder(10)
+	rollout := &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: "default", Generation: 3}}
+
+	plansMade := 0
+	makePlan := func() (*apiv1.UpgradePlan, error) {
+		plansMade++
+		return &apiv1.UpgradePlan{Generation: rollout.Generation, Strategy: apiv1.UpgradeStrategyPPND}, nil
+	}
+
+	// the plan is published and waits for approval
+	approved, err := ProcessUpgradePlan(ctx, rollout, "hash-a", makePlan, recorder)
+	assert.NoError(t, err)
+	assert.False(t, approved)
+	assert.NotNil(t, rollout.Status.UpgradePlan)
+	assert.Equal(t, "hash-a", rollout.Status.UpgradePlan.DefinitionHash)
+	assert.Equal(t, metav1.ConditionFalse, rollout.Status.GetCondition(apiv1.ConditionUpgradePlanApproved).Status)
+	assert.Contains(t, rollout.Status.GetCondition(apiv1.ConditionUpgradePlanApproved).Message, "hash-a")
+	assert.Len(t, recorder.Events, 1)
+
+	// approval of a different plan doesn't apply, and the plan isn't computed again
+	rollout.Annotations = map[string]string{common.AnnotationKeyApprovePlan: "hash-z"}
+	approved, err = ProcessUpgradePlan(ctx, rollout, "hash-a", makePlan, recorder)
+	assert.NoError(t, err)
+	assert.False(t, approved)
+	assert.Equal(t, 1, plansMade)
+
+	// the generation alone doesn't approve the plan
+	rollout.Annotations = map[string]string{common.AnnotationKeyApprovePlan: "3"}
+	approved, err = ProcessUpgradePlan(ctx, rollout, "hash-a", makePlan, recorder)
+	assert.NoError(t, err)
+	assert.False(t, approved)
+
+	rollout.Annotations = map[string]string{common.AnnotationKeyApprovePlan: "hash-a", common.AnnotationKeyApprovedBy: "jane"}
+	approved, err = ProcessUpgradePlan(ctx, rollout, "hash-a", makePlan, recorder)
+	assert.NoError(t, err)
+	assert.True(t, approved)
+	assert.True(t, rollout.Status.UpgradePlan.Approved)
+	assert.Equal(t, "jane", rollout.Status.UpgradePlan.ApprovedBy)
+	assert.Equal(t, metav1.ConditionTrue, rollout.Status.GetCondition(apiv1.ConditionUpgradePlanApproved).Status)
+
+	// a change to the child definition within the same generation (e.g. from a template) requires a new plan and approval
+	approved, err = ProcessUpgradePlan(ctx, rollout, "hash-b", makePlan, recorder)
+	assert.NoError(t, err)
+	assert.False(t, approved)
+	assert.Equal(t, 2, plansMade)
+	assert.Equal(t, "hash-b", rollout.Status.UpgradePlan.DefinitionHash)
+	assert.False(t, rollout.Status.UpgradePlan.Approved)
+
+	// a new generation requires a new plan
+	rollout.Annotations = map[string]string{common.AnnotationKeyApprovePlan: "hash-b"}
+	rollout.Generation = 4
+	approved, err = ProcessUpgradePlan(ctx, rollout, "hash-b", makePlan, recorder)
+	assert.NoError(t, err)
+	assert.True(t, approved)
+	assert.Equal(t, 3, plansMade)
+	assert.Equal(t, int64(4), rollout.Status.UpgradePlan.Generation)
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// ValuesFromRolloutObject is a Rollout which can reference ConfigMaps and Secrets supplying values for templating its child
+type ValuesFromRolloutObject interface {
+	RolloutObject
+
+	GetValuesFrom() []apiv1.ValuesFromSource
+}
+
+// GetTemplateValues returns the values from the ConfigMaps and Secrets referenced by the Rollout, keyed by their data keys: where more
+// than one of them has the same key, the last one takes precedence
+func GetTemplateValues(ctx context.Context, c client.Client, rolloutObject ValuesFromRolloutObject) (map[string]string, error) {
+	namespace := rolloutObject.GetRolloutObjectMeta().GetNamespace()
+	values := map[string]string{}
+	for _, source := range rolloutObject.GetValuesFrom() {
+		switch {
+		case source.ConfigMapRef != nil:
+			configMap := &corev1.ConfigMap{}
+			if err := c.Get(ctx, k8stypes.NamespacedName{Namespace: namespace, Name: source.ConfigMapRef.Name}, configMap); err != nil {
+				if apierrors.IsNotFound(err) && source.ConfigMapRef.Optional {
+					continue
+				}
+				return nil, fmt.Errorf("failed to get ConfigMap %s/%s for valuesFrom: %w", namespace, source.ConfigMapRef.Name, err)
+			}
+			for key, value := range configMap.Data {
+				values[key] = value
+			}
+		case source.SecretRef != nil:
+			secret := &corev1.Secret{}
+			if err := c.Get(ctx, k8stypes.NamespacedName{Namespace: namespace, Name: source.SecretRef.Name}, secret); err != nil {
+				if apierrors.IsNotFound(err) && source.SecretRef.Optional {
+					continue
+				}
+				return nil, fmt.Errorf("failed to get Secret %s/%s for valuesFrom: %w", namespace, source.SecretRef.Name, err)
+			}
+			for key, value := range secret.Data {
+				values[key] = string(value)
+			}
+		}
+	}
+	return values, nil
+}
+
+// SecretMetadata returns the object to watch the Secrets referenced by ValuesFrom with: only their metadata is cached, so that their
+// data isn't held in memory. Their data is read from the API server instead, since the manager's client doesn't cache Secrets.
+func SecretMetadata() *metav1.PartialObjectMetadata {
+	secretMetadata := &metav1.PartialObjectMetadata{}
+	secretMetadata.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+	return secretMetadata
+}
+
+// ReferencesValuesFrom determines if the Rollout references the ConfigMap or Secret in its ValuesFrom
+func ReferencesValuesFrom(rolloutObject ValuesFromRolloutObject, obj client.Object) bool {
+	if rolloutObject.GetRolloutObjectMeta().GetNamespace() != obj.GetNamespace() {
+		return false
+	}
+	for _, source := range rolloutObject.GetValuesFrom() {
+		switch obj.(type) {
+		case *corev1.ConfigMap:
+			if source.ConfigMapRef != nil && source.ConfigMapRef.Name == obj.GetName() {
+				return true
+			}
+		case *corev1.Secret:
+			if source.SecretRef != nil && source.SecretRef.Name == obj.GetName() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ValuesFromRequests returns the reconcile requests for the Rollouts which reference the ConfigMap or Secret in their ValuesFrom,
+// so their children are upgraded when it changes
+func ValuesFromRequests[T ValuesFromRolloutObject](rolloutObjects []T, obj client.Object) []reconcile.Request {
+	reqs := []reconcile.Request{}
+	for _, rolloutObject := range rolloutObjects {
+		if ReferencesValuesFrom(rolloutObject, obj) {
+			rolloutMeta := rolloutObject.GetRolloutObjectMeta()
+			reqs = append(reqs, reconcile.Request{NamespacedName: k8stypes.NamespacedName{Namespace: rolloutMeta.GetNamespace(), Name: rolloutMeta.GetName()}})
+		}
+	}
+	return reqs
+}
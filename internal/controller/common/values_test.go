@@ -0,0 +1,104 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func TestGetTemplateValues(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: "default"},
+		Data: map[string]string{"region": "us-west-2", "replicas": "3"}}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "default"},
+		Data: map[string][]byte{"password": []byte("secret"), "region": []byte("us-east-1")}}
+	otherNamespace := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "other"}, Data: map[string]string{"a": "b"}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap, secret, otherNamespace).Build()
+
+	tests := []struct {
+		name           string
+		valuesFrom     []apiv1.ValuesFromSource
+		expectedValues map[string]string
+		expectedError  string
+	}{
+		{
+			name:           "no references",
+			expectedValues: map[string]string{},
+		},
+		{
+			name: "the last reference takes precedence",
+			valuesFrom: []apiv1.ValuesFromSource{
+				{ConfigMapRef: &apiv1.ValuesFromReference{Name: "env"}},
+				{SecretRef: &apiv1.ValuesFromReference{Name: "creds"}},
+			},
+			expectedValues: map[string]string{"region": "us-east-1", "replicas": "3", "password": "secret"},
+		},
+		{
+			name: "optional reference which doesn't exist",
+			valuesFrom: []apiv1.ValuesFromSource{
+				{ConfigMapRef: &apiv1.ValuesFromReference{Name: "env"}},
+				{SecretRef: &apiv1.ValuesFromReference{Name: "missing", Optional: true}},
+			},
+			expectedValues: map[string]string{"region": "us-west-2", "replicas": "3"},
+		},
+		{
+			name:          "required reference which doesn't exist",
+			valuesFrom:    []apiv1.ValuesFromSource{{ConfigMapRef: &apiv1.ValuesFromReference{Name: "missing"}}},
+			expectedError: "failed to get ConfigMap default/missing",
+		},
+		{
+			name:          "reference to an object in another namespace",
+			valuesFrom:    []apiv1.ValuesFromSource{{ConfigMapRef: &apiv1.ValuesFromReference{Name: "other"}}},
+			expectedError: "failed to get ConfigMap default/other",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rollout := &apiv1.MonoVertexRollout{ObjectMeta: metav1.ObjectMeta{Name: "my-monovertex", Namespace: "default"},
+				Spec: apiv1.MonoVertexRolloutSpec{ValuesFrom: tc.valuesFrom}}
+			values, err := GetTemplateValues(ctx, c, rollout)
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedValues, values)
+		})
+	}
+}
+
+func TestValuesFromRequests(t *testing.T) {
+	rollouts := []*apiv1.PipelineRollout{
+		{ObjectMeta: metav1.ObjectMeta{Name: "uses-configmap", Namespace: "default"},
+			Spec: apiv1.PipelineRolloutSpec{ValuesFrom: []apiv1.ValuesFromSource{{ConfigMapRef: &apiv1.ValuesFromReference{Name: "env"}}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "uses-secret", Namespace: "default"},
+			Spec: apiv1.PipelineRolloutSpec{ValuesFrom: []apiv1.ValuesFromSource{{SecretRef: &apiv1.ValuesFromReference{Name: "env"}}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "other"},
+			Spec: apiv1.PipelineRolloutSpec{ValuesFrom: []apiv1.ValuesFromSource{{ConfigMapRef: &apiv1.ValuesFromReference{Name: "env"}}}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "no-values", Namespace: "default"}},
+	}
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: "default"}}
+	assert.Equal(t, []reconcile.Request{{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "uses-configmap"}}},
+		ValuesFromRequests(rollouts, configMap))
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: "default"}}
+	assert.Equal(t, []reconcile.Request{{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "uses-secret"}}},
+		ValuesFromRequests(rollouts, secret))
+
+	unreferenced := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unreferenced", Namespace: "default"}}
+	assert.Empty(t, ValuesFromRequests(rollouts, unreferenced))
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/fsnotify/fsnotify"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/numaproj/numaplane/internal/common"
 	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
@@ -57,6 +58,14 @@ type NumaflowControllerDefinitionsManager struct {
 
 type NamespaceConfig struct {
 	UpgradeStrategy USDEUserStrategy `json:"upgradeStrategy,omitempty" yaml:"upgradeStrategy,omitempty"`
+	// USDE overrides the USDE Config for the namespace (parsed from the "usde" key of the namespace-level ConfigMap)
+	USDE USDEOverrides `json:"-" yaml:"usde,omitempty"`
+	// PauseTimeout is how long to wait for the Pipelines in the namespace to pause for the "pause-and-drain" strategy (e.g. "30m"),
+	// unless their PipelineRollout defines its own
+	PauseTimeout string `json:"pauseTimeout,omitempty" yaml:"pauseTimeout,omitempty"`
+	// PauseTimeoutPolicy is what to do when a Pipeline in the namespace doesn't pause within its pause timeout,
+	// unless its PipelineRollout defines its own
+	PauseTimeoutPolicy apiv1.PauseTimeoutPolicy `json:"pauseTimeoutPolicy,omitempty" yaml:"pauseTimeoutPolicy,omitempty"`
 }
 
 var instance *ConfigManager
@@ -123,6 +132,25 @@ type PipelineConfig struct {
 	// ForceDrainFailureWaitDuration is the duration to wait after a force drain failure before deleting.
 	// If not defined, default to 15 seconds
 	ForceDrainFailureWaitDuration *int32 `json:"forceDrainFailureWaitDuration,omitempty"`
+	// PauseTimeout is how long an ISBServiceRollout or NumaflowControllerRollout waits for a Pipeline to pause for the "pause-and-drain"
+	// strategy, unless its namespace or PipelineRollout defines its own.
+	// If not defined, wait indefinitely
+	PauseTimeout time.Duration `json:"pauseTimeout,omitempty"`
+	// PauseTimeoutPolicy is what to do when a Pipeline doesn't pause within its pause timeout, unless its namespace or PipelineRollout
+	// defines its own.
+	// If not defined, default to "Fail"
+	PauseTimeoutPolicy apiv1.PauseTimeoutPolicy `json:"pauseTimeoutPolicy,omitempty"`
+}
+
+// DefaultRecycleScaleFactor is the RecycleScaleFactor used if it's not defined
+const DefaultRecycleScaleFactor = int32(50)
+
+// GetRecycleScaleFactor returns the configured RecycleScaleFactor, or the default if it's not defined
+func (config *PipelineConfig) GetRecycleScaleFactor() int32 {
+	if config.RecycleScaleFactor != nil {
+		return *config.RecycleScaleFactor
+	}
+	return DefaultRecycleScaleFactor
 }
 
 type ProgressiveConfig struct {
@@ -131,6 +159,9 @@ type ProgressiveConfig struct {
 
 	// timeout duration which AnalysisRuns cannot continue to run after
 	AnalysisRunTimeout string `json:"analysisRunTimeout" mapstructure:"analysisRunTimeout"`
+
+	// address of the Prometheus server used for Analysis Metrics which don't specify their own address
+	PrometheusAddress string `json:"prometheusAddress" mapstructure:"prometheusAddress"`
 }
 
 // DefaultAssessmentSchedule defines a default schedule for each Kind
@@ -138,18 +169,21 @@ type DefaultAssessmentSchedule struct {
 	Kind string `json:"kind" mapstructure:"kind"`
 
 	// The Schedule variables defines time information used when assessing a child health status.
-	// It is a string with 3 comma-separated integer values representing the following:
+	// It is a string with 4 comma-separated integer values representing the following:
 	// 1. AssessmentDelay: indicates the amount of seconds to delay before assessing the status of the child resource to determine healthiness
-	// 2. AssessmentPeriod: indicates the amount of seconds to perform assessments for after the first assessment has been performed
-	//    (if failure is indicated before that, it will not continue however)
-	// 3. AssessmentInterval: indicates how often to assess the child status once the first assessment has been performed and before the end
-	// of the assessments window defined by adding the AssessmentDelay seconds to the AssessmentPeriod seconds
+	// 2. AssessmentEnd: indicates the amount of seconds after the AssessmentDelay within which the assessment has to be successful
+	// 3. AssessmentPeriod: indicates the amount of seconds for which the child must be continuously healthy
+	// 4. AssessmentInterval: indicates how often to assess the child status once the first assessment has been performed and before the end
 	// NOTE: the order of the values is important since each value represents a specific amount of time
-	// Example: "120,60,10" => delay assessment by 120s, assess for 60s, assess every 10s
+	// Example: "120,360,60,10" => delay assessment by 120s, succeed within 360s, be healthy for 60s, assess every 10s
 	// 0 can be used for AssessmentDelay to mean no delay
 	// 0 can be used for AssessmentPeriod to not require a period of time to wait before assessment
 	// Don't use 0 for AssessmentInterval: if the result is initially "unknown", this value is still needed as a requeing time interval for checking
+	// Deprecated: set the named fields of the AssessmentSchedule instead (e.g. "delay: 2m"); if Schedule is set, it takes precedence
 	Schedule string `json:"schedule" mapstructure:"schedule"`
+
+	// the schedule may alternatively be defined by its named fields
+	AssessmentSchedule `mapstructure:",squash"`
 }
 
 type NumaflowControllerDefinitionConfig struct {
@@ -329,14 +363,67 @@ func (cm *ConfigManager) GetNamespaceConfig(namespace string) *NamespaceConfig {
 // AssessmentSchedule is used for progressive rollout to assess the upgrading child
 type AssessmentSchedule struct {
 	// Delay indicates the number of seconds to delay before assessing the status of the child resource to determine healthiness
-	Delay time.Duration
+	Delay time.Duration `json:"delay,omitempty" mapstructure:"delay"`
 	// End indicates the time delta from `Delay` that the assessment window has to be successful within
-	End time.Duration
+	End time.Duration `json:"end,omitempty" mapstructure:"end"`
 	// Period indicates the minimum window of time for which there must be consecutive success before we can declare
 	// the resource successful
-	Period time.Duration
+	Period time.Duration `json:"period,omitempty" mapstructure:"period"`
 	// Interval indicates how often to assess the child status once the first assessment has been performed and before the end
-	Interval time.Duration
+	Interval time.Duration `json:"interval,omitempty" mapstructure:"interval"`
+	// ConsecutiveHealthyChecks indicates the minimum number of consecutive healthy checks required, in addition to the Period
+	ConsecutiveHealthyChecks int32 `json:"consecutiveHealthyChecks,omitempty" mapstructure:"consecutiveHealthyChecks"`
+	// MaxUnhealthyChecks indicates the number of unhealthy checks tolerated within the Period without restarting it
+	MaxUnhealthyChecks int32 `json:"maxUnhealthyChecks,omitempty" mapstructure:"maxUnhealthyChecks"`
+}
+
+// Validate checks that the AssessmentSchedule is self-consistent
+func (schedule AssessmentSchedule) Validate() error {
+	if schedule.Delay < 0 || schedule.End < 0 || schedule.Period < 0 {
+		return fmt.Errorf("invalid schedule: delay, end and period must not be negative")
+	}
+	if schedule.Interval <= 0 {
+		return fmt.Errorf("invalid schedule: interval must be greater than 0")
+	}
+	if schedule.End > 0 && schedule.Period > schedule.End {
+		return fmt.Errorf("invalid schedule: period (%s) must not be greater than end (%s)", schedule.Period, schedule.End)
+	}
+	if schedule.ConsecutiveHealthyChecks < 0 || schedule.MaxUnhealthyChecks < 0 {
+		return fmt.Errorf("invalid schedule: consecutiveHealthyChecks and maxUnhealthyChecks must not be negative")
+	}
+	if schedule.End > 0 && time.Duration(schedule.ConsecutiveHealthyChecks-1)*schedule.Interval > schedule.End {
+		return fmt.Errorf("invalid schedule: %d consecutive healthy checks every %s can't be performed within end (%s)",
+			schedule.ConsecutiveHealthyChecks, schedule.Interval, schedule.End)
+	}
+	return nil
+}
+
+// AssessmentScheduleFromSpec converts the AssessmentSchedule defined in a Rollout into an AssessmentSchedule, validating it
+func AssessmentScheduleFromSpec(spec apiv1.AssessmentSchedule) (AssessmentSchedule, error) {
+	schedule := AssessmentSchedule{
+		Delay:                    spec.Delay.Duration,
+		End:                      spec.End.Duration,
+		Period:                   spec.Period.Duration,
+		Interval:                 spec.Interval.Duration,
+		ConsecutiveHealthyChecks: spec.ConsecutiveHealthyChecks,
+		MaxUnhealthyChecks:       spec.MaxUnhealthyChecks,
+	}
+	if err := schedule.Validate(); err != nil {
+		return AssessmentSchedule{}, err
+	}
+	return schedule, nil
+}
+
+// ToSpec converts the AssessmentSchedule into the AssessmentSchedule defined in a Rollout
+func (schedule AssessmentSchedule) ToSpec() apiv1.AssessmentSchedule {
+	return apiv1.AssessmentSchedule{
+		Delay:                    metav1.Duration{Duration: schedule.Delay},
+		End:                      metav1.Duration{Duration: schedule.End},
+		Period:                   metav1.Duration{Duration: schedule.Period},
+		Interval:                 metav1.Duration{Duration: schedule.Interval},
+		ConsecutiveHealthyChecks: schedule.ConsecutiveHealthyChecks,
+		MaxUnhealthyChecks:       schedule.MaxUnhealthyChecks,
+	}
 }
 
 // ParseAssessmentSchedule parses the string indicating the AssessmentSchedule
@@ -372,20 +459,32 @@ func ParseAssessmentSchedule(str string) (AssessmentSchedule, error) {
 	assessmentPeriod := time.Duration(assessmentPeriodSeconds) * time.Second
 	assessmentInterval := time.Duration(assessmentIntervalSeconds) * time.Second
 
-	return AssessmentSchedule{
+	schedule := AssessmentSchedule{
 		Delay:    assessmentDelay,
 		End:      assessmentEnd,
 		Period:   assessmentPeriod,
 		Interval: assessmentInterval,
-	}, nil
+	}
+	// catch values given in the wrong order
+	if err := schedule.Validate(); err != nil {
+		return AssessmentSchedule{}, fmt.Errorf("%w (expected \"delay,end,period,interval\")", err)
+	}
+	return schedule, nil
 }
 
-// GetChildStatusAssessmentSchedule parses the GlobalConfig Default Assessment Schedule string for this kind into an AssessmentSchedule
+// GetChildStatusAssessmentSchedule gets the GlobalConfig Default Assessment Schedule for this kind, either by parsing
+// its Schedule string or otherwise from its named fields
 func (config *ProgressiveConfig) GetChildStatusAssessmentSchedule(kind string) (AssessmentSchedule, error) {
 
 	for _, schedule := range config.DefaultAssessmentSchedule {
 		if strings.EqualFold(schedule.Kind, kind) { // case-insensitive equals check
-			return ParseAssessmentSchedule(schedule.Schedule)
+			if schedule.Schedule != "" {
+				return ParseAssessmentSchedule(schedule.Schedule)
+			}
+			if err := schedule.AssessmentSchedule.Validate(); err != nil {
+				return AssessmentSchedule{}, err
+			}
+			return schedule.AssessmentSchedule, nil
 		}
 	}
 	return AssessmentSchedule{}, fmt.Errorf("no Assessment Schedule found for kind %q", kind)
@@ -17,6 +17,7 @@ limitations under the License.
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -25,6 +26,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/numaproj/numaplane/internal/util"
@@ -286,3 +288,124 @@ func TestNumaflowControllerDefinitionsManager_GetNumaflowControllerDefinitionsCo
 		})
 	}
 }
+
+func TestParseAssessmentSchedule(t *testing.T) {
+	schedule, err := ParseAssessmentSchedule("120,360,60,10")
+	assert.NoError(t, err)
+	assert.Equal(t, AssessmentSchedule{Delay: 120 * time.Second, End: 360 * time.Second, Period: 60 * time.Second, Interval: 10 * time.Second}, schedule)
+
+	// period and end in the wrong order
+	_, err = ParseAssessmentSchedule("120,60,360,10")
+	assert.Error(t, err)
+	// interval of 0
+	_, err = ParseAssessmentSchedule("120,360,60,0")
+	assert.Error(t, err)
+	_, err = ParseAssessmentSchedule("120,360,60")
+	assert.Error(t, err)
+}
+
+func TestGetChildStatusAssessmentSchedule(t *testing.T) {
+	configYAML := `
+defaultAssessmentSchedule:
+  - kind: Pipeline
+    schedule: "120,360,0,10"
+  - kind: MonoVertex
+    delay: 2m
+    end: 6m
+    period: 1m
+    interval: 10s
+    consecutiveHealthyChecks: 3
+    maxUnhealthyChecks: 1
+  - kind: InterstepBufferService
+    delay: 2m
+    end: 1m
+    period: 5m
+    interval: 10s
+`
+	v := viper.New()
+	v.SetConfigType("yaml")
+	assert.NoError(t, v.ReadConfig(bytes.NewBufferString(configYAML)))
+	progressiveConfig := ProgressiveConfig{}
+	assert.NoError(t, v.Unmarshal(&progressiveConfig))
+
+	// the string format is still supported
+	schedule, err := progressiveConfig.GetChildStatusAssessmentSchedule("Pipeline")
+	assert.NoError(t, err)
+	assert.Equal(t, AssessmentSchedule{Delay: 120 * time.Second, End: 360 * time.Second, Interval: 10 * time.Second}, schedule)
+
+	schedule, err = progressiveConfig.GetChildStatusAssessmentSchedule("MonoVertex")
+	assert.NoError(t, err)
+	assert.Equal(t, AssessmentSchedule{Delay: 2 * time.Minute, End: 6 * time.Minute, Period: time.Minute, Interval: 10 * time.Second,
+		ConsecutiveHealthyChecks: 3, MaxUnhealthyChecks: 1}, schedule)
+
+	// period is greater than end
+	_, err = progressiveConfig.GetChildStatusAssessmentSchedule("InterstepBufferService")
+	assert.Error(t, err)
+
+	_, err = progressiveConfig.GetChildStatusAssessmentSchedule("Vertex")
+	assert.Error(t, err)
+}
+
+func TestGetEffectiveUSDEConfig(t *testing.T) {
+	configManager := GetConfigManagerInstance()
+	configManager.UpdateUSDEConfig(USDEConfig{
+		"pipeline": USDEResourceConfig{
+			Recreate:    []SpecField{{Path: "spec.interStepBufferServiceName"}},
+			DataLoss:    []SpecField{{Path: "spec.edges"}, {Path: "spec.vertices.sink.udsink.container.image"}},
+			Progressive: []SpecField{{Path: "spec.vertices.scale"}},
+			Rules:       []ChangeRule{{Name: "a", Expression: "'dataLoss'"}, {Name: "b", Expression: "'recreate'"}},
+		},
+		"monovertex": USDEResourceConfig{
+			DataLoss: []SpecField{{Path: "spec.source"}},
+		},
+	})
+	defer configManager.UnsetUSDEConfig()
+
+	getPaths := func(specFields []SpecField) []string {
+		paths := []string{}
+		for _, specField := range specFields {
+			paths = append(paths, specField.Path)
+		}
+		return paths
+	}
+	getNames := func(rules []ChangeRule) []string {
+		names := []string{}
+		for _, rule := range rules {
+			names = append(names, rule.Name)
+		}
+		return names
+	}
+
+	// without overrides, the global config is in effect
+	assert.Equal(t, configManager.GetUSDEConfig(), configManager.GetEffectiveUSDEConfig("my-namespace"))
+
+	configManager.UpdateNamespaceConfig("my-namespace", NamespaceConfig{USDE: USDEOverrides{
+		"pipeline": USDEResourceOverride{
+			Remove: []string{"spec.edges", "b"},
+			USDEResourceConfig: USDEResourceConfig{
+				// reclassify a global field and add a new one
+				Progressive: []SpecField{{Path: "spec.vertices.sink.udsink.container.image"}, {Path: "spec.vertices.udf.container.image"}},
+				Rules:       []ChangeRule{{Name: "a", Expression: "'directApply'"}, {Name: "c", Expression: "''"}},
+			},
+		},
+		"monovertex": USDEResourceOverride{
+			Replace:            true,
+			USDEResourceConfig: USDEResourceConfig{Progressive: []SpecField{{Path: "spec.sink"}}},
+		},
+	}})
+	defer configManager.UnsetNamespaceConfig("my-namespace")
+
+	effectiveConfig := configManager.GetEffectiveUSDEConfig("my-namespace")
+	assert.Equal(t, []string{"spec.interStepBufferServiceName"}, getPaths(effectiveConfig["pipeline"].Recreate))
+	assert.Equal(t, []string{}, getPaths(effectiveConfig["pipeline"].DataLoss))
+	assert.Equal(t, []string{"spec.vertices.scale", "spec.vertices.sink.udsink.container.image", "spec.vertices.udf.container.image"},
+		getPaths(effectiveConfig["pipeline"].Progressive))
+	assert.Equal(t, []string{"a", "c"}, getNames(effectiveConfig["pipeline"].Rules))
+	assert.Equal(t, "'directApply'", effectiveConfig["pipeline"].Rules[0].Expression)
+
+	assert.Empty(t, effectiveConfig["monovertex"].DataLoss)
+	assert.Equal(t, []string{"spec.sink"}, getPaths(effectiveConfig["monovertex"].Progressive))
+
+	// other namespaces are unaffected
+	assert.Equal(t, configManager.GetUSDEConfig(), configManager.GetEffectiveUSDEConfig("other-namespace"))
+}
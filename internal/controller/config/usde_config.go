@@ -18,13 +18,37 @@ package config
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/rs/zerolog/log"
+
+	"github.com/numaproj/numaplane/internal/util"
 )
 
 type SpecField struct {
+	// Path is either a dotted path, such as "spec.vertices.udf.container.image", or a JSONPath-style selector with list wildcards,
+	// indices, and filters, such as "spec.vertices[*].scale" or "spec.vertices[?(@.name=='in')].source" (see util.FieldSelector)
 	Path             string `json:"path" yaml:"path"`
 	IncludeSubfields bool   `json:"includeSubfields,omitempty" yaml:"includeSubfields,omitempty"`
+	// Semver, if set, parses the field's value(s) as semantic versions (either an image tag or a plain version) so that
+	// a change can be classified differently depending on whether it's a patch, minor, or major version bump
+	Semver *SemverClassification `json:"semver,omitempty" yaml:"semver,omitempty"`
+}
+
+// compiled selectors, keyed by Path: they're kept out of SpecField so that it remains a plain value
+var fieldSelectors sync.Map
+
+// GetSelector returns the compiled selector for the Path, compiling it if it hasn't been already
+func (specField SpecField) GetSelector() (*util.FieldSelector, error) {
+	if selector, found := fieldSelectors.Load(specField.Path); found {
+		return selector.(*util.FieldSelector), nil
+	}
+	selector, err := util.ParseFieldSelector(specField.Path)
+	if err != nil {
+		return nil, err
+	}
+	fieldSelectors.Store(specField.Path, selector)
+	return selector, nil
 }
 
 type USDEResourceConfig struct {
@@ -37,6 +61,74 @@ type USDEResourceConfig struct {
 	// Progressive contains fields that can be updated without requiring a full resource recreation and by performing an in-place update.
 	// For PPND strategy, this list is checked after the other two lists.
 	Progressive []SpecField `json:"progressive,omitempty" yaml:"progressive,omitempty"`
+	// Rules are custom rules which classify a change by evaluating a CEL expression over the old and new definitions.
+	// They're evaluated if the spec changed, and the most conservative of their results and the lists' results is used.
+	Rules []ChangeRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// USDEChangeClass is the list a change to a field is classified into
+type USDEChangeClass string
+
+const (
+	// ChangeClassDefault means the change is classified into the list which the field is defined in
+	ChangeClassDefault     USDEChangeClass = ""
+	ChangeClassDirectApply USDEChangeClass = "directApply"
+	ChangeClassRecreate    USDEChangeClass = "recreate"
+	ChangeClassDataLoss    USDEChangeClass = "dataLoss"
+	ChangeClassProgressive USDEChangeClass = "progressive"
+)
+
+func (changeClass USDEChangeClass) IsValid() bool {
+	switch changeClass {
+	case ChangeClassDefault, ChangeClassDirectApply, ChangeClassRecreate, ChangeClassDataLoss, ChangeClassProgressive:
+		return true
+	default:
+		return false
+	}
+}
+
+// SemverClassification classifies a change to a version by which part of the semantic version changed.
+// If a version can't be parsed, or the level isn't set, the change is classified into the list which the field is defined in.
+type SemverClassification struct {
+	Patch USDEChangeClass `json:"patch,omitempty" yaml:"patch,omitempty"`
+	Minor USDEChangeClass `json:"minor,omitempty" yaml:"minor,omitempty"`
+	Major USDEChangeClass `json:"major,omitempty" yaml:"major,omitempty"`
+}
+
+// Compile compiles the Path of each field and the expression of each Rule so that they're only parsed once, and validates
+// each field's Semver classification, returning an error if any is invalid (a ChangeRuleCompileError in the case of a Rule)
+func (resourceConfig *USDEResourceConfig) Compile() error {
+	for listName, specFields := range map[string][]SpecField{
+		"recreate":    resourceConfig.Recreate,
+		"dataLoss":    resourceConfig.DataLoss,
+		"progressive": resourceConfig.Progressive,
+	} {
+		for i := range specFields {
+			if _, err := specFields[i].GetSelector(); err != nil {
+				return fmt.Errorf("invalid path in %s list: %v", listName, err)
+			}
+
+			if semver := specFields[i].Semver; semver != nil {
+				for _, changeClass := range []USDEChangeClass{semver.Patch, semver.Minor, semver.Major} {
+					if !changeClass.IsValid() {
+						return fmt.Errorf("invalid semver classification %q for %q in %s list", changeClass, specFields[i].Path, listName)
+					}
+				}
+			}
+		}
+	}
+
+	ruleNames := map[string]struct{}{}
+	for i := range resourceConfig.Rules {
+		if err := resourceConfig.Rules[i].compile(); err != nil {
+			return err
+		}
+		if _, found := ruleNames[resourceConfig.Rules[i].Name]; found {
+			return &ChangeRuleCompileError{RuleName: resourceConfig.Rules[i].Name, Err: fmt.Errorf("duplicate rule name")}
+		}
+		ruleNames[resourceConfig.Rules[i].Name] = struct{}{}
+	}
+	return nil
 }
 
 type USDEConfig map[string]USDEResourceConfig
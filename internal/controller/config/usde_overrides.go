@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// USDEOverrides are the namespace-level overrides of the USDE Config, keyed by Kind (as in USDEConfig)
+type USDEOverrides map[string]USDEResourceOverride
+
+// USDEResourceOverride overrides the USDE Config for a Kind within a namespace.
+//
+// Unless Replace is set, the override is merged with the global USDE Config for the Kind as follows:
+//   - fields whose Path is in Remove are removed from the global lists, and Rules whose Name is in Remove are removed
+//   - a field defined by the override is removed from the global lists and added to the override's list, so that a namespace
+//     can both add fields and reclassify existing ones
+//   - a Rule defined by the override replaces the global Rule with the same Name, if any, or else is added
+type USDEResourceOverride struct {
+	// Replace, if set, causes the override's lists and Rules to replace the global ones for the Kind rather than be merged with them
+	Replace bool `json:"replace,omitempty" yaml:"replace,omitempty"`
+	// Remove lists the Paths of fields and the Names of Rules to remove from the global USDE Config for the Kind
+	Remove []string `json:"remove,omitempty" yaml:"remove,omitempty"`
+
+	USDEResourceConfig `json:",inline" yaml:",inline"`
+}
+
+// merge returns the result of applying the override to the global USDE Config for a Kind
+func (override USDEResourceOverride) merge(global USDEResourceConfig) USDEResourceConfig {
+	if override.Replace {
+		return override.USDEResourceConfig
+	}
+
+	removed := make(map[string]struct{}, len(override.Remove))
+	for _, name := range override.Remove {
+		removed[name] = struct{}{}
+	}
+	for _, specFields := range [][]SpecField{override.Recreate, override.DataLoss, override.Progressive} {
+		for _, specField := range specFields {
+			removed[specField.Path] = struct{}{}
+		}
+	}
+	removedRules := make(map[string]struct{}, len(override.Remove)+len(override.Rules))
+	for _, name := range override.Remove {
+		removedRules[name] = struct{}{}
+	}
+	for _, rule := range override.Rules {
+		removedRules[rule.Name] = struct{}{}
+	}
+
+	mergeFields := func(globalFields, overrideFields []SpecField) []SpecField {
+		merged := []SpecField{}
+		for _, specField := range globalFields {
+			if _, found := removed[specField.Path]; !found {
+				merged = append(merged, specField)
+			}
+		}
+		return append(merged, overrideFields...)
+	}
+
+	merged := USDEResourceConfig{
+		Recreate:    mergeFields(global.Recreate, override.Recreate),
+		DataLoss:    mergeFields(global.DataLoss, override.DataLoss),
+		Progressive: mergeFields(global.Progressive, override.Progressive),
+	}
+	for _, rule := range global.Rules {
+		if _, found := removedRules[rule.Name]; !found {
+			merged.Rules = append(merged.Rules, rule)
+		}
+	}
+	merged.Rules = append(merged.Rules, override.Rules...)
+	return merged
+}
+
+// GetEffectiveUSDEConfig returns the USDE Config in effect for the namespace: the global USDE Config merged with any overrides
+// in the namespace's ConfigMap
+func (cm *ConfigManager) GetEffectiveUSDEConfig(namespace string) USDEConfig {
+	globalConfig := cm.GetUSDEConfig()
+
+	namespaceConfig := cm.GetNamespaceConfig(namespace)
+	if namespaceConfig == nil || len(namespaceConfig.USDE) == 0 {
+		return globalConfig
+	}
+
+	effectiveConfig := make(USDEConfig, len(globalConfig)+len(namespaceConfig.USDE))
+	for kind, resourceConfig := range globalConfig {
+		effectiveConfig[kind] = resourceConfig
+	}
+	for kind, override := range namespaceConfig.USDE {
+		effectiveConfig[kind] = override.merge(globalConfig[kind])
+	}
+	return effectiveConfig
+}
+
+// USDEConfigHandler serves the USDE Config in effect for the namespace given by the "namespace" query parameter, for debugging
+// (or the global USDE Config if no namespace is given)
+func USDEConfigHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		usdeConfig := GetConfigManagerInstance().GetEffectiveUSDEConfig(r.URL.Query().Get("namespace"))
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(usdeConfig); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
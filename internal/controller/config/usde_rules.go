@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+)
+
+const (
+	// ChangeRuleOldVariable and ChangeRuleNewVariable are the names of the variables a ChangeRule expression is evaluated over
+	ChangeRuleOldVariable = "old"
+	ChangeRuleNewVariable = "new"
+
+	// changeRuleCostLimit bounds the cost of evaluating a ChangeRule expression
+	changeRuleCostLimit = 1000000
+)
+
+var (
+	changeRuleEnv     *cel.Env
+	changeRuleEnvErr  error
+	changeRuleEnvOnce sync.Once
+)
+
+// ChangeRule is a custom USDE rule which classifies a change to a resource by evaluating a CEL expression
+type ChangeRule struct {
+	// Name identifies the rule in logs and metrics
+	Name string `json:"name" yaml:"name"`
+	// Expression is a CEL expression evaluated over the "old" and "new" definitions of the resource, which returns the class
+	// of the change as a string ("directApply", "recreate", "dataLoss", or "progressive"), or "" if the rule doesn't apply.
+	// For example: "new.spec.vertices.size() < old.spec.vertices.size() ? 'recreate' : ''"
+	// A rule which fails at runtime, such as one reading an optional field without guarding it with has(), doesn't apply.
+	Expression string `json:"expression" yaml:"expression"`
+
+	// program is the compiled Expression
+	program cel.Program
+}
+
+// ChangeRuleCompileError is the error returned when a ChangeRule's expression can't be compiled
+type ChangeRuleCompileError struct {
+	RuleName string
+	Err      error
+}
+
+func (e *ChangeRuleCompileError) Error() string {
+	return fmt.Sprintf("error compiling USDE rule %q: %v", e.RuleName, e.Err)
+}
+
+func (e *ChangeRuleCompileError) Unwrap() error {
+	return e.Err
+}
+
+func getChangeRuleEnv() (*cel.Env, error) {
+	changeRuleEnvOnce.Do(func() {
+		changeRuleEnv, changeRuleEnvErr = cel.NewEnv(
+			cel.Variable(ChangeRuleOldVariable, cel.DynType),
+			cel.Variable(ChangeRuleNewVariable, cel.DynType),
+		)
+	})
+	return changeRuleEnv, changeRuleEnvErr
+}
+
+// compile compiles the rule's Expression, returning a ChangeRuleCompileError if it's invalid
+func (rule *ChangeRule) compile() error {
+	if rule.Name == "" {
+		return &ChangeRuleCompileError{RuleName: rule.Name, Err: fmt.Errorf("name is required")}
+	}
+	program, err := compileChangeRuleExpression(rule.Expression)
+	if err != nil {
+		return &ChangeRuleCompileError{RuleName: rule.Name, Err: err}
+	}
+	rule.program = program
+	return nil
+}
+
+func compileChangeRuleExpression(expression string) (cel.Program, error) {
+	env, err := getChangeRuleEnv()
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.StringType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("expression must return a string, not %v", ast.OutputType())
+	}
+	return env.Program(ast, cel.CostLimit(changeRuleCostLimit))
+}
+
+// Evaluate evaluates the rule over the old and new definitions of a resource, returning the class of the change
+func (rule ChangeRule) Evaluate(oldDef, newDef map[string]any) (USDEChangeClass, error) {
+	program := rule.program
+	if program == nil {
+		var err error
+		if program, err = compileChangeRuleExpression(rule.Expression); err != nil {
+			return ChangeClassDefault, &ChangeRuleCompileError{RuleName: rule.Name, Err: err}
+		}
+	}
+
+	result, _, err := program.Eval(map[string]any{ChangeRuleOldVariable: oldDef, ChangeRuleNewVariable: newDef})
+	if err != nil {
+		return ChangeClassDefault, fmt.Errorf("error evaluating USDE rule %q: %v", rule.Name, err)
+	}
+	value, ok := result.Value().(string)
+	if !ok {
+		return ChangeClassDefault, fmt.Errorf("USDE rule %q returned %v rather than a string", rule.Name, result.Value())
+	}
+	changeClass := USDEChangeClass(value)
+	if !changeClass.IsValid() {
+		return ChangeClassDefault, fmt.Errorf("USDE rule %q returned invalid change class %q", rule.Name, value)
+	}
+	return changeClass, nil
+}
@@ -40,12 +40,15 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/numaproj/numaplane/internal/common"
 	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
 	"github.com/numaproj/numaplane/internal/controller/common/numaflowtypes"
+	"github.com/numaproj/numaplane/internal/controller/common/revisions"
 	"github.com/numaproj/numaplane/internal/controller/common/riders"
+	"github.com/numaproj/numaplane/internal/controller/common/templates"
 	"github.com/numaproj/numaplane/internal/controller/pipelinerollout"
 	"github.com/numaproj/numaplane/internal/controller/ppnd"
 	"github.com/numaproj/numaplane/internal/controller/progressive"
@@ -58,9 +61,7 @@ import (
 )
 
 const (
-	ControllerISBSVCRollout     = "isbsvc-rollout-controller"
-	TemplateISBServiceName      = ".isbsvc-name"
-	TemplateISBServiceNamespace = ".isbsvc-namespace"
+	ControllerISBSVCRollout = "isbsvc-rollout-controller"
 )
 
 // ISBServiceRolloutReconciler reconciles an ISBServiceRollout object
@@ -111,9 +112,24 @@ func NewISBServiceRolloutReconciler(
 	return r
 }
 
+// Rehydrate restores the in-progress upgrade strategies of the ISBServiceRollouts from their Statuses
+func (r *ISBServiceRolloutReconciler) Rehydrate(ctx context.Context, c client.Reader) error {
+	isbServiceRollouts := &apiv1.ISBServiceRolloutList{}
+	if err := c.List(ctx, isbServiceRollouts); err != nil {
+		return fmt.Errorf("error listing ISBServiceRollouts: %w", err)
+	}
+	rollouts := make([]client.Object, len(isbServiceRollouts.Items))
+	for i := range isbServiceRollouts.Items {
+		rollouts[i] = &isbServiceRollouts.Items[i]
+	}
+	r.inProgressStrategyMgr.Rehydrate(ctx, rollouts)
+	return nil
+}
+
 //+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=isbservicerollouts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=isbservicerollouts/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=isbservicerollouts/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=configmaps;secrets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -127,6 +143,11 @@ func (r *ISBServiceRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	ctx = logger.WithLogger(ctx, numaLogger)
 	r.customMetrics.ISBServiceROSyncs.WithLabelValues().Inc()
 
+	// don't reconcile until the state kept in memory has been rehydrated from the Rollout Statuses
+	if err := ctlrcommon.WaitForRehydration(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Get the live ISBServiceRollout since we need latest Status for Progressive rollout case
 	// TODO: consider storing ISBServiceRollout Status in a local cache instead of this
 	isbServiceRollout, err := getLiveISBServiceRollout(ctx, req.NamespacedName.Name, req.NamespacedName.Namespace)
@@ -157,7 +178,12 @@ func (r *ISBServiceRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 	// Update the resource definition (everything except the Status subresource)
 	if r.needsUpdate(isbServiceRolloutOrig, isbServiceRollout) {
-		if err := r.client.Update(ctx, isbServiceRollout); err != nil {
+		// the in-memory spec may have been rolled back to a revision, so write back the original spec
+		isbServiceRolloutUpdate := isbServiceRollout.DeepCopy()
+		isbServiceRolloutUpdate.Spec = *isbServiceRolloutOrig.Spec.DeepCopy()
+		err := r.client.Update(ctx, isbServiceRolloutUpdate)
+		isbServiceRollout.ObjectMeta = isbServiceRolloutUpdate.ObjectMeta
+		if err != nil {
 			r.ErrorHandler(ctx, isbServiceRollout, err, "UpdateFailed", "Failed to update isb service rollout")
 			if statusUpdateErr := r.updateISBServiceRolloutStatusToFailed(ctx, isbServiceRollout, err); statusUpdateErr != nil {
 				r.ErrorHandler(ctx, isbServiceRollout, statusUpdateErr, "UpdateStatusFailed", "Failed to update isb service rollout status")
@@ -231,6 +257,18 @@ func (r *ISBServiceRolloutReconciler) reconcile(ctx context.Context, isbServiceR
 		controllerutil.AddFinalizer(isbServiceRollout, common.FinalizerName)
 	}
 
+	// if the user requested a rollback to a previous revision, deploy that revision's definition instead of the one in the spec
+	rollingBack, err := revisions.ApplyRollbackToRevision(ctx, r.client, isbServiceRollout)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// if a failed progressive upgrade of this generation was rolled back, keep deploying the last promoted revision
+	if !rollingBack {
+		if _, err := revisions.ApplyAutoRollback(ctx, r.client, isbServiceRollout); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	_, pauseRequestExists := ppnd.GetPauseModule().GetPauseRequest(isbsvcKey)
 	if !pauseRequestExists {
 		// this is just creating an entry in the map if it doesn't already exist
@@ -341,7 +379,7 @@ func (r *ISBServiceRolloutReconciler) processExistingISBService(ctx context.Cont
 	numaLogger := logger.FromContext(ctx)
 
 	// get the list of Riders that we need based on the ISBServiceRollout definition
-	currentRiderList, err := r.GetDesiredRiders(isbServiceRollout, existingISBServiceDef.GetName(), newISBServiceDef)
+	currentRiderList, err := r.GetDesiredRiders(ctx, isbServiceRollout, existingISBServiceDef.GetName(), newISBServiceDef)
 	if err != nil {
 		return 0, fmt.Errorf("error getting desired Riders for isbsvc %s: %s", existingISBServiceDef.GetName(), err)
 	}
@@ -354,10 +392,17 @@ func (r *ISBServiceRolloutReconciler) processExistingISBService(ctx context.Cont
 	// update our Status with the ISBService's Status
 	r.processISBServiceStatus(ctx, existingISBServiceDef, isbServiceRollout)
 
+	// report the upgrade strategy in effect for this ISBServiceRollout
+	effectiveUpgradeStrategy, err := usde.ResolveUserStrategy(ctx, newISBServiceDef.GetNamespace(), existingISBServiceDef.GetKind(), isbServiceRollout.GetUpgradeStrategy())
+	if err != nil {
+		return 0, err
+	}
+	isbServiceRollout.Status.EffectiveUpgradeStrategy = &effectiveUpgradeStrategy
+
 	// determine if we're trying to update the ISBService spec
 	// if it's a simple change, direct apply
 	// if not, it will require PPND or Progressive
-	needsUpdate, upgradeStrategyType, needsRecreate, riderAdditions, riderModifications, riderDeletions, err := usde.ResourceNeedsUpdating(ctx, newISBServiceDef, existingISBServiceDef, currentRiderList, existingRiderList)
+	needsUpdate, upgradeStrategyType, needsRecreate, riderAdditions, riderModifications, riderDeletions, err := usde.ResourceNeedsUpdating(ctx, newISBServiceDef, existingISBServiceDef, currentRiderList, existingRiderList, isbServiceRollout.GetUpgradeStrategy())
 	if err != nil {
 		return 0, err
 	}
@@ -378,7 +423,38 @@ func (r *ISBServiceRolloutReconciler) processExistingISBService(ctx context.Cont
 	inProgressStrategy := r.inProgressStrategyMgr.GetStrategy(ctx, isbServiceRollout)
 	inProgressStrategySet := (inProgressStrategy != apiv1.UpgradeStrategyNoOp)
 
-	// if not, should we set one?
+	// if approval of the upgrade plan is required, don't start upgrading until it's approved
+	requiredBySpec := isbServiceRollout.Spec.Strategy != nil && isbServiceRollout.Spec.Strategy.RequireApprovalOfPlan
+	if needsUpdate && !inProgressStrategySet && ctlrcommon.UpgradePlanApprovalRequired(isbServiceRollout, requiredBySpec) {
+		definitionHash, err := ctlrcommon.UpgradeDefinitionHash(newISBServiceDef, riderAdditions, riderModifications, riderDeletions)
+		if err != nil {
+			return 0, err
+		}
+		approved, err := ctlrcommon.ProcessUpgradePlan(ctx, isbServiceRollout, definitionHash, func() (*apiv1.UpgradePlan, error) {
+			var pipelinesToPause []string
+			if upgradeStrategyType == apiv1.UpgradeStrategyPPND {
+				pipelines, err := r.getPipelineListForChildISBSvc(ctx, existingISBServiceDef.GetNamespace(), existingISBServiceDef.GetName())
+				if err != nil {
+					return nil, err
+				}
+				for _, pipeline := range pipelines.Items {
+					pipelinesToPause = append(pipelinesToPause, pipeline.GetName())
+				}
+			}
+			nameCount, _ := r.getCurrentChildCount(isbServiceRollout)
+			return ctlrcommon.NewUpgradePlan(isbServiceRollout, newISBServiceDef, existingISBServiceDef, upgradeStrategyType, needsRecreate,
+				riderAdditions, riderModifications, riderDeletions, fmt.Sprintf("%s-%d", isbServiceRollout.Name, nameCount), pipelinesToPause)
+		}, r.recorder)
+		if err != nil {
+			return 0, err
+		}
+		if !approved {
+			numaLogger.Debug("waiting for upgrade plan to be approved")
+			return common.DefaultRequeueDelay, nil
+		}
+	}
+
+	// if there is no inProgressStrategy, should we set one?
 	if !inProgressStrategySet {
 		if upgradeStrategyType == apiv1.UpgradeStrategyPPND {
 			inProgressStrategy = apiv1.UpgradeStrategyPPND
@@ -430,7 +506,7 @@ func (r *ISBServiceRolloutReconciler) processExistingISBService(ctx context.Cont
 			return 0, fmt.Errorf("error determining if ISBService is updating: %v", err)
 		}
 
-		done, err := ppnd.ProcessChildObjectWithPPND(ctx, r.client, isbServiceRollout, r, needsUpdate, isbServiceIsUpdating, func() error {
+		done, err := ppnd.ProcessChildObjectWithPPND(ctx, r.client, r.recorder, isbServiceRollout, r, needsUpdate, isbServiceIsUpdating, func() error {
 			r.recorder.Eventf(isbServiceRollout, corev1.EventTypeNormal, "PipelinesPaused", "All Pipelines have paused for ISBService update")
 			err = r.updateISBService(ctx, isbServiceRollout, newISBServiceDef, needsRecreate)
 			if err != nil {
@@ -464,7 +540,7 @@ func (r *ISBServiceRolloutReconciler) processExistingISBService(ctx context.Cont
 				return 0, err
 			}
 
-			currentRiderList, err := r.GetDesiredRiders(isbServiceRollout, promotedISBService.GetName(), promotedISBService)
+			currentRiderList, err := r.GetDesiredRiders(ctx, isbServiceRollout, promotedISBService.GetName(), promotedISBService)
 			if err != nil {
 				return 0, fmt.Errorf("error getting desired Riders for pipeline %s: %s", newISBServiceDef.GetName(), err)
 			}
@@ -535,6 +611,10 @@ func (r *ISBServiceRolloutReconciler) updateISBService(ctx context.Context, isbS
 			return err
 		}
 		isbServiceRollout.Status.MarkDeployed(isbServiceRollout.Generation)
+
+		if err := revisions.RecordRevision(ctx, r.client, isbServiceRollout, newISBServiceDef.GetName(), revisions.OutcomePromoted); err != nil {
+			logger.FromContext(ctx).Error(err, "failed to record revision")
+		}
 	}
 	return nil
 }
@@ -617,6 +697,11 @@ func (r *ISBServiceRolloutReconciler) getPipelineRolloutList(ctx context.Context
 		return pipelineRolloutsForISBSvc, err
 	}
 	for _, pipelineRollout := range pipelineRolloutInNamespace.Items {
+		// the Pipeline may be defined by a PipelineTemplate: one which can't be resolved shouldn't prevent finding the others
+		if err = templates.ResolveTemplateRef(ctx, r.client, &pipelineRollout); err != nil {
+			numaLogger.WithValues("pipelinerollout", pipelineRollout.Name).Warnf("skipping PipelineRollout whose template can't be resolved: %v", err)
+			continue
+		}
 		// which ISBServiceRollout is this PipelineRollout using?
 		var pipelineSpec numaflowtypes.PipelineSpec
 		err = json.Unmarshal(pipelineRollout.Spec.Pipeline.Spec.Raw, &pipelineSpec)
@@ -792,6 +877,7 @@ func (r *ISBServiceRolloutReconciler) processISBServiceStatus(ctx context.Contex
 	// check if PPND strategy is requesting Pipelines to pause, and set true/false
 	// (currently, only PPND is accounted for as far as system pausing, not Progressive)
 	_ = r.MarkRolloutPaused(ctx, rollout, ppnd.IsRequestingPause(r, rollout))
+	rollout.Status.PauseRequested = ppnd.GetPersistedPauseRequest(r, rollout)
 }
 
 func (r *ISBServiceRolloutReconciler) needsUpdate(old, new *apiv1.ISBServiceRollout) bool {
@@ -817,6 +903,10 @@ func (r *ISBServiceRolloutReconciler) createPromotedISBService(ctx context.Conte
 		return fmt.Errorf("error creating riders: %s", err)
 	}
 
+	if err := revisions.RecordRevision(ctx, r.client, isbServiceRollout, newISBServiceDef.GetName(), revisions.OutcomePromoted); err != nil {
+		logger.FromContext(ctx).Error(err, "failed to record revision")
+	}
+
 	// if user somehow has no Promoted ISBService and is in the middle of Progressive, this isn't right - user may have deleted the Promoted one
 	// if this happens, we need to stop the Progressive upgrade and remove any Upgrading children
 	inProgressStrategy := r.inProgressStrategyMgr.GetStrategy(ctx, isbServiceRollout)
@@ -831,6 +921,21 @@ func (r *ISBServiceRolloutReconciler) createPromotedISBService(ctx context.Conte
 
 }
 
+// get the reconcile requests for the ISBServiceRollouts which reference the ConfigMap or Secret in their ValuesFrom
+func (r *ISBServiceRolloutReconciler) valuesFromRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	isbServiceRolloutList := &apiv1.ISBServiceRolloutList{}
+	if err := r.client.List(ctx, isbServiceRolloutList, client.InNamespace(obj.GetNamespace())); err != nil {
+		logger.FromContext(ctx).WithValues("object", fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())).
+			Error(err, "failed to list the ISBServiceRollouts which may reference the object in their ValuesFrom")
+		return nil
+	}
+	isbServiceRollouts := make([]*apiv1.ISBServiceRollout, 0, len(isbServiceRolloutList.Items))
+	for i := range isbServiceRolloutList.Items {
+		isbServiceRollouts = append(isbServiceRollouts, &isbServiceRolloutList.Items[i])
+	}
+	return ctlrcommon.ValuesFromRequests(isbServiceRollouts, obj)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ISBServiceRolloutReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	controller, err := runtimecontroller.New(ControllerISBSVCRollout, mgr, runtimecontroller.Options{Reconciler: r})
@@ -844,6 +949,22 @@ func (r *ISBServiceRolloutReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return fmt.Errorf("failed to watch ISBServiceRollout: %v", err)
 	}
 
+	// Watch the ConfigMaps and Secrets which ISBServiceRollouts can reference in their ValuesFrom (this enqueues the ISBServiceRollouts which reference them)
+	if err := controller.Watch(source.Kind(mgr.GetCache(), &corev1.ConfigMap{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, configMap *corev1.ConfigMap) []reconcile.Request {
+			return r.valuesFromRequests(ctx, configMap)
+		}),
+		predicate.TypedResourceVersionChangedPredicate[*corev1.ConfigMap]{})); err != nil {
+		return fmt.Errorf("failed to watch ConfigMaps: %v", err)
+	}
+	if err := controller.Watch(source.Kind(mgr.GetCache(), ctlrcommon.SecretMetadata(),
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, secret *metav1.PartialObjectMetadata) []reconcile.Request {
+			return r.valuesFromRequests(ctx, &corev1.Secret{ObjectMeta: secret.ObjectMeta})
+		}),
+		predicate.TypedResourceVersionChangedPredicate[*metav1.PartialObjectMetadata]{})); err != nil {
+		return fmt.Errorf("failed to watch Secrets: %v", err)
+	}
+
 	// Watch InterStepBufferServices
 	isbServiceUns := &unstructured.Unstructured{}
 	isbServiceUns.SetGroupVersionKind(schema.GroupVersionKind{
@@ -924,28 +1045,47 @@ func (r *ISBServiceRolloutReconciler) makeTargetISBServiceDef(
 	}
 	metadata.Labels[common.LabelKeyUpgradeState] = string(common.LabelValueUpgradePromoted)
 
-	return r.makeISBServiceDefinition(isbServiceRollout, isbsvcName, metadata)
+	upgradeState, err := ctlrcommon.ResolveTemplatedUpgradeState(ctx, r.client, isbServiceRollout, isbsvcName, common.LabelValueUpgradePromoted)
+	if err != nil {
+		return nil, err
+	}
+
+	isbsvcDef, err := r.makeISBServiceDefinition(ctx, isbServiceRollout, isbsvcName, metadata, upgradeState)
+	if err != nil {
+		return nil, err
+	}
+	ctlrcommon.SetTemplatedUpgradeState(isbsvcDef, upgradeState)
+	return isbsvcDef, nil
 }
 
 // templates are used to dynamically evaluate child spec, metadata, as well as Riders
-func (r *ISBServiceRolloutReconciler) GetTemplateArguments(isbsvc *unstructured.Unstructured) map[string]interface{} {
-	return r.getTemplateArguments(isbsvc.GetName(), isbsvc.GetNamespace())
+func (r *ISBServiceRolloutReconciler) GetTemplateArguments(ctx context.Context, rolloutObject ctlrcommon.RolloutObject, isbsvc *unstructured.Unstructured) (map[string]interface{}, error) {
+	return r.getTemplateArguments(ctx, rolloutObject.(*apiv1.ISBServiceRollout), isbsvc.GetName(), ctlrcommon.GetTemplatedUpgradeState(isbsvc))
 }
 
-func (r *ISBServiceRolloutReconciler) getTemplateArguments(isbsvcName string, namespace string) map[string]interface{} {
-	return map[string]interface{}{
-		TemplateISBServiceName:      isbsvcName,
-		TemplateISBServiceNamespace: namespace,
+func (r *ISBServiceRolloutReconciler) getTemplateArguments(ctx context.Context, isbServiceRollout *apiv1.ISBServiceRollout, isbsvcName string, upgradeState common.UpgradeState) (map[string]interface{}, error) {
+	args, err := ctlrcommon.GetCommonTemplateArguments(ctx, r.client, isbServiceRollout, isbsvcName, upgradeState)
+	if err != nil {
+		return nil, err
 	}
+	args[common.TemplateISBServiceName] = isbsvcName
+	args[common.TemplateISBServiceNamespace] = isbServiceRollout.Namespace
+	return args, nil
 }
 
+// make the definition of an InterstepBufferService, templated with the given upgrade state (see ctlrcommon.ResolveTemplatedUpgradeState)
 func (r *ISBServiceRolloutReconciler) makeISBServiceDefinition(
+	ctx context.Context,
 	isbServiceRollout *apiv1.ISBServiceRollout,
 	isbsvcName string,
 	metadata apiv1.Metadata,
+	upgradeState common.UpgradeState,
 ) (*unstructured.Unstructured, error) {
 
-	args := r.getTemplateArguments(isbsvcName, isbServiceRollout.Namespace)
+	args, err := r.getTemplateArguments(ctx, isbServiceRollout, isbsvcName, upgradeState)
+	if err != nil {
+		return nil, err
+	}
 
 	isbServiceSpec, err := util.ResolveTemplatedSpec(isbServiceRollout.Spec.InterStepBufferService.Spec, args)
 	if err != nil {
@@ -1067,20 +1207,21 @@ func getLiveISBServiceRollout(ctx context.Context, name, namespace string) (*api
 }
 
 // Get the list of Riders that we need based on what's defined in the ISBServiceRollout, templated according to the isbsvc child's name
-// (isbsvcDef is not used and comes from the RolloutController interface)
+// (isbsvcDef is only used for the upgrade state its definition was templated with)
 
-func (r *ISBServiceRolloutReconciler) GetDesiredRiders(rolloutObject ctlrcommon.RolloutObject, isbsvcName string, isbsvcDef *unstructured.Unstructured) ([]riders.Rider, error) {
+func (r *ISBServiceRolloutReconciler) GetDesiredRiders(ctx context.Context, rolloutObject ctlrcommon.RolloutObject, isbsvcName string, isbsvcDef *unstructured.Unstructured) ([]riders.Rider, error) {
 	isbServiceRollout := rolloutObject.(*apiv1.ISBServiceRollout)
+	args, err := r.getTemplateArguments(ctx, isbServiceRollout, isbsvcName, ctlrcommon.GetTemplatedUpgradeState(isbsvcDef))
+	if err != nil {
+		return nil, err
+	}
 	desiredRiders := []riders.Rider{}
 	for _, rider := range isbServiceRollout.Spec.Riders {
 		var asMap map[string]interface{}
 		if err := util.StructToStruct(rider.Definition, &asMap); err != nil {
 			return desiredRiders, fmt.Errorf("rider definition could not converted to map: %w", err)
 		}
-		resolvedMap, err := util.ResolveTemplatedSpec(asMap, map[string]interface{}{
-			TemplateISBServiceName:      isbsvcName,
-			TemplateISBServiceNamespace: isbServiceRollout.Namespace,
-		})
+		resolvedMap, err := util.ResolveTemplatedSpec(asMap, args)
 		if err != nil {
 			return desiredRiders, err
 		}
@@ -7,6 +7,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/numaproj/numaplane/internal/common"
@@ -28,7 +29,11 @@ func (r *ISBServiceRolloutReconciler) CreateUpgradingChildDefinition(ctx context
 	if err != nil {
 		return nil, err
 	}
-	isbsvc, err := r.makeISBServiceDefinition(isbsvcRollout, name, metadata)
+	upgradeState, err := ctlrcommon.ResolveTemplatedUpgradeState(ctx, r.client, isbsvcRollout, name, common.LabelValueUpgradeInProgress)
+	if err != nil {
+		return nil, err
+	}
+	isbsvc, err := r.makeISBServiceDefinition(ctx, isbsvcRollout, name, metadata, upgradeState)
 	if err != nil {
 		return nil, err
 	}
@@ -36,6 +41,7 @@ func (r *ISBServiceRolloutReconciler) CreateUpgradingChildDefinition(ctx context
 	labels := isbsvc.GetLabels()
 	labels[common.LabelKeyUpgradeState] = string(common.LabelValueUpgradeInProgress)
 	isbsvc.SetLabels(labels)
+	ctlrcommon.SetTemplatedUpgradeState(isbsvc, upgradeState)
 
 	return isbsvc, nil
 }
@@ -144,7 +150,8 @@ func (r *ISBServiceRolloutReconciler) CheckForDifferences(ctx context.Context, i
 func (r *ISBServiceRolloutReconciler) CheckForDifferencesWithRolloutDef(ctx context.Context, existingISBSvc *unstructured.Unstructured, rolloutObject ctlrcommon.RolloutObject) (bool, error) {
 	isbsvcRollout := rolloutObject.(*apiv1.ISBServiceRollout)
 
-	rolloutBasedISBSvcDef, err := r.makeISBServiceDefinition(isbsvcRollout, existingISBSvc.GetName(), isbsvcRollout.Spec.InterStepBufferService.Metadata)
+	rolloutBasedISBSvcDef, err := r.makeISBServiceDefinition(ctx, isbsvcRollout, existingISBSvc.GetName(), isbsvcRollout.Spec.InterStepBufferService.Metadata,
+		ctlrcommon.GetTemplatedUpgradeState(existingISBSvc))
 	if err != nil {
 		return false, err
 	}
@@ -198,6 +205,40 @@ func (r *ISBServiceRolloutReconciler) ProcessUpgradingChildPostSuccess(
 	return nil
 }
 
+// ProcessChildrenPostStepAdvance is a no-op since Progressive Steps are not currently supported for ISBServiceRollout
+// This implements a function of the progressiveController interface
+func (r *ISBServiceRolloutReconciler) ProcessChildrenPostStepAdvance(
+	ctx context.Context,
+	rolloutObject progressive.ProgressiveRolloutObject,
+	promotedChildDef, upgradingChildDef *unstructured.Unstructured,
+	c client.Client,
+) (bool, error) {
+	return false, nil
+}
+
+// ProcessPreviousChildPostPromotion is a no-op since post-promotion analysis is not supported for ISBServiceRollout
+// This implements a function of the progressiveController interface
+func (r *ISBServiceRolloutReconciler) ProcessPreviousChildPostPromotion(
+	ctx context.Context,
+	rolloutObject progressive.ProgressiveRolloutObject,
+	previousChildDef *unstructured.Unstructured,
+	c client.Client,
+) (map[string]apiv1.ScaleValues, error) {
+	return nil, nil
+}
+
+// RevivePreviousChild is a no-op since post-promotion analysis is not supported for ISBServiceRollout
+// This implements a function of the progressiveController interface
+func (r *ISBServiceRolloutReconciler) RevivePreviousChild(
+	ctx context.Context,
+	rolloutObject progressive.ProgressiveRolloutObject,
+	previousChildDef *unstructured.Unstructured,
+	scaleValues map[string]apiv1.ScaleValues,
+	c client.Client,
+) error {
+	return nil
+}
+
 func (r *ISBServiceRolloutReconciler) ProcessUpgradingChildPreUpgrade(
 	ctx context.Context,
 	rolloutObject progressive.ProgressiveRolloutObject,
@@ -233,6 +274,12 @@ func (r *ISBServiceRolloutReconciler) UpdateProgressiveMetrics(rolloutObject pro
 	}
 }
 
+// GetRecorder returns the EventRecorder used to emit Events for the Rollout
+// This implements a function of the progressiveController interface
+func (r *ISBServiceRolloutReconciler) GetRecorder() record.EventRecorder {
+	return r.recorder
+}
+
 func (r *ISBServiceRolloutReconciler) ProgressiveUnsupported(ctx context.Context, rolloutObject progressive.ProgressiveRolloutObject) bool {
 
 	return false
@@ -48,7 +48,9 @@ import (
 	"github.com/numaproj/numaplane/internal/common"
 	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
 	"github.com/numaproj/numaplane/internal/controller/common/numaflowtypes"
+	"github.com/numaproj/numaplane/internal/controller/common/revisions"
 	"github.com/numaproj/numaplane/internal/controller/common/riders"
+	"github.com/numaproj/numaplane/internal/controller/common/templates"
 	"github.com/numaproj/numaplane/internal/controller/progressive"
 	"github.com/numaproj/numaplane/internal/usde"
 	"github.com/numaproj/numaplane/internal/util"
@@ -110,8 +112,24 @@ func NewMonoVertexRolloutReconciler(
 	return r
 }
 
+// Rehydrate restores the in-progress upgrade strategies of the MonoVertexRollouts from their Statuses
+func (r *MonoVertexRolloutReconciler) Rehydrate(ctx context.Context, c client.Reader) error {
+	monoVertexRollouts := &apiv1.MonoVertexRolloutList{}
+	if err := c.List(ctx, monoVertexRollouts); err != nil {
+		return fmt.Errorf("error listing MonoVertexRollouts: %w", err)
+	}
+	rollouts := make([]client.Object, len(monoVertexRollouts.Items))
+	for i := range monoVertexRollouts.Items {
+		rollouts[i] = &monoVertexRollouts.Items[i]
+	}
+	r.inProgressStrategyMgr.Rehydrate(ctx, rollouts)
+	return nil
+}
+
 //+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=monovertexrollouts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=monovertexrollouts/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=monovertextemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps;secrets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -127,6 +145,11 @@ func (r *MonoVertexRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	ctx = logger.WithLogger(ctx, numaLogger)
 	r.customMetrics.MonoVertexROSyncs.WithLabelValues().Inc()
 
+	// don't reconcile until the state kept in memory has been rehydrated from the Rollout Statuses
+	if err := ctlrcommon.WaitForRehydration(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Get the live MonoVertexRollout since we need latest Status for Progressive rollout case
 	// TODO: consider storing MonoVertexRollout Status in a local cache instead of this
 	monoVertexRollout, err := getLiveMonovertexRollout(ctx, req.NamespacedName.Name, req.NamespacedName.Namespace)
@@ -157,7 +180,12 @@ func (r *MonoVertexRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 	// Update the resource definition (everything except the Status subresource)
 	if r.needsUpdate(monoVertexRolloutOrig, monoVertexRollout) {
-		if err := r.client.Update(ctx, monoVertexRollout); err != nil {
+		// the in-memory spec may have been resolved from a MonoVertexTemplate or rolled back to a revision, so write back the original spec
+		monoVertexRolloutUpdate := monoVertexRollout.DeepCopy()
+		monoVertexRolloutUpdate.Spec = *monoVertexRolloutOrig.Spec.DeepCopy()
+		err := r.client.Update(ctx, monoVertexRolloutUpdate)
+		monoVertexRollout.ObjectMeta = monoVertexRolloutUpdate.ObjectMeta
+		if err != nil {
 			r.ErrorHandler(ctx, monoVertexRollout, err, "UpdateFailed", "Failed to update MonoVertexRollout")
 			if statusUpdateErr := r.updateMonoVertexRolloutStatusToFailed(ctx, monoVertexRollout, err); statusUpdateErr != nil {
 				r.ErrorHandler(ctx, monoVertexRollout, statusUpdateErr, "UpdateStatusFailed", "Failed to update MonoVertexRollout status")
@@ -215,6 +243,24 @@ func (r *MonoVertexRolloutReconciler) reconcile(ctx context.Context, monoVertexR
 		controllerutil.AddFinalizer(monoVertexRollout, common.FinalizerName)
 	}
 
+	// if the MonoVertex is defined by a MonoVertexTemplate, resolve it into the in-memory spec
+	waitingForTemplate, err := templates.ApplyTemplate(ctx, r.client, monoVertexRollout)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// if the user requested a rollback to a previous revision, deploy that revision's definition instead of the one in the spec
+	rollingBack, err := revisions.ApplyRollbackToRevision(ctx, r.client, monoVertexRollout)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	// if a failed progressive upgrade of this generation was rolled back, keep deploying the last promoted revision
+	if !rollingBack {
+		if _, err := revisions.ApplyAutoRollback(ctx, r.client, monoVertexRollout); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// check if there's a promoted monovertex yet
 	promotedMonovertices, err := ctlrcommon.FindChildrenOfUpgradeState(ctx, monoVertexRollout, common.LabelValueUpgradePromoted, nil, false, r.client)
 	if err != nil {
@@ -267,8 +313,9 @@ func (r *MonoVertexRolloutReconciler) reconcile(ctx context.Context, monoVertexR
 		return ctrl.Result{}, err
 	}
 
-	// if we still have monovertices that need deleting, or if we're in the middle of an upgrade strategy, then requeue
-	if !allDeleted || inProgressStrategy != apiv1.UpgradeStrategyNoOp {
+	// if we still have monovertices that need deleting, or if we're in the middle of an upgrade strategy, or waiting to upgrade to
+	// a new template generation, then requeue
+	if !allDeleted || inProgressStrategy != apiv1.UpgradeStrategyNoOp || waitingForTemplate {
 		if requeueDelay == 0 {
 			requeueDelay = common.DefaultRequeueDelay
 		} else {
@@ -289,7 +336,7 @@ func (r *MonoVertexRolloutReconciler) processExistingMonoVertex(ctx context.Cont
 	numaLogger := logger.FromContext(ctx)
 
 	// get the list of Riders that we need based on the MonoVertexRollout definition
-	currentRiderList, err := r.GetDesiredRiders(monoVertexRollout, existingMonoVertexDef.GetName(), newMonoVertexDef)
+	currentRiderList, err := r.GetDesiredRiders(ctx, monoVertexRollout, existingMonoVertexDef.GetName(), newMonoVertexDef)
 	if err != nil {
 		return 0, fmt.Errorf("error getting desired Riders for MonoVertex %s: %s", existingMonoVertexDef.GetName(), err)
 	}
@@ -299,11 +346,18 @@ func (r *MonoVertexRolloutReconciler) processExistingMonoVertex(ctx context.Cont
 		return 0, fmt.Errorf("error getting existing Riders for MonoVertex %s: %s", existingMonoVertexDef.GetName(), err)
 	}
 
+	// report the upgrade strategy in effect for this MonoVertexRollout
+	effectiveUpgradeStrategy, err := usde.ResolveUserStrategy(ctx, newMonoVertexDef.GetNamespace(), existingMonoVertexDef.GetKind(), monoVertexRollout.GetUpgradeStrategy())
+	if err != nil {
+		return 0, err
+	}
+	monoVertexRollout.Status.EffectiveUpgradeStrategy = &effectiveUpgradeStrategy
+
 	// determine if we're trying to update the MonoVertex spec
 	// if it's a simple change, direct apply
 	// if not and if user-preferred strategy is "Progressive", it will require Progressive rollout to perform the update with guaranteed no-downtime
 	// and capability to rollback an unhealthy one
-	needsUpdate, upgradeStrategyType, _, riderAdditions, riderModifications, riderDeletions, err := usde.ResourceNeedsUpdating(ctx, newMonoVertexDef, existingMonoVertexDef, currentRiderList, existingRiderList)
+	needsUpdate, upgradeStrategyType, _, riderAdditions, riderModifications, riderDeletions, err := usde.ResourceNeedsUpdating(ctx, newMonoVertexDef, existingMonoVertexDef, currentRiderList, existingRiderList, monoVertexRollout.GetUpgradeStrategy())
 	if err != nil {
 		return 0, err
 	}
@@ -324,7 +378,28 @@ func (r *MonoVertexRolloutReconciler) processExistingMonoVertex(ctx context.Cont
 	inProgressStrategy := r.inProgressStrategyMgr.GetStrategy(ctx, monoVertexRollout)
 	inProgressStrategySet := (inProgressStrategy != apiv1.UpgradeStrategyNoOp)
 
-	// if not, should we set one?
+	// if approval of the upgrade plan is required, don't start upgrading until it's approved
+	requiredBySpec := monoVertexRollout.Spec.Strategy != nil && monoVertexRollout.Spec.Strategy.RequireApprovalOfPlan
+	if needsUpdate && !inProgressStrategySet && ctlrcommon.UpgradePlanApprovalRequired(monoVertexRollout, requiredBySpec) {
+		definitionHash, err := ctlrcommon.UpgradeDefinitionHash(newMonoVertexDef, riderAdditions, riderModifications, riderDeletions)
+		if err != nil {
+			return 0, err
+		}
+		approved, err := ctlrcommon.ProcessUpgradePlan(ctx, monoVertexRollout, definitionHash, func() (*apiv1.UpgradePlan, error) {
+			nameCount, _ := r.getCurrentChildCount(monoVertexRollout)
+			return ctlrcommon.NewUpgradePlan(monoVertexRollout, newMonoVertexDef, existingMonoVertexDef, upgradeStrategyType, false,
+				riderAdditions, riderModifications, riderDeletions, fmt.Sprintf("%s-%d", monoVertexRollout.Name, nameCount), nil)
+		}, r.recorder)
+		if err != nil {
+			return 0, err
+		}
+		if !approved {
+			numaLogger.Debug("waiting for upgrade plan to be approved")
+			return common.DefaultRequeueDelay, nil
+		}
+	}
+
+	// if there is no inProgressStrategy, should we set one?
 	if !inProgressStrategySet {
 		if upgradeStrategyType == apiv1.UpgradeStrategyProgressive {
 			inProgressStrategy = apiv1.UpgradeStrategyProgressive
@@ -361,7 +436,7 @@ func (r *MonoVertexRolloutReconciler) processExistingMonoVertex(ctx context.Cont
 			if err != nil {
 				return 0, err
 			}
-			currentRiderList, err := r.GetDesiredRiders(monoVertexRollout, promotedMonoVertex.GetName(), promotedMonoVertex)
+			currentRiderList, err := r.GetDesiredRiders(ctx, monoVertexRollout, promotedMonoVertex.GetName(), promotedMonoVertex)
 			if err != nil {
 				return 0, fmt.Errorf("error getting desired Riders for MonoVertex %s: %s", newMonoVertexDef.GetName(), err)
 			}
@@ -405,12 +480,31 @@ func (r *MonoVertexRolloutReconciler) processExistingMonoVertex(ctx context.Cont
 
 			// update the list of riders in the Status
 			r.SetCurrentRiderList(ctx, monoVertexRollout, currentRiderList)
+
+			if err := revisions.RecordRevision(ctx, r.client, monoVertexRollout, newMonoVertexDef.GetName(), revisions.OutcomePromoted); err != nil {
+				numaLogger.Error(err, "failed to record revision")
+			}
 		}
 	}
 
 	return requeueDelay, nil
 }
 
+// get the reconcile requests for the MonoVertexRollouts which reference the ConfigMap or Secret in their ValuesFrom
+func (r *MonoVertexRolloutReconciler) valuesFromRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	monoVertexRolloutList := &apiv1.MonoVertexRolloutList{}
+	if err := r.client.List(ctx, monoVertexRolloutList, client.InNamespace(obj.GetNamespace())); err != nil {
+		logger.FromContext(ctx).WithValues("object", fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())).
+			Error(err, "failed to list the MonoVertexRollouts which may reference the object in their ValuesFrom")
+		return nil
+	}
+	monoVertexRollouts := make([]*apiv1.MonoVertexRollout, 0, len(monoVertexRolloutList.Items))
+	for i := range monoVertexRolloutList.Items {
+		monoVertexRollouts = append(monoVertexRollouts, &monoVertexRolloutList.Items[i])
+	}
+	return ctlrcommon.ValuesFromRequests(monoVertexRollouts, obj)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *MonoVertexRolloutReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 
@@ -427,6 +521,31 @@ func (r *MonoVertexRolloutReconciler) SetupWithManager(ctx context.Context, mgr
 		return fmt.Errorf("failed to watch MonoVertexRollouts: %w", err)
 	}
 
+	// Watch MonoVertexTemplates (this enqueues the MonoVertexRollouts which reference the MonoVertexTemplate)
+	if err := controller.Watch(source.Kind(mgr.GetCache(), &apiv1.MonoVertexTemplate{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, monoVertexTemplate *apiv1.MonoVertexTemplate) []reconcile.Request {
+			return templates.ReferencingRolloutRequests(ctx, r.client, monoVertexTemplate)
+		}),
+		ctlrcommon.TypedGenerationChangedPredicate[*apiv1.MonoVertexTemplate]{})); err != nil {
+		return fmt.Errorf("failed to watch MonoVertexTemplates: %w", err)
+	}
+
+	// Watch the ConfigMaps and Secrets which MonoVertexRollouts can reference in their ValuesFrom (this enqueues the MonoVertexRollouts which reference them)
+	if err := controller.Watch(source.Kind(mgr.GetCache(), &corev1.ConfigMap{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, configMap *corev1.ConfigMap) []reconcile.Request {
+			return r.valuesFromRequests(ctx, configMap)
+		}),
+		predicate.TypedResourceVersionChangedPredicate[*corev1.ConfigMap]{})); err != nil {
+		return fmt.Errorf("failed to watch ConfigMaps: %w", err)
+	}
+	if err := controller.Watch(source.Kind(mgr.GetCache(), ctlrcommon.SecretMetadata(),
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, secret *metav1.PartialObjectMetadata) []reconcile.Request {
+			return r.valuesFromRequests(ctx, &corev1.Secret{ObjectMeta: secret.ObjectMeta})
+		}),
+		predicate.TypedResourceVersionChangedPredicate[*metav1.PartialObjectMetadata]{})); err != nil {
+		return fmt.Errorf("failed to watch Secrets: %w", err)
+	}
+
 	// Watch MonoVertices
 	monoVertexUns := &unstructured.Unstructured{}
 	monoVertexUns.SetGroupVersionKind(schema.GroupVersionKind{
@@ -703,28 +822,47 @@ func (r *MonoVertexRolloutReconciler) makeTargetMonoVertexDefinition(
 	}
 	metadata.Labels[common.LabelKeyUpgradeState] = string(common.LabelValueUpgradePromoted)
 
-	return r.makeMonoVertexDefinition(monoVertexRollout, monoVertexName, metadata)
+	upgradeState, err := ctlrcommon.ResolveTemplatedUpgradeState(ctx, r.client, monoVertexRollout, monoVertexName, common.LabelValueUpgradePromoted)
+	if err != nil {
+		return nil, err
+	}
+
+	monoVertexDef, err := r.makeMonoVertexDefinition(ctx, monoVertexRollout, monoVertexName, metadata, upgradeState)
+	if err != nil {
+		return nil, err
+	}
+	ctlrcommon.SetTemplatedUpgradeState(monoVertexDef, upgradeState)
+	return monoVertexDef, nil
 }
 
 // templates are used to dynamically evaluate child spec, metadata, as well as Riders
-func (r *MonoVertexRolloutReconciler) GetTemplateArguments(monovertex *unstructured.Unstructured) map[string]interface{} {
-	return r.getTemplateArguments(monovertex.GetName(), monovertex.GetNamespace())
+func (r *MonoVertexRolloutReconciler) GetTemplateArguments(ctx context.Context, rolloutObject ctlrcommon.RolloutObject, monovertex *unstructured.Unstructured) (map[string]interface{}, error) {
+	return r.getTemplateArguments(ctx, rolloutObject.(*apiv1.MonoVertexRollout), monovertex.GetName(), ctlrcommon.GetTemplatedUpgradeState(monovertex))
 }
 
-func (r *MonoVertexRolloutReconciler) getTemplateArguments(monovertexName string, namespace string) map[string]interface{} {
-	return map[string]interface{}{
-		common.TemplateMonoVertexName:      monovertexName,
-		common.TemplateMonoVertexNamespace: namespace,
+func (r *MonoVertexRolloutReconciler) getTemplateArguments(ctx context.Context, monoVertexRollout *apiv1.MonoVertexRollout, monovertexName string, upgradeState common.UpgradeState) (map[string]interface{}, error) {
+	args, err := ctlrcommon.GetCommonTemplateArguments(ctx, r.client, monoVertexRollout, monovertexName, upgradeState)
+	if err != nil {
+		return nil, err
 	}
+	args[common.TemplateMonoVertexName] = monovertexName
+	args[common.TemplateMonoVertexNamespace] = monoVertexRollout.Namespace
+	return args, nil
 }
 
+// make the definition of a MonoVertex, templated with the given upgrade state (see ctlrcommon.ResolveTemplatedUpgradeState)
 func (r *MonoVertexRolloutReconciler) makeMonoVertexDefinition(
+	ctx context.Context,
 	monoVertexRollout *apiv1.MonoVertexRollout,
 	monoVertexName string,
 	metadata apiv1.Metadata,
+	upgradeState common.UpgradeState,
 ) (*unstructured.Unstructured, error) {
 
-	args := r.getTemplateArguments(monoVertexName, monoVertexRollout.Namespace)
+	args, err := r.getTemplateArguments(ctx, monoVertexRollout, monoVertexName, upgradeState)
+	if err != nil {
+		return nil, err
+	}
 
 	monoVertexSpec, err := util.ResolveTemplatedSpec(monoVertexRollout.Spec.MonoVertex.Spec, args)
 	if err != nil {
@@ -768,6 +906,10 @@ func (r *MonoVertexRolloutReconciler) createPromotedMonoVertex(ctx context.Conte
 		return fmt.Errorf("error creating riders: %s", err)
 	}
 
+	if err := revisions.RecordRevision(ctx, r.client, monoVertexRollout, newMonoVertexDef.GetName(), revisions.OutcomePromoted); err != nil {
+		logger.FromContext(ctx).Error(err, "failed to record revision")
+	}
+
 	// if user somehow has no Promoted MonoVertex and is in the middle of Progressive, this isn't right - user may have deleted the Promoted MonoVertex
 	// if this happens, we need to stop the Progressive upgrade and remove any Upgrading children
 	inProgressStrategy := r.inProgressStrategyMgr.GetStrategy(ctx, monoVertexRollout)
@@ -846,19 +988,20 @@ func getLiveMonovertexRollout(ctx context.Context, name, namespace string) (*api
 }
 
 // Get the list of Riders that we need based on what's defined in the MonoVertexRollout, templated according to the monoVertex child's name
-// (monoVertexDef is not used and comes from the RolloutController interface)
-func (r *MonoVertexRolloutReconciler) GetDesiredRiders(rolloutObject ctlrcommon.RolloutObject, monoVertexName string, monoVertexDef *unstructured.Unstructured) ([]riders.Rider, error) {
+// (monoVertexDef is only used for the upgrade state its definition was templated with)
+func (r *MonoVertexRolloutReconciler) GetDesiredRiders(ctx context.Context, rolloutObject ctlrcommon.RolloutObject, monoVertexName string, monoVertexDef *unstructured.Unstructured) ([]riders.Rider, error) {
 	monoVertexRollout := rolloutObject.(*apiv1.MonoVertexRollout)
+	args, err := r.getTemplateArguments(ctx, monoVertexRollout, monoVertexName, ctlrcommon.GetTemplatedUpgradeState(monoVertexDef))
+	if err != nil {
+		return nil, err
+	}
 	desiredRiders := []riders.Rider{}
 	for _, rider := range monoVertexRollout.Spec.Riders {
 		var asMap map[string]interface{}
 		if err := util.StructToStruct(rider.Definition, &asMap); err != nil {
 			return desiredRiders, fmt.Errorf("rider definition could not converted to map: %w", err)
 		}
-		resolvedMap, err := util.ResolveTemplatedSpec(asMap, map[string]interface{}{
-			common.TemplateMonoVertexName:      monoVertexName,
-			common.TemplateMonoVertexNamespace: monoVertexRollout.Namespace,
-		})
+		resolvedMap, err := util.ResolveTemplatedSpec(asMap, args)
 		if err != nil {
 			return desiredRiders, err
 		}
@@ -9,6 +9,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
@@ -32,7 +33,11 @@ func (r *MonoVertexRolloutReconciler) CreateUpgradingChildDefinition(ctx context
 	if err != nil {
 		return nil, err
 	}
-	monoVertex, err := r.makeMonoVertexDefinition(monoVertexRollout, name, metadata)
+	upgradeState, err := ctlrcommon.ResolveTemplatedUpgradeState(ctx, r.client, monoVertexRollout, name, common.LabelValueUpgradeInProgress)
+	if err != nil {
+		return nil, err
+	}
+	monoVertex, err := r.makeMonoVertexDefinition(ctx, monoVertexRollout, name, metadata, upgradeState)
 	if err != nil {
 		return nil, err
 	}
@@ -40,6 +45,7 @@ func (r *MonoVertexRolloutReconciler) CreateUpgradingChildDefinition(ctx context
 	labels := monoVertex.GetLabels()
 	labels[common.LabelKeyUpgradeState] = string(common.LabelValueUpgradeInProgress)
 	monoVertex.SetLabels(labels)
+	ctlrcommon.SetTemplatedUpgradeState(monoVertex, upgradeState)
 
 	return monoVertex, nil
 }
@@ -78,28 +84,11 @@ func (r *MonoVertexRolloutReconciler) AssessUpgradingChild(
 		}
 
 		// if we fail once, it's okay: we'll check again later
-		if assessment == apiv1.AssessmentResultFailure {
-			numaLogger.Debugf("Assessment failed for upgrading child %s, checking again...", existingUpgradingChildDef.GetName())
-			_ = progressive.UpdateUpgradingChildStatus(mvtxRollout, func(status *apiv1.UpgradingChildStatus) {
-				status.TrialWindowStartTime = nil
-				status.AssessmentResult = apiv1.AssessmentResultUnknown
-			})
-			return apiv1.AssessmentResultUnknown, "", nil
-		}
-
 		// if we succeed, we must continue to succeed for a prescribed period of time to consider the resource health
 		// check "successful".
-		if assessment == apiv1.AssessmentResultSuccess {
-			if !childStatus.IsTrialWindowStartTimeSet() {
-				_ = progressive.UpdateUpgradingChildStatus(mvtxRollout, func(status *apiv1.UpgradingChildStatus) {
-					status.TrialWindowStartTime = &metav1.Time{Time: currentTime}
-					status.AssessmentResult = apiv1.AssessmentResultUnknown
-				})
-				numaLogger.Debugf("Assessment succeeded for upgrading child %s, setting TrialWindowStartTime to %s", existingUpgradingChildDef.GetName(), currentTime)
-			}
-
-			// Check if the trial window is set and if the success window has passed.
-			if childStatus.IsTrialWindowStartTimeSet() && currentTime.Sub(childStatus.TrialWindowStartTime.Time) >= assessmentSchedule.Period {
+		if assessment == apiv1.AssessmentResultFailure || assessment == apiv1.AssessmentResultSuccess {
+			healthy := assessment == apiv1.AssessmentResultSuccess
+			if progressive.RecordBasicHealthCheck(mvtxRollout, healthy, assessmentSchedule, currentTime) {
 				// Success window passed, launch AnalysisRuns or declare success
 				_ = progressive.UpdateUpgradingChildStatus(mvtxRollout, func(status *apiv1.UpgradingChildStatus) {
 					status.BasicAssessmentEndTime = &metav1.Time{Time: currentTime}
@@ -108,7 +97,11 @@ func (r *MonoVertexRolloutReconciler) AssessUpgradingChild(
 				return r.checkAnalysisTemplates(ctx, mvtxRollout, existingUpgradingChildDef)
 			}
 
-			numaLogger.Debugf("Assessment succeeded for upgrading child %s, but success window has not passed yet", existingUpgradingChildDef.GetName())
+			if healthy {
+				numaLogger.Debugf("Assessment succeeded for upgrading child %s, but success window has not passed yet", existingUpgradingChildDef.GetName())
+			} else {
+				numaLogger.Debugf("Assessment failed for upgrading child %s, checking again...", existingUpgradingChildDef.GetName())
+			}
 			// Still waiting for a success window to pass
 			return apiv1.AssessmentResultUnknown, "", nil
 		}
@@ -129,14 +122,34 @@ func (r *MonoVertexRolloutReconciler) checkAnalysisTemplates(ctx context.Context
 	existingUpgradingChildDef *unstructured.Unstructured) (apiv1.AssessmentResult, string, error) {
 
 	numaLogger := logger.FromContext(ctx)
-	analysis := mvtxRollout.GetAnalysis()
-	// only check for and create AnalysisRun if templates are specified
-	if len(analysis.Templates) > 0 {
-		// this will create an AnalysisRun if it doesn't exist yet; or otherwise it will check if it's finished running
-		numaLogger.Debugf("Performing analysis for upgrading child %s", existingUpgradingChildDef.GetName())
-		analysisStatus, err := progressive.PerformAnalysis(ctx, existingUpgradingChildDef, mvtxRollout, mvtxRollout.GetAnalysis(), mvtxRollout.GetAnalysisStatus(), r.client)
-		if err != nil {
-			return apiv1.AssessmentResultUnknown, "", err
+
+	// a user may have requested to skip the analysis of this upgrading child
+	if progressive.IsAnalysisSkipped(mvtxRollout, existingUpgradingChildDef.GetName()) {
+		numaLogger.Debugf("Skipping analysis for upgrading child %s as requested by user", existingUpgradingChildDef.GetName())
+		return apiv1.AssessmentResultSuccess, "", nil
+	}
+
+	// if the current Progressive Step defines its own Analysis, it overrides the Rollout's Analysis
+	analysis := progressive.GetStepAnalysis(mvtxRollout, mvtxRollout.GetAnalysis())
+	// only perform analysis if templates or metrics are specified
+	if len(analysis.Templates) > 0 || len(analysis.Metrics) > 0 {
+		analysisStatus := mvtxRollout.GetAnalysisStatus()
+		var err error
+		if len(analysis.Templates) > 0 {
+			// this will create an AnalysisRun if it doesn't exist yet; or otherwise it will check if it's finished running
+			numaLogger.Debugf("Performing analysis for upgrading child %s", existingUpgradingChildDef.GetName())
+			analysisStatus, err = progressive.PerformAnalysis(ctx, existingUpgradingChildDef, mvtxRollout, analysis, analysisStatus, r.client)
+			if err != nil {
+				return apiv1.AssessmentResultUnknown, "", err
+			}
+		}
+		if len(analysis.Metrics) > 0 {
+			// this measures any Metrics which are due for measurement, without requiring Argo Rollouts
+			numaLogger.Debugf("Measuring analysis metrics for upgrading child %s", existingUpgradingChildDef.GetName())
+			analysisStatus, err = progressive.PerformMetricAnalysis(ctx, existingUpgradingChildDef, mvtxRollout, analysis, analysisStatus)
+			if err != nil {
+				return apiv1.AssessmentResultUnknown, "", err
+			}
 		}
 		return progressive.AssessAnalysisStatus(ctx, existingUpgradingChildDef, analysisStatus)
 	}
@@ -200,8 +213,9 @@ func (r *MonoVertexRolloutReconciler) CheckForDifferencesWithRolloutDef(ctx cont
 	monoVertexRollout := rolloutObject.(*apiv1.MonoVertexRollout)
 
 	// In order to effectively compare, we need to create a MonoVertex Definition from the MonoVertexRollout which uses the same name as our current MonoVertex
-	// (so that won't be interpreted as a difference)
-	rolloutBasedMVDef, err := r.makeMonoVertexDefinition(monoVertexRollout, existingMonoVertex.GetName(), monoVertexRollout.Spec.MonoVertex.Metadata)
+	// (so that won't be interpreted as a difference), templated with the same upgrade state
+	rolloutBasedMVDef, err := r.makeMonoVertexDefinition(ctx, monoVertexRollout, existingMonoVertex.GetName(), monoVertexRollout.Spec.MonoVertex.Metadata,
+		ctlrcommon.GetTemplatedUpgradeState(existingMonoVertex))
 	if err != nil {
 		return false, err
 	}
@@ -245,7 +259,8 @@ func (r *MonoVertexRolloutReconciler) ProcessPromotedChildPreUpgrade(
 		return true, errors.New("unable to perform pre-upgrade operations because the rollout does not have promotedChildStatus set")
 	}
 
-	requeue, err := computePromotedMonoVertexScaleValues(ctx, monoVertexRollout.Status.ProgressiveStatus.PromotedMonoVertexStatus, promotedMonoVertexDef, c)
+	// the first Progressive Step (if any) determines how many Pods to shift to the upgrading monovertex initially
+	requeue, err := computePromotedMonoVertexScaleValues(ctx, monoVertexRollout.Status.ProgressiveStatus.PromotedMonoVertexStatus, promotedMonoVertexDef, progressive.GetStepWeight(monoVertexRollout, 0), c)
 	if err != nil {
 		return true, err
 	}
@@ -398,6 +413,74 @@ func (r *MonoVertexRolloutReconciler) ProcessUpgradingChildPostSuccess(
 	return nil
 }
 
+/*
+ProcessChildrenPostStepAdvance handles the promoted and upgrading monovertices after the upgrade advances to the next Progressive Step.
+It performs the following operations:
+- it shifts Pods from the promoted monovertex to the upgrading monovertex based on the weight of the new Step
+- it resets the analysis status so that a new AnalysisRun is performed for the new Step
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - rolloutObject: the MonoVertexRollout instance
+  - promotedMonoVertexDef: the definition of the promoted monovertex as an unstructured object.
+  - upgradingMonoVertexDef: the definition of the upgrading monovertex as an unstructured object.
+  - c: the client used for interacting with the Kubernetes API.
+
+Returns:
+  - A boolean indicating whether we should requeue.
+  - An error if any issues occur during processing.
+*/
+func (r *MonoVertexRolloutReconciler) ProcessChildrenPostStepAdvance(
+	ctx context.Context,
+	rolloutObject progressive.ProgressiveRolloutObject,
+	promotedMonoVertexDef, upgradingMonoVertexDef *unstructured.Unstructured,
+	c client.Client,
+) (bool, error) {
+
+	numaLogger := logger.FromContext(ctx).WithName("ProcessChildrenPostStepAdvance").WithName("MonoVertexRollout").
+		WithValues("promotedMonoVertexName", promotedMonoVertexDef.GetName(), "upgradingMonoVertexName", upgradingMonoVertexDef.GetName())
+
+	monoVertexRollout, ok := rolloutObject.(*apiv1.MonoVertexRollout)
+	if !ok {
+		return true, fmt.Errorf("unexpected type for ProgressiveRolloutObject: %+v; can't process monovertices post-step-advance", rolloutObject)
+	}
+
+	promotedMVStatus := monoVertexRollout.Status.ProgressiveStatus.PromotedMonoVertexStatus
+	if promotedMVStatus == nil || promotedMVStatus.ScaleValues == nil {
+		return true, errors.New("unable to advance to the next step because the rollout does not have promotedChildStatus scaleValues set")
+	}
+	scaleValue, found := promotedMVStatus.ScaleValues[promotedMonoVertexDef.GetName()]
+	if !found {
+		return true, fmt.Errorf("unable to advance to the next step because the rollout does not have scaleValues for promoted monovertex %s", promotedMonoVertexDef.GetName())
+	}
+
+	stepIndex := progressive.GetCurrentStepIndex(monoVertexRollout)
+	weight := progressive.GetStepWeight(monoVertexRollout, stepIndex)
+	promotedScaleTo := progressive.CalculateScaleMinMaxValuesForWeight(int(scaleValue.Initial), weight)
+	upgradingScaleTo := scaleValue.Initial - promotedScaleTo
+	if upgradingScaleTo <= 0 {
+		upgradingScaleTo = 1
+	}
+
+	// scale up the upgrading monovertex before scaling down the promoted one, so that the total number of Pods doesn't drop
+	if err := scaleMonoVertex(ctx, upgradingMonoVertexDef, &apiv1.ScaleDefinition{Min: &upgradingScaleTo, Max: &upgradingScaleTo, Disabled: false}, c); err != nil {
+		return true, fmt.Errorf("error scaling the upgrading monovertex for step %d: %w", stepIndex, err)
+	}
+	if err := scaleMonoVertex(ctx, promotedMonoVertexDef, &apiv1.ScaleDefinition{Min: &promotedScaleTo, Max: &promotedScaleTo, Disabled: false}, c); err != nil {
+		return true, fmt.Errorf("error scaling the promoted monovertex for step %d: %w", stepIndex, err)
+	}
+
+	scaleValue.ScaleTo = promotedScaleTo
+	promotedMVStatus.ScaleValues[promotedMonoVertexDef.GetName()] = scaleValue
+
+	monoVertexRollout.SetAnalysisStatus(&apiv1.AnalysisStatus{})
+
+	numaLogger.WithValues("step", stepIndex, "weight", weight, "promotedScaleTo", promotedScaleTo, "upgradingScaleTo", upgradingScaleTo).
+		Debug("shifted pods for next progressive step")
+
+	return false, nil
+}
+
 /*
 ProcessUpgradingChildPreUpgrade handles the processing of an upgrading monovertex definition before it's been created
 It performs the following pre-upgrade operations:
@@ -575,6 +658,7 @@ Parameters:
 - ctx: the context for managing request-scoped values.
 - promotedMVStatus: the status of the promoted monovertex in the rollout.
 - promotedMonoVertexDef: the unstructured object representing the promoted monovertex definition.
+- weight: the percentage of Pods to shift from the promoted monovertex to the upgrading monovertex.
 - c: the Kubernetes client for resource operations.
 
 Returns:
@@ -585,6 +669,7 @@ func computePromotedMonoVertexScaleValues(
 	ctx context.Context,
 	promotedMVStatus *apiv1.PromotedMonoVertexStatus,
 	promotedMonoVertexDef *unstructured.Unstructured,
+	weight int32,
 	c client.Client,
 ) (bool, error) {
 
@@ -612,7 +697,7 @@ func computePromotedMonoVertexScaleValues(
 		return true, fmt.Errorf("cannot extract the scale min and max values from the promoted monovertex: %w", err)
 	}
 
-	scaleTo := progressive.CalculateScaleMinMaxValues(int(currentPodsCount))
+	scaleTo := progressive.CalculateScaleMinMaxValuesForWeight(int(currentPodsCount), weight)
 	newMin := scaleTo
 	newMax := scaleTo
 
@@ -734,6 +819,76 @@ func scaleDefinitionToPatchString(scaleDefinition *apiv1.ScaleDefinition) string
 	return scaleValue
 }
 
+/*
+ProcessPreviousChildPostPromotion handles the previously promoted monovertex when it's put on standby for the post-promotion analysis of the
+newly promoted monovertex: it scales it to zero and returns its original scale values, so that it can be revived later.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - rolloutObject: the MonoVertexRollout instance
+  - previousMonoVertexDef: the definition of the previously promoted monovertex as an unstructured object.
+  - c: the client used for interacting with the Kubernetes API.
+
+Returns:
+  - The original scale values of the monovertex, keyed by its name.
+  - An error if any issues occur during processing.
+*/
+func (r *MonoVertexRolloutReconciler) ProcessPreviousChildPostPromotion(
+	ctx context.Context,
+	rolloutObject progressive.ProgressiveRolloutObject,
+	previousMonoVertexDef *unstructured.Unstructured,
+	c client.Client,
+) (map[string]apiv1.ScaleValues, error) {
+
+	monoVertexRollout, ok := rolloutObject.(*apiv1.MonoVertexRollout)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type for ProgressiveRolloutObject: %+v; can't process previous monovertex post-promotion", rolloutObject)
+	}
+
+	// the monovertex was scaled down during the upgrade, so its original scale values are those stored in the promoted status if present
+	promotedMVStatus := monoVertexRollout.Status.ProgressiveStatus.PromotedMonoVertexStatus
+	var scaleValues map[string]apiv1.ScaleValues
+	if promotedMVStatus != nil && promotedMVStatus.Name == previousMonoVertexDef.GetName() && promotedMVStatus.ScaleValues != nil {
+		scaleValues = map[string]apiv1.ScaleValues{previousMonoVertexDef.GetName(): promotedMVStatus.ScaleValues[previousMonoVertexDef.GetName()]}
+	} else {
+		originalScaleMinMax, err := progressive.ExtractScaleMinMaxAsJSONString(previousMonoVertexDef.Object, []string{"spec", "scale"})
+		if err != nil {
+			return nil, fmt.Errorf("cannot extract the scale min and max values from the previous monovertex: %w", err)
+		}
+		scaleValues = map[string]apiv1.ScaleValues{previousMonoVertexDef.GetName(): {OriginalScaleMinMax: originalScaleMinMax}}
+	}
+
+	zero := int64(0)
+	if err := scaleMonoVertex(ctx, previousMonoVertexDef, &apiv1.ScaleDefinition{Min: &zero, Max: &zero}, c); err != nil {
+		return nil, fmt.Errorf("error scaling the previous monovertex to zero: %w", err)
+	}
+
+	return scaleValues, nil
+}
+
+// RevivePreviousChild scales the previously promoted monovertex back to its original scale values
+// This implements a function of the progressiveController interface
+func (r *MonoVertexRolloutReconciler) RevivePreviousChild(
+	ctx context.Context,
+	rolloutObject progressive.ProgressiveRolloutObject,
+	previousMonoVertexDef *unstructured.Unstructured,
+	scaleValues map[string]apiv1.ScaleValues,
+	c client.Client,
+) error {
+	return scalePromotedMonoVertexToOriginalValues(ctx, &apiv1.PromotedMonoVertexStatus{
+		PromotedPipelineTypeStatus: apiv1.PromotedPipelineTypeStatus{
+			PromotedChildStatus: apiv1.PromotedChildStatus{Name: previousMonoVertexDef.GetName()},
+			ScaleValues:         scaleValues,
+		},
+	}, previousMonoVertexDef, c)
+}
+
+// GetRecorder returns the EventRecorder used to emit Events for the Rollout
+// This implements a function of the progressiveController interface
+func (r *MonoVertexRolloutReconciler) GetRecorder() record.EventRecorder {
+	return r.recorder
+}
+
 func (r *MonoVertexRolloutReconciler) ProgressiveUnsupported(ctx context.Context, rolloutObject progressive.ProgressiveRolloutObject) bool {
 	numaLogger := logger.FromContext(ctx)
 
@@ -490,6 +490,13 @@ func determineTargetObjects(
 	return targetObjs, nil
 }
 
+// ValidateControllerDefinition verifies that a definition exists for the NumaflowController's version, and that its
+// manifests can be resolved for the NumaflowController
+func ValidateControllerDefinition(controller *apiv1.NumaflowController) error {
+	_, err := determineTargetObjects(controller, controller.Spec.Version, controller.Namespace)
+	return err
+}
+
 func (r *NumaflowControllerReconciler) sync(
 	controller *apiv1.NumaflowController,
 	namespace string,
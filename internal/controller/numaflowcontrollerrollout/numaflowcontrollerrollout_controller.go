@@ -103,6 +103,20 @@ func NewNumaflowControllerRolloutReconciler(
 	}
 }
 
+// Rehydrate restores the in-progress upgrade strategies of the NumaflowControllerRollouts from their Statuses
+func (r *NumaflowControllerRolloutReconciler) Rehydrate(ctx context.Context, c client.Reader) error {
+	nfcRollouts := &apiv1.NumaflowControllerRolloutList{}
+	if err := c.List(ctx, nfcRollouts); err != nil {
+		return fmt.Errorf("error listing NumaflowControllerRollouts: %w", err)
+	}
+	rollouts := make([]client.Object, len(nfcRollouts.Items))
+	for i := range nfcRollouts.Items {
+		rollouts[i] = &nfcRollouts.Items[i]
+	}
+	r.inProgressStrategyMgr.Rehydrate(ctx, rollouts)
+	return nil
+}
+
 //+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=numaflowcontrollerrollouts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=numaflowcontrollerrollouts/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=numaflowcontrollerrollouts/finalizers,verbs=update
@@ -119,6 +133,11 @@ func (r *NumaflowControllerRolloutReconciler) Reconcile(ctx context.Context, req
 	ctx = logger.WithLogger(ctx, numaLogger)
 	r.customMetrics.NumaflowControllerRolloutSyncs.WithLabelValues().Inc()
 
+	// don't reconcile until the state kept in memory has been rehydrated from the Rollout Statuses
+	if err := ctlrcommon.WaitForRehydration(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	numaflowControllerRollout := &apiv1.NumaflowControllerRollout{}
 	if err := r.client.Get(ctx, req.NamespacedName, numaflowControllerRollout); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -324,7 +343,8 @@ func (r *NumaflowControllerRolloutReconciler) processExistingNumaflowController(
 	// determine if we're trying to update the NumaflowController spec
 	// if it's a simple change, direct apply
 	// if not, it will require PPND or Progressive
-	numaflowControllerNeedsToUpdate, upgradeStrategyType, _, _, _, _, err := usde.ResourceNeedsUpdating(ctx, newNumaflowControllerDef, existingNumaflowControllerDef, []riders.Rider{}, unstructured.UnstructuredList{})
+	// (NumaflowControllerRollout doesn't select its own upgrade strategy, so the namespace or global strategy applies)
+	numaflowControllerNeedsToUpdate, upgradeStrategyType, _, _, _, _, err := usde.ResourceNeedsUpdating(ctx, newNumaflowControllerDef, existingNumaflowControllerDef, []riders.Rider{}, unstructured.UnstructuredList{}, "")
 	if err != nil {
 		return false, err
 	}
@@ -346,6 +366,19 @@ func (r *NumaflowControllerRolloutReconciler) processExistingNumaflowController(
 	inProgressStrategy := r.inProgressStrategyMgr.GetStrategy(ctx, nfcRollout)
 	inProgressStrategySet := (inProgressStrategy != apiv1.UpgradeStrategyNoOp)
 
+	// if approval of the upgrade plan is required, don't start upgrading until it's approved
+	// (NumaflowControllerRollout doesn't have a strategy in its spec, so it's only required by annotation)
+	if numaflowControllerNeedsToUpdate && !inProgressStrategySet && ctlrcommon.UpgradePlanApprovalRequired(nfcRollout, false) {
+		approved, err := r.processUpgradePlan(ctx, nfcRollout, existingNumaflowControllerDef, newNumaflowControllerDef, upgradeStrategyType)
+		if err != nil {
+			return false, err
+		}
+		if !approved {
+			numaLogger.Debug("waiting for upgrade plan to be approved")
+			return true, nil
+		}
+	}
+
 	// if not, should we set one?
 	if !inProgressStrategySet {
 		if upgradeStrategyType == apiv1.UpgradeStrategyPPND {
@@ -366,7 +399,7 @@ func (r *NumaflowControllerRolloutReconciler) processExistingNumaflowController(
 
 	switch inProgressStrategy {
 	case apiv1.UpgradeStrategyPPND:
-		done, err := ppnd.ProcessChildObjectWithPPND(ctx, r.client, nfcRollout, r, numaflowControllerNeedsToUpdate, numaflowControllerIsUpdating, func() error {
+		done, err := ppnd.ProcessChildObjectWithPPND(ctx, r.client, r.recorder, nfcRollout, r, numaflowControllerNeedsToUpdate, numaflowControllerIsUpdating, func() error {
 			r.recorder.Eventf(nfcRollout, corev1.EventTypeNormal, "PipelinesPaused", "All Pipelines have paused for NumaflowController update")
 			err = r.updateNumaflowController(ctx, nfcRollout, newNumaflowControllerDef)
 			if err != nil {
@@ -401,6 +434,38 @@ func (r *NumaflowControllerRolloutReconciler) processExistingNumaflowController(
 	return false, nil
 }
 
+// processUpgradePlan publishes the UpgradePlan for the new NumaflowController definition and determines if it's been approved
+func (r *NumaflowControllerRolloutReconciler) processUpgradePlan(
+	ctx context.Context,
+	nfcRollout *apiv1.NumaflowControllerRollout,
+	existingNumaflowControllerDef, newNumaflowControllerDef *unstructured.Unstructured,
+	upgradeStrategyType apiv1.UpgradeStrategy,
+) (bool, error) {
+	definitionHash, err := ctlrcommon.UpgradeDefinitionHash(newNumaflowControllerDef, unstructured.UnstructuredList{}, unstructured.UnstructuredList{}, unstructured.UnstructuredList{})
+	if err != nil {
+		return false, err
+	}
+	return ctlrcommon.ProcessUpgradePlan(ctx, nfcRollout, definitionHash, func() (*apiv1.UpgradePlan, error) {
+		// the Progressive strategy isn't supported for NumaflowController yet, so it's applied directly
+		plannedStrategy := upgradeStrategyType
+		if plannedStrategy == apiv1.UpgradeStrategyProgressive {
+			plannedStrategy = apiv1.UpgradeStrategyApply
+		}
+		var pipelinesToPause []string
+		if plannedStrategy == apiv1.UpgradeStrategyPPND {
+			pipelines, err := r.GetPipelineList(ctx, nfcRollout.Namespace, nfcRollout.Name)
+			if err != nil {
+				return nil, err
+			}
+			for _, pipeline := range pipelines.Items {
+				pipelinesToPause = append(pipelinesToPause, pipeline.GetName())
+			}
+		}
+		return ctlrcommon.NewUpgradePlan(nfcRollout, newNumaflowControllerDef, existingNumaflowControllerDef, plannedStrategy, false,
+			unstructured.UnstructuredList{}, unstructured.UnstructuredList{}, unstructured.UnstructuredList{}, "", pipelinesToPause)
+	}, r.recorder)
+}
+
 func (r *NumaflowControllerRolloutReconciler) updateNumaflowController(ctx context.Context, nfcRollout *apiv1.NumaflowControllerRollout, newNumaflowControllerDef *unstructured.Unstructured) error {
 	if err := kubernetes.UpdateResource(ctx, r.client, newNumaflowControllerDef); err != nil {
 		return err
@@ -539,6 +604,7 @@ func (r *NumaflowControllerRolloutReconciler) processNumaflowControllerStatus(
 	// check if PPND strategy is requesting Pipelines to pause, and set true/false
 	// (currently, only PPND is accounted for as far as system pausing, not Progressive)
 	r.MarkRolloutPaused(ctx, nfcRollout, ppnd.IsRequestingPause(r, nfcRollout))
+	nfcRollout.Status.PauseRequested = ppnd.GetPersistedPauseRequest(r, nfcRollout)
 
 	return nil
 }
@@ -28,6 +28,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
@@ -46,6 +47,7 @@ import (
 	commontest "github.com/numaproj/numaplane/tests/common"
 
 	crCli "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func Test_reconcile_NumaflowControllerRollout_PPND(t *testing.T) {
@@ -299,6 +301,50 @@ func Test_reconcile_NumaflowControllerRollout_PPND(t *testing.T) {
 	}
 }
 
+func Test_processUpgradePlan(t *testing.T) {
+	ctx := context.Background()
+
+	pipeline := &numaflowv1.Pipeline{ObjectMeta: metav1.ObjectMeta{Namespace: ctlrcommon.DefaultTestNamespace, Name: "my-pipeline-0"}}
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, numaflowv1.AddToScheme(testScheme))
+	assert.NoError(t, apiv1.AddToScheme(testScheme))
+	r := &NumaflowControllerRolloutReconciler{
+		client:   fake.NewClientBuilder().WithScheme(testScheme).WithObjects(pipeline).Build(),
+		recorder: record.NewFakeRecorder(10),
+	}
+
+	nfcRollout := createNumaflowControllerRollout("1.2.4")
+	nfcRollout.Annotations = map[string]string{common.AnnotationKeyRequirePlanApproval: "true"}
+	existingDef, err := kubernetes.RawExtensionToUnstructured(runtime.RawExtension{Object: createDefaultNumaflowController("1.2.3", apiv1.PhaseDeployed, true)})
+	assert.NoError(t, err)
+	newDef := existingDef.DeepCopy()
+	newDef.Object["spec"] = map[string]interface{}{"version": "1.2.4"}
+
+	// the plan is published, listing the Pipelines to pause, and waits for approval
+	approved, err := r.processUpgradePlan(ctx, nfcRollout, existingDef, newDef, apiv1.UpgradeStrategyPPND)
+	assert.NoError(t, err)
+	assert.False(t, approved)
+	plan := nfcRollout.Status.UpgradePlan
+	assert.NotNil(t, plan)
+	assert.Equal(t, apiv1.UpgradeStrategyPPND, plan.Strategy)
+	assert.Equal(t, []string{"my-pipeline-0"}, plan.PipelinesToPause)
+	assert.Equal(t, []string{"NumaflowController/" + ctlrcommon.DefaultTestNumaflowControllerRolloutName}, plan.ChildrenToUpdate)
+
+	nfcRollout.Annotations[common.AnnotationKeyApprovePlan] = plan.DefinitionHash
+	approved, err = r.processUpgradePlan(ctx, nfcRollout, existingDef, newDef, apiv1.UpgradeStrategyPPND)
+	assert.NoError(t, err)
+	assert.True(t, approved)
+
+	// a different definition requires a new plan, and Progressive is planned as Apply since it isn't supported yet
+	newDef.Object["spec"] = map[string]interface{}{"version": "1.2.5"}
+	approved, err = r.processUpgradePlan(ctx, nfcRollout, existingDef, newDef, apiv1.UpgradeStrategyProgressive)
+	assert.NoError(t, err)
+	assert.False(t, approved)
+	assert.NotEqual(t, plan.DefinitionHash, nfcRollout.Status.UpgradePlan.DefinitionHash)
+	assert.Equal(t, apiv1.UpgradeStrategyApply, nfcRollout.Status.UpgradePlan.Strategy)
+	assert.Nil(t, nfcRollout.Status.UpgradePlan.PipelinesToPause)
+}
+
 func createDefaultNumaflowController(version string, phase apiv1.Phase, fullyReconciled bool) *apiv1.NumaflowController {
 	status := apiv1.NumaflowControllerStatus{
 		Status: apiv1.Status{
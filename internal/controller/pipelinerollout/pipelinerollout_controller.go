@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"runtime"
 	"strings"
 	"sync"
@@ -52,7 +53,9 @@ import (
 	"github.com/numaproj/numaplane/internal/common"
 	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
 	"github.com/numaproj/numaplane/internal/controller/common/numaflowtypes"
+	"github.com/numaproj/numaplane/internal/controller/common/revisions"
 	"github.com/numaproj/numaplane/internal/controller/common/riders"
+	"github.com/numaproj/numaplane/internal/controller/common/templates"
 	"github.com/numaproj/numaplane/internal/controller/config"
 	"github.com/numaproj/numaplane/internal/controller/progressive"
 	"github.com/numaproj/numaplane/internal/usde"
@@ -145,9 +148,25 @@ func NewPipelineRolloutReconciler(
 	return r
 }
 
+// Rehydrate restores the in-progress upgrade strategies of the PipelineRollouts from their Statuses
+func (r *PipelineRolloutReconciler) Rehydrate(ctx context.Context, c client.Reader) error {
+	pipelineRollouts := &apiv1.PipelineRolloutList{}
+	if err := c.List(ctx, pipelineRollouts); err != nil {
+		return fmt.Errorf("error listing PipelineRollouts: %w", err)
+	}
+	rollouts := make([]client.Object, len(pipelineRollouts.Items))
+	for i := range pipelineRollouts.Items {
+		rollouts[i] = &pipelineRollouts.Items[i]
+	}
+	r.inProgressStrategyMgr.Rehydrate(ctx, rollouts)
+	return nil
+}
+
 //+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=pipelinerollouts,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=pipelinerollouts/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=pipelinerollouts/finalizers,verbs=update
+//+kubebuilder:rbac:groups=numaplane.numaproj.io,resources=pipelinetemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=configmaps;secrets,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -156,6 +175,10 @@ func NewPipelineRolloutReconciler(
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.17.3/pkg/reconcile
 func (r *PipelineRolloutReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	numaLogger := logger.GetBaseLogger().WithName(loggerName).WithValues("pipelinerollout", req.NamespacedName)
+	// don't reconcile until the state kept in memory has been rehydrated from the Rollout Statuses
+	if err := ctlrcommon.WaitForRehydration(ctx); err != nil {
+		return ctrl.Result{}, err
+	}
 	r.EnqueuePipeline(req.NamespacedName)
 	numaLogger.Debugf("PipelineRollout Reconciler added PipelineRollout %v to queue", req.NamespacedName)
 	r.customMetrics.PipelineRolloutQueueLength.WithLabelValues().Set(float64(r.Queue.Len()))
@@ -227,7 +250,12 @@ func (r *PipelineRolloutReconciler) processPipelineRollout(ctx context.Context,
 
 	// Update the resource definition (everything except the Status subresource)
 	if r.needsUpdate(pipelineRolloutOrig, pipelineRollout) {
-		if err := r.client.Update(ctx, pipelineRollout); err != nil {
+		// the in-memory spec may have been resolved from a PipelineTemplate or rolled back to a revision, so write back the original spec
+		pipelineRolloutUpdate := pipelineRollout.DeepCopy()
+		pipelineRolloutUpdate.Spec = *pipelineRolloutOrig.Spec.DeepCopy()
+		err := r.client.Update(ctx, pipelineRolloutUpdate)
+		pipelineRollout.ObjectMeta = pipelineRolloutUpdate.ObjectMeta
+		if err != nil {
 			r.ErrorHandler(ctx, pipelineRollout, err, "UpdateFailed", "Failed to update PipelineRollout")
 			statusUpdateErr := r.updatePipelineRolloutStatusToFailed(ctx, pipelineRollout, err)
 			if statusUpdateErr != nil {
@@ -376,6 +404,9 @@ func (r *PipelineRolloutReconciler) reconcile(
 		r.customMetrics.DecPipelineROsRunning(pipelineRollout.Name, pipelineRollout.Namespace)
 		r.customMetrics.ReconciliationDuration.WithLabelValues(ControllerPipelineRollout, "delete").Observe(time.Since(syncStartTime).Seconds())
 		r.customMetrics.DeletePipelineRolloutHealth(pipelineRollout.Namespace, pipelineRollout.Name)
+		for _, pauseHolder := range pipelineRollout.Status.PauseHolders {
+			r.customMetrics.DeletePipelinePauseHolder(pipelineRollout.Namespace, pipelineRollout.Name, pauseHolder)
+		}
 		return 0, nil, nil
 	}
 
@@ -384,6 +415,24 @@ func (r *PipelineRolloutReconciler) reconcile(
 		controllerutil.AddFinalizer(pipelineRollout, common.FinalizerName)
 	}
 
+	// if the Pipeline is defined by a PipelineTemplate, resolve it into the in-memory spec
+	waitingForTemplate, err := templates.ApplyTemplate(ctx, r.client, pipelineRollout)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// if the user requested a rollback to a previous revision, deploy that revision's definition instead of the one in the spec
+	rollingBack, err := revisions.ApplyRollbackToRevision(ctx, r.client, pipelineRollout)
+	if err != nil {
+		return 0, nil, err
+	}
+	// if a failed progressive upgrade of this generation was rolled back, keep deploying the last promoted revision
+	if !rollingBack {
+		if _, err := revisions.ApplyAutoRollback(ctx, r.client, pipelineRollout); err != nil {
+			return 0, nil, err
+		}
+	}
+
 	// check if there's a promoted pipeline yet
 	promotedPipelines, err := ctlrcommon.FindChildrenOfUpgradeState(ctx, pipelineRollout, common.LabelValueUpgradePromoted, nil, false, r.client)
 	if err != nil {
@@ -453,7 +502,7 @@ func (r *PipelineRolloutReconciler) reconcile(
 		return 0, nil, err
 	}
 	// there are some cases that require re-queueing
-	if !allDeleted || inProgressStrategySet {
+	if !allDeleted || inProgressStrategySet || waitingForTemplate {
 		if requeueDelay == 0 {
 			requeueDelay = common.DefaultRequeueDelay
 		} else {
@@ -500,7 +549,7 @@ func (r *PipelineRolloutReconciler) processExistingPipeline(ctx context.Context,
 	numaLogger := logger.FromContext(ctx)
 
 	// get the list of Riders that we need based on the PipelineRollout definition
-	currentRiderList, err := r.GetDesiredRiders(pipelineRollout, existingPipelineDef.GetName(), newPipelineDef)
+	currentRiderList, err := r.GetDesiredRiders(ctx, pipelineRollout, existingPipelineDef.GetName(), newPipelineDef)
 	if err != nil {
 		return 0, fmt.Errorf("error getting desired Riders for pipeline %s: %s", existingPipelineDef.GetName(), err)
 	}
@@ -510,15 +559,17 @@ func (r *PipelineRolloutReconciler) processExistingPipeline(ctx context.Context,
 		return 0, fmt.Errorf("error getting existing Riders for pipeline %s: %s", existingPipelineDef.GetName(), err)
 	}
 
-	// what is the preferred strategy for this namespace?
-	userPreferredStrategy, err := usde.GetUserStrategy(ctx, newPipelineDef.GetNamespace(), existingPipelineDef.GetKind())
+	// what is the preferred strategy for this PipelineRollout (or else its namespace)?
+	effectiveUpgradeStrategy, err := usde.ResolveUserStrategy(ctx, newPipelineDef.GetNamespace(), existingPipelineDef.GetKind(), pipelineRollout.GetUpgradeStrategy())
 	if err != nil {
 		return 0, err
 	}
+	pipelineRollout.Status.EffectiveUpgradeStrategy = &effectiveUpgradeStrategy
+	userPreferredStrategy := config.USDEUserStrategy(effectiveUpgradeStrategy.Strategy)
 
 	// does the Resource need updating, and if so how?
 	// TODO: handle recreate parameter
-	needsUpdate, upgradeStrategyType, _, riderAdditions, riderModifications, riderDeletions, err := usde.ResourceNeedsUpdating(ctx, newPipelineDef, existingPipelineDef, currentRiderList, existingRiderList)
+	needsUpdate, upgradeStrategyType, _, riderAdditions, riderModifications, riderDeletions, err := usde.ResourceNeedsUpdating(ctx, newPipelineDef, existingPipelineDef, currentRiderList, existingRiderList, pipelineRollout.GetUpgradeStrategy())
 	if err != nil {
 		return 0, err
 	}
@@ -539,7 +590,32 @@ func (r *PipelineRolloutReconciler) processExistingPipeline(ctx context.Context,
 	numaLogger.Debugf("current inProgressStrategy=%s", inProgressStrategy)
 	inProgressStrategySet := (inProgressStrategy != apiv1.UpgradeStrategyNoOp)
 
-	// if not, should we set one?
+	// if approval of the upgrade plan is required, don't start upgrading until it's approved
+	requiredBySpec := pipelineRollout.Spec.Strategy != nil && pipelineRollout.Spec.Strategy.RequireApprovalOfPlan
+	if needsUpdate && !inProgressStrategySet && ctlrcommon.UpgradePlanApprovalRequired(pipelineRollout, requiredBySpec) {
+		definitionHash, err := ctlrcommon.UpgradeDefinitionHash(newPipelineDef, riderAdditions, riderModifications, riderDeletions)
+		if err != nil {
+			return 0, err
+		}
+		approved, err := ctlrcommon.ProcessUpgradePlan(ctx, pipelineRollout, definitionHash, func() (*apiv1.UpgradePlan, error) {
+			var pipelinesToPause []string
+			if upgradeStrategyType == apiv1.UpgradeStrategyPPND {
+				pipelinesToPause = []string{existingPipelineDef.GetName()}
+			}
+			nameCount, _ := r.getCurrentChildCount(pipelineRollout)
+			return ctlrcommon.NewUpgradePlan(pipelineRollout, newPipelineDef, existingPipelineDef, upgradeStrategyType, false,
+				riderAdditions, riderModifications, riderDeletions, fmt.Sprintf("%s-%d", pipelineRollout.Name, nameCount), pipelinesToPause)
+		}, r.recorder)
+		if err != nil {
+			return 0, err
+		}
+		if !approved {
+			numaLogger.Debug("waiting for upgrade plan to be approved")
+			return common.DefaultRequeueDelay, nil
+		}
+	}
+
+	// if there is no inProgressStrategy, should we set one?
 	if !inProgressStrategySet {
 		if userPreferredStrategy == config.PPNDStrategyID {
 			// if the preferred strategy is PPND, do we need to start the process for PPND (if we haven't already)?
@@ -596,6 +672,10 @@ func (r *PipelineRolloutReconciler) processExistingPipeline(ctx context.Context,
 		}
 		if done {
 			r.inProgressStrategyMgr.UnsetStrategy(ctx, pipelineRollout)
+
+			if err := revisions.RecordRevision(ctx, r.client, pipelineRollout, newPipelineDef.GetName(), revisions.OutcomePromoted); err != nil {
+				numaLogger.Error(err, "failed to record revision")
+			}
 		}
 		// update the cluster to reflect the Rider additions, modifications, and deletions
 		if err := riders.UpdateRidersInK8S(ctx, newPipelineDef, riderAdditions, riderModifications, riderDeletions, r.client); err != nil {
@@ -618,7 +698,7 @@ func (r *PipelineRolloutReconciler) processExistingPipeline(ctx context.Context,
 			if err != nil {
 				return 0, err
 			}
-			currentRiderList, err := r.GetDesiredRiders(pipelineRollout, promotedPipeline.GetName(), promotedPipeline)
+			currentRiderList, err := r.GetDesiredRiders(ctx, pipelineRollout, promotedPipeline.GetName(), promotedPipeline)
 			if err != nil {
 				return 0, fmt.Errorf("error getting desired Riders for pipeline %s: %s", newPipelineDef.GetName(), err)
 			}
@@ -660,6 +740,10 @@ func (r *PipelineRolloutReconciler) processExistingPipeline(ctx context.Context,
 
 			// update the list of riders in the Status
 			r.SetCurrentRiderList(ctx, pipelineRollout, currentRiderList)
+
+			if err := revisions.RecordRevision(ctx, r.client, pipelineRollout, newPipelineDef.GetName(), revisions.OutcomePromoted); err != nil {
+				numaLogger.Error(err, "failed to record revision")
+			}
 		}
 
 	}
@@ -677,7 +761,7 @@ func (r *PipelineRolloutReconciler) createPromotedPipeline(ctx context.Context,
 	// first need to know if the pipeline needs to be created with "desiredPhase" = "Paused" or not
 	// (i.e. if isbsvc or numaflow controller is requesting pause)
 	// this can happen during delete/recreate of pipeline
-	userPreferredStrategy, err := usde.GetUserStrategy(ctx, newPipelineDef.GetNamespace(), numaflowv1.PipelineGroupVersionKind.Kind)
+	userPreferredStrategy, err := usde.GetUserStrategy(ctx, newPipelineDef.GetNamespace(), numaflowv1.PipelineGroupVersionKind.Kind, pipelineRollout.GetUpgradeStrategy())
 	if err != nil {
 		return err
 	}
@@ -705,6 +789,10 @@ func (r *PipelineRolloutReconciler) createPromotedPipeline(ctx context.Context,
 		return fmt.Errorf("error creating riders: %s", err)
 	}
 
+	if err := revisions.RecordRevision(ctx, r.client, pipelineRollout, newPipelineDef.GetName(), revisions.OutcomePromoted); err != nil {
+		logger.FromContext(ctx).Error(err, "failed to record revision")
+	}
+
 	// if user somehow has no Promoted Pipeline and is in the middle of Progressive, this isn't right - user may have deleted the Promoted Pipeline
 	// if this happens, we need to stop the Progressive upgrade and remove any Upgrading children
 	inProgressStrategy := r.inProgressStrategyMgr.GetStrategy(ctx, pipelineRollout)
@@ -737,6 +825,9 @@ func (r *PipelineRolloutReconciler) processPipelineStatus(ctx context.Context, p
 
 		r.setChildResourcesHealthCondition(pipelineRollout, existingPipelineDef, &pipelineStatus)
 		r.setChildResourcesPauseCondition(pipelineRollout, &pipelineStatus)
+		if err := r.refreshPauseHolders(ctx, pipelineRollout, existingPipelineDef, &pipelineStatus); err != nil {
+			return fmt.Errorf("failed to refresh pause holders: %v", err)
+		}
 	}
 	return nil
 }
@@ -878,6 +969,21 @@ func (r *PipelineRolloutReconciler) needsUpdate(old, new *apiv1.PipelineRollout)
 	return false
 }
 
+// get the reconcile requests for the PipelineRollouts which reference the ConfigMap or Secret in their ValuesFrom
+func (r *PipelineRolloutReconciler) valuesFromRequests(ctx context.Context, obj client.Object) []reconcile.Request {
+	pipelineRolloutList := &apiv1.PipelineRolloutList{}
+	if err := r.client.List(ctx, pipelineRolloutList, client.InNamespace(obj.GetNamespace())); err != nil {
+		logger.FromContext(ctx).WithValues("object", fmt.Sprintf("%s/%s", obj.GetNamespace(), obj.GetName())).
+			Error(err, "failed to list the PipelineRollouts which may reference the object in their ValuesFrom")
+		return nil
+	}
+	pipelineRollouts := make([]*apiv1.PipelineRollout, 0, len(pipelineRolloutList.Items))
+	for i := range pipelineRolloutList.Items {
+		pipelineRollouts = append(pipelineRollouts, &pipelineRolloutList.Items[i])
+	}
+	return ctlrcommon.ValuesFromRequests(pipelineRollouts, obj)
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *PipelineRolloutReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 
@@ -893,6 +999,31 @@ func (r *PipelineRolloutReconciler) SetupWithManager(ctx context.Context, mgr ct
 		return fmt.Errorf("failed to watch PipelineRollouts: %v", err)
 	}
 
+	// Watch PipelineTemplates (this enqueues the PipelineRollouts which reference the PipelineTemplate)
+	if err := controller.Watch(source.Kind(mgr.GetCache(), &apiv1.PipelineTemplate{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, pipelineTemplate *apiv1.PipelineTemplate) []reconcile.Request {
+			return templates.ReferencingRolloutRequests(ctx, r.client, pipelineTemplate)
+		}),
+		ctlrcommon.TypedGenerationChangedPredicate[*apiv1.PipelineTemplate]{})); err != nil {
+		return fmt.Errorf("failed to watch PipelineTemplates: %v", err)
+	}
+
+	// Watch the ConfigMaps and Secrets which PipelineRollouts can reference in their ValuesFrom (this enqueues the PipelineRollouts which reference them)
+	if err := controller.Watch(source.Kind(mgr.GetCache(), &corev1.ConfigMap{},
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, configMap *corev1.ConfigMap) []reconcile.Request {
+			return r.valuesFromRequests(ctx, configMap)
+		}),
+		predicate.TypedResourceVersionChangedPredicate[*corev1.ConfigMap]{})); err != nil {
+		return fmt.Errorf("failed to watch ConfigMaps: %v", err)
+	}
+	if err := controller.Watch(source.Kind(mgr.GetCache(), ctlrcommon.SecretMetadata(),
+		handler.TypedEnqueueRequestsFromMapFunc(func(ctx context.Context, secret *metav1.PartialObjectMetadata) []reconcile.Request {
+			return r.valuesFromRequests(ctx, &corev1.Secret{ObjectMeta: secret.ObjectMeta})
+		}),
+		predicate.TypedResourceVersionChangedPredicate[*metav1.PartialObjectMetadata]{})); err != nil {
+		return fmt.Errorf("failed to watch Secrets: %v", err)
+	}
+
 	// Watch Pipelines
 	pipelineUns := &unstructured.Unstructured{}
 	pipelineUns.SetGroupVersionKind(schema.GroupVersionKind{
@@ -1104,8 +1235,17 @@ func (r *PipelineRolloutReconciler) makeTargetPipelineDefinition(
 		return nil, err
 	}
 
-	pipelineDef, err := r.makePipelineDefinition(pipelineRollout, pipelineName, isbsvc.GetName(), metadata)
-	return pipelineDef, err
+	upgradeState, err := ctlrcommon.ResolveTemplatedUpgradeState(ctx, r.client, pipelineRollout, pipelineName, common.LabelValueUpgradePromoted)
+	if err != nil {
+		return nil, err
+	}
+
+	pipelineDef, err := r.makePipelineDefinition(ctx, pipelineRollout, pipelineName, isbsvc.GetName(), metadata, upgradeState)
+	if err != nil {
+		return nil, err
+	}
+	ctlrcommon.SetTemplatedUpgradeState(pipelineDef, upgradeState)
+	return pipelineDef, nil
 }
 
 func (r *PipelineRolloutReconciler) getISBSvcRollout(
@@ -1125,25 +1265,36 @@ func (r *PipelineRolloutReconciler) getISBSvcRollout(
 }
 
 // templates are used to dynamically evaluate child spec, metadata, as well as Riders
-func (r *PipelineRolloutReconciler) GetTemplateArguments(pipeline *unstructured.Unstructured) map[string]interface{} {
-	return r.getTemplateArguments(pipeline.GetName(), pipeline.GetNamespace())
+func (r *PipelineRolloutReconciler) GetTemplateArguments(ctx context.Context, rolloutObject ctlrcommon.RolloutObject, pipeline *unstructured.Unstructured) (map[string]interface{}, error) {
+	pipelineRollout := rolloutObject.(*apiv1.PipelineRollout)
+	isbsvcName, _ := numaflowtypes.GetPipelineISBSVCName(pipeline) // only fails for a malformed spec, which can't be templated anyway
+	return r.getTemplateArguments(ctx, pipelineRollout, pipeline.GetName(), isbsvcName, ctlrcommon.GetTemplatedUpgradeState(pipeline))
 }
 
-func (r *PipelineRolloutReconciler) getTemplateArguments(pipelineName string, namespace string) map[string]interface{} {
-	return map[string]interface{}{
-		common.TemplatePipelineName:      pipelineName,
-		common.TemplatePipelineNamespace: namespace,
-	}
+func (r *PipelineRolloutReconciler) getTemplateArguments(
+	ctx context.Context,
+	pipelineRollout *apiv1.PipelineRollout,
+	pipelineName string,
+	isbsvcName string,
+	upgradeState common.UpgradeState,
+) (map[string]interface{}, error) {
+	return numaflowtypes.GetPipelineTemplateArguments(ctx, r.client, pipelineRollout, pipelineName, isbsvcName, upgradeState)
 }
 
+// make the definition of a Pipeline, templated with the given upgrade state (see ctlrcommon.ResolveTemplatedUpgradeState)
 func (r *PipelineRolloutReconciler) makePipelineDefinition(
+	ctx context.Context,
 	pipelineRollout *apiv1.PipelineRollout,
 	pipelineName string,
 	isbsvcName string,
 	metadata apiv1.Metadata,
+	upgradeState common.UpgradeState,
 ) (*unstructured.Unstructured, error) {
 
-	args := r.getTemplateArguments(pipelineName, pipelineRollout.Namespace)
+	args, err := r.getTemplateArguments(ctx, pipelineRollout, pipelineName, isbsvcName, upgradeState)
+	if err != nil {
+		return nil, err
+	}
 
 	pipelineSpec, err := util.ResolveTemplatedSpec(pipelineRollout.Spec.Pipeline.Spec, args)
 	if err != nil {
@@ -1314,11 +1465,20 @@ func getLivePipelineRollout(ctx context.Context, name, namespace string) (*apiv1
 // GetDesiredRiders gets the list of Riders as specified in the PipelineRollout, templated for the specific pipeline name and
 // based on the pipeline definition.
 // Note the pipelineName can be different from pipelineDef.GetName().
-// The pipelineName is what's used for templating the Rider definition, while the pipelineDef is really only used in the case of "per-vertex" Riders.
+// The pipelineName is what's used for templating the Rider definition, while the pipelineDef is only used for the isbsvc name and upgrade state it was
+// templated with, and in the case of "per-vertex" Riders.
 // In this case, it's necessary to use the existing pipeline's name to template in order to effectively compare whether the Rider has changed, but
 // use the latest pipeline definition to derive the current list of Vertices that need Riders.
-func (r *PipelineRolloutReconciler) GetDesiredRiders(rolloutObject ctlrcommon.RolloutObject, pipelineName string, pipelineDef *unstructured.Unstructured) ([]riders.Rider, error) {
+func (r *PipelineRolloutReconciler) GetDesiredRiders(ctx context.Context, rolloutObject ctlrcommon.RolloutObject, pipelineName string, pipelineDef *unstructured.Unstructured) ([]riders.Rider, error) {
 	pipelineRollout := rolloutObject.(*apiv1.PipelineRollout)
+	isbsvcName, err := numaflowtypes.GetPipelineISBSVCName(pipelineDef)
+	if err != nil {
+		return nil, err
+	}
+	args, err := r.getTemplateArguments(ctx, pipelineRollout, pipelineName, isbsvcName, ctlrcommon.GetTemplatedUpgradeState(pipelineDef))
+	if err != nil {
+		return nil, err
+	}
 	desiredRiders := []riders.Rider{}
 	for _, rider := range pipelineRollout.Spec.Riders {
 
@@ -1335,11 +1495,9 @@ func (r *PipelineRolloutReconciler) GetDesiredRiders(rolloutObject ctlrcommon.Ro
 			}
 			for _, vertex := range vertices {
 				vertexName := vertex.(map[string]interface{})["name"]
-				resolvedMap, err := util.ResolveTemplatedSpec(asMap, map[string]interface{}{
-					common.TemplatePipelineName:      pipelineName,
-					common.TemplatePipelineNamespace: pipelineRollout.Namespace,
-					common.TemplateVertexName:        vertexName,
-				})
+				vertexArgs := maps.Clone(args)
+				vertexArgs[common.TemplateVertexName] = vertexName
+				resolvedMap, err := util.ResolveTemplatedSpec(asMap, vertexArgs)
 				if err != nil {
 					return desiredRiders, err
 				}
@@ -1351,10 +1509,7 @@ func (r *PipelineRolloutReconciler) GetDesiredRiders(rolloutObject ctlrcommon.Ro
 			}
 		} else {
 			// create one Rider for the Pipeline
-			resolvedMap, err := util.ResolveTemplatedSpec(asMap, map[string]interface{}{
-				common.TemplatePipelineName:      pipelineName,
-				common.TemplatePipelineNamespace: pipelineRollout.Namespace,
-			})
+			resolvedMap, err := util.ResolveTemplatedSpec(asMap, args)
 			if err != nil {
 				return desiredRiders, err
 			}
@@ -36,6 +36,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	ctlrruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/numaproj/numaplane/internal/common"
 	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
@@ -806,6 +807,63 @@ func withInterstepBufferService(origPipelineSpec numaflowv1.PipelineSpec, isbsvc
 	return *newPipelineSpec
 }
 
+// a Pipeline which didn't pause within its pause timeout is force drained with the spec it will have once the pause is over
+func Test_forceDrainForPauseTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name                    string
+		pipelineNeedsToUpdate   bool
+		expectWatermarkDisabled bool
+	}{
+		{
+			// e.g. the pause was requested for an InterstepBufferService or Numaflow Controller update
+			name:                    "Pipeline spec unchanged: its own spec is applied",
+			pipelineNeedsToUpdate:   false,
+			expectWatermarkDisabled: false,
+		},
+		{
+			name:                    "Pipeline spec changed: the PipelineRollout's definition is applied",
+			pipelineNeedsToUpdate:   true,
+			expectWatermarkDisabled: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			existingPipeline := ctlrcommon.CreateTestPipelineOfSpec(runningPipelineSpec, ctlrcommon.DefaultTestPipelineName, numaflowv1.PipelinePhaseRunning,
+				numaflowv1.Status{}, false, map[string]string{}, map[string]string{common.AnnotationKeyPauseTimeoutForceDrain: "2025-01-01T00:00:00Z"})
+			existingPipeline.Spec.Lifecycle.DesiredPhase = numaflowv1.PipelinePhasePaused
+			existingPipelineMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existingPipeline)
+			assert.NoError(t, err)
+			existingPipelineDef := &unstructured.Unstructured{Object: existingPipelineMap}
+			newPipelineMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ctlrcommon.CreateTestPipelineOfSpec(runningPipelineSpecWithWatermarkDisabled,
+				ctlrcommon.DefaultTestPipelineName, numaflowv1.PipelinePhaseRunning, numaflowv1.Status{}, false, map[string]string{}, map[string]string{}))
+			assert.NoError(t, err)
+			newPipelineDef := &unstructured.Unstructured{Object: newPipelineMap}
+
+			c := fake.NewClientBuilder().WithObjects(existingPipelineDef.DeepCopy()).Build()
+			r := &PipelineRolloutReconciler{client: c, recorder: record.NewFakeRecorder(10)}
+			pipelineRollout := ctlrcommon.CreateTestPipelineRollout(numaflowv1.PipelineSpec{}, map[string]string{}, map[string]string{}, map[string]string{}, map[string]string{}, nil)
+
+			assert.NoError(t, r.forceDrainForPauseTimeout(ctx, pipelineRollout, existingPipelineDef, newPipelineDef, tc.pipelineNeedsToUpdate))
+
+			livePipelineDef := existingPipelineDef.DeepCopy()
+			assert.NoError(t, c.Get(ctx, k8stypes.NamespacedName{Namespace: existingPipelineDef.GetNamespace(), Name: existingPipelineDef.GetName()}, livePipelineDef))
+			assert.True(t, isPipelineSpecOverridden(livePipelineDef))
+			assert.Contains(t, livePipelineDef.GetAnnotations(), common.AnnotationKeyPauseTimeoutForceDrain)
+
+			var livePipelineSpec numaflowv1.PipelineSpec
+			assert.NoError(t, util.StructToStruct(livePipelineDef.Object["spec"], &livePipelineSpec))
+			assert.Equal(t, tc.expectWatermarkDisabled, livePipelineSpec.Watermark.Disabled)
+			// it's set running with its source scaled to zero so that it drains
+			assert.Equal(t, numaflowv1.PipelinePhaseRunning, livePipelineSpec.Lifecycle.DesiredPhase)
+			assert.Equal(t, "in", livePipelineSpec.Vertices[0].Name)
+			assert.Equal(t, int32(0), *livePipelineSpec.Vertices[0].Scale.Max)
+		})
+	}
+}
+
 // process an existing pipeline
 // in this test, the user preferred strategy is PPND
 func Test_processExistingPipeline_PPND(t *testing.T) {
@@ -860,6 +918,8 @@ func Test_processExistingPipeline_PPND(t *testing.T) {
 		expectedRolloutPhase       apiv1.Phase
 		// require these Conditions to be set (note that in real life, previous reconciliations may have set other Conditions from before which are still present)
 		expectedPipelineSpecResult func(numaflowv1.PipelineSpec) bool
+		// if set, the reasons of the pause holders in the Status
+		expectedPauseHolderReasons []string
 	}{
 		{
 			name:                           "nothing to do",
@@ -902,6 +962,7 @@ func Test_processExistingPipeline_PPND(t *testing.T) {
 			expectedPipelineSpecResult: func(spec numaflowv1.PipelineSpec) bool {
 				return util.CompareStructNumTypeAgnostic(ctlrcommon.PipelineWithDesiredPhase(runningPipelineSpec, numaflowv1.PipelinePhasePaused), spec)
 			},
+			expectedPauseHolderReasons: []string{apiv1.PauseReasonPipelineUpdate},
 		},
 		{
 			name:                           "external pause request at the same time as a DirectApply change",
@@ -916,6 +977,7 @@ func Test_processExistingPipeline_PPND(t *testing.T) {
 			expectedPipelineSpecResult: func(spec numaflowv1.PipelineSpec) bool {
 				return util.CompareStructNumTypeAgnostic(ctlrcommon.PipelineWithDesiredPhase(runningPipelineSpec, numaflowv1.PipelinePhasePaused), spec)
 			},
+			expectedPauseHolderReasons: []string{apiv1.PauseReasonNumaflowControllerUpdate, apiv1.PauseReasonPipelineUpdate},
 		},
 		{
 			name:                           "user sets desiredPhase=Paused",
@@ -1091,6 +1153,16 @@ func Test_processExistingPipeline_PPND(t *testing.T) {
 			assert.NoError(t, err)
 			assert.NotNil(t, resultPipeline)
 			assert.True(t, tc.expectedPipelineSpecResult(resultPipeline.Spec), "result spec", resultPipeline.Spec)
+
+			// Check pause holders
+			if tc.expectedPauseHolderReasons != nil {
+				pauseHolderReasons := []string{}
+				for _, pauseHolder := range rollout.Status.PauseHolders {
+					pauseHolderReasons = append(pauseHolderReasons, pauseHolder.Reason)
+					assert.False(t, pauseHolder.StartTime.IsZero())
+				}
+				assert.ElementsMatch(t, tc.expectedPauseHolderReasons, pauseHolderReasons)
+			}
 		})
 	}
 }
@@ -2190,7 +2262,7 @@ func Test_PipelineRollout_CheckRidersForDifferences(t *testing.T) {
 			}
 
 			// Call GetDesiredRiders with the new pipeline definition
-			desiredRiders, err := reconciler.GetDesiredRiders(pipelineRollout, tc.newPipelineName, newPipelineDef)
+			desiredRiders, err := reconciler.GetDesiredRiders(context.Background(), pipelineRollout, tc.newPipelineName, newPipelineDef)
 			assert.NoError(t, err)
 
 			// Verify rider count
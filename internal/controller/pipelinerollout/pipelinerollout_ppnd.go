@@ -18,12 +18,18 @@ package pipelinerollout
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	"github.com/numaproj/numaplane/internal/common"
 	"github.com/numaproj/numaplane/internal/controller/common/numaflowtypes"
 	"github.com/numaproj/numaplane/internal/controller/ppnd"
 	"github.com/numaproj/numaplane/internal/usde"
@@ -65,6 +71,12 @@ func (r *PipelineRolloutReconciler) processExistingPipelineWithPPND(ctx context.
 	}
 
 	shouldBePaused := *needsPaused
+	// if the Pipeline didn't pause within its pause timeout and is to be force drained, do that instead of the usual pause
+	// (once it's paused, the usual process continues, which restores its spec)
+	if shouldBePaused && isPauseTimeoutForceDrainRequested(existingPipelineDef) &&
+		!numaflowtypes.CheckPipelinePhase(ctx, existingPipelineDef, numaflowv1.PipelinePhasePaused) {
+		return false, r.forceDrainForPauseTimeout(ctx, pipelineRollout, existingPipelineDef, newPipelineDef, pipelineNeedsToUpdate)
+	}
 	if shouldBePaused {
 		if err := r.setPipelineLifecyclePaused(ctx, existingPipelineDef); err != nil {
 			return false, err
@@ -73,6 +85,9 @@ func (r *PipelineRolloutReconciler) processExistingPipelineWithPPND(ctx context.
 		if err := r.setPipelineLifecycleRunning(ctx, pipelineRollout, existingPipelineDef, forceResume); err != nil {
 			return false, err
 		}
+		if err := clearPauseTimeoutForceDrain(ctx, existingPipelineDef, r.client); err != nil {
+			return false, err
+		}
 	}
 
 	// update the ResourceVersion in the newPipelineDef in case it got updated
@@ -157,6 +172,7 @@ func (r *PipelineRolloutReconciler) shouldBePaused(ctx context.Context, pipeline
 
 	ppndPause := (pipelineNeedsToUpdate || externalPauseRequest) && !wontPause
 	shouldBePaused := ppndPause || specBasedPause
+	r.setPauseHolders(pipelineRollout, r.getPauseHolders(pipelineRollout, isbsvcRollout.Name, pipelineNeedsToUpdate, specBasedPause, wontPause))
 	numaLogger.Debugf("shouldBePaused=%t, pipelineNeedsToUpdate=%t, externalPauseRequest=%t, specBasedPause=%t, wontPause=%t",
 		shouldBePaused, pipelineNeedsToUpdate, externalPauseRequest, specBasedPause, wontPause)
 
@@ -170,6 +186,134 @@ func (r *PipelineRolloutReconciler) shouldBePaused(ctx context.Context, pipeline
 	return &shouldBePaused, wontPause, nil
 }
 
+// get what is holding the Pipeline paused: pause requests from the Numaflow Controller or ISBService and the PipelineRollout's own need
+// to update only hold it paused if it can pause, while the PipelineRollout's spec saying to pause always does
+func (r *PipelineRolloutReconciler) getPauseHolders(pipelineRollout *apiv1.PipelineRollout, isbsvcName string, pipelineNeedsToUpdate bool,
+	specBasedPause bool, wontPause bool) []apiv1.PauseHolder {
+
+	pauseHolders := []apiv1.PauseHolder{}
+	if !wontPause {
+		pm := ppnd.GetPauseModule()
+		for _, requester := range []string{pm.GetNumaflowControllerKey(pipelineRollout.Namespace), pm.GetISBServiceKey(pipelineRollout.Namespace, isbsvcName)} {
+			if details, requested := pm.GetActivePauseRequest(requester); requested {
+				pauseHolders = append(pauseHolders, apiv1.PauseHolder{Kind: details.RequesterKind, Name: details.RequesterName, Reason: details.Reason,
+					StartTime: metav1.NewTime(details.StartTime), Generation: details.Generation})
+			}
+		}
+		if pipelineNeedsToUpdate {
+			pauseHolders = append(pauseHolders, newPipelineRolloutPauseHolder(pipelineRollout, apiv1.PauseReasonPipelineUpdate))
+		}
+	}
+	if specBasedPause {
+		pauseHolders = append(pauseHolders, newPipelineRolloutPauseHolder(pipelineRollout, apiv1.PauseReasonDesiredPhase))
+	}
+	return pauseHolders
+}
+
+// the PipelineRollout itself holding its Pipeline paused (its start time is set when it's added to the Status)
+func newPipelineRolloutPauseHolder(pipelineRollout *apiv1.PipelineRollout, reason string) apiv1.PauseHolder {
+	return apiv1.PauseHolder{Kind: apiv1.PipelineRolloutGroupVersionKind.Kind, Name: pipelineRollout.Name, Reason: reason, Generation: pipelineRollout.Generation}
+}
+
+// set the pause holders in the PipelineRollout Status along with their metrics, and record an event for each which is added or released
+func (r *PipelineRolloutReconciler) setPauseHolders(pipelineRollout *apiv1.PipelineRollout, pauseHolders []apiv1.PauseHolder) {
+	previousHolders := pipelineRollout.Status.PauseHolders
+
+	for i := range pauseHolders {
+		holder := &pauseHolders[i]
+		previousHolder := findPauseHolder(previousHolders, *holder)
+		if holder.StartTime.IsZero() {
+			if previousHolder != nil {
+				holder.StartTime = previousHolder.StartTime
+			} else {
+				holder.StartTime = metav1.Now()
+			}
+		}
+		if previousHolder == nil {
+			r.recorder.Eventf(pipelineRollout, "Normal", "PauseHolderAdded", "Pipeline held paused by %s (reason: %s, generation: %d)",
+				describePauseHolder(*holder), holder.Reason, holder.Generation)
+		}
+		r.customMetrics.SetPipelinePauseHolder(pipelineRollout.Namespace, pipelineRollout.Name, *holder)
+	}
+
+	for _, previousHolder := range previousHolders {
+		holder := findPauseHolder(pauseHolders, previousHolder)
+		if holder == nil {
+			r.recorder.Eventf(pipelineRollout, "Normal", "PauseHolderReleased", "Pipeline no longer held paused by %s (reason: %s, held since %s)",
+				describePauseHolder(previousHolder), previousHolder.Reason, previousHolder.StartTime.UTC().Format(time.RFC3339))
+		}
+		if holder == nil || holder.Generation != previousHolder.Generation {
+			r.customMetrics.DeletePipelinePauseHolder(pipelineRollout.Namespace, pipelineRollout.Name, previousHolder)
+		}
+	}
+
+	if len(pauseHolders) == 0 {
+		pauseHolders = nil
+	}
+	pipelineRollout.Status.PauseHolders = pauseHolders
+}
+
+// once the Pipeline is neither paused, pausing nor desired to be paused, nothing is holding it paused anymore; until then, release it
+// from whatever has stopped holding it paused since: withdrawn pause requests, a PPND upgrade which is done, or the PipelineRollout's
+// spec no longer saying to pause
+func (r *PipelineRolloutReconciler) refreshPauseHolders(ctx context.Context, pipelineRollout *apiv1.PipelineRollout, pipeline *unstructured.Unstructured,
+	pipelineStatus *kubernetes.GenericStatus) error {
+	if len(pipelineRollout.Status.PauseHolders) == 0 {
+		return nil
+	}
+
+	var pipelineSpec numaflowtypes.PipelineSpec
+	if err := util.StructToStruct(pipeline.Object["spec"], &pipelineSpec); err != nil {
+		return err
+	}
+	pipelinePhase := numaflowv1.PipelinePhase(pipelineStatus.Phase)
+	if pipelinePhase != numaflowv1.PipelinePhasePaused && pipelinePhase != numaflowv1.PipelinePhasePausing && !r.isSpecBasedPause(pipelineSpec) {
+		r.setPauseHolders(pipelineRollout, nil)
+		return nil
+	}
+
+	var rolloutPipelineSpec numaflowtypes.PipelineSpec
+	_ = json.Unmarshal(pipelineRollout.Spec.Pipeline.Spec.Raw, &rolloutPipelineSpec)
+	ppndInProgress := r.inProgressStrategyMgr.GetStrategy(ctx, pipelineRollout) == apiv1.UpgradeStrategyPPND
+
+	pm := ppnd.GetPauseModule()
+	pauseHolders := []apiv1.PauseHolder{}
+	for _, holder := range pipelineRollout.Status.PauseHolders {
+		stillHolding := true
+		switch holder.Reason {
+		case apiv1.PauseReasonNumaflowControllerUpdate:
+			_, stillHolding = pm.GetActivePauseRequest(pm.GetNumaflowControllerKey(pipelineRollout.Namespace))
+		case apiv1.PauseReasonISBServiceUpdate:
+			_, stillHolding = pm.GetActivePauseRequest(pm.GetISBServiceKey(pipelineRollout.Namespace, holder.Name))
+		case apiv1.PauseReasonPipelineUpdate:
+			stillHolding = ppndInProgress
+		case apiv1.PauseReasonDesiredPhase:
+			stillHolding = r.isSpecBasedPause(rolloutPipelineSpec)
+		}
+		if stillHolding {
+			pauseHolders = append(pauseHolders, holder)
+		}
+	}
+	r.setPauseHolders(pipelineRollout, pauseHolders)
+	return nil
+}
+
+func findPauseHolder(pauseHolders []apiv1.PauseHolder, pauseHolder apiv1.PauseHolder) *apiv1.PauseHolder {
+	for i := range pauseHolders {
+		if pauseHolders[i].Kind == pauseHolder.Kind && pauseHolders[i].Name == pauseHolder.Name && pauseHolders[i].Reason == pauseHolder.Reason {
+			return &pauseHolders[i]
+		}
+	}
+	return nil
+}
+
+func describePauseHolder(pauseHolder apiv1.PauseHolder) string {
+	if pauseHolder.Name == "" {
+		return pauseHolder.Kind
+	}
+	return fmt.Sprintf("%s %s", pauseHolder.Kind, pauseHolder.Name)
+}
+
 // do we need to start the PPND process, if we haven't already?
 // this is based on if:
 //
@@ -306,3 +450,47 @@ func (r *PipelineRolloutReconciler) setPipelineLifecycleRunning(ctx context.Cont
 	}
 	return nil
 }
+
+func isPauseTimeoutForceDrainRequested(pipeline *unstructured.Unstructured) bool {
+	_, found := pipeline.GetAnnotations()[common.AnnotationKeyPauseTimeoutForceDrain]
+	return found
+}
+
+// force drain a Pipeline which didn't pause within its pause timeout the same way as a Pipeline being recycled
+// The spec applied to drain it is the one it's going to have once the pause is over: if the Pipeline itself needs updating, that's the
+// PipelineRollout's definition; otherwise (e.g. the pause was requested for an InterstepBufferService or Numaflow Controller update) it's
+// the Pipeline's own spec, which is only scaled and set running so that it drains
+func (r *PipelineRolloutReconciler) forceDrainForPauseTimeout(ctx context.Context, pipelineRollout *apiv1.PipelineRollout,
+	existingPipelineDef, newPipelineDef *unstructured.Unstructured, pipelineNeedsToUpdate bool) error {
+	numaLogger := logger.FromContext(ctx)
+
+	drainPipelineDef := existingPipelineDef
+	if pipelineNeedsToUpdate {
+		drainPipelineDef = newPipelineDef
+	}
+	paused, drained, failed, err := forceDrainPipeline(ctx, existingPipelineDef, drainPipelineDef, pipelineRollout, !isPipelineSpecOverridden(existingPipelineDef), r.client)
+	if err != nil {
+		return err
+	}
+	numaLogger.WithValues("paused", paused, "drained", drained, "failed", failed).Debug("force draining Pipeline which didn't pause within its pause timeout")
+	if paused {
+		if drained {
+			r.recorder.Eventf(pipelineRollout, "Normal", "PauseTimeoutForceDrained", "Pipeline %s has been force drained", existingPipelineDef.GetName())
+		} else {
+			r.recorder.Eventf(pipelineRollout, "Warning", "PauseTimeoutForceDrained", "Pipeline %s has paused after being force drained but never drained", existingPipelineDef.GetName())
+		}
+	}
+	return nil
+}
+
+// once the Pipeline no longer needs to be paused, it's no longer to be force drained: its spec will have been restored by then
+func clearPauseTimeoutForceDrain(ctx context.Context, pipeline *unstructured.Unstructured, c client.Client) error {
+	if !isPauseTimeoutForceDrainRequested(pipeline) {
+		return nil
+	}
+	patchJson := fmt.Sprintf(`{"metadata": {"annotations": {"%s": null, "%s": null}}}`, common.AnnotationKeyPauseTimeoutForceDrain, common.AnnotationKeyOverriddenSpec)
+	if err := kubernetes.PatchResource(ctx, c, pipeline, patchJson, k8stypes.MergePatchType); err != nil {
+		return fmt.Errorf("failed to clear force drain request on pipeline %s/%s: %w", pipeline.GetNamespace(), pipeline.GetName(), err)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/numaproj/numaplane/internal/common"
@@ -50,7 +51,12 @@ func (r *PipelineRolloutReconciler) CreateUpgradingChildDefinition(ctx context.C
 		}
 	}
 
-	pipeline, err := r.makePipelineDefinition(pipelineRollout, name, isbsvc.GetName(), metadata)
+	upgradeState, err := ctlrcommon.ResolveTemplatedUpgradeState(ctx, r.client, pipelineRollout, name, common.LabelValueUpgradeInProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline, err := r.makePipelineDefinition(ctx, pipelineRollout, name, isbsvc.GetName(), metadata, upgradeState)
 	if err != nil {
 		return nil, err
 	}
@@ -59,6 +65,7 @@ func (r *PipelineRolloutReconciler) CreateUpgradingChildDefinition(ctx context.C
 	labels[common.LabelKeyUpgradeState] = string(common.LabelValueUpgradeInProgress)
 	labels[common.LabelKeyISBServiceChildNameForPipeline] = isbsvc.GetName()
 	pipeline.SetLabels(labels)
+	ctlrcommon.SetTemplatedUpgradeState(pipeline, upgradeState)
 
 	return pipeline, nil
 }
@@ -124,29 +131,11 @@ func (r *PipelineRolloutReconciler) AssessUpgradingChild(
 		}
 
 		// if we fail once, it's okay: we'll check again later
-		if assessment == apiv1.AssessmentResultFailure {
-			numaLogger.Debugf("Assessment failed for upgrading child %s, checking again...", existingUpgradingChildDef.GetName())
-			_ = progressive.UpdateUpgradingChildStatus(pipelineRollout, func(status *apiv1.UpgradingChildStatus) {
-				status.TrialWindowStartTime = nil
-				status.AssessmentResult = apiv1.AssessmentResultUnknown
-			})
-
-			return apiv1.AssessmentResultUnknown, "", nil
-		}
-
 		// if we succeed, we must continue to succeed for a prescribed period of time to consider the resource health
 		// check "successful".
-		if assessment == apiv1.AssessmentResultSuccess {
-			if !childStatus.IsTrialWindowStartTimeSet() {
-				_ = progressive.UpdateUpgradingChildStatus(pipelineRollout, func(status *apiv1.UpgradingChildStatus) {
-					status.TrialWindowStartTime = &metav1.Time{Time: currentTime}
-					status.AssessmentResult = apiv1.AssessmentResultUnknown
-				})
-				numaLogger.Debugf("Assessment succeeded for upgrading child %s, setting TrialWindowStartTime to %s", existingUpgradingChildDef.GetName(), currentTime)
-			}
-
-			// Check if the trail window is set and if the success window has passed.
-			if childStatus.IsTrialWindowStartTimeSet() && currentTime.Sub(childStatus.TrialWindowStartTime.Time) >= assessmentSchedule.Period {
+		if assessment == apiv1.AssessmentResultFailure || assessment == apiv1.AssessmentResultSuccess {
+			healthy := assessment == apiv1.AssessmentResultSuccess
+			if progressive.RecordBasicHealthCheck(pipelineRollout, healthy, assessmentSchedule, currentTime) {
 				// Success window passed, launch AnalysisRun or declared success
 				_ = progressive.UpdateUpgradingChildStatus(pipelineRollout, func(status *apiv1.UpgradingChildStatus) {
 					status.BasicAssessmentEndTime = &metav1.Time{Time: currentTime}
@@ -155,7 +144,11 @@ func (r *PipelineRolloutReconciler) AssessUpgradingChild(
 				return r.checkAnalysisTemplates(ctx, pipelineRollout, existingUpgradingChildDef)
 			}
 
-			numaLogger.Debugf("Assessment succeeded for upgrading child %s, but success window has not passed yet", existingUpgradingChildDef.GetName())
+			if healthy {
+				numaLogger.Debugf("Assessment succeeded for upgrading child %s, but success window has not passed yet", existingUpgradingChildDef.GetName())
+			} else {
+				numaLogger.Debugf("Assessment failed for upgrading child %s, checking again...", existingUpgradingChildDef.GetName())
+			}
 			// Still waiting for a success window to pass
 			return apiv1.AssessmentResultUnknown, "", nil
 		}
@@ -175,15 +168,34 @@ func (r *PipelineRolloutReconciler) checkAnalysisTemplates(ctx context.Context,
 	existingUpgradingChildDef *unstructured.Unstructured) (apiv1.AssessmentResult, string, error) {
 
 	numaLogger := logger.FromContext(ctx)
+
+	// a user may have requested to skip the analysis of this upgrading child
+	if progressive.IsAnalysisSkipped(pipelineRollout, existingUpgradingChildDef.GetName()) {
+		numaLogger.Debugf("Skipping analysis for upgrading child %s as requested by user", existingUpgradingChildDef.GetName())
+		return apiv1.AssessmentResultSuccess, "", nil
+	}
+
 	analysis := pipelineRollout.GetAnalysis()
 
-	// only check for and create AnalysisRun if templates are specified
-	if len(analysis.Templates) > 0 {
-		// this will create an AnalysisRun if it doesn't exist yet; or otherwise it will check if it's finished running
-		numaLogger.Debugf("Performing analysis for upgrading child %s", existingUpgradingChildDef.GetName())
-		analysisStatus, err := progressive.PerformAnalysis(ctx, existingUpgradingChildDef, pipelineRollout, pipelineRollout.GetAnalysis(), pipelineRollout.GetAnalysisStatus(), r.client)
-		if err != nil {
-			return apiv1.AssessmentResultUnknown, "", err
+	// only perform analysis if templates or metrics are specified
+	if len(analysis.Templates) > 0 || len(analysis.Metrics) > 0 {
+		analysisStatus := pipelineRollout.GetAnalysisStatus()
+		var err error
+		if len(analysis.Templates) > 0 {
+			// this will create an AnalysisRun if it doesn't exist yet; or otherwise it will check if it's finished running
+			numaLogger.Debugf("Performing analysis for upgrading child %s", existingUpgradingChildDef.GetName())
+			analysisStatus, err = progressive.PerformAnalysis(ctx, existingUpgradingChildDef, pipelineRollout, analysis, analysisStatus, r.client)
+			if err != nil {
+				return apiv1.AssessmentResultUnknown, "", err
+			}
+		}
+		if len(analysis.Metrics) > 0 {
+			// this measures any Metrics which are due for measurement, without requiring Argo Rollouts
+			numaLogger.Debugf("Measuring analysis metrics for upgrading child %s", existingUpgradingChildDef.GetName())
+			analysisStatus, err = progressive.PerformMetricAnalysis(ctx, existingUpgradingChildDef, pipelineRollout, analysis, analysisStatus)
+			if err != nil {
+				return apiv1.AssessmentResultUnknown, "", err
+			}
 		}
 		return progressive.AssessAnalysisStatus(ctx, existingUpgradingChildDef, analysisStatus)
 	}
@@ -237,8 +249,9 @@ func (r *PipelineRolloutReconciler) CheckForDifferencesWithRolloutDef(ctx contex
 	}
 
 	// In order to effectively compare, we need to create a Pipeline Definition from the PipelineRollout which uses the same name and isbsvc name as our current Pipeline
-	// (so that won't be interpreted as a difference)
-	rolloutBasedPipelineDef, err := r.makePipelineDefinition(pipelineRollout, existingPipeline.GetName(), isbsvcName, pipelineRollout.Spec.Pipeline.Metadata)
+	// (so that won't be interpreted as a difference), templated with the same upgrade state
+	rolloutBasedPipelineDef, err := r.makePipelineDefinition(ctx, pipelineRollout, existingPipeline.GetName(), isbsvcName, pipelineRollout.Spec.Pipeline.Metadata,
+		ctlrcommon.GetTemplatedUpgradeState(existingPipeline))
 	if err != nil {
 		return false, err
 	}
@@ -419,6 +432,17 @@ func (r *PipelineRolloutReconciler) ProcessUpgradingChildPostSuccess(
 	return numaflowtypes.ApplyScaleValuesToLivePipeline(ctx, upgradingPipelineDef, upgradingPipelineStatus.OriginalScaleMinMax, c)
 }
 
+// ProcessChildrenPostStepAdvance is a no-op since Progressive Steps are not currently supported for PipelineRollout
+// This implements a function of the progressiveController interface
+func (r *PipelineRolloutReconciler) ProcessChildrenPostStepAdvance(
+	ctx context.Context,
+	rolloutObject progressive.ProgressiveRolloutObject,
+	promotedPipelineDef, upgradingPipelineDef *unstructured.Unstructured,
+	c client.Client,
+) (bool, error) {
+	return false, nil
+}
+
 /*
 ProcessUpgradingChildPreUpgrade handles the processing of an upgrading pipeline before it's been created
 It performs the following pre-upgrade operations:
@@ -766,6 +790,87 @@ func scalePromotedPipelineToOriginalScale(
 
 	return nil
 }
+
+/*
+ProcessPreviousChildPostPromotion handles the previously promoted pipeline when it's put on standby for the post-promotion analysis of the
+newly promoted pipeline: it scales all of its vertices to zero and returns their original scale values, so that it can be revived later.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - rolloutObject: the PipelineRollout instance
+  - previousPipelineDef: the definition of the previously promoted pipeline as an unstructured object.
+  - c: the client used for interacting with the Kubernetes API.
+
+Returns:
+  - The original scale values of the pipeline, keyed by vertex name.
+  - An error if any issues occur during processing.
+*/
+func (r *PipelineRolloutReconciler) ProcessPreviousChildPostPromotion(
+	ctx context.Context,
+	rolloutObject progressive.ProgressiveRolloutObject,
+	previousPipelineDef *unstructured.Unstructured,
+	c client.Client,
+) (map[string]apiv1.ScaleValues, error) {
+
+	pipelineRollout, ok := rolloutObject.(*apiv1.PipelineRollout)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type for ProgressiveRolloutObject: %+v; can't process previous pipeline post-promotion", rolloutObject)
+	}
+
+	// the pipeline was scaled down during the upgrade, so its original scale values are those stored in the promoted status if present
+	promotedPipelineStatus := pipelineRollout.Status.ProgressiveStatus.PromotedPipelineStatus
+	var scaleValues map[string]apiv1.ScaleValues
+	if promotedPipelineStatus != nil && promotedPipelineStatus.Name == previousPipelineDef.GetName() && promotedPipelineStatus.ScaleValues != nil {
+		scaleValues = promotedPipelineStatus.ScaleValues
+	} else {
+		vertexScaleDefinitions, err := numaflowtypes.GetScaleValuesFromPipelineDefinition(ctx, previousPipelineDef)
+		if err != nil {
+			return nil, err
+		}
+		scaleValues = make(map[string]apiv1.ScaleValues, len(vertexScaleDefinitions))
+		for _, vertexScaleDefinition := range vertexScaleDefinitions {
+			originalScaleMinMax := "null"
+			if vertexScaleDefinition.ScaleDefinition != nil {
+				scaleJSON, err := json.Marshal(vertexScaleDefinition.ScaleDefinition)
+				if err != nil {
+					return nil, err
+				}
+				originalScaleMinMax = string(scaleJSON)
+			}
+			scaleValues[vertexScaleDefinition.VertexName] = apiv1.ScaleValues{OriginalScaleMinMax: originalScaleMinMax}
+		}
+	}
+
+	if err := numaflowtypes.ScalePipelineVerticesToZero(ctx, previousPipelineDef, c); err != nil {
+		return nil, fmt.Errorf("error scaling the previous pipeline to zero: %w", err)
+	}
+
+	return scaleValues, nil
+}
+
+// RevivePreviousChild scales the vertices of the previously promoted pipeline back to their original scale values
+// This implements a function of the progressiveController interface
+func (r *PipelineRolloutReconciler) RevivePreviousChild(
+	ctx context.Context,
+	rolloutObject progressive.ProgressiveRolloutObject,
+	previousPipelineDef *unstructured.Unstructured,
+	scaleValues map[string]apiv1.ScaleValues,
+	c client.Client,
+) error {
+	return scalePromotedPipelineToOriginalScale(ctx, &apiv1.PromotedPipelineStatus{
+		PromotedPipelineTypeStatus: apiv1.PromotedPipelineTypeStatus{
+			PromotedChildStatus: apiv1.PromotedChildStatus{Name: previousPipelineDef.GetName()},
+			ScaleValues:         scaleValues,
+		},
+	}, previousPipelineDef, c)
+}
+
+// GetRecorder returns the EventRecorder used to emit Events for the Rollout
+// This implements a function of the progressiveController interface
+func (r *PipelineRolloutReconciler) GetRecorder() record.EventRecorder {
+	return r.recorder
+}
+
 func (r *PipelineRolloutReconciler) ProgressiveUnsupported(ctx context.Context, rolloutObject progressive.ProgressiveRolloutObject) bool {
 	numaLogger := logger.FromContext(ctx)
 
@@ -16,6 +16,7 @@ import (
 	"github.com/numaproj/numaplane/internal/common"
 	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
 	"github.com/numaproj/numaplane/internal/controller/common/numaflowtypes"
+	"github.com/numaproj/numaplane/internal/controller/common/templates"
 	"github.com/numaproj/numaplane/internal/controller/config"
 	"github.com/numaproj/numaplane/internal/util/kubernetes"
 	"github.com/numaproj/numaplane/internal/util/logger"
@@ -40,6 +41,9 @@ func (r *PipelineRolloutReconciler) Recycle(
 	if err != nil {
 		return false, fmt.Errorf("failed to get rollout for pipeline %s/%s: %w", pipeline.GetNamespace(), pipeline.GetName(), err)
 	}
+	if err := templates.ResolveTemplateRef(ctx, c, pipelineRollout); err != nil {
+		return false, err
+	}
 
 	// Need to determine how to delete the pipeline
 	// Use the "upgrade-strategy-reason" Label to determine how
@@ -67,8 +71,10 @@ func (r *PipelineRolloutReconciler) Recycle(
 			requiresPause = true
 			// first attempt the pause with the original spec because it may be able to pause on its own
 			requiresPauseOriginalSpec = true
-		case common.LabelValueProgressiveReplacedFailed:
+		case common.LabelValueProgressiveReplacedFailed, common.LabelValuePostPromotionRolledBack, common.LabelValueProgressiveRolledBack:
 			// LabelValueProgressiveReplacedFailed is the case of the "upgrading" pipeline failing and then being replaced with a newer Pipeline
+			// LabelValuePostPromotionRolledBack is the case of the "promoted" pipeline failing its post-promotion analysis and being replaced by the previous Pipeline
+			// LabelValueProgressiveRolledBack is the case of the "upgrading" pipeline failing and being discarded by the AutoRollback policy
 			requiresPause = true
 			// We don't attempt to pause it with the original spec first since it's failed and is very unlikely to be able to pause on its own.
 			requiresPauseOriginalSpec = false
@@ -183,60 +189,77 @@ func (r *PipelineRolloutReconciler) registerFinalDrainStatus(namespace, pipeline
 func (r *PipelineRolloutReconciler) forceDrain(ctx context.Context, pipeline, promotedPipeline *unstructured.Unstructured, pipelineRollout *apiv1.PipelineRollout, originalSpec bool, c client.Client) (bool, error) {
 	numaLogger := logger.FromContext(ctx)
 
+	paused, drained, failed, err := forceDrainPipeline(ctx, pipeline, promotedPipeline, pipelineRollout, originalSpec, c)
+	if err != nil {
+		return false, err
+	}
+	// if it's either paused or failed, delete it
+	if paused {
+		numaLogger.WithValues("paused", paused, "drained", drained).Infof("Pipeline has the promoted pipeline's spec and has paused, now deleting it")
+		err = kubernetes.DeleteResource(ctx, c, pipeline)
+		if drained {
+			r.registerFinalDrainStatus(pipelineRollout.Namespace, pipelineRollout.Name, pipeline, true, metrics.LabelValueDrainResult_ForceDrain)
+		} else {
+			numaLogger.Debugf("Pipeline never drained, pipeline definition: %v", kubernetes.GetLoggableResource(pipeline))
+			r.registerFinalDrainStatus(pipelineRollout.Namespace, pipelineRollout.Name, pipeline, false, metrics.LabelValueDrainResult_NeverDrained)
+		}
+		return true, err
+	}
+	// If force drain failed, we need to wait some time before deleting it, as there may be transient failures.
+	if failed {
+		return r.checkForFailedPipeline(ctx, c, pipelineRollout, pipeline)
+	}
+
+	return false, nil
+}
+
+// drive the force drain of a pipeline by applying the promoted pipeline's spec over top it and then pausing it
+// (see forceDrain for the parameters)
+// return:
+// - whether phase==Paused
+// - whether fully drained
+// - whether failed
+// - error if any
+func forceDrainPipeline(ctx context.Context, pipeline, promotedPipeline *unstructured.Unstructured, pipelineRollout *apiv1.PipelineRollout, originalSpec bool, c client.Client) (bool, bool, bool, error) {
+	numaLogger := logger.FromContext(ctx)
+
 	// if we still have the original spec, we need to update with the promoted pipeline's spec
 	if originalSpec {
 		numaLogger.WithValues("promotedPipeline", promotedPipeline.GetName()).Info("Found promoted pipeline, will force apply it")
 		// update spec with desiredPhase=Running and scaled to 0 initially, plus update the annotation to indicate that we've overridden the spec
 		err := forceApplySpecOnUndrainablePipeline(ctx, pipeline, promotedPipeline, c)
-		return false, err
+		return false, false, false, err
 	}
 
 	// we need to make sure we get out of the previous Paused state before we Pause again, just to make sure that Numaflow will restart the pause
 	// if desiredPhase==Running and phase==Paused, return
 	desiredPhase, err := numaflowtypes.GetPipelineDesiredPhase(pipeline)
 	if err != nil {
-		return false, err
+		return false, false, false, err
 	}
 	isPaused := numaflowtypes.CheckPipelinePhase(ctx, pipeline, numaflowv1.PipelinePhasePaused)
 	if desiredPhase == string(numaflowv1.PipelinePhaseRunning) && isPaused {
 		numaLogger.WithValues("desiredPhase", desiredPhase, "currentPhase", "Paused").Debug("Pipeline transitioning from paused to running, waiting for completion")
-		return false, nil
+		return false, false, false, nil
 	}
 
 	// just to be sure, we also verify that observedGeneration==generation in order to confirm that numaflow has reconciled our previous changes first before we set desiredPhase=Running
 	pipelineReconciled, generation, observedGeneration, err := numaflowtypes.CheckPipelineObservedGeneration(ctx, pipeline)
 	if err != nil {
-		return false, fmt.Errorf("error checking pipeline %s/%s observed generation: %v", pipeline.GetNamespace(), pipeline.GetName(), err)
+		return false, false, false, fmt.Errorf("error checking pipeline %s/%s observed generation: %v", pipeline.GetNamespace(), pipeline.GetName(), err)
 	}
 	if !pipelineReconciled {
 		numaLogger.WithValues("generation", generation, "observedGeneration", observedGeneration).Debug("waiting for pipeline observedGeneration to match generation")
-		return false, nil
+		return false, false, false, nil
 	}
 
 	// perform the drain
 	paused, drained, failed, err := drainRecyclablePipeline(ctx, pipeline, pipelineRollout, c)
 	if err != nil {
-		return false, fmt.Errorf("failed to drain recyclable pipeline %s/%s: %w", pipeline.GetNamespace(), pipeline.GetName(), err)
+		return false, false, false, fmt.Errorf("failed to drain recyclable pipeline %s/%s: %w", pipeline.GetNamespace(), pipeline.GetName(), err)
 	}
 	numaLogger.WithValues("paused", paused, "drained", drained, "failed", failed).Debug("checking drain of Pipeline using latest promoted pipeline's spec")
-	// if it's either paused or failed, delete it
-	if paused {
-		numaLogger.WithValues("paused", paused, "drained", drained).Infof("Pipeline has the promoted pipeline's spec and has paused, now deleting it")
-		err = kubernetes.DeleteResource(ctx, c, pipeline)
-		if drained {
-			r.registerFinalDrainStatus(pipelineRollout.Namespace, pipelineRollout.Name, pipeline, true, metrics.LabelValueDrainResult_ForceDrain)
-		} else {
-			numaLogger.Debugf("Pipeline never drained, pipeline definition: %v", kubernetes.GetLoggableResource(pipeline))
-			r.registerFinalDrainStatus(pipelineRollout.Namespace, pipelineRollout.Name, pipeline, false, metrics.LabelValueDrainResult_NeverDrained)
-		}
-		return true, err
-	}
-	// If force drain failed, we need to wait some time before deleting it, as there may be transient failures.
-	if failed {
-		return r.checkForFailedPipeline(ctx, c, pipelineRollout, pipeline)
-	}
-
-	return false, nil
+	return paused, drained, failed, nil
 }
 
 // checkForFailedPipeline checks if the Pipeline has been in Failed state for long enough to consider it a permanent failure
@@ -370,12 +393,12 @@ func drainRecyclablePipeline(
 		recycleScaleFactor := getRecycleScaleFactor(pipelineRollout)
 		numaLogger.WithValues("scaleFactor", recycleScaleFactor).Debug("scale factor to scale down by during pausing")
 
-		newVertexScaleDefinitions, err := calculateScaleForRecycle(ctx, pipeline, pipelineRollout, recycleScaleFactor)
+		newVertexScaleDefinitions, err := calculateScaleForRecycle(ctx, pipeline, pipelineRollout, recycleScaleFactor, c)
 		if err != nil {
 			return false, false, false, err
 		}
 
-		newPauseGracePeriodSeconds, err := calculatePauseTimeForRecycle(ctx, pipeline, pipelineRollout, 100.0/float64(recycleScaleFactor))
+		newPauseGracePeriodSeconds, err := calculatePauseTimeForRecycle(ctx, pipeline, pipelineRollout, 100.0/float64(recycleScaleFactor), c)
 		if err != nil {
 			return false, false, false, err
 		}
@@ -427,11 +450,12 @@ func calculatePauseTimeForRecycle(
 	pipeline *unstructured.Unstructured,
 	pipelineRollout *apiv1.PipelineRollout,
 	multiplier float64,
+	c client.Client,
 ) (int64, error) {
 	numaLogger := logger.FromContext(ctx)
 
 	// get pipeline spec
-	pipelineSpec, err := numaflowtypes.GetPipelineSpecFromRollout(pipeline.GetName(), pipelineRollout)
+	pipelineSpec, err := numaflowtypes.GetPipelineSpecFromRollout(ctx, c, pipeline, pipelineRollout)
 	if err != nil {
 		return 0, err
 	}
@@ -456,6 +480,7 @@ func calculateScaleForRecycle(
 	pipeline *unstructured.Unstructured,
 	pipelineRollout *apiv1.PipelineRollout,
 	percent int32,
+	c client.Client,
 ) ([]apiv1.VertexScaleDefinition, error) {
 	numaLogger := logger.FromContext(ctx)
 
@@ -467,7 +492,7 @@ func calculateScaleForRecycle(
 
 	// get the definition of the pipeline spec in the PipelineRollout: if we don't have the historical pod count for a given vertex because it's new
 	// then we will need to refer here for the scale.min value
-	pipelineRolloutDefinedSpec, err := numaflowtypes.GetPipelineSpecFromRollout(pipeline.GetName(), pipelineRollout)
+	pipelineRolloutDefinedSpec, err := numaflowtypes.GetPipelineSpecFromRollout(ctx, c, pipeline, pipelineRollout)
 	if err != nil {
 		return nil, err
 	}
@@ -569,20 +594,16 @@ func getRecycleScaleFactor(pipelineRollout *apiv1.PipelineRollout) int32 {
 		return *pipelineRollout.Spec.Strategy.RecycleStrategy.ScaleFactor
 	}
 
-	// get the globally configured strategy
+	// get the globally configured strategy, or otherwise the default
 	globalConfig, _ := config.GetConfigManagerInstance().GetConfig()
-	if globalConfig.Pipeline.RecycleScaleFactor != nil {
-		return *globalConfig.Pipeline.RecycleScaleFactor
-	}
-	// not defined, return default
-	return 50
+	return globalConfig.Pipeline.GetRecycleScaleFactor()
 }
 
 // if the user has set desiredPhase=Paused or any Vertex to scale.max=0, it means the user prefers not to run their Pipeline
 // so we should hold off on draining it until they set the PipelineRollout back for running again
 // Scale it to zero in the meantime (if it's not)
 func checkUserDesiresPause(ctx context.Context, pipelineRollout *apiv1.PipelineRollout, pipeline *unstructured.Unstructured, c client.Client) (bool, error) {
-	pipelineSpec, err := numaflowtypes.GetPipelineSpecFromRollout(pipeline.GetName(), pipelineRollout)
+	pipelineSpec, err := numaflowtypes.GetPipelineSpecFromRollout(ctx, c, pipeline, pipelineRollout)
 	if err != nil {
 		return false, err
 	}
@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctlrruntimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/numaproj/numaplane/internal/common"
 	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
@@ -241,7 +242,7 @@ func Test_calculateScaleForRecycle(t *testing.T) {
 			}
 
 			// Call the function
-			result, err := calculateScaleForRecycle(ctx, pipeline, pipelineRollout, tc.percent)
+			result, err := calculateScaleForRecycle(ctx, pipeline, pipelineRollout, tc.percent, fake.NewClientBuilder().Build())
 
 			// Check error expectations
 			if tc.expectedError {
@@ -385,6 +386,17 @@ func Test_Recycle(t *testing.T) {
 			expectSpecOverridden:  false,
 			expectedError:         false,
 		},
+		{
+			name:                  "Progressive Rolled Back - not deleted: force drain with the promoted spec",
+			upgradeStateReason:    string(common.LabelValueProgressiveRolledBack),
+			specHasBeenOverridden: false,
+			requiresDrain:         true,
+			pipelinePhase:         failed,
+			isPromotedPipelineNew: true,
+			expectedDeleted:       false, // a rolled back pipeline may still have data, so it must be drained first
+			expectSpecOverridden:  true,  // having failed, it's force drained with the promoted spec right away
+			expectedError:         false,
+		},
 		{
 			name:                           "Progressive Replace - second attempt (force drain failed)",
 			upgradeStateReason:             string(common.LabelValueProgressiveReplaced),
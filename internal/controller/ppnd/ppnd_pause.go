@@ -19,7 +19,9 @@ package ppnd
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8stypes "k8s.io/apimachinery/pkg/types"
@@ -28,6 +30,8 @@ import (
 	"github.com/numaproj/numaplane/internal/controller/common/numaflowtypes"
 	"github.com/numaproj/numaplane/internal/util"
 	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
 )
 
 var (
@@ -40,9 +44,15 @@ type PipelineDesiredPhase string
 const RunningDesiredPhase PipelineDesiredPhase = "Running"
 const PausedDesiredPhase PipelineDesiredPhase = "Paused"
 
+// prefixes of the keys of the pause requesters
+const (
+	numaflowControllerKeyPrefix = "NC:"
+	isbServiceKeyPrefix         = "I:"
+)
+
 func GetPauseModule() *PauseModule {
 	once.Do(func() {
-		pauseModuleInstance = &PauseModule{PauseRequests: make(map[string]*bool)}
+		pauseModuleInstance = &PauseModule{PauseRequests: make(map[string]*bool), pauseRequestDetails: make(map[string]PauseRequestDetails)}
 	})
 
 	return pauseModuleInstance
@@ -52,6 +62,22 @@ type PauseModule struct {
 	lock sync.RWMutex
 	// map of pause requester to Pause Request
 	PauseRequests map[string]*bool // having *bool gives us 3 states: [true=pause-required, false=pause-not-required, nil=unknown]
+	// map of pause requester to the details of its Pause Request, for those which are requesting pause
+	pauseRequestDetails map[string]PauseRequestDetails
+	// map of pause requester to the Pipelines which have been warned about proceeding without pausing during its current Pause Request
+	dataLossWarnings map[string]map[string]bool
+}
+
+// PauseRequestDetails describes which Rollout is requesting Pipelines to pause and why
+type PauseRequestDetails struct {
+	// the kind and name of the requesting Rollout
+	RequesterKind string
+	RequesterName string
+	Reason        string
+	// when the Rollout started requesting the pause
+	StartTime time.Time
+	// the generation of the Rollout which requires the pause
+	Generation int64
 }
 
 func (pm *PauseModule) NewPauseRequest(requester string) {
@@ -67,14 +93,18 @@ func (pm *PauseModule) DeletePauseRequest(requester string) {
 	pm.lock.Lock()
 	defer pm.lock.Unlock()
 	delete(pm.PauseRequests, requester)
+	delete(pm.pauseRequestDetails, requester)
+	delete(pm.dataLossWarnings, requester)
 }
 
 // update and return whether the value changed
-func (pm *PauseModule) UpdatePauseRequest(requester string, pause bool) bool {
+// requesterName and generation identify the Rollout making the request and its generation which requires the pause
+func (pm *PauseModule) UpdatePauseRequest(requester string, pause bool, requesterName string, generation int64) bool {
 	// first check to see if the same using read lock
 	pm.lock.RLock()
 	entry := pm.PauseRequests[requester]
-	if entry != nil && *entry == pause {
+	details, detailsFound := pm.pauseRequestDetails[requester]
+	if entry != nil && *entry == pause && (!pause || (detailsFound && details.Generation == generation)) {
 		// nothing to do
 		pm.lock.RUnlock()
 		return false
@@ -84,10 +114,136 @@ func (pm *PauseModule) UpdatePauseRequest(requester string, pause bool) bool {
 	// if not the same, use write lock to modify
 	pm.lock.Lock()
 	defer pm.lock.Unlock()
+	changed := entry == nil || *entry != pause
 	pm.PauseRequests[requester] = &pause
+	if pause {
+		pm.setPauseRequestDetails(requester, requesterName, generation, time.Now())
+	} else {
+		delete(pm.pauseRequestDetails, requester)
+		delete(pm.dataLossWarnings, requester)
+	}
+	return changed
+}
+
+// record the details of a request to pause: if the requester was already requesting to pause, it keeps its start time
+// (the caller must hold the write lock)
+func (pm *PauseModule) setPauseRequestDetails(requester string, requesterName string, generation int64, startTime time.Time) {
+	if pm.pauseRequestDetails == nil {
+		pm.pauseRequestDetails = make(map[string]PauseRequestDetails)
+	}
+	if existing, found := pm.pauseRequestDetails[requester]; found {
+		startTime = existing.StartTime
+	}
+	details := describeRequester(requester)
+	details.RequesterName = requesterName
+	details.StartTime = startTime
+	details.Generation = generation
+	pm.pauseRequestDetails[requester] = details
+}
+
+// GetActivePauseRequest returns the details of the requester's request, if it's requesting Pipelines to pause
+func (pm *PauseModule) GetActivePauseRequest(requester string) (PauseRequestDetails, bool) {
+	pm.lock.RLock()
+	defer pm.lock.RUnlock()
+	entry := pm.PauseRequests[requester]
+	if entry == nil || !*entry {
+		return PauseRequestDetails{}, false
+	}
+	if details, found := pm.pauseRequestDetails[requester]; found {
+		return details, true
+	}
+	return describeRequester(requester), true
+}
+
+// MarkDataLossWarned records that the Pipeline has been warned about proceeding without pausing during the requester's current request,
+// and returns whether it hadn't been already
+func (pm *PauseModule) MarkDataLossWarned(requester string, pipeline string) bool {
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	if pm.dataLossWarnings == nil {
+		pm.dataLossWarnings = make(map[string]map[string]bool)
+	}
+	if pm.dataLossWarnings[requester] == nil {
+		pm.dataLossWarnings[requester] = make(map[string]bool)
+	}
+	if pm.dataLossWarnings[requester][pipeline] {
+		return false
+	}
+	pm.dataLossWarnings[requester][pipeline] = true
 	return true
 }
 
+// describe the kind of Rollout which made the request, and why, from its key
+func describeRequester(requester string) PauseRequestDetails {
+	switch {
+	case strings.HasPrefix(requester, numaflowControllerKeyPrefix):
+		return PauseRequestDetails{RequesterKind: apiv1.NumaflowControllerRolloutGroupVersionKind.Kind, Reason: apiv1.PauseReasonNumaflowControllerUpdate}
+	case strings.HasPrefix(requester, isbServiceKeyPrefix):
+		// the key includes the name of the ISBServiceRollout
+		_, name, _ := strings.Cut(strings.TrimPrefix(requester, isbServiceKeyPrefix), "/")
+		return PauseRequestDetails{RequesterKind: apiv1.ISBServiceRolloutGroupVersionKind.Kind, RequesterName: name, Reason: apiv1.PauseReasonISBServiceUpdate}
+	default:
+		return PauseRequestDetails{}
+	}
+}
+
+// Rehydrate restores the pause requests from the Statuses of the ISBServiceRollouts and NumaflowControllerRollouts which made them,
+// so that a newly started (or newly elected) Numaplane doesn't consider them unknown until each of those Rollouts reconciles again.
+// Any request which has already been made in memory is newer and is kept.
+func (pm *PauseModule) Rehydrate(ctx context.Context, c client.Reader) error {
+	numaLogger := logger.FromContext(ctx)
+
+	type persistedRequest struct {
+		pause       *bool
+		name        string
+		generation  int64
+		pauseStatus apiv1.PauseStatus
+	}
+	persistedRequests := map[string]persistedRequest{}
+
+	isbServiceRollouts := &apiv1.ISBServiceRolloutList{}
+	if err := c.List(ctx, isbServiceRollouts); err != nil {
+		return fmt.Errorf("error listing ISBServiceRollouts: %w", err)
+	}
+	for _, isbServiceRollout := range isbServiceRollouts.Items {
+		if isbServiceRollout.DeletionTimestamp.IsZero() {
+			persistedRequests[pm.GetISBServiceKey(isbServiceRollout.Namespace, isbServiceRollout.Name)] = persistedRequest{
+				isbServiceRollout.Status.PauseRequested, isbServiceRollout.Name, isbServiceRollout.Status.ObservedGeneration, isbServiceRollout.Status.PauseRequestStatus}
+		}
+	}
+
+	nfcRollouts := &apiv1.NumaflowControllerRolloutList{}
+	if err := c.List(ctx, nfcRollouts); err != nil {
+		return fmt.Errorf("error listing NumaflowControllerRollouts: %w", err)
+	}
+	for _, nfcRollout := range nfcRollouts.Items {
+		if nfcRollout.DeletionTimestamp.IsZero() {
+			persistedRequests[pm.GetNumaflowControllerKey(nfcRollout.Namespace)] = persistedRequest{
+				nfcRollout.Status.PauseRequested, nfcRollout.Name, nfcRollout.Status.ObservedGeneration, nfcRollout.Status.PauseRequestStatus}
+		}
+	}
+
+	pm.lock.Lock()
+	defer pm.lock.Unlock()
+	for requester, request := range persistedRequests {
+		if pm.PauseRequests[requester] != nil {
+			continue
+		}
+		if request.pause == nil {
+			pm.PauseRequests[requester] = nil
+			continue
+		}
+		pause := *request.pause
+		pm.PauseRequests[requester] = &pause
+		if pause {
+			// the pause began when the Rollout last started requesting it
+			pm.setPauseRequestDetails(requester, request.name, request.generation, request.pauseStatus.LastPauseBeginTime.Time)
+		}
+		numaLogger.Debugf("rehydrated pause request for %q: %t", requester, pause)
+	}
+	return nil
+}
+
 func (pm *PauseModule) GetPauseRequest(requester string) (*bool, bool) {
 	pm.lock.RLock()
 	defer pm.lock.RUnlock()
@@ -155,9 +311,9 @@ func (pm *PauseModule) SetPipelineLifecycleRunning(ctx context.Context, c client
 }
 
 func (pm *PauseModule) GetNumaflowControllerKey(namespace string) string {
-	return fmt.Sprintf("NC:%s", namespace)
+	return fmt.Sprintf("%s%s", numaflowControllerKeyPrefix, namespace)
 }
 
 func (pm *PauseModule) GetISBServiceKey(namespace string, name string) string {
-	return fmt.Sprintf("I:%s/%s", namespace, name)
+	return fmt.Sprintf("%s%s/%s", isbServiceKeyPrefix, namespace, name)
 }
@@ -22,6 +22,7 @@ import (
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
@@ -43,15 +44,25 @@ type PauseRequester interface {
 }
 
 // process a child object, pausing pipelines or resuming pipelines if needed
+// if the pipelines don't all pause within their pause timeout, their PauseTimeoutPolicy is applied
 // return:
 // - true if done with PPND
 // - error if any (note we'll automatically reuqueue if there's an error anyway)
-func ProcessChildObjectWithPPND(ctx context.Context, k8sclient client.Client, rollout client.Object, pauseRequester PauseRequester,
+func ProcessChildObjectWithPPND(ctx context.Context, k8sclient client.Client, recorder record.EventRecorder, rollout PauseRequestingRollout, pauseRequester PauseRequester,
 	resourceNeedsUpdating bool, resourceIsUpdating bool, updateFunc func() error, enqueuePipelineFunc func(k8stypes.NamespacedName)) (bool, error) {
 	numaLogger := logger.FromContext(ctx)
 
-	rolloutNamespace := rollout.GetNamespace()
-	rolloutName := rollout.GetName()
+	if resourceNeedsUpdating && !resourceIsUpdating && rollout.GetPauseTimedOutGeneration() == rollout.GetGeneration() {
+		// the update of this generation of the Rollout already failed because the pipelines didn't pause in time:
+		// let them keep running until the Rollout changes
+		numaLogger.Debugf("%s update failed due to pause timeout for generation %d; not requesting Pipelines pause", pauseRequester.GetChildTypeString(), rollout.GetGeneration())
+		rollout.GetRolloutStatus().MarkFailed(fmt.Sprintf("%s update failed: Pipelines didn't pause within their pause timeout; update the Rollout to try again",
+			pauseRequester.GetChildTypeString()))
+		if _, err := requestPipelinesPause(ctx, pauseRequester, rollout, false, enqueuePipelineFunc); err != nil {
+			return false, fmt.Errorf("error requesting Pipelines resume: %w", err)
+		}
+		return true, nil
+	}
 
 	if resourceNeedsUpdating || resourceIsUpdating {
 		numaLogger.Infof("%s either needs to or is in the process of updating", pauseRequester.GetChildTypeString())
@@ -67,10 +78,20 @@ func ProcessChildObjectWithPPND(ctx context.Context, k8sclient client.Client, ro
 		if !pauseRequestUpdated && resourceNeedsUpdating {
 
 			// check if the pipelines are all paused (or can't be paused)
-			allPaused, err := areAllPipelinesPausedOrWontPause(ctx, k8sclient, pauseRequester, rolloutNamespace, rolloutName)
+			allPaused, timeouts, err := areAllPipelinesPausedOrWontPause(ctx, k8sclient, pauseRequester, rollout)
 			if err != nil {
 				return false, fmt.Errorf("error checking if all Pipelines are paused: %w", err)
 			}
+			fail, err := escalatePauseTimeouts(ctx, k8sclient, recorder, pauseRequester, timeouts, enqueuePipelineFunc)
+			if err != nil {
+				return false, err
+			}
+			if fail {
+				if err := failUpgradeOnPauseTimeout(ctx, recorder, pauseRequester, rollout, timeouts, enqueuePipelineFunc); err != nil {
+					return false, err
+				}
+				return true, nil
+			}
 			if allPaused {
 				numaLogger.Infof("confirmed all Pipelines have paused (or can't pause) so %s can safely update", pauseRequester.GetChildTypeString())
 				err = updateFunc()
@@ -102,6 +123,17 @@ func IsRequestingPause(pauseRequester PauseRequester, rollout client.Object) boo
 	return found && requested != nil && *requested
 }
 
+// GetPersistedPauseRequest returns a copy of this Rollout's pause request (nil if unknown), to be persisted in its Status
+// so it can be rehydrated after a restart or change of leader
+func GetPersistedPauseRequest(pauseRequester PauseRequester, rollout client.Object) *bool {
+	requested, found := GetPauseModule().GetPauseRequest(pauseRequester.GetRolloutKey(rollout.GetNamespace(), rollout.GetName()))
+	if !found || requested == nil {
+		return nil
+	}
+	pause := *requested
+	return &pause
+}
+
 // request that the Pipelines corresponding to this Rollout pause
 // return whether an update was made
 func requestPipelinesPause(ctx context.Context, pauseRequester PauseRequester, rollout client.Object, pause bool, enqueuePipelineFunc func(k8stypes.NamespacedName)) (bool, error) {
@@ -109,7 +141,7 @@ func requestPipelinesPause(ctx context.Context, pauseRequester PauseRequester, r
 
 	pm := GetPauseModule()
 
-	updated := pm.UpdatePauseRequest(pauseRequester.GetRolloutKey(rollout.GetNamespace(), rollout.GetName()), pause)
+	updated := pm.UpdatePauseRequest(pauseRequester.GetRolloutKey(rollout.GetNamespace(), rollout.GetName()), pause, rollout.GetName(), rollout.GetGeneration())
 	if updated { // if the value is different from what it was then make sure we queue the pipelines to be processed
 		numaLogger.Infof("updated pause request = %t", pause)
 		pipelines, err := pauseRequester.GetPipelineList(ctx, rollout.GetNamespace(), rollout.GetName())
@@ -130,33 +162,48 @@ func requestPipelinesPause(ctx context.Context, pauseRequester PauseRequester, r
 
 // check if all Pipelines corresponding to this Rollout have paused or are otherwise not pausible (contract with Numaflow is that this is Pipelines which are "Failed")
 // or have an exception for allowing data loss
-func areAllPipelinesPausedOrWontPause(ctx context.Context, k8sClient client.Client, pauseRequester PauseRequester, rolloutNamespace string, rolloutName string) (bool, error) {
+// Pipelines which haven't paused within their pause timeout are also returned: those whose PauseTimeoutPolicy is "Proceed" are considered not
+// pausible
+func areAllPipelinesPausedOrWontPause(ctx context.Context, k8sClient client.Client, pauseRequester PauseRequester, rollout client.Object) (bool, []pauseTimeout, error) {
 	numaLogger := logger.FromContext(ctx)
-	pipelines, err := pauseRequester.GetPipelineList(ctx, rolloutNamespace, rolloutName)
+	rolloutNamespace := rollout.GetNamespace()
+	pipelines, err := pauseRequester.GetPipelineList(ctx, rolloutNamespace, rollout.GetName())
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
+	allPaused := true
+	timeouts := []pauseTimeout{}
 	for _, pipeline := range pipelines.Items {
 
 		// Get PipelineRollout CR
 		pipelineRolloutName, err := ctlrcommon.GetRolloutParentName(pipeline.GetName())
 		if err != nil {
-			return false, err
+			return false, nil, err
 		}
 		pipelineRollout := &apiv1.PipelineRollout{}
 		if err = k8sClient.Get(ctx, k8stypes.NamespacedName{Namespace: rolloutNamespace, Name: pipelineRolloutName}, pipelineRollout); err != nil {
-			return false, err
+			return false, nil, err
 		}
 
 		pausedOrWontPause, err := numaflowtypes.IsPipelinePausedOrWontPause(ctx, &pipeline, pipelineRollout, false)
 		if err != nil {
-			return false, err
+			return false, nil, err
 		}
 
 		if !pausedOrWontPause {
 			numaLogger.Debugf("pipeline %q not paused/won't pause", pipeline.GetName())
-			return false, nil
+			timedOut, err := checkPauseTimeout(ctx, pauseRequester, rollout, pipeline.DeepCopy(), pipelineRollout)
+			if err != nil {
+				return false, nil, err
+			}
+			if timedOut != nil {
+				timeouts = append(timeouts, *timedOut)
+				if timedOut.policy == apiv1.PauseTimeoutPolicyProceed {
+					continue
+				}
+			}
+			allPaused = false
 		}
 	}
-	return true, nil
+	return allPaused, timeouts, nil
 }
@@ -0,0 +1,77 @@
+package ppnd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func TestPauseModule_Rehydrate(t *testing.T) {
+	pauseBeginTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	scheme := runtime.NewScheme()
+	assert.NoError(t, apiv1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&apiv1.ISBServiceRollout{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pausing"},
+			Status: apiv1.ISBServiceRolloutStatus{Status: apiv1.Status{ObservedGeneration: 3}, PauseRequested: ptr.To(true),
+				PauseRequestStatus: apiv1.PauseStatus{LastPauseBeginTime: pauseBeginTime}}},
+		&apiv1.ISBServiceRollout{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "never-requested"}},
+		&apiv1.ISBServiceRollout{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "already-requested"},
+			Status: apiv1.ISBServiceRolloutStatus{PauseRequested: ptr.To(true)}},
+		&apiv1.NumaflowControllerRollout{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "numaflow-controller"},
+			Status: apiv1.NumaflowControllerRolloutStatus{PauseRequested: ptr.To(false)}},
+	).Build()
+
+	pm := &PauseModule{PauseRequests: map[string]*bool{}}
+	// a request which has already been made in memory is newer than the one in the Rollout Status
+	pm.PauseRequests[pm.GetISBServiceKey("default", "already-requested")] = ptr.To(false)
+
+	assert.NoError(t, pm.Rehydrate(context.Background(), c))
+
+	assert.Equal(t, map[string]*bool{
+		pm.GetISBServiceKey("default", "pausing"):           ptr.To(true),
+		pm.GetISBServiceKey("default", "never-requested"):   nil,
+		pm.GetISBServiceKey("default", "already-requested"): ptr.To(false),
+		pm.GetNumaflowControllerKey("default"):              ptr.To(false),
+	}, pm.PauseRequests)
+
+	details, requested := pm.GetActivePauseRequest(pm.GetISBServiceKey("default", "pausing"))
+	assert.True(t, requested)
+	assert.Equal(t, PauseRequestDetails{RequesterKind: "ISBServiceRollout", RequesterName: "pausing", Reason: apiv1.PauseReasonISBServiceUpdate,
+		StartTime: pauseBeginTime.Time, Generation: 3}, details)
+}
+
+func TestPauseModule_UpdatePauseRequest(t *testing.T) {
+	pm := &PauseModule{PauseRequests: map[string]*bool{}}
+	requester := pm.GetNumaflowControllerKey("default")
+
+	_, requested := pm.GetActivePauseRequest(requester)
+	assert.False(t, requested)
+
+	assert.True(t, pm.UpdatePauseRequest(requester, true, "numaflow-controller", 1))
+	details, requested := pm.GetActivePauseRequest(requester)
+	assert.True(t, requested)
+	assert.Equal(t, "NumaflowControllerRollout", details.RequesterKind)
+	assert.Equal(t, "numaflow-controller", details.RequesterName)
+	assert.Equal(t, apiv1.PauseReasonNumaflowControllerUpdate, details.Reason)
+	assert.Equal(t, int64(1), details.Generation)
+	startTime := details.StartTime
+
+	// a newer generation still requesting the pause doesn't change whether it's requested, nor when it started
+	assert.False(t, pm.UpdatePauseRequest(requester, true, "numaflow-controller", 2))
+	details, _ = pm.GetActivePauseRequest(requester)
+	assert.Equal(t, int64(2), details.Generation)
+	assert.Equal(t, startTime, details.StartTime)
+
+	assert.True(t, pm.UpdatePauseRequest(requester, false, "numaflow-controller", 2))
+	_, requested = pm.GetActivePauseRequest(requester)
+	assert.False(t, requested)
+}
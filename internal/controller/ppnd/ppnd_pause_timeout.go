@@ -0,0 +1,191 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ppnd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// PauseRequestingRollout is a Rollout which requests its Pipelines to pause, and whose upgrade fails if they don't pause within their
+// pause timeout and the PauseTimeoutPolicy is "Fail"
+type PauseRequestingRollout interface {
+	client.Object
+
+	GetRolloutStatus() *apiv1.Status
+
+	// the generation of the Rollout whose upgrade failed because its Pipelines didn't pause in time (0 if none)
+	GetPauseTimedOutGeneration() int64
+	SetPauseTimedOutGeneration(generation int64)
+}
+
+// a Pipeline which didn't pause within its pause timeout
+type pauseTimeout struct {
+	// the key of the Rollout requesting the pause
+	requester       string
+	pipeline        *unstructured.Unstructured
+	pipelineRollout *apiv1.PipelineRollout
+	timeout         time.Duration
+	policy          apiv1.PauseTimeoutPolicy
+}
+
+// ResolvePauseTimeout returns how long to wait for the PipelineRollout's Pipeline to pause and what to do if it doesn't.
+// The PauseTimeout of the PipelineRollout takes precedence over the one configured for the namespace, which takes precedence
+// over the global one. A timeout of 0 means to wait indefinitely.
+func ResolvePauseTimeout(ctx context.Context, pipelineRollout *apiv1.PipelineRollout) (time.Duration, apiv1.PauseTimeoutPolicy, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	globalConfig, err := config.GetConfigManagerInstance().GetConfig()
+	if err != nil {
+		return 0, "", fmt.Errorf("error getting the global config: %v", err)
+	}
+	timeout := globalConfig.Pipeline.PauseTimeout
+	policy := globalConfig.Pipeline.PauseTimeoutPolicy
+
+	if namespaceConfig := config.GetConfigManagerInstance().GetNamespaceConfig(pipelineRollout.Namespace); namespaceConfig != nil {
+		if namespaceConfig.PauseTimeout != "" {
+			namespaceTimeout, err := time.ParseDuration(namespaceConfig.PauseTimeout)
+			if err != nil {
+				numaLogger.WithValues("pause timeout", namespaceConfig.PauseTimeout).Warnf("invalid pause timeout for namespace %s", pipelineRollout.Namespace)
+			} else {
+				timeout = namespaceTimeout
+			}
+		}
+		if namespaceConfig.PauseTimeoutPolicy != "" {
+			policy = namespaceConfig.PauseTimeoutPolicy
+		}
+	}
+
+	if pipelineRollout.Spec.Strategy != nil && pipelineRollout.Spec.Strategy.PPNDStrategy.PauseTimeout != nil {
+		rolloutPauseTimeout := pipelineRollout.Spec.Strategy.PPNDStrategy.PauseTimeout
+		if rolloutPauseTimeout.Duration != nil {
+			timeout = rolloutPauseTimeout.Duration.Duration
+		}
+		if rolloutPauseTimeout.Policy != "" {
+			policy = rolloutPauseTimeout.Policy
+		}
+	}
+
+	switch policy {
+	case apiv1.PauseTimeoutPolicyFail, apiv1.PauseTimeoutPolicyProceed, apiv1.PauseTimeoutPolicyForceDrain:
+	case "":
+		policy = apiv1.PauseTimeoutPolicyFail
+	default:
+		numaLogger.WithValues("pause timeout policy", policy).Warnf("invalid pause timeout policy for PipelineRollout %s/%s, using %s",
+			pipelineRollout.Namespace, pipelineRollout.Name, apiv1.PauseTimeoutPolicyFail)
+		policy = apiv1.PauseTimeoutPolicyFail
+	}
+
+	return timeout, policy, nil
+}
+
+// check whether the Pipeline has been asked to pause by this Rollout for longer than its pause timeout
+func checkPauseTimeout(ctx context.Context, pauseRequester PauseRequester, rollout client.Object, pipeline *unstructured.Unstructured,
+	pipelineRollout *apiv1.PipelineRollout) (*pauseTimeout, error) {
+
+	timeout, policy, err := ResolvePauseTimeout(ctx, pipelineRollout)
+	if err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		return nil, nil
+	}
+	requester := pauseRequester.GetRolloutKey(rollout.GetNamespace(), rollout.GetName())
+	details, requested := GetPauseModule().GetActivePauseRequest(requester)
+	if !requested || time.Since(details.StartTime) < timeout {
+		return nil, nil
+	}
+	return &pauseTimeout{requester: requester, pipeline: pipeline, pipelineRollout: pipelineRollout, timeout: timeout, policy: policy}, nil
+}
+
+// apply the PauseTimeoutPolicy of each Pipeline which didn't pause in time
+// return whether the upgrade needs to be failed
+func escalatePauseTimeouts(ctx context.Context, k8sClient client.Client, recorder record.EventRecorder, pauseRequester PauseRequester,
+	timeouts []pauseTimeout, enqueuePipelineFunc func(k8stypes.NamespacedName)) (bool, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	fail := false
+	for _, timedOut := range timeouts {
+		pipelineName := timedOut.pipeline.GetName()
+		switch timedOut.policy {
+		case apiv1.PauseTimeoutPolicyProceed:
+			// warn only once for each pause request
+			if !GetPauseModule().MarkDataLossWarned(timedOut.requester, pipelineName) {
+				continue
+			}
+			numaLogger.WithValues("pipeline", pipelineName, "timeout", timedOut.timeout).Warnf("Pipeline didn't pause in time, proceeding with %s update anyway",
+				pauseRequester.GetChildTypeString())
+			recorder.Eventf(timedOut.pipelineRollout, corev1.EventTypeWarning, "PauseTimeoutDataLoss",
+				"Pipeline %s didn't pause within %s, so %s is being updated without it paused, which may cause data loss",
+				pipelineName, timedOut.timeout, pauseRequester.GetChildTypeString())
+		case apiv1.PauseTimeoutPolicyForceDrain:
+			if _, found := timedOut.pipeline.GetAnnotations()[common.AnnotationKeyPauseTimeoutForceDrain]; found {
+				continue
+			}
+			numaLogger.WithValues("pipeline", pipelineName, "timeout", timedOut.timeout).Info("Pipeline didn't pause in time, requesting that it be force drained")
+			patchJson := fmt.Sprintf(`{"metadata": {"annotations": {"%s": "%s"}}}`, common.AnnotationKeyPauseTimeoutForceDrain, time.Now().Format(time.RFC3339))
+			if err := kubernetes.PatchResource(ctx, k8sClient, timedOut.pipeline, patchJson, k8stypes.MergePatchType); err != nil {
+				return false, fmt.Errorf("failed to request force drain of pipeline %s/%s: %w", timedOut.pipeline.GetNamespace(), pipelineName, err)
+			}
+			recorder.Eventf(timedOut.pipelineRollout, corev1.EventTypeWarning, "PauseTimeoutForceDrain",
+				"Pipeline %s didn't pause within %s for %s update, so it will be force drained", pipelineName, timedOut.timeout, pauseRequester.GetChildTypeString())
+			enqueuePipelineFunc(k8stypes.NamespacedName{Namespace: timedOut.pipelineRollout.Namespace, Name: timedOut.pipelineRollout.Name})
+		default:
+			fail = true
+		}
+	}
+	return fail, nil
+}
+
+// fail the upgrade of the Rollout because some of its Pipelines didn't pause in time, and let its Pipelines resume:
+// the upgrade won't be attempted again until the Rollout changes
+func failUpgradeOnPauseTimeout(ctx context.Context, recorder record.EventRecorder, pauseRequester PauseRequester, rollout PauseRequestingRollout,
+	timeouts []pauseTimeout, enqueuePipelineFunc func(k8stypes.NamespacedName)) error {
+	numaLogger := logger.FromContext(ctx)
+
+	pipelineNames := []string{}
+	for _, timedOut := range timeouts {
+		if timedOut.policy == apiv1.PauseTimeoutPolicyFail {
+			pipelineNames = append(pipelineNames, timedOut.pipeline.GetName())
+		}
+	}
+	msg := fmt.Sprintf("%s update failed: Pipelines %s didn't pause within their pause timeout", pauseRequester.GetChildTypeString(), strings.Join(pipelineNames, ", "))
+	numaLogger.Info(msg)
+	recorder.Event(rollout, corev1.EventTypeWarning, "PauseTimeout", msg)
+
+	rollout.SetPauseTimedOutGeneration(rollout.GetGeneration())
+	rollout.GetRolloutStatus().MarkFailed(msg)
+
+	if _, err := requestPipelinesPause(ctx, pauseRequester, rollout, false, enqueuePipelineFunc); err != nil {
+		return fmt.Errorf("error requesting Pipelines resume: %w", err)
+	}
+	return nil
+}
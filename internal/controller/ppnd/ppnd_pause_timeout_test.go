@@ -0,0 +1,218 @@
+package ppnd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/controller/config"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// a PauseRequester for an InterstepBufferService with no Pipelines of its own
+type fakePauseRequester struct{}
+
+func (fakePauseRequester) GetPipelineList(ctx context.Context, rolloutNamespace string, rolloutName string) (unstructured.UnstructuredList, error) {
+	return unstructured.UnstructuredList{}, nil
+}
+
+func (fakePauseRequester) GetRolloutKey(rolloutNamespace string, rolloutName string) string {
+	return GetPauseModule().GetISBServiceKey(rolloutNamespace, rolloutName)
+}
+
+func (fakePauseRequester) GetChildTypeString() string {
+	return "InterstepBufferService"
+}
+
+func newTimedOutPipeline(namespace string, name string) *unstructured.Unstructured {
+	pipeline := &unstructured.Unstructured{}
+	pipeline.SetAPIVersion("numaflow.numaproj.io/v1alpha1")
+	pipeline.SetKind("Pipeline")
+	pipeline.SetNamespace(namespace)
+	pipeline.SetName(name)
+	return pipeline
+}
+
+func TestResolvePauseTimeout(t *testing.T) {
+	const namespace = "pause-timeout-test"
+	configManager := config.GetConfigManagerInstance()
+	defer configManager.UnsetNamespaceConfig(namespace)
+
+	tests := []struct {
+		name            string
+		namespaceConfig *config.NamespaceConfig
+		rolloutTimeout  *apiv1.PauseTimeout
+		expectedTimeout time.Duration
+		expectedPolicy  apiv1.PauseTimeoutPolicy
+	}{
+		{
+			name:            "nothing configured",
+			expectedTimeout: 0,
+			expectedPolicy:  apiv1.PauseTimeoutPolicyFail,
+		},
+		{
+			name:            "namespace configured",
+			namespaceConfig: &config.NamespaceConfig{PauseTimeout: "30m", PauseTimeoutPolicy: apiv1.PauseTimeoutPolicyProceed},
+			expectedTimeout: 30 * time.Minute,
+			expectedPolicy:  apiv1.PauseTimeoutPolicyProceed,
+		},
+		{
+			name:            "rollout overrides namespace",
+			namespaceConfig: &config.NamespaceConfig{PauseTimeout: "30m", PauseTimeoutPolicy: apiv1.PauseTimeoutPolicyProceed},
+			rolloutTimeout:  &apiv1.PauseTimeout{Duration: &metav1.Duration{Duration: 5 * time.Minute}, Policy: apiv1.PauseTimeoutPolicyForceDrain},
+			expectedTimeout: 5 * time.Minute,
+			expectedPolicy:  apiv1.PauseTimeoutPolicyForceDrain,
+		},
+		{
+			name:            "rollout overrides only the policy",
+			namespaceConfig: &config.NamespaceConfig{PauseTimeout: "30m"},
+			rolloutTimeout:  &apiv1.PauseTimeout{Policy: apiv1.PauseTimeoutPolicyForceDrain},
+			expectedTimeout: 30 * time.Minute,
+			expectedPolicy:  apiv1.PauseTimeoutPolicyForceDrain,
+		},
+		{
+			name:            "invalid namespace values",
+			namespaceConfig: &config.NamespaceConfig{PauseTimeout: "soon", PauseTimeoutPolicy: "Ignore"},
+			expectedTimeout: 0,
+			expectedPolicy:  apiv1.PauseTimeoutPolicyFail,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.namespaceConfig != nil {
+				configManager.UpdateNamespaceConfig(namespace, *tc.namespaceConfig)
+			} else {
+				configManager.UnsetNamespaceConfig(namespace)
+			}
+			pipelineRollout := &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "my-pipeline"}}
+			if tc.rolloutTimeout != nil {
+				pipelineRollout.Spec.Strategy = &apiv1.PipelineStrategy{PPNDStrategy: apiv1.PPNDStrategy{PauseTimeout: tc.rolloutTimeout}}
+			}
+
+			timeout, policy, err := ResolvePauseTimeout(context.Background(), pipelineRollout)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedTimeout, timeout)
+			assert.Equal(t, tc.expectedPolicy, policy)
+		})
+	}
+}
+
+func TestEscalatePauseTimeouts(t *testing.T) {
+	const namespace = "escalate-test"
+	ctx := context.Background()
+	pauseRequester := fakePauseRequester{}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, apiv1.AddToScheme(scheme))
+
+	newTimeout := func(requester string, pipeline *unstructured.Unstructured, policy apiv1.PauseTimeoutPolicy) pauseTimeout {
+		return pauseTimeout{
+			requester:       requester,
+			pipeline:        pipeline,
+			pipelineRollout: &apiv1.PipelineRollout{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "my-pipeline"}},
+			timeout:         time.Minute,
+			policy:          policy,
+		}
+	}
+
+	t.Run("Fail fails the upgrade", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		requester := pauseRequester.GetRolloutKey(namespace, "fail")
+
+		fail, err := escalatePauseTimeouts(ctx, c, recorder, pauseRequester,
+			[]pauseTimeout{newTimeout(requester, newTimedOutPipeline(namespace, "my-pipeline-0"), apiv1.PauseTimeoutPolicyFail)},
+			func(k8stypes.NamespacedName) {})
+		assert.NoError(t, err)
+		assert.True(t, fail)
+		assert.Empty(t, recorder.Events)
+	})
+
+	t.Run("Proceed warns once for each pause request", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		pm := GetPauseModule()
+		requester := pauseRequester.GetRolloutKey(namespace, "proceed")
+		defer pm.DeletePauseRequest(requester)
+		timeouts := []pauseTimeout{newTimeout(requester, newTimedOutPipeline(namespace, "my-pipeline-0"), apiv1.PauseTimeoutPolicyProceed)}
+
+		pm.UpdatePauseRequest(requester, true, "proceed", 1)
+		for i := 0; i < 3; i++ {
+			fail, err := escalatePauseTimeouts(ctx, c, recorder, pauseRequester, timeouts, func(k8stypes.NamespacedName) {})
+			assert.NoError(t, err)
+			assert.False(t, fail)
+		}
+		assert.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "PauseTimeoutDataLoss")
+
+		// a new pause request warns again
+		pm.UpdatePauseRequest(requester, false, "proceed", 1)
+		pm.UpdatePauseRequest(requester, true, "proceed", 2)
+		_, err := escalatePauseTimeouts(ctx, c, recorder, pauseRequester, timeouts, func(k8stypes.NamespacedName) {})
+		assert.NoError(t, err)
+		assert.Len(t, recorder.Events, 1)
+	})
+
+	t.Run("ForceDrain requests the force drain once", func(t *testing.T) {
+		recorder := record.NewFakeRecorder(10)
+		pipeline := newTimedOutPipeline(namespace, "my-pipeline-0")
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pipeline.DeepCopy()).Build()
+		requester := pauseRequester.GetRolloutKey(namespace, "force-drain")
+		enqueued := []k8stypes.NamespacedName{}
+		enqueue := func(name k8stypes.NamespacedName) { enqueued = append(enqueued, name) }
+
+		fail, err := escalatePauseTimeouts(ctx, c, recorder, pauseRequester,
+			[]pauseTimeout{newTimeout(requester, pipeline, apiv1.PauseTimeoutPolicyForceDrain)}, enqueue)
+		assert.NoError(t, err)
+		assert.False(t, fail)
+		assert.Equal(t, []k8stypes.NamespacedName{{Namespace: namespace, Name: "my-pipeline"}}, enqueued)
+		assert.Contains(t, <-recorder.Events, "PauseTimeoutForceDrain")
+
+		livePipeline := newTimedOutPipeline(namespace, "my-pipeline-0")
+		assert.NoError(t, c.Get(ctx, k8stypes.NamespacedName{Namespace: namespace, Name: "my-pipeline-0"}, livePipeline))
+		assert.Contains(t, livePipeline.GetAnnotations(), common.AnnotationKeyPauseTimeoutForceDrain)
+
+		// the Pipeline has already been asked to force drain
+		_, err = escalatePauseTimeouts(ctx, c, recorder, pauseRequester,
+			[]pauseTimeout{newTimeout(requester, livePipeline, apiv1.PauseTimeoutPolicyForceDrain)}, enqueue)
+		assert.NoError(t, err)
+		assert.Len(t, enqueued, 1)
+		assert.Empty(t, recorder.Events)
+	})
+}
+
+func TestProcessChildObjectWithPPND_pauseTimedOut(t *testing.T) {
+	const namespace = "pause-timed-out-test"
+	ctx := context.Background()
+	pauseRequester := fakePauseRequester{}
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, apiv1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	rollout := &apiv1.ISBServiceRollout{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "my-isbsvc", Generation: 2}}
+	rollout.Status.PauseTimedOutGeneration = 2
+	requester := pauseRequester.GetRolloutKey(namespace, "my-isbsvc")
+	defer GetPauseModule().DeletePauseRequest(requester)
+	GetPauseModule().UpdatePauseRequest(requester, true, "my-isbsvc", 2)
+
+	updated := false
+	done, err := ProcessChildObjectWithPPND(ctx, c, record.NewFakeRecorder(10), rollout, pauseRequester, true, false,
+		func() error { updated = true; return nil }, func(k8stypes.NamespacedName) {})
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.False(t, updated)
+	assert.Equal(t, apiv1.PhaseFailed, rollout.Status.Phase)
+	// the Pipelines are allowed to resume
+	assert.False(t, IsRequestingPause(pauseRequester, rollout))
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progressive
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/common"
+	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// upgradeActionAnnotations are the annotations by which a user requests each UpgradeAction, in order of precedence
+var upgradeActionAnnotations = []struct {
+	action        apiv1.UpgradeAction
+	annotationKey string
+}{
+	{apiv1.UpgradeActionAbort, common.AnnotationKeyAbortUpgrade},
+	{apiv1.UpgradeActionRetry, common.AnnotationKeyRetryUpgrade},
+	{apiv1.UpgradeActionSkipAnalysis, common.AnnotationKeySkipAnalysis},
+}
+
+// getRequestedUpgradeAction returns the action requested by annotation for the given child which hasn't been acknowledged yet, if any.
+// The annotation must name the child, so that an action requested for a previous upgrade doesn't carry over.
+func getRequestedUpgradeAction(rolloutObject ProgressiveRolloutObject, childName string) (apiv1.UpgradeAction, bool) {
+	if childName == "" {
+		return "", false
+	}
+	annotations := rolloutObject.GetRolloutObjectMeta().GetAnnotations()
+	for _, actionAnnotation := range upgradeActionAnnotations {
+		if annotations[actionAnnotation.annotationKey] == childName &&
+			!isUpgradeActionAcknowledged(rolloutObject, actionAnnotation.action, childName) {
+			return actionAnnotation.action, true
+		}
+	}
+	return "", false
+}
+
+// isUpgradeActionAcknowledged determines if the action was already performed for the given child.
+// A skipped Analysis is also recorded on the upgrading child's status, so it stays acknowledged after later actions.
+func isUpgradeActionAcknowledged(rolloutObject ProgressiveRolloutObject, action apiv1.UpgradeAction, childName string) bool {
+	if action == apiv1.UpgradeActionSkipAnalysis {
+		upgradingChildStatus := rolloutObject.GetUpgradingChildStatus()
+		if upgradingChildStatus != nil && upgradingChildStatus.Name == childName && upgradingChildStatus.AnalysisSkipped {
+			return true
+		}
+	}
+	lastAction := rolloutObject.GetRolloutStatus().LastUpgradeAction
+	return lastAction != nil && lastAction.Action == action && lastAction.ChildName == childName
+}
+
+// IsAnalysisSkipped determines if a user requested to skip the Analysis of the given upgrading child
+func IsAnalysisSkipped(rolloutObject ProgressiveRolloutObject, upgradingChildName string) bool {
+	return isUpgradeActionAcknowledged(rolloutObject, apiv1.UpgradeActionSkipAnalysis, upgradingChildName)
+}
+
+/*
+processUpgradeAction performs any action requested by a user on the in-flight progressive upgrade, and acknowledges it
+in the Rollout Status and with an Event:
+  - abort: discontinues the upgrade and restores the promoted child; the same generation of the Rollout isn't attempted again
+  - retry: replaces the failed upgrading child (or the one which was rolled back) with a new one of the same spec
+  - skip-analysis: the Analysis of the upgrading child is treated as successful, while the basic assessment is still required
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - rolloutObject: the rollout object.
+  - controller: the progressive controller for the Kind.
+  - existingPromotedChildDef: the definition of the promoted child.
+  - existingUpgradingChildDef: the definition of the upgrading child, or nil if there isn't one.
+  - c: the client used for interacting with the Kubernetes API.
+
+Returns:
+  - Whether the caller should stop processing the upgrade for now.
+  - Whether the upgrade is done.
+  - An error if any issues occur during processing.
+*/
+func processUpgradeAction(
+	ctx context.Context,
+	rolloutObject ProgressiveRolloutObject,
+	controller progressiveController,
+	existingPromotedChildDef, existingUpgradingChildDef *unstructured.Unstructured,
+	c client.Client,
+) (bool, bool, error) {
+
+	// the action applies to the upgrading child if there is one, or otherwise to the last upgrading child, which may have been rolled back
+	var childName string
+	if existingUpgradingChildDef != nil {
+		childName = existingUpgradingChildDef.GetName()
+	} else if upgradingChildStatus := rolloutObject.GetUpgradingChildStatus(); upgradingChildStatus != nil {
+		childName = upgradingChildStatus.Name
+	}
+
+	action, found := getRequestedUpgradeAction(rolloutObject, childName)
+	if !found {
+		return false, false, nil
+	}
+
+	numaLogger := logger.FromContext(ctx).WithValues("action", action, "child", childName)
+	numaLogger.Info("processing upgrade action requested by user")
+
+	var stop, done bool
+	var message string
+	switch action {
+	case apiv1.UpgradeActionAbort:
+		if existingUpgradingChildDef == nil {
+			message = "there is no upgrade in progress to abort"
+			break
+		}
+		requeue, err := abortUpgrade(ctx, rolloutObject, controller, existingPromotedChildDef, existingUpgradingChildDef, c)
+		if err != nil || requeue {
+			// the action will be acknowledged once it's complete
+			return true, false, err
+		}
+		stop, done = true, true
+		message = fmt.Sprintf("upgrade aborted: %s was discontinued and %s remains promoted", childName, existingPromotedChildDef.GetName())
+
+	case apiv1.UpgradeActionRetry:
+		var err error
+		stop, message, err = retryUpgrade(ctx, rolloutObject, existingUpgradingChildDef, c)
+		if err != nil {
+			return false, false, err
+		}
+
+	case apiv1.UpgradeActionSkipAnalysis:
+		if existingUpgradingChildDef == nil {
+			message = "there is no upgrade in progress for which to skip analysis"
+			break
+		}
+		if upgradingChildStatus := rolloutObject.GetUpgradingChildStatus(); upgradingChildStatus != nil && upgradingChildStatus.Name == childName {
+			upgradingChildStatus.AnalysisSkipped = true
+			rolloutObject.SetUpgradingChildStatus(upgradingChildStatus)
+		}
+		message = fmt.Sprintf("analysis of %s will be skipped, but its basic assessment is still required", childName)
+	}
+
+	acknowledgeUpgradeAction(ctx, rolloutObject, controller, action, childName, message)
+	return stop, done, nil
+}
+
+// abortUpgrade restores the promoted child and discontinues the upgrade, marking the Rollout so that the same generation isn't attempted again
+// return true if requeue is needed (note this is ignored if error != nil)
+func abortUpgrade(
+	ctx context.Context,
+	rolloutObject ProgressiveRolloutObject,
+	controller progressiveController,
+	existingPromotedChildDef, existingUpgradingChildDef *unstructured.Unstructured,
+	c client.Client,
+) (bool, error) {
+	requeue, err := controller.ProcessPromotedChildPostFailure(ctx, rolloutObject, existingPromotedChildDef, c)
+	if err != nil || requeue {
+		return requeue, err
+	}
+
+	if err := Discontinue(ctx, rolloutObject, controller, c); err != nil {
+		return false, err
+	}
+
+	rolloutObject.GetRolloutStatus().MarkUpgradeAborted(fmt.Sprintf("New Child Object %s/%s was aborted by user",
+		existingUpgradingChildDef.GetNamespace(), existingUpgradingChildDef.GetName()), rolloutObject.GetRolloutObjectMeta().Generation)
+	return false, nil
+}
+
+/*
+retryUpgrade discards the failed upgrading child, if it's still there, and clears the RolledBack condition, so that a new
+upgrading child is created with the same spec.
+
+Returns:
+  - Whether the upgrade will be retried.
+  - A message describing the outcome.
+  - An error if any issues occur during processing.
+*/
+func retryUpgrade(
+	ctx context.Context,
+	rolloutObject ProgressiveRolloutObject,
+	existingUpgradingChildDef *unstructured.Unstructured,
+	c client.Client,
+) (bool, string, error) {
+	if existingUpgradingChildDef != nil {
+		upgradingChildStatus := rolloutObject.GetUpgradingChildStatus()
+		if upgradingChildStatus == nil || upgradingChildStatus.Name != existingUpgradingChildDef.GetName() || !upgradingChildStatus.IsFailed() {
+			return false, fmt.Sprintf("%s has not failed, so it can't be retried", existingUpgradingChildDef.GetName()), nil
+		}
+		reason := common.LabelValueProgressiveReplacedFailed
+		if err := ctlrcommon.UpdateUpgradeState(ctx, c, common.LabelValueUpgradeRecyclable, &reason, existingUpgradingChildDef); err != nil {
+			return false, "", err
+		}
+	} else if !isGenerationRolledBack(rolloutObject) {
+		return false, "there is no failed upgrade to retry", nil
+	}
+
+	rolloutObject.GetRolloutStatus().ClearRolledBack(rolloutObject.GetRolloutObjectMeta().Generation)
+	return true, "a new upgrading child will be created with the same spec", nil
+}
+
+// acknowledgeUpgradeAction records the action in the Rollout Status and emits an Event for it
+func acknowledgeUpgradeAction(
+	ctx context.Context,
+	rolloutObject ProgressiveRolloutObject,
+	controller progressiveController,
+	action apiv1.UpgradeAction,
+	childName string,
+	message string,
+) {
+	rolloutObject.GetRolloutStatus().LastUpgradeAction = &apiv1.UpgradeActionStatus{
+		Action:           action,
+		ChildName:        childName,
+		RequestedBy:      rolloutObject.GetRolloutObjectMeta().GetAnnotations()[common.AnnotationKeyActionRequestedBy],
+		AcknowledgedTime: metav1.NewTime(time.Now()),
+		Message:          message,
+	}
+
+	if eventObject, ok := rolloutObject.(runtime.Object); ok && controller.GetRecorder() != nil {
+		controller.GetRecorder().Eventf(eventObject, corev1.EventTypeNormal, "UpgradeAction", "%s requested for %s: %s", action, childName, message)
+	}
+
+	logger.FromContext(ctx).WithValues("action", action, "child", childName, "message", message).Info("acknowledged upgrade action")
+}
@@ -0,0 +1,97 @@
+package progressive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_getRequestedUpgradeAction(t *testing.T) {
+	rollout := stepsMonoVertexRollout(nil, 0)
+
+	_, found := getRequestedUpgradeAction(rollout, "test-1")
+	assert.False(t, found)
+
+	// an annotation for a different child doesn't apply
+	rollout.Annotations = map[string]string{common.AnnotationKeyAbortUpgrade: "test-0"}
+	_, found = getRequestedUpgradeAction(rollout, "test-1")
+	assert.False(t, found)
+
+	// abort takes precedence over the other actions
+	rollout.Annotations = map[string]string{common.AnnotationKeySkipAnalysis: "test-1", common.AnnotationKeyAbortUpgrade: "test-1"}
+	action, found := getRequestedUpgradeAction(rollout, "test-1")
+	assert.True(t, found)
+	assert.Equal(t, apiv1.UpgradeActionAbort, action)
+
+	// an acknowledged action isn't requested again
+	rollout.Annotations = map[string]string{common.AnnotationKeySkipAnalysis: "test-1"}
+	rollout.Status.LastUpgradeAction = &apiv1.UpgradeActionStatus{Action: apiv1.UpgradeActionSkipAnalysis, ChildName: "test-1"}
+	_, found = getRequestedUpgradeAction(rollout, "test-1")
+	assert.False(t, found)
+	assert.True(t, IsAnalysisSkipped(rollout, "test-1"))
+	assert.False(t, IsAnalysisSkipped(rollout, "test-2"))
+}
+
+func Test_processUpgradeAction(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("skip analysis", func(t *testing.T) {
+		rollout := stepsMonoVertexRollout(nil, 0)
+		rollout.Annotations = map[string]string{common.AnnotationKeySkipAnalysis: "test-1", common.AnnotationKeyActionRequestedBy: "jane"}
+
+		stop, done, err := processUpgradeAction(ctx, rollout, fakeProgressiveController{},
+			monoVertexToUnstruct(createMonoVertex("test-0")), monoVertexToUnstruct(createMonoVertex("test-1")), nil)
+		assert.NoError(t, err)
+		assert.False(t, stop)
+		assert.False(t, done)
+		assert.NotNil(t, rollout.Status.LastUpgradeAction)
+		assert.Equal(t, apiv1.UpgradeActionSkipAnalysis, rollout.Status.LastUpgradeAction.Action)
+		assert.Equal(t, "jane", rollout.Status.LastUpgradeAction.RequestedBy)
+		assert.True(t, IsAnalysisSkipped(rollout, "test-1"))
+		assert.True(t, rollout.GetUpgradingChildStatus().AnalysisSkipped)
+
+		// the skip still applies after a later action replaces the LastUpgradeAction, and isn't requested again
+		rollout.Annotations[common.AnnotationKeyRetryUpgrade] = "test-1"
+		_, _, err = processUpgradeAction(ctx, rollout, fakeProgressiveController{},
+			monoVertexToUnstruct(createMonoVertex("test-0")), monoVertexToUnstruct(createMonoVertex("test-1")), nil)
+		assert.NoError(t, err)
+		assert.Equal(t, apiv1.UpgradeActionRetry, rollout.Status.LastUpgradeAction.Action)
+		assert.True(t, IsAnalysisSkipped(rollout, "test-1"))
+		_, found := getRequestedUpgradeAction(rollout, "test-1")
+		assert.False(t, found)
+	})
+
+	t.Run("retry rolled back upgrade", func(t *testing.T) {
+		rollout := stepsMonoVertexRollout(nil, 0)
+		rollout.Status.MarkRolledBack("failed", rollout.Generation)
+		rollout.Annotations = map[string]string{common.AnnotationKeyRetryUpgrade: "test-1"}
+
+		stop, done, err := processUpgradeAction(ctx, rollout, fakeProgressiveController{}, monoVertexToUnstruct(createMonoVertex("test-0")), nil, nil)
+		assert.NoError(t, err)
+		assert.True(t, stop)
+		assert.False(t, done)
+		assert.False(t, isGenerationRolledBack(rollout))
+		assert.Equal(t, apiv1.UpgradeActionRetry, rollout.Status.LastUpgradeAction.Action)
+
+		// the retry is only performed once
+		stop, _, err = processUpgradeAction(ctx, rollout, fakeProgressiveController{}, monoVertexToUnstruct(createMonoVertex("test-0")), nil, nil)
+		assert.NoError(t, err)
+		assert.False(t, stop)
+	})
+
+	t.Run("retry without failure", func(t *testing.T) {
+		rollout := stepsMonoVertexRollout(nil, 0)
+		rollout.Annotations = map[string]string{common.AnnotationKeyRetryUpgrade: "test-1"}
+
+		stop, _, err := processUpgradeAction(ctx, rollout, fakeProgressiveController{},
+			monoVertexToUnstruct(createMonoVertex("test-0")), monoVertexToUnstruct(createMonoVertex("test-1")), nil)
+		assert.NoError(t, err)
+		assert.False(t, stop)
+		// the action is still acknowledged, with a message explaining why nothing was done
+		assert.Contains(t, rollout.Status.LastUpgradeAction.Message, "has not failed")
+	})
+}
@@ -157,6 +157,31 @@ func PerformAnalysis(
 	analysis apiv1.Analysis,
 	analysisStatus *apiv1.AnalysisStatus,
 	c client.Client,
+) (*apiv1.AnalysisStatus, error) {
+	analysisRunName := fmt.Sprintf("%s-%s", strings.ToLower(existingUpgradingChildDef.GetKind()), existingUpgradingChildDef.GetName())
+	// each Progressive Step gets its own AnalysisRun
+	if len(rolloutObject.GetProgressiveSteps()) > 0 {
+		analysisRunName = fmt.Sprintf("%s-step-%d", analysisRunName, GetCurrentStepIndex(rolloutObject))
+	}
+
+	var promotedChildName string
+	if promotedChildStatus := rolloutObject.GetPromotedChildStatus(); promotedChildStatus != nil {
+		promotedChildName = promotedChildStatus.Name
+	}
+
+	return performAnalysisRun(ctx, existingUpgradingChildDef, analysis, analysisStatus, analysisRunName, promotedChildName, c)
+}
+
+// performAnalysisRun creates the AnalysisRun with the given name for the child if it doesn't exist yet, or otherwise
+// updates the AnalysisStatus with its phase
+func performAnalysisRun(
+	ctx context.Context,
+	existingUpgradingChildDef *unstructured.Unstructured,
+	analysis apiv1.Analysis,
+	analysisStatus *apiv1.AnalysisStatus,
+	analysisRunName string,
+	promotedChildName string,
+	c client.Client,
 ) (*apiv1.AnalysisStatus, error) {
 	if analysisStatus == nil {
 		return analysisStatus, errors.New("analysisStatus not set")
@@ -164,8 +189,6 @@ func PerformAnalysis(
 
 	analysisRun := &argorolloutsv1.AnalysisRun{}
 
-	analysisRunName := fmt.Sprintf("%s-%s", strings.ToLower(existingUpgradingChildDef.GetKind()), existingUpgradingChildDef.GetName())
-
 	// check if analysisRun has already been created
 	if err := c.Get(ctx, client.ObjectKey{Name: analysisRunName, Namespace: existingUpgradingChildDef.GetNamespace()}, analysisRun); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -174,11 +197,6 @@ func PerformAnalysis(
 				Namespace: existingUpgradingChildDef.GetNamespace(),
 				UID:       existingUpgradingChildDef.GetUID()},
 				existingUpgradingChildDef.GroupVersionKind())
-			promotedChildStatus := rolloutObject.GetPromotedChildStatus()
-			var promotedChildName string
-			if promotedChildStatus != nil {
-				promotedChildName = promotedChildStatus.Name
-			}
 			err := CreateAnalysisRun(ctx, analysis, existingUpgradingChildDef, analysisRunName, ownerRef, c, promotedChildName)
 			if err != nil {
 				return analysisStatus, err
@@ -209,6 +227,22 @@ func AssessAnalysisStatus(
 	ctx context.Context,
 	existingUpgradingChildDef *unstructured.Unstructured,
 	analysisStatus *apiv1.AnalysisStatus) (apiv1.AssessmentResult, string, error) {
+
+	// make sure we haven't gone past the max time allowed for an AnalysisRun
+	analysisRunTimeout, err := getAnalysisRunTimeout(ctx)
+	if err != nil {
+		return apiv1.AssessmentResultUnknown, "", err
+	}
+
+	return assessAnalysisStatus(ctx, existingUpgradingChildDef, analysisStatus, analysisRunTimeout)
+}
+
+// assessAnalysisStatus returns the AssessmentResult of the AnalysisStatus, which fails if it's still running after the timeout
+func assessAnalysisStatus(
+	ctx context.Context,
+	existingUpgradingChildDef *unstructured.Unstructured,
+	analysisStatus *apiv1.AnalysisStatus,
+	analysisRunTimeout time.Duration) (apiv1.AssessmentResult, string, error) {
 	numaLogger := logger.FromContext(ctx)
 
 	// check for feature flag to skip checking the AnalysisRun
@@ -221,30 +255,65 @@ func AssessAnalysisStatus(
 		return apiv1.AssessmentResultSuccess, "", nil
 	}
 
-	// make sure we haven't gone past the max time allowed for an AnalysisRun
-	analysisRunTimeout, err := getAnalysisRunTimeout(ctx)
-	if err != nil {
-		return apiv1.AssessmentResultUnknown, "", err
+	if analysisStatus == nil {
+		// no analysis so by default we can mark this successful
+		return apiv1.AssessmentResultSuccess, "", nil
 	}
 
+	result := apiv1.AssessmentResultSuccess
+	failureReason := ""
+
 	// if analysisStatus is set with an AnalysisRun's name, we must also check that it is in a Completed phase to declare success
-	if analysisStatus != nil && analysisStatus.AnalysisRunName != "" {
+	if analysisStatus.AnalysisRunName != "" {
 		numaLogger.WithValues("namespace", existingUpgradingChildDef.GetNamespace(), "name", existingUpgradingChildDef.GetName()).
 			Debugf("AnalysisRun %s is in phase %s", analysisStatus.AnalysisRunName, analysisStatus.Phase)
-		switch analysisStatus.Phase {
-		case argorolloutsv1.AnalysisPhaseSuccessful:
-			return apiv1.AssessmentResultSuccess, "", nil
-		case argorolloutsv1.AnalysisPhaseError, argorolloutsv1.AnalysisPhaseFailed, argorolloutsv1.AnalysisPhaseInconclusive:
-			return apiv1.AssessmentResultFailure, fmt.Sprintf("AnalysisRun %s is in phase %s", analysisStatus.AnalysisRunName, analysisStatus.Phase), nil
-		default:
-			// if analysisRun is not completed yet, we check if it has exceeded the analysisRunTimeout
-			if time.Since(analysisStatus.StartTime.Time) >= analysisRunTimeout {
-				return apiv1.AssessmentResultFailure, fmt.Sprintf("AnalysisRun %s in phase %s has exceeded the analysisRunTimeout", analysisStatus.AnalysisRunName, analysisStatus.Phase), nil
-			}
-			return apiv1.AssessmentResultUnknown, "", nil
+		result, failureReason = assessAnalysisPhase(fmt.Sprintf("AnalysisRun %s", analysisStatus.AnalysisRunName), analysisStatus.Phase, analysisStatus.StartTime, analysisRunTimeout)
+		if result == apiv1.AssessmentResultFailure {
+			return result, failureReason, nil
+		}
+	}
+
+	// likewise, any Metrics measured natively must be in a Completed phase to declare success
+	if len(analysisStatus.MetricResults) > 0 {
+		metricsPhase := GetMetricsPhase(analysisStatus.MetricResults)
+		numaLogger.WithValues("namespace", existingUpgradingChildDef.GetNamespace(), "name", existingUpgradingChildDef.GetName()).
+			Debugf("Analysis Metrics are in phase %s", metricsPhase)
+		metricsResult, metricsFailureReason := assessAnalysisPhase("Analysis Metrics", metricsPhase, analysisStatus.StartTime, analysisRunTimeout)
+		if metricsResult == apiv1.AssessmentResultFailure {
+			return metricsResult, fmt.Sprintf("%s (%s)", metricsFailureReason, describeFailedMetrics(analysisStatus.MetricResults)), nil
+		}
+		if metricsResult == apiv1.AssessmentResultUnknown {
+			result = apiv1.AssessmentResultUnknown
+		}
+	}
+
+	return result, failureReason, nil
+}
+
+// assessAnalysisPhase returns the AssessmentResult for the phase of an analysis, which fails if it's still running after the timeout
+func assessAnalysisPhase(description string, phase argorolloutsv1.AnalysisPhase, startTime *metav1.Time, timeout time.Duration) (apiv1.AssessmentResult, string) {
+	switch phase {
+	case argorolloutsv1.AnalysisPhaseSuccessful:
+		return apiv1.AssessmentResultSuccess, ""
+	case argorolloutsv1.AnalysisPhaseError, argorolloutsv1.AnalysisPhaseFailed, argorolloutsv1.AnalysisPhaseInconclusive:
+		return apiv1.AssessmentResultFailure, fmt.Sprintf("%s is in phase %s", description, phase)
+	default:
+		// if analysis is not completed yet, we check if it has exceeded the analysisRunTimeout
+		if startTime != nil && time.Since(startTime.Time) >= timeout {
+			return apiv1.AssessmentResultFailure, fmt.Sprintf("%s in phase %s has exceeded the analysisRunTimeout", description, phase)
 		}
+		return apiv1.AssessmentResultUnknown, ""
 	}
+}
 
-	// no AnalysisRun so by default we can mark this successful
-	return apiv1.AssessmentResultSuccess, "", nil
+// describeFailedMetrics summarizes the Metrics which didn't succeed
+func describeFailedMetrics(results []apiv1.MetricResult) string {
+	descriptions := []string{}
+	for _, result := range results {
+		if result.Phase == argorolloutsv1.AnalysisPhaseSuccessful || !result.Phase.Completed() {
+			continue
+		}
+		descriptions = append(descriptions, fmt.Sprintf("metric %s is %s: %s", result.Name, result.Phase, result.Message))
+	}
+	return strings.Join(descriptions, "; ")
 }
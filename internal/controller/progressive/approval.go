@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progressive
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+/*
+checkManualApproval is called once the assessment of the upgrading child has succeeded.
+If the Progressive strategy requires manual approval, it holds the upgrading child in a waiting state until it's approved,
+either by annotation on the Rollout or by the Approved field in the upgrading child's ApprovalStatus, or until the optional timeout elapses.
+
+Parameters:
+- ctx: The context for managing request-scoped values, cancellation, and timeouts.
+- rolloutObject: The current rollout object.
+- upgradingChildName: The name of the child resource currently being upgraded.
+
+Returns:
+- The assessment result: Success if approved (or approval not required), Failure if the timeout elapsed with a "Fail" action, Unknown if still waiting
+- A failure reason in the case of Failure
+*/
+func checkManualApproval(
+	ctx context.Context,
+	rolloutObject ProgressiveRolloutObject,
+	upgradingChildName string,
+) (apiv1.AssessmentResult, string) {
+	numaLogger := logger.FromContext(ctx).WithValues("upgrading child", upgradingChildName)
+
+	manualApproval := rolloutObject.GetProgressiveStrategy().ManualApproval
+	if manualApproval == nil {
+		return apiv1.AssessmentResultSuccess, ""
+	}
+
+	now := metav1.NewTime(time.Now())
+	childStatus := UpdateUpgradingChildStatus(rolloutObject, func(status *apiv1.UpgradingChildStatus) {
+		if status.Approval == nil {
+			status.Approval = &apiv1.ApprovalStatus{}
+		}
+		if status.Approval.WaitingSince == nil {
+			status.Approval.WaitingSince = &now
+		}
+	})
+	approval := childStatus.Approval
+
+	if approval.Approved {
+		if approval.ApprovalTime == nil {
+			_ = UpdateUpgradingChildStatus(rolloutObject, func(status *apiv1.UpgradingChildStatus) {
+				status.Approval.ApprovalTime = &now
+			})
+		}
+		return apiv1.AssessmentResultSuccess, ""
+	}
+
+	// the approval annotation must name this upgrading child, so that an approval of a previous upgrade doesn't carry over
+	annotations := rolloutObject.GetRolloutObjectMeta().GetAnnotations()
+	if annotations[common.AnnotationKeyApproveUpgrade] == upgradingChildName {
+		_ = UpdateUpgradingChildStatus(rolloutObject, func(status *apiv1.UpgradingChildStatus) {
+			status.Approval.Approved = true
+			status.Approval.ApprovedBy = annotations[common.AnnotationKeyApprovedBy]
+			status.Approval.ApprovalTime = &now
+		})
+		numaLogger.WithValues("approvedBy", annotations[common.AnnotationKeyApprovedBy]).Info("upgrading child approved by annotation")
+		return apiv1.AssessmentResultSuccess, ""
+	}
+
+	if manualApproval.Timeout != nil && time.Since(approval.WaitingSince.Time) >= manualApproval.Timeout.Duration {
+		if manualApproval.TimeoutAction == apiv1.ApprovalTimeoutActionApprove {
+			_ = UpdateUpgradingChildStatus(rolloutObject, func(status *apiv1.UpgradingChildStatus) {
+				status.Approval.Approved = true
+				status.Approval.AutoApproved = true
+				status.Approval.ApprovalTime = &now
+			})
+			numaLogger.Info("approval timeout elapsed, auto-approving upgrading child")
+			return apiv1.AssessmentResultSuccess, ""
+		}
+		return apiv1.AssessmentResultFailure, fmt.Sprintf("upgrading child was not approved within %s", manualApproval.Timeout.Duration)
+	}
+
+	numaLogger.Debug("upgrading child is waiting for approval")
+	return apiv1.AssessmentResultUnknown, ""
+}
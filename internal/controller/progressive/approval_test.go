@@ -0,0 +1,112 @@
+package progressive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_checkManualApproval(t *testing.T) {
+	const childName = "test-1"
+	longAgo := &metav1.Time{Time: time.Now().Add(-1 * time.Hour)}
+
+	testCases := []struct {
+		name                 string
+		manualApproval       *apiv1.ManualApprovalStrategy
+		annotations          map[string]string
+		approvalStatus       *apiv1.ApprovalStatus
+		expectedResult       apiv1.AssessmentResult
+		expectedApprovedBy   string
+		expectedAutoApproved bool
+	}{
+		{
+			name:           "approval not required",
+			manualApproval: nil,
+			expectedResult: apiv1.AssessmentResultSuccess,
+		},
+		{
+			name:           "waiting for approval",
+			manualApproval: &apiv1.ManualApprovalStrategy{},
+			expectedResult: apiv1.AssessmentResultUnknown,
+		},
+		{
+			name:           "approved by annotation",
+			manualApproval: &apiv1.ManualApprovalStrategy{},
+			annotations: map[string]string{
+				common.AnnotationKeyApproveUpgrade: childName,
+				common.AnnotationKeyApprovedBy:     "jane",
+			},
+			expectedResult:     apiv1.AssessmentResultSuccess,
+			expectedApprovedBy: "jane",
+		},
+		{
+			name:           "annotation approves a different child",
+			manualApproval: &apiv1.ManualApprovalStrategy{},
+			annotations:    map[string]string{common.AnnotationKeyApproveUpgrade: "test-0"},
+			expectedResult: apiv1.AssessmentResultUnknown,
+		},
+		{
+			name:               "approved by status",
+			manualApproval:     &apiv1.ManualApprovalStrategy{},
+			approvalStatus:     &apiv1.ApprovalStatus{Approved: true, ApprovedBy: "john"},
+			expectedResult:     apiv1.AssessmentResultSuccess,
+			expectedApprovedBy: "john",
+		},
+		{
+			name:           "timeout elapsed, fail",
+			manualApproval: &apiv1.ManualApprovalStrategy{Timeout: &metav1.Duration{Duration: time.Minute}},
+			approvalStatus: &apiv1.ApprovalStatus{WaitingSince: longAgo},
+			expectedResult: apiv1.AssessmentResultFailure,
+		},
+		{
+			name: "timeout elapsed, approve",
+			manualApproval: &apiv1.ManualApprovalStrategy{
+				Timeout:       &metav1.Duration{Duration: time.Minute},
+				TimeoutAction: apiv1.ApprovalTimeoutActionApprove,
+			},
+			approvalStatus:       &apiv1.ApprovalStatus{WaitingSince: longAgo},
+			expectedResult:       apiv1.AssessmentResultSuccess,
+			expectedAutoApproved: true,
+		},
+		{
+			name:           "timeout not yet elapsed",
+			manualApproval: &apiv1.ManualApprovalStrategy{Timeout: &metav1.Duration{Duration: 2 * time.Hour}},
+			approvalStatus: &apiv1.ApprovalStatus{WaitingSince: longAgo},
+			expectedResult: apiv1.AssessmentResultUnknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rollout := stepsMonoVertexRollout(nil, 0)
+			rollout.Spec.Strategy.Progressive.ManualApproval = tc.manualApproval
+			rollout.Annotations = tc.annotations
+			rollout.Status.ProgressiveStatus.UpgradingMonoVertexStatus.Approval = tc.approvalStatus
+
+			result, failureReason := checkManualApproval(context.Background(), rollout, childName)
+			assert.Equal(t, tc.expectedResult, result)
+			if result == apiv1.AssessmentResultFailure {
+				assert.NotEmpty(t, failureReason)
+			}
+
+			approval := rollout.GetUpgradingChildStatus().Approval
+			if tc.manualApproval == nil {
+				assert.Nil(t, approval)
+				return
+			}
+			assert.NotNil(t, approval.WaitingSince)
+			assert.Equal(t, result == apiv1.AssessmentResultSuccess, approval.Approved)
+			if approval.Approved {
+				assert.NotNil(t, approval.ApprovalTime)
+			}
+			assert.Equal(t, tc.expectedApprovedBy, approval.ApprovedBy)
+			assert.Equal(t, tc.expectedAutoApproved, approval.AutoApproved)
+		})
+	}
+}
@@ -0,0 +1,392 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progressive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	argorolloutsv1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+const (
+	// argument resolved to the name of the child being measured, for comparative metrics
+	comparisonChildNameArg = "child-name"
+
+	defaultComparisonMarginPercent     = int32(10)
+	defaultComparisonMinSamples        = int32(5)
+	defaultComparisonConfidencePercent = int32(95)
+	// the number of most recent samples of each child which are retained in the MetricResult
+	maxComparisonSamples = 30
+)
+
+// the PromQL queries of the builtin metrics for each Kind of child, in which "{{args.child-name}}" is resolved to the child being measured
+var builtinMetricQueries = map[string]map[apiv1.BuiltinMetric]string{
+	"MonoVertex": {
+		apiv1.BuiltinMetricProcessingRate: `sum(rate(monovtx_read_total{namespace="{{args.monovertex-namespace}}", mvtx_name="{{args.child-name}}"}[2m]))`,
+		apiv1.BuiltinMetricErrorRate: `clamp_min(1 - sum(rate(monovtx_ack_total{namespace="{{args.monovertex-namespace}}", mvtx_name="{{args.child-name}}"}[2m])) / ` +
+			`clamp_min(sum(rate(monovtx_read_total{namespace="{{args.monovertex-namespace}}", mvtx_name="{{args.child-name}}"}[2m])), 1e-9), 0)`,
+		apiv1.BuiltinMetricAckLatency: `histogram_quantile(0.95, sum by (le) (rate(monovtx_ack_time_bucket{namespace="{{args.monovertex-namespace}}", mvtx_name="{{args.child-name}}"}[2m])))`,
+		apiv1.BuiltinMetricPending:    `sum(monovtx_pending{namespace="{{args.monovertex-namespace}}", mvtx_name="{{args.child-name}}", period="1m"})`,
+	},
+	"Pipeline": {
+		apiv1.BuiltinMetricProcessingRate: `sum(rate(forwarder_data_read_total{namespace="{{args.pipeline-namespace}}", pipeline="{{args.child-name}}", vertex_type="Source"}[2m]))`,
+		apiv1.BuiltinMetricErrorRate: `(sum(rate({__name__=~"forwarder_(read|write|ack|udf|platform)_error_total", namespace="{{args.pipeline-namespace}}", pipeline="{{args.child-name}}"}[2m])) or vector(0)) / ` +
+			`clamp_min(sum(rate(forwarder_data_read_total{namespace="{{args.pipeline-namespace}}", pipeline="{{args.child-name}}"}[2m])), 1e-9)`,
+		apiv1.BuiltinMetricAckLatency: `histogram_quantile(0.95, sum by (le) (rate(forwarder_forward_chunk_processing_time_bucket{namespace="{{args.pipeline-namespace}}", pipeline="{{args.child-name}}"}[2m])))`,
+		apiv1.BuiltinMetricPending:    `sum(vertex_pending_messages{namespace="{{args.pipeline-namespace}}", pipeline="{{args.child-name}}", period="1m"})`,
+	},
+}
+
+// the PromQL queries which count the running pods of each Kind of child, for the builtin metrics which are totals over the child's pods:
+// the children are scaled differently during the upgrade, so those metrics are compared per pod
+var builtinMetricPodCountQueries = map[string]map[apiv1.BuiltinMetric]string{
+	"MonoVertex": {
+		apiv1.BuiltinMetricProcessingRate: `count(count by (mvtx_replica) (monovtx_read_total{namespace="{{args.monovertex-namespace}}", mvtx_name="{{args.child-name}}"}))`,
+		apiv1.BuiltinMetricPending:        `count(count by (mvtx_replica) (monovtx_read_total{namespace="{{args.monovertex-namespace}}", mvtx_name="{{args.child-name}}"}))`,
+	},
+	"Pipeline": {
+		apiv1.BuiltinMetricProcessingRate: `count(count by (vertex, replica) (forwarder_data_read_total{namespace="{{args.pipeline-namespace}}", pipeline="{{args.child-name}}", vertex_type="Source"}))`,
+		apiv1.BuiltinMetricPending:        `count(count by (vertex, replica) (forwarder_data_read_total{namespace="{{args.pipeline-namespace}}", pipeline="{{args.child-name}}"}))`,
+	},
+}
+
+// the direction which suits each builtin metric
+var builtinMetricDirections = map[apiv1.BuiltinMetric]apiv1.ComparisonDirection{
+	apiv1.BuiltinMetricProcessingRate: apiv1.ComparisonDirectionHigherIsBetter,
+	apiv1.BuiltinMetricErrorRate:      apiv1.ComparisonDirectionLowerIsBetter,
+	apiv1.BuiltinMetricAckLatency:     apiv1.ComparisonDirectionLowerIsBetter,
+	apiv1.BuiltinMetricPending:        apiv1.ComparisonDirectionLowerIsBetter,
+}
+
+/*
+measureComparison takes one sample of a comparative metric for each of the promoted ("baseline") and upgrading ("canary") children,
+and once there are enough samples of each, judges the canary against the baseline using a one-sided Welch's t-test.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - metric: the comparative metric
+  - result: the MetricResult, to which the samples are added
+  - address: the address of the Prometheus server
+  - args: the Analysis arguments, to which "child-name" is added for each child
+  - childKind: the Kind of the children, which determines the query of a builtin metric
+  - promotedChildName: the name of the promoted child
+  - upgradingChildName: the name of the upgrading child
+
+Returns:
+  - Running if there aren't yet enough samples to judge the canary; Failed if the canary is significantly worse than the baseline
+    by more than the margin; otherwise Successful
+  - An error if either child couldn't be measured
+*/
+func measureComparison(
+	ctx context.Context,
+	metric apiv1.AnalysisMetric,
+	result *apiv1.MetricResult,
+	address string,
+	args map[string]string,
+	childKind string,
+	promotedChildName, upgradingChildName string,
+) (argorolloutsv1.AnalysisPhase, error) {
+	if promotedChildName == "" {
+		return argorolloutsv1.AnalysisPhaseError, errors.New("comparative metric requires a promoted child to compare to")
+	}
+
+	query, podCountQuery, comparison, err := resolveComparisonMetric(metric, childKind)
+	if err != nil {
+		return argorolloutsv1.AnalysisPhaseError, err
+	}
+
+	baseline, err := queryChildComparisonValue(ctx, query, podCountQuery, address, args, promotedChildName)
+	if err != nil {
+		return argorolloutsv1.AnalysisPhaseError, err
+	}
+	canary, err := queryChildComparisonValue(ctx, query, podCountQuery, address, args, upgradingChildName)
+	if err != nil {
+		return argorolloutsv1.AnalysisPhaseError, err
+	}
+
+	result.BaselineSamples = appendSample(result.BaselineSamples, baseline)
+	result.CanarySamples = appendSample(result.CanarySamples, canary)
+	result.LastValue = fmt.Sprintf("baseline=%v canary=%v", baseline, canary)
+
+	minSamples := comparisonMinSamples(comparison)
+	if len(result.BaselineSamples) < int(minSamples) || len(result.CanarySamples) < int(minSamples) {
+		result.Message = fmt.Sprintf("collecting samples: %d of %d", min(len(result.BaselineSamples), len(result.CanarySamples)), minSamples)
+		return argorolloutsv1.AnalysisPhaseRunning, nil
+	}
+
+	baselineSamples, err := parseSamples(result.BaselineSamples)
+	if err != nil {
+		return argorolloutsv1.AnalysisPhaseError, err
+	}
+	canarySamples, err := parseSamples(result.CanarySamples)
+	if err != nil {
+		return argorolloutsv1.AnalysisPhaseError, err
+	}
+
+	worse, judgement, err := isCanaryWorse(comparison, baselineSamples, canarySamples)
+	if err != nil {
+		return argorolloutsv1.AnalysisPhaseError, err
+	}
+	if worse {
+		result.Message = fmt.Sprintf("upgrading child is worse than promoted child: %s", judgement)
+		return argorolloutsv1.AnalysisPhaseFailed, nil
+	}
+	return argorolloutsv1.AnalysisPhaseSuccessful, nil
+}
+
+// resolveComparisonMetric returns the query of the comparative metric, the query which counts the child's pods if the metric is compared
+// per pod (otherwise ""), and its comparison, with the direction defaulted for a builtin metric
+func resolveComparisonMetric(metric apiv1.AnalysisMetric, childKind string) (string, string, apiv1.MetricComparison, error) {
+	comparison := *metric.Comparison
+	if metric.Builtin == "" {
+		return metric.Prometheus.Query, "", comparison, nil
+	}
+	query, found := builtinMetricQueries[childKind][metric.Builtin]
+	if !found {
+		return "", "", comparison, fmt.Errorf("builtin metric %q is not supported for %s", metric.Builtin, childKind)
+	}
+	if comparison.Direction == "" {
+		comparison.Direction = builtinMetricDirections[metric.Builtin]
+	}
+	return query, builtinMetricPodCountQueries[childKind][metric.Builtin], comparison, nil
+}
+
+// comparisonMinSamples returns the number of samples of each child required to judge the upgrading child
+func comparisonMinSamples(comparison apiv1.MetricComparison) int32 {
+	if comparison.MinSamples != nil {
+		return *comparison.MinSamples
+	}
+	return defaultComparisonMinSamples
+}
+
+// append the sample, retaining only the most recent ones
+func appendSample(samples []string, value float64) []string {
+	samples = append(samples, strconv.FormatFloat(value, 'g', -1, 64))
+	if len(samples) > maxComparisonSamples {
+		samples = samples[len(samples)-maxComparisonSamples:]
+	}
+	return samples
+}
+
+/*
+isCanaryWorse determines if the canary is worse than the baseline by more than the margin (or the absolute tolerance, if greater),
+with the confidence required by the comparison. This is a one-sided Welch's t-test of the null hypothesis that the degradation of the
+canary's mean relative to the baseline's mean is no more than the allowed degradation.
+
+Returns:
+  - whether the canary is judged worse
+  - a description of the judgement
+  - An error if the comparison is invalid or there are fewer than 2 samples of either child
+*/
+func isCanaryWorse(comparison apiv1.MetricComparison, baselineSamples, canarySamples []float64) (bool, string, error) {
+	if len(baselineSamples) < 2 || len(canarySamples) < 2 {
+		return false, "", errors.New("at least 2 samples of each child are required")
+	}
+	marginPercent := defaultComparisonMarginPercent
+	if comparison.MarginPercent != nil {
+		marginPercent = *comparison.MarginPercent
+	}
+	confidencePercent := defaultComparisonConfidencePercent
+	if comparison.ConfidencePercent != nil {
+		confidencePercent = *comparison.ConfidencePercent
+	}
+	tolerance := 0.0
+	if comparison.AbsoluteTolerance != "" {
+		var err error
+		tolerance, err = strconv.ParseFloat(comparison.AbsoluteTolerance, 64)
+		if err != nil {
+			return false, "", fmt.Errorf("invalid absoluteTolerance %q: %w", comparison.AbsoluteTolerance, err)
+		}
+	}
+
+	baselineMean, baselineVariance := meanAndVariance(baselineSamples)
+	canaryMean, canaryVariance := meanAndVariance(canarySamples)
+
+	var degradation float64
+	switch comparison.Direction {
+	case apiv1.ComparisonDirectionHigherIsBetter:
+		degradation = baselineMean - canaryMean
+	case apiv1.ComparisonDirectionLowerIsBetter:
+		degradation = canaryMean - baselineMean
+	default:
+		return false, "", fmt.Errorf("invalid comparison direction %q", comparison.Direction)
+	}
+	allowed := math.Max(math.Abs(baselineMean)*float64(marginPercent)/100, tolerance)
+
+	judgement := fmt.Sprintf("mean %v vs %v, degradation %v exceeds the allowed %v", canaryMean, baselineMean, degradation, allowed)
+	if degradation <= allowed {
+		return false, judgement, nil
+	}
+
+	baselineSE2 := baselineVariance / float64(len(baselineSamples))
+	canarySE2 := canaryVariance / float64(len(canarySamples))
+	standardError := math.Sqrt(baselineSE2 + canarySE2)
+	if standardError == 0 {
+		// the samples don't vary, so the degradation is certain
+		return true, judgement, nil
+	}
+	t := (degradation - allowed) / standardError
+	degreesOfFreedom := math.Pow(baselineSE2+canarySE2, 2) /
+		(baselineSE2*baselineSE2/float64(len(baselineSamples)-1) + canarySE2*canarySE2/float64(len(canarySamples)-1))
+	pValue := 1 - studentTCDF(t, degreesOfFreedom)
+
+	judgement = fmt.Sprintf("%s (p=%.3g)", judgement, pValue)
+	return pValue < 1-float64(confidencePercent)/100, judgement, nil
+}
+
+// queryChildComparisonValue measures the comparative metric for the given child, dividing it by the child's number of running pods
+// if there's a podCountQuery
+func queryChildComparisonValue(ctx context.Context, query string, podCountQuery string, address string, args map[string]string, childName string) (float64, error) {
+	value, err := queryChildValue(ctx, query, address, args, childName)
+	if err != nil || podCountQuery == "" {
+		return value, err
+	}
+	pods, err := queryChildValue(ctx, podCountQuery, address, args, childName)
+	if err != nil {
+		return 0, err
+	}
+	if pods <= 0 {
+		return 0, fmt.Errorf("child %s has no running pods to measure", childName)
+	}
+	return value / pods, nil
+}
+
+// queryChildValue runs the query for the given child, which must return a single value
+func queryChildValue(ctx context.Context, query string, address string, args map[string]string, childName string) (float64, error) {
+	childArgs := make(map[string]string, len(args)+1)
+	for name, value := range args {
+		childArgs[name] = value
+	}
+	childArgs[comparisonChildNameArg] = childName
+
+	value, err := queryPrometheus(ctx, address, resolveAnalysisArgs(query, childArgs))
+	if err != nil {
+		return 0, err
+	}
+	var childValue float64
+	switch typedValue := value.(type) {
+	case float64:
+		childValue = typedValue
+	case []float64:
+		if len(typedValue) != 1 {
+			return 0, fmt.Errorf("comparative metric query for child %s returned %d values, but must return exactly one", childName, len(typedValue))
+		}
+		childValue = typedValue[0]
+	default:
+		return 0, fmt.Errorf("unexpected value type %T for child %s", value, childName)
+	}
+	if math.IsNaN(childValue) || math.IsInf(childValue, 0) {
+		return 0, fmt.Errorf("comparative metric query for child %s returned %v", childName, childValue)
+	}
+	return childValue, nil
+}
+
+func parseSamples(samples []string) ([]float64, error) {
+	values := make([]float64, 0, len(samples))
+	for _, sample := range samples {
+		value, err := strconv.ParseFloat(sample, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sample %q: %w", sample, err)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// meanAndVariance returns the mean and the unbiased sample variance of the values
+func meanAndVariance(values []float64) (float64, float64) {
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	sumOfSquares := 0.0
+	for _, value := range values {
+		sumOfSquares += (value - mean) * (value - mean)
+	}
+	return mean, sumOfSquares / float64(len(values)-1)
+}
+
+// studentTCDF returns the cumulative distribution function of Student's t-distribution with the given degrees of freedom at t
+func studentTCDF(t, degreesOfFreedom float64) float64 {
+	tail := 0.5 * regularizedIncompleteBeta(degreesOfFreedom/2, 0.5, degreesOfFreedom/(degreesOfFreedom+t*t))
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// regularizedIncompleteBeta returns I_x(a, b), evaluated by its continued fraction using the modified Lentz's method
+func regularizedIncompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	// the continued fraction converges quickly only for x < (a+1)/(a+b+2), so otherwise use the symmetry I_x(a,b) = 1 - I_1-x(b,a)
+	if x > (a+1)/(a+b+2) {
+		return 1 - regularizedIncompleteBeta(b, a, 1-x)
+	}
+	lgammaA, _ := math.Lgamma(a)
+	lgammaB, _ := math.Lgamma(b)
+	lgammaAB, _ := math.Lgamma(a + b)
+	front := math.Exp(lgammaAB-lgammaA-lgammaB+a*math.Log(x)+b*math.Log(1-x)) / a
+
+	const (
+		tiny          = 1e-300
+		epsilon       = 1e-14
+		maxIterations = 300
+	)
+	f, c, d := 1.0, 1.0, 0.0
+	for i := 0; i <= maxIterations; i++ {
+		m := float64(i / 2)
+		var numerator float64
+		switch {
+		case i == 0:
+			numerator = 1
+		case i%2 == 0:
+			numerator = m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m))
+		default:
+			numerator = -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1))
+		}
+		d = 1 + numerator*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		d = 1 / d
+		c = 1 + numerator/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		cd := c * d
+		f *= cd
+		if math.Abs(1-cd) < epsilon {
+			break
+		}
+	}
+	return front * (f - 1)
+}
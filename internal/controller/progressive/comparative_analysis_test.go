@@ -0,0 +1,227 @@
+package progressive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	argorolloutsv1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_isCanaryWorse(t *testing.T) {
+	five := int32(5)
+
+	testCases := []struct {
+		name            string
+		comparison      apiv1.MetricComparison
+		baselineSamples []float64
+		canarySamples   []float64
+		expectedWorse   bool
+		expectError     bool
+	}{
+		{
+			name:            "higher is better, canary within default margin",
+			comparison:      apiv1.MetricComparison{Direction: apiv1.ComparisonDirectionHigherIsBetter},
+			baselineSamples: []float64{1000, 1010, 990},
+			canarySamples:   []float64{950, 960, 940},
+			expectedWorse:   false,
+		},
+		{
+			name:            "higher is better, canary significantly beyond margin",
+			comparison:      apiv1.MetricComparison{Direction: apiv1.ComparisonDirectionHigherIsBetter, MarginPercent: &five},
+			baselineSamples: []float64{1000, 1002, 998, 1001, 999},
+			canarySamples:   []float64{900, 902, 898, 901, 899},
+			expectedWorse:   true,
+		},
+		{
+			name:            "higher is better, canary mean beyond margin but too noisy to be significant",
+			comparison:      apiv1.MetricComparison{Direction: apiv1.ComparisonDirectionHigherIsBetter},
+			baselineSamples: []float64{1000, 1000, 1000},
+			canarySamples:   []float64{400, 1500, 700},
+			expectedWorse:   false,
+		},
+		{
+			name:            "lower is better, canary improved",
+			comparison:      apiv1.MetricComparison{Direction: apiv1.ComparisonDirectionLowerIsBetter},
+			baselineSamples: []float64{20, 21, 19},
+			canarySamples:   []float64{10, 11, 9},
+			expectedWorse:   false,
+		},
+		{
+			name:            "lower is better, baseline of zero without tolerance",
+			comparison:      apiv1.MetricComparison{Direction: apiv1.ComparisonDirectionLowerIsBetter},
+			baselineSamples: []float64{0, 0},
+			canarySamples:   []float64{0.01, 0.01},
+			expectedWorse:   true,
+		},
+		{
+			name:            "lower is better, baseline of zero within tolerance",
+			comparison:      apiv1.MetricComparison{Direction: apiv1.ComparisonDirectionLowerIsBetter, AbsoluteTolerance: "0.05"},
+			baselineSamples: []float64{0, 0},
+			canarySamples:   []float64{0.01, 0.01},
+			expectedWorse:   false,
+		},
+		{
+			name:            "too few samples",
+			comparison:      apiv1.MetricComparison{Direction: apiv1.ComparisonDirectionLowerIsBetter},
+			baselineSamples: []float64{0},
+			canarySamples:   []float64{1},
+			expectError:     true,
+		},
+		{
+			name:            "invalid direction",
+			comparison:      apiv1.MetricComparison{Direction: "Sideways"},
+			baselineSamples: []float64{0, 0},
+			canarySamples:   []float64{1, 1},
+			expectError:     true,
+		},
+		{
+			name:            "invalid tolerance",
+			comparison:      apiv1.MetricComparison{Direction: apiv1.ComparisonDirectionLowerIsBetter, AbsoluteTolerance: "a lot"},
+			baselineSamples: []float64{0, 0},
+			canarySamples:   []float64{1, 1},
+			expectError:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			worse, _, err := isCanaryWorse(tc.comparison, tc.baselineSamples, tc.canarySamples)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedWorse, worse)
+		})
+	}
+}
+
+func Test_studentTCDF(t *testing.T) {
+	// critical values of the one-sided 95% t-test
+	assert.InDelta(t, 0.95, studentTCDF(2.015, 5), 0.0005)
+	assert.InDelta(t, 0.95, studentTCDF(1.812, 10), 0.0005)
+	assert.InDelta(t, 0.05, studentTCDF(-1.812, 10), 0.0005)
+	assert.InDelta(t, 0.5, studentTCDF(0, 3), 1e-9)
+}
+
+func Test_appendSample(t *testing.T) {
+	samples := []string{}
+	for i := 0; i < maxComparisonSamples+5; i++ {
+		samples = appendSample(samples, float64(i))
+	}
+	assert.Len(t, samples, maxComparisonSamples)
+	assert.Equal(t, "5", samples[0])
+}
+
+func Test_PerformMetricAnalysis_comparison(t *testing.T) {
+	// the promoted child (test-0) processes 1000 messages/second and the upgrading child (test-1) processes 500, each with the given number of pods
+	var lastQuery string
+	promotedValue, upgradingValue := "1000", "500"
+	promotedPods, upgradingPods := "1", "1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		query := r.Form.Get("query")
+		value := promotedValue
+		if strings.HasPrefix(query, "count(") {
+			value = promotedPods
+			if strings.Contains(query, "test-1") {
+				value = upgradingPods
+			}
+		} else {
+			lastQuery = query
+			if strings.Contains(query, "test-1") {
+				value = upgradingValue
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"` + value + `"]}]}}`))
+	}))
+	defer server.Close()
+
+	rollout := stepsMonoVertexRollout(nil, 0)
+	rollout.Status.ProgressiveStatus.PromotedMonoVertexStatus = &apiv1.PromotedMonoVertexStatus{
+		PromotedPipelineTypeStatus: apiv1.PromotedPipelineTypeStatus{PromotedChildStatus: apiv1.PromotedChildStatus{Name: "test-0"}},
+	}
+
+	analysis := apiv1.Analysis{Metrics: []apiv1.AnalysisMetric{{
+		Name:       "processing-rate",
+		Prometheus: apiv1.PrometheusMetric{Address: server.URL, Query: `sum(rate(processed{name="{{args.child-name}}"}[1m]))`},
+		Interval:   &metav1.Duration{Duration: 0},
+		Comparison: &apiv1.MetricComparison{Direction: apiv1.ComparisonDirectionHigherIsBetter},
+	}}}
+
+	// measure until the metric completes, returning its result and the number of measurements taken
+	measure := func(analysis apiv1.Analysis) (apiv1.MetricResult, int) {
+		analysisStatus := &apiv1.AnalysisStatus{}
+		for i := 1; i <= 10; i++ {
+			var err error
+			analysisStatus, err = PerformMetricAnalysis(context.Background(), monoVertexToUnstruct(createMonoVertex("test-1")), rollout, analysis, analysisStatus)
+			assert.NoError(t, err)
+			if analysisStatus.MetricResults[0].Phase.Completed() {
+				return analysisStatus.MetricResults[0], i
+			}
+			assert.Equal(t, argorolloutsv1.AnalysisPhaseRunning, analysisStatus.MetricResults[0].Phase)
+		}
+		return analysisStatus.MetricResults[0], 10
+	}
+
+	// the upgrading child isn't judged until there are the minimum number of samples
+	result, measurements := measure(analysis)
+	assert.Equal(t, argorolloutsv1.AnalysisPhaseFailed, result.Phase)
+	assert.Equal(t, int(defaultComparisonMinSamples), measurements)
+	assert.Equal(t, []string{"1000", "1000", "1000", "1000", "1000"}, result.BaselineSamples)
+	assert.Equal(t, []string{"500", "500", "500", "500", "500"}, result.CanarySamples)
+	assert.NotEmpty(t, result.Message)
+
+	// with a larger margin the upgrading child is acceptable
+	sixty := int32(60)
+	analysis.Metrics[0].Comparison.MarginPercent = &sixty
+	result, measurements = measure(analysis)
+	assert.Equal(t, argorolloutsv1.AnalysisPhaseSuccessful, result.Phase)
+	assert.Equal(t, int(defaultComparisonMinSamples), measurements)
+
+	// a builtin metric uses the query defined for the Kind, with the direction which suits it
+	two := int32(2)
+	analysis.Metrics[0] = apiv1.AnalysisMetric{
+		Name:       "processing-rate",
+		Prometheus: apiv1.PrometheusMetric{Address: server.URL},
+		Builtin:    apiv1.BuiltinMetricProcessingRate,
+		Interval:   &metav1.Duration{Duration: 0},
+		Comparison: &apiv1.MetricComparison{MinSamples: &two},
+	}
+	result, measurements = measure(analysis)
+	assert.Equal(t, argorolloutsv1.AnalysisPhaseFailed, result.Phase)
+	assert.Equal(t, 2, measurements)
+	assert.Contains(t, lastQuery, `monovtx_read_total{namespace="default", mvtx_name="test-1"}`)
+
+	// the children are scaled differently during the upgrade, so the builtin metrics which are totals are compared per pod:
+	// the upgrading child with 1 of the 10 pods processes as much per pod as the promoted child
+	promotedValue, upgradingValue = "1000", "100"
+	promotedPods, upgradingPods = "10", "1"
+	result, _ = measure(analysis)
+	assert.Equal(t, argorolloutsv1.AnalysisPhaseSuccessful, result.Phase)
+	assert.Equal(t, []string{"100", "100"}, result.BaselineSamples)
+	assert.Equal(t, []string{"100", "100"}, result.CanarySamples)
+
+	// and fewer messages pending on fewer pods isn't better if there are more pending per pod
+	analysis.Metrics[0].Builtin = apiv1.BuiltinMetricPending
+	promotedValue, upgradingValue = "100", "50"
+	result, _ = measure(analysis)
+	assert.Equal(t, argorolloutsv1.AnalysisPhaseFailed, result.Phase)
+	assert.Equal(t, []string{"50", "50"}, result.CanarySamples)
+	assert.Contains(t, lastQuery, `monovtx_pending{namespace="default", mvtx_name="test-1", period="1m"}`)
+
+	// the builtin metrics which aren't totals aren't divided
+	analysis.Metrics[0].Builtin = apiv1.BuiltinMetricAckLatency
+	promotedValue, upgradingValue = "20", "20"
+	result, _ = measure(analysis)
+	assert.Equal(t, argorolloutsv1.AnalysisPhaseSuccessful, result.Phase)
+	assert.Equal(t, []string{"20", "20"}, result.BaselineSamples)
+}
@@ -0,0 +1,332 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progressive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	argorolloutsv1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/evaluate"
+	promapi "github.com/prometheus/client_golang/api"
+	promv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+const (
+	defaultMetricInterval              = time.Minute
+	defaultMetricCount                 = int32(1)
+	defaultMetricConsecutiveErrorLimit = int32(4)
+	prometheusQueryTimeout             = 30 * time.Second
+)
+
+/*
+PerformMetricAnalysis measures the Metrics of the Analysis natively by querying Prometheus, without requiring Argo Rollouts.
+Each time it's called, each Metric which hasn't completed yet and whose Interval has elapsed since its last measurement is measured once,
+and the resulting counts and phase are written to its MetricResult in the AnalysisStatus.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - existingUpgradingChildDef: the definition of the upgrading child as an unstructured object.
+  - rolloutObject: the rollout object.
+  - analysis: the Analysis containing the Metrics and arguments which can be referenced by the queries
+  - analysisStatus: the AnalysisStatus to update
+
+Returns:
+  - the updated AnalysisStatus
+  - An error if any issues occur during processing.
+*/
+func PerformMetricAnalysis(
+	ctx context.Context,
+	existingUpgradingChildDef *unstructured.Unstructured,
+	rolloutObject ProgressiveRolloutObject,
+	analysis apiv1.Analysis,
+	analysisStatus *apiv1.AnalysisStatus,
+) (*apiv1.AnalysisStatus, error) {
+	var promotedChildName string
+	if promotedChildStatus := rolloutObject.GetPromotedChildStatus(); promotedChildStatus != nil {
+		promotedChildName = promotedChildStatus.Name
+	}
+	return performMetricAnalysis(ctx, existingUpgradingChildDef, promotedChildName, analysis, analysisStatus)
+}
+
+// performMetricAnalysis measures the Metrics of the Analysis for the child, given the name of the promoted child to which it can be compared
+func performMetricAnalysis(
+	ctx context.Context,
+	existingUpgradingChildDef *unstructured.Unstructured,
+	promotedChildName string,
+	analysis apiv1.Analysis,
+	analysisStatus *apiv1.AnalysisStatus,
+) (*apiv1.AnalysisStatus, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	if analysisStatus == nil {
+		return analysisStatus, errors.New("analysisStatus not set")
+	}
+
+	globalConfig, err := config.GetConfigManagerInstance().GetConfig()
+	if err != nil {
+		return analysisStatus, fmt.Errorf("error getting the global config: %v", err)
+	}
+
+	timeNow := metav1.NewTime(time.Now())
+	if analysisStatus.StartTime == nil {
+		analysisStatus.StartTime = &timeNow
+	}
+
+	args := getAnalysisArgs(analysis, existingUpgradingChildDef, promotedChildName)
+
+	for _, metric := range analysis.Metrics {
+		result := findOrAddMetricResult(analysisStatus, metric.Name)
+		if result.Phase.Completed() {
+			continue
+		}
+		interval := defaultMetricInterval
+		if metric.Interval != nil {
+			interval = metric.Interval.Duration
+		}
+		if result.LastMeasurementTime != nil && time.Since(result.LastMeasurementTime.Time) < interval {
+			continue
+		}
+
+		address := metric.Prometheus.Address
+		if address == "" {
+			address = globalConfig.Progressive.PrometheusAddress
+		}
+
+		measureMetric(ctx, metric, result, address, args, existingUpgradingChildDef.GetKind(), promotedChildName, existingUpgradingChildDef.GetName())
+		result.LastMeasurementTime = &timeNow
+
+		numaLogger.WithValues("metric", metric.Name, "value", result.LastValue, "phase", result.Phase).Debug("measured analysis metric")
+	}
+
+	if analysisStatus.AnalysisRunName == "" && analysisStatus.EndTime == nil && GetMetricsPhase(analysisStatus.MetricResults).Completed() {
+		analysisStatus.EndTime = &timeNow
+	}
+
+	return analysisStatus, nil
+}
+
+// GetMetricsPhase returns the overall phase of the Metric results: a failure of any Metric takes precedence,
+// followed by any Metric which is still running; otherwise all Metrics were successful
+func GetMetricsPhase(results []apiv1.MetricResult) argorolloutsv1.AnalysisPhase {
+	phasePrecedence := []argorolloutsv1.AnalysisPhase{
+		argorolloutsv1.AnalysisPhaseFailed,
+		argorolloutsv1.AnalysisPhaseError,
+		argorolloutsv1.AnalysisPhaseInconclusive,
+		argorolloutsv1.AnalysisPhaseRunning,
+		argorolloutsv1.AnalysisPhasePending,
+	}
+	for _, phase := range phasePrecedence {
+		for _, result := range results {
+			if result.Phase == phase {
+				return phase
+			}
+		}
+	}
+	return argorolloutsv1.AnalysisPhaseSuccessful
+}
+
+// measure the metric once and update its result
+func measureMetric(
+	ctx context.Context,
+	metric apiv1.AnalysisMetric,
+	result *apiv1.MetricResult,
+	address string,
+	args map[string]string,
+	childKind string,
+	promotedChildName, upgradingChildName string,
+) {
+	var phase argorolloutsv1.AnalysisPhase
+	var err error
+	if metric.Comparison != nil {
+		phase, err = measureComparison(ctx, metric, result, address, args, childKind, promotedChildName, upgradingChildName)
+	} else {
+		var value any
+		value, err = queryPrometheus(ctx, address, resolveAnalysisArgs(metric.Prometheus.Query, args))
+		if err == nil {
+			result.LastValue = fmt.Sprintf("%v", value)
+			phase, err = evaluateMetricResult(metric, value)
+		}
+	}
+
+	switch {
+	case err != nil:
+		result.Error++
+		result.ConsecutiveError++
+		result.Message = err.Error()
+	case phase == argorolloutsv1.AnalysisPhaseSuccessful:
+		result.Successful++
+		result.Message = ""
+	case phase == argorolloutsv1.AnalysisPhaseFailed:
+		result.Failed++
+		if metric.Comparison == nil {
+			result.Message = fmt.Sprintf("value %s met the failure condition or did not meet the success condition", result.LastValue)
+		}
+	case phase == argorolloutsv1.AnalysisPhaseRunning:
+		// a comparative metric is still collecting samples, so this measurement isn't judged
+	default:
+		result.Inconclusive++
+		result.Message = fmt.Sprintf("value %s met neither the success nor the failure condition", result.LastValue)
+	}
+	if err == nil {
+		result.Count++
+		result.ConsecutiveError = 0
+	}
+
+	count := defaultMetricCount
+	if metric.Count != nil {
+		count = *metric.Count
+	}
+	if metric.Comparison != nil {
+		// a comparative metric can't succeed before the upgrading child has been judged
+		count = max(count, comparisonMinSamples(*metric.Comparison))
+	}
+	consecutiveErrorLimit := defaultMetricConsecutiveErrorLimit
+	if metric.ConsecutiveErrorLimit != nil {
+		consecutiveErrorLimit = *metric.ConsecutiveErrorLimit
+	}
+
+	switch {
+	case result.Failed > metric.FailureLimit:
+		result.Phase = argorolloutsv1.AnalysisPhaseFailed
+	case result.Inconclusive > metric.InconclusiveLimit:
+		result.Phase = argorolloutsv1.AnalysisPhaseInconclusive
+	case result.ConsecutiveError > consecutiveErrorLimit:
+		result.Phase = argorolloutsv1.AnalysisPhaseError
+	case result.Count >= count:
+		result.Phase = argorolloutsv1.AnalysisPhaseSuccessful
+	default:
+		result.Phase = argorolloutsv1.AnalysisPhaseRunning
+	}
+}
+
+// evaluateMetricResult evaluates the measured value against the Metric's conditions, using the same semantics as Argo Rollouts:
+// - if the failure condition is met, the measurement fails
+// - if the success condition is met, the measurement succeeds
+// - if both conditions are defined and neither is met, the measurement is inconclusive
+// - if only one condition is defined and it isn't met, the measurement has the opposite result
+func evaluateMetricResult(metric apiv1.AnalysisMetric, value any) (argorolloutsv1.AnalysisPhase, error) {
+	if metric.FailureCondition != "" {
+		failed, err := evaluate.EvalCondition(value, metric.FailureCondition)
+		if err != nil {
+			return argorolloutsv1.AnalysisPhaseError, fmt.Errorf("error evaluating failure condition %q: %w", metric.FailureCondition, err)
+		}
+		if failed {
+			return argorolloutsv1.AnalysisPhaseFailed, nil
+		}
+	}
+	if metric.SuccessCondition != "" {
+		succeeded, err := evaluate.EvalCondition(value, metric.SuccessCondition)
+		if err != nil {
+			return argorolloutsv1.AnalysisPhaseError, fmt.Errorf("error evaluating success condition %q: %w", metric.SuccessCondition, err)
+		}
+		if succeeded {
+			return argorolloutsv1.AnalysisPhaseSuccessful, nil
+		}
+		if metric.FailureCondition != "" {
+			return argorolloutsv1.AnalysisPhaseInconclusive, nil
+		}
+		return argorolloutsv1.AnalysisPhaseFailed, nil
+	}
+	return argorolloutsv1.AnalysisPhaseSuccessful, nil
+}
+
+// queryPrometheus runs the PromQL query and returns the result as a float64 for a scalar, or a []float64 for a vector
+func queryPrometheus(ctx context.Context, address string, query string) (any, error) {
+	if address == "" {
+		return nil, errors.New("no Prometheus address configured for the metric or in the controller config")
+	}
+	promClient, err := promapi.NewClient(promapi.Config{Address: address})
+	if err != nil {
+		return nil, fmt.Errorf("error creating Prometheus client for %s: %w", address, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, prometheusQueryTimeout)
+	defer cancel()
+
+	response, warnings, err := promv1.NewAPI(promClient).Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("error querying Prometheus %q: %w", query, err)
+	}
+	if len(warnings) > 0 {
+		logger.FromContext(ctx).WithValues("query", query, "warnings", warnings).Debug("Prometheus query returned warnings")
+	}
+
+	switch value := response.(type) {
+	case *model.Scalar:
+		return float64(value.Value), nil
+	case model.Vector:
+		results := make([]float64, 0, len(value))
+		for _, sample := range value {
+			results = append(results, float64(sample.Value))
+		}
+		return results, nil
+	default:
+		return nil, fmt.Errorf("unsupported Prometheus result type %q for query %q", response.Type(), query)
+	}
+}
+
+// getAnalysisArgs returns the Analysis arguments by name, along with the special arguments for the upgrading and promoted children
+func getAnalysisArgs(analysis apiv1.Analysis, existingUpgradingChildDef *unstructured.Unstructured, promotedChildName string) map[string]string {
+	args := map[string]string{}
+	for _, arg := range analysis.Args {
+		if arg.Value != nil {
+			args[arg.Name] = *arg.Value
+		}
+	}
+
+	switch existingUpgradingChildDef.GetKind() {
+	case "MonoVertex":
+		args["upgrading-monovertex-name"] = existingUpgradingChildDef.GetName()
+		args["promoted-monovertex-name"] = promotedChildName
+		args["monovertex-namespace"] = existingUpgradingChildDef.GetNamespace()
+	case "Pipeline":
+		args["upgrading-pipeline-name"] = existingUpgradingChildDef.GetName()
+		args["promoted-pipeline-name"] = promotedChildName
+		args["pipeline-namespace"] = existingUpgradingChildDef.GetNamespace()
+	}
+	return args
+}
+
+// resolveAnalysisArgs replaces each "{{args.<name>}}" in the query with the argument's value
+func resolveAnalysisArgs(query string, args map[string]string) string {
+	oldNew := make([]string, 0, len(args)*2)
+	for name, value := range args {
+		oldNew = append(oldNew, fmt.Sprintf("{{args.%s}}", name), value)
+	}
+	return strings.NewReplacer(oldNew...).Replace(query)
+}
+
+func findOrAddMetricResult(analysisStatus *apiv1.AnalysisStatus, name string) *apiv1.MetricResult {
+	for i := range analysisStatus.MetricResults {
+		if analysisStatus.MetricResults[i].Name == name {
+			return &analysisStatus.MetricResults[i]
+		}
+	}
+	analysisStatus.MetricResults = append(analysisStatus.MetricResults, apiv1.MetricResult{Name: name, Phase: argorolloutsv1.AnalysisPhasePending})
+	return &analysisStatus.MetricResults[len(analysisStatus.MetricResults)-1]
+}
@@ -0,0 +1,175 @@
+package progressive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	argorolloutsv1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// fakePrometheus is a local stand-in for the Prometheus query API, which returns the given vector value for every query
+type fakePrometheus struct {
+	sync.Mutex
+	value   string
+	queries []string
+}
+
+func (f *fakePrometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	_ = r.ParseForm()
+	f.queries = append(f.queries, r.Form.Get("query"))
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,"` + f.value + `"]}]}}`))
+}
+
+func Test_PerformMetricAnalysis(t *testing.T) {
+	ctx := context.Background()
+	three := int32(3)
+
+	testCases := []struct {
+		name              string
+		value             string
+		metric            apiv1.AnalysisMetric
+		measurements      int
+		expectedPhase     argorolloutsv1.AnalysisPhase
+		expectedSucceeded int32
+		expectedFailed    int32
+	}{
+		{
+			name:              "single successful measurement",
+			value:             "0.99",
+			metric:            apiv1.AnalysisMetric{SuccessCondition: "result[0] >= 0.95"},
+			measurements:      1,
+			expectedPhase:     argorolloutsv1.AnalysisPhaseSuccessful,
+			expectedSucceeded: 1,
+		},
+		{
+			name:              "still running until count is reached",
+			value:             "0.99",
+			metric:            apiv1.AnalysisMetric{SuccessCondition: "result[0] >= 0.95", Count: &three},
+			measurements:      2,
+			expectedPhase:     argorolloutsv1.AnalysisPhaseRunning,
+			expectedSucceeded: 2,
+		},
+		{
+			name:           "failure condition met",
+			value:          "0.5",
+			metric:         apiv1.AnalysisMetric{FailureCondition: "result[0] < 0.95"},
+			measurements:   1,
+			expectedPhase:  argorolloutsv1.AnalysisPhaseFailed,
+			expectedFailed: 1,
+		},
+		{
+			name:           "failures within the failure limit",
+			value:          "0.5",
+			metric:         apiv1.AnalysisMetric{SuccessCondition: "result[0] >= 0.95", Count: &three, FailureLimit: 2},
+			measurements:   2,
+			expectedPhase:  argorolloutsv1.AnalysisPhaseRunning,
+			expectedFailed: 2,
+		},
+		{
+			name:          "neither condition met",
+			value:         "0.9",
+			metric:        apiv1.AnalysisMetric{SuccessCondition: "result[0] >= 0.95", FailureCondition: "result[0] < 0.5"},
+			measurements:  1,
+			expectedPhase: argorolloutsv1.AnalysisPhaseInconclusive,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			prometheus := &fakePrometheus{value: tc.value}
+			server := httptest.NewServer(prometheus)
+			defer server.Close()
+
+			tc.metric.Name = "success-rate"
+			tc.metric.Prometheus = apiv1.PrometheusMetric{Address: server.URL, Query: `rate(requests{name="{{args.upgrading-monovertex-name}}"}[1m])`}
+			// measure on every call
+			tc.metric.Interval = &metav1.Duration{Duration: 0}
+			analysis := apiv1.Analysis{Metrics: []apiv1.AnalysisMetric{tc.metric}}
+
+			rollout := stepsMonoVertexRollout(nil, 0)
+			analysisStatus := &apiv1.AnalysisStatus{}
+			upgradingChild := monoVertexToUnstruct(createMonoVertex("test-1"))
+
+			for i := 0; i < tc.measurements; i++ {
+				var err error
+				analysisStatus, err = PerformMetricAnalysis(ctx, upgradingChild, rollout, analysis, analysisStatus)
+				assert.NoError(t, err)
+			}
+
+			assert.Len(t, analysisStatus.MetricResults, 1)
+			result := analysisStatus.MetricResults[0]
+			assert.Equal(t, tc.expectedPhase, result.Phase)
+			assert.Equal(t, int32(tc.measurements), result.Count)
+			assert.Equal(t, tc.expectedSucceeded, result.Successful)
+			assert.Equal(t, tc.expectedFailed, result.Failed)
+			assert.Equal(t, tc.value, result.LastValue[1:len(result.LastValue)-1])
+			assert.NotNil(t, analysisStatus.StartTime)
+			assert.Equal(t, tc.expectedPhase.Completed(), analysisStatus.EndTime != nil)
+			assert.Equal(t, `rate(requests{name="test-1"}[1m])`, prometheus.queries[0])
+		})
+	}
+}
+
+func Test_PerformMetricAnalysis_interval(t *testing.T) {
+	prometheus := &fakePrometheus{value: "1"}
+	server := httptest.NewServer(prometheus)
+	defer server.Close()
+
+	three := int32(3)
+	analysis := apiv1.Analysis{Metrics: []apiv1.AnalysisMetric{{
+		Name:       "throughput",
+		Prometheus: apiv1.PrometheusMetric{Address: server.URL, Query: "sum(rate(requests[1m]))"},
+		Interval:   &metav1.Duration{Duration: time.Hour},
+		Count:      &three,
+	}}}
+
+	analysisStatus := &apiv1.AnalysisStatus{}
+	for i := 0; i < 2; i++ {
+		_, err := PerformMetricAnalysis(context.Background(), monoVertexToUnstruct(createMonoVertex("test-1")), stepsMonoVertexRollout(nil, 0), analysis, analysisStatus)
+		assert.NoError(t, err)
+	}
+	// the second call is within the interval so no measurement is taken
+	assert.Len(t, prometheus.queries, 1)
+	assert.Equal(t, int32(1), analysisStatus.MetricResults[0].Count)
+}
+
+func Test_PerformMetricAnalysis_errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	zero := int32(0)
+	analysis := apiv1.Analysis{Metrics: []apiv1.AnalysisMetric{{
+		Name:                  "throughput",
+		Prometheus:            apiv1.PrometheusMetric{Address: server.URL, Query: "sum(rate(requests[1m]))"},
+		ConsecutiveErrorLimit: &zero,
+	}}}
+
+	analysisStatus, err := PerformMetricAnalysis(context.Background(), monoVertexToUnstruct(createMonoVertex("test-1")), stepsMonoVertexRollout(nil, 0), analysis, &apiv1.AnalysisStatus{})
+	assert.NoError(t, err)
+	result := analysisStatus.MetricResults[0]
+	assert.Equal(t, argorolloutsv1.AnalysisPhaseError, result.Phase)
+	assert.Equal(t, int32(1), result.Error)
+	assert.NotEmpty(t, result.Message)
+}
+
+func Test_GetMetricsPhase(t *testing.T) {
+	assert.Equal(t, argorolloutsv1.AnalysisPhaseSuccessful, GetMetricsPhase([]apiv1.MetricResult{
+		{Phase: argorolloutsv1.AnalysisPhaseSuccessful}, {Phase: argorolloutsv1.AnalysisPhaseSuccessful}}))
+	assert.Equal(t, argorolloutsv1.AnalysisPhaseRunning, GetMetricsPhase([]apiv1.MetricResult{
+		{Phase: argorolloutsv1.AnalysisPhaseSuccessful}, {Phase: argorolloutsv1.AnalysisPhaseRunning}}))
+	assert.Equal(t, argorolloutsv1.AnalysisPhaseFailed, GetMetricsPhase([]apiv1.MetricResult{
+		{Phase: argorolloutsv1.AnalysisPhaseRunning}, {Phase: argorolloutsv1.AnalysisPhaseFailed}}))
+}
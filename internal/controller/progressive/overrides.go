@@ -0,0 +1,189 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progressive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	"github.com/numaproj/numaplane/internal/util/logger"
+)
+
+// getUpgradingOverrides returns the UpgradingOverrides of the Rollout as a map, or nil if there aren't any
+func getUpgradingOverrides(rolloutObject ProgressiveRolloutObject) (map[string]interface{}, error) {
+	overrides := rolloutObject.GetProgressiveStrategy().UpgradingOverrides
+	if overrides == nil || len(overrides.Raw) == 0 {
+		return nil, nil
+	}
+	var overridesMap map[string]interface{}
+	if err := json.Unmarshal(overrides.Raw, &overridesMap); err != nil {
+		return nil, fmt.Errorf("failed to parse upgradingOverrides as a JSON object: %w", err)
+	}
+	return overridesMap, nil
+}
+
+// applyUpgradingOverrides merges the Rollout's UpgradingOverrides, if any, into the spec of the upgrading child definition
+func applyUpgradingOverrides(rolloutObject ProgressiveRolloutObject, upgradingChildDef *unstructured.Unstructured) error {
+	overrides, err := getUpgradingOverrides(rolloutObject)
+	if err != nil || overrides == nil {
+		return err
+	}
+
+	// round trip the spec through JSON so that its values are of the same types as the overrides
+	specAsJSON, err := json.Marshal(upgradingChildDef.Object["spec"])
+	if err != nil {
+		return err
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal(specAsJSON, &spec); err != nil {
+		return fmt.Errorf("failed to apply upgradingOverrides to %s/%s: %w", upgradingChildDef.GetNamespace(), upgradingChildDef.GetName(), err)
+	}
+	upgradingChildDef.Object["spec"] = mergeOverrides(spec, overrides)
+	return nil
+}
+
+/*
+mergeOverrides merges the overrides into the original as a JSON merge patch would, except that lists whose items all have a
+"name" (such as the vertices of a Pipeline, or the env of a container) are merged item by item: an item of the overrides is
+merged into the item of the original of the same name, or appended if there isn't one. Any other list replaces the original list.
+
+Parameters:
+  - original: the object to merge into, which isn't modified.
+  - overrides: the fields to override; a null value removes the field.
+
+Returns:
+  - The merged object.
+*/
+func mergeOverrides(original, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(original))
+	for key, value := range original {
+		merged[key] = value
+	}
+	for key, overrideValue := range overrides {
+		switch override := overrideValue.(type) {
+		case nil:
+			delete(merged, key)
+		case map[string]interface{}:
+			originalMap, isMap := merged[key].(map[string]interface{})
+			if !isMap {
+				originalMap = map[string]interface{}{}
+			}
+			merged[key] = mergeOverrides(originalMap, override)
+		case []interface{}:
+			originalList, isList := merged[key].([]interface{})
+			if isList && isNamedList(originalList) && isNamedList(override) {
+				merged[key] = mergeNamedList(originalList, override)
+			} else {
+				merged[key] = override
+			}
+		default:
+			merged[key] = override
+		}
+	}
+	return merged
+}
+
+// isNamedList determines if the list is non-empty and all of its items are objects with a "name"
+func isNamedList(list []interface{}) bool {
+	for _, item := range list {
+		itemMap, isMap := item.(map[string]interface{})
+		if !isMap {
+			return false
+		}
+		if _, hasName := itemMap["name"].(string); !hasName {
+			return false
+		}
+	}
+	return len(list) > 0
+}
+
+// mergeNamedList merges each item of the overrides into the item of the original of the same name, or appends it
+func mergeNamedList(original, overrides []interface{}) []interface{} {
+	merged := make([]interface{}, len(original))
+	copy(merged, original)
+	indexByName := make(map[string]int, len(original))
+	for i, item := range original {
+		indexByName[item.(map[string]interface{})["name"].(string)] = i
+	}
+	for _, item := range overrides {
+		overrideItem := item.(map[string]interface{})
+		if i, found := indexByName[overrideItem["name"].(string)]; found {
+			merged[i] = mergeOverrides(merged[i].(map[string]interface{}), overrideItem)
+		} else {
+			merged = append(merged, overrideItem)
+		}
+	}
+	return merged
+}
+
+// removeUpgradingOverrides restores the fields of the upgrading child which were set by the Rollout's UpgradingOverrides,
+// if any, to the values derived from the Rollout definition, by patching the child in place
+func removeUpgradingOverrides(
+	ctx context.Context,
+	rolloutObject ProgressiveRolloutObject,
+	controller progressiveController,
+	upgradingChildDef *unstructured.Unstructured,
+	c client.Client,
+) error {
+	overrides, err := getUpgradingOverrides(rolloutObject)
+	if err != nil || overrides == nil {
+		return err
+	}
+
+	originalChildDef, err := controller.CreateUpgradingChildDefinition(ctx, rolloutObject, upgradingChildDef.GetName())
+	if err != nil {
+		return err
+	}
+	originalSpec, _, err := unstructured.NestedMap(originalChildDef.Object, "spec")
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{"spec": makeRevertPatch(overrides, originalSpec)})
+	if err != nil {
+		return err
+	}
+	logger.FromContext(ctx).WithValues("child", upgradingChildDef.GetName(), "patch", string(patch)).Debug("removing upgradingOverrides from child")
+	return kubernetes.PatchResource(ctx, c, upgradingChildDef, string(patch), k8stypes.MergePatchType)
+}
+
+// makeRevertPatch creates a JSON merge patch which sets each field in the overrides back to its value in the original,
+// or removes it if the original doesn't have it (a list is restored entirely, including one whose items were merged by name)
+func makeRevertPatch(overrides, original map[string]interface{}) map[string]interface{} {
+	revert := make(map[string]interface{}, len(overrides))
+	for key, overrideValue := range overrides {
+		originalValue, found := original[key]
+		if !found {
+			revert[key] = nil
+			continue
+		}
+		overrideMap, overrideIsMap := overrideValue.(map[string]interface{})
+		originalMap, originalIsMap := originalValue.(map[string]interface{})
+		if overrideIsMap && originalIsMap {
+			revert[key] = makeRevertPatch(overrideMap, originalMap)
+		} else {
+			revert[key] = originalValue
+		}
+	}
+	return revert
+}
@@ -0,0 +1,132 @@
+package progressive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_applyUpgradingOverrides(t *testing.T) {
+	childDef := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"source": map[string]interface{}{
+				"udsource": map[string]interface{}{"container": map[string]interface{}{"image": "my-source:v1"}},
+			},
+			"limits": map[string]interface{}{"readBatchSize": int64(100)},
+		},
+	}}
+
+	// no overrides
+	rollout := stepsMonoVertexRollout(nil, 0)
+	assert.NoError(t, applyUpgradingOverrides(rollout, childDef))
+	assert.Equal(t, int64(100), childDef.Object["spec"].(map[string]interface{})["limits"].(map[string]interface{})["readBatchSize"])
+
+	rollout.Spec.Strategy.Progressive.UpgradingOverrides = &runtime.RawExtension{Raw: []byte(
+		`{"source":{"udsource":{"container":{"env":[{"name":"LOG_LEVEL","value":"debug"}]}}},"limits":null}`)}
+	assert.NoError(t, applyUpgradingOverrides(rollout, childDef))
+
+	container, _, _ := unstructured.NestedMap(childDef.Object, "spec", "source", "udsource", "container")
+	assert.Equal(t, "my-source:v1", container["image"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"}}, container["env"])
+	_, found, _ := unstructured.NestedMap(childDef.Object, "spec", "limits")
+	assert.False(t, found)
+
+	// invalid patch
+	rollout.Spec.Strategy.Progressive.UpgradingOverrides = &runtime.RawExtension{Raw: []byte(`[1,2]`)}
+	assert.Error(t, applyUpgradingOverrides(rollout, childDef))
+}
+
+func Test_applyUpgradingOverrides_pipeline(t *testing.T) {
+	childDef := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"vertices": []interface{}{
+				map[string]interface{}{"name": "in", "source": map[string]interface{}{"generator": map[string]interface{}{"rpu": int64(5)}}},
+				map[string]interface{}{"name": "cat", "udf": map[string]interface{}{"container": map[string]interface{}{
+					"image": "my-udf:v1",
+					"env":   []interface{}{map[string]interface{}{"name": "LOG_LEVEL", "value": "info"}, map[string]interface{}{"name": "REGION", "value": "us"}},
+				}}},
+				map[string]interface{}{"name": "out", "sink": map[string]interface{}{"log": map[string]interface{}{}}},
+			},
+			"edges": []interface{}{
+				map[string]interface{}{"from": "in", "to": "cat"},
+				map[string]interface{}{"from": "cat", "to": "out"},
+			},
+		},
+	}}
+
+	// override one field of one vertex, and one env var of its container
+	rollout := pipelineRolloutWithOverrides(`{"vertices":[{"name":"cat","limits":{"readBatchSize":10},` +
+		`"udf":{"container":{"env":[{"name":"LOG_LEVEL","value":"debug"}]}}}]}`)
+	assert.NoError(t, applyUpgradingOverrides(rollout, childDef))
+
+	vertices, _, _ := unstructured.NestedSlice(childDef.Object, "spec", "vertices")
+	assert.Len(t, vertices, 3)
+	assert.Equal(t, "in", vertices[0].(map[string]interface{})["name"])
+	assert.Equal(t, map[string]interface{}{"generator": map[string]interface{}{"rpu": float64(5)}}, vertices[0].(map[string]interface{})["source"])
+	assert.Equal(t, "out", vertices[2].(map[string]interface{})["name"])
+
+	cat := vertices[1].(map[string]interface{})
+	assert.Equal(t, "cat", cat["name"])
+	assert.Equal(t, map[string]interface{}{"readBatchSize": float64(10)}, cat["limits"])
+	container, _, _ := unstructured.NestedMap(cat, "udf", "container")
+	assert.Equal(t, "my-udf:v1", container["image"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"},
+		map[string]interface{}{"name": "REGION", "value": "us"},
+	}, container["env"])
+
+	// a vertex which isn't in the child is appended, and a list without names replaces the list of the child
+	rollout = pipelineRolloutWithOverrides(`{"vertices":[{"name":"audit","sink":{"log":{}}}],"edges":[{"from":"in","to":"out"}]}`)
+	assert.NoError(t, applyUpgradingOverrides(rollout, childDef))
+	vertices, _, _ = unstructured.NestedSlice(childDef.Object, "spec", "vertices")
+	assert.Len(t, vertices, 4)
+	assert.Equal(t, "audit", vertices[3].(map[string]interface{})["name"])
+	edges, _, _ := unstructured.NestedSlice(childDef.Object, "spec", "edges")
+	assert.Equal(t, []interface{}{map[string]interface{}{"from": "in", "to": "out"}}, edges)
+}
+
+// pipelineRolloutWithOverrides returns a PipelineRollout with the given UpgradingOverrides
+func pipelineRolloutWithOverrides(overrides string) *apiv1.PipelineRollout {
+	return &apiv1.PipelineRollout{
+		Spec: apiv1.PipelineRolloutSpec{
+			Strategy: &apiv1.PipelineStrategy{
+				PipelineTypeRolloutStrategy: apiv1.PipelineTypeRolloutStrategy{
+					PipelineTypeProgressiveStrategy: apiv1.PipelineTypeProgressiveStrategy{
+						Progressive: apiv1.ProgressiveStrategy{
+							UpgradingOverrides: &runtime.RawExtension{Raw: []byte(overrides)},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_makeRevertPatch(t *testing.T) {
+	overrides := map[string]interface{}{
+		"source": map[string]interface{}{
+			"udsource": map[string]interface{}{"container": map[string]interface{}{"env": []interface{}{"LOG_LEVEL=debug"}}},
+		},
+		"limits":    nil,
+		"lifecycle": map[string]interface{}{"desiredPhase": "Paused"},
+	}
+	original := map[string]interface{}{
+		"source": map[string]interface{}{
+			"udsource": map[string]interface{}{"container": map[string]interface{}{"image": "my-source:v1", "env": []interface{}{"LOG_LEVEL=info"}}},
+		},
+		"limits": map[string]interface{}{"readBatchSize": int64(100)},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"source": map[string]interface{}{
+			"udsource": map[string]interface{}{"container": map[string]interface{}{"env": []interface{}{"LOG_LEVEL=info"}}},
+		},
+		// a removed field is restored and an added field is removed
+		"limits":    map[string]interface{}{"readBatchSize": int64(100)},
+		"lifecycle": nil,
+	}, makeRevertPatch(overrides, original))
+}
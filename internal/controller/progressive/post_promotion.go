@@ -0,0 +1,316 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progressive
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/common"
+	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
+	"github.com/numaproj/numaplane/internal/controller/common/revisions"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+/*
+startPostPromotion puts the previously promoted child on standby for the post-promotion analysis of the newly promoted child:
+the previous child is scaled to zero rather than recycled, so that it can be revived if the analysis fails.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - rolloutObject: the rollout object.
+  - controller: the progressive controller for the Kind.
+  - postPromotionAnalysis: the PostPromotionAnalysis defined for the rollout.
+  - previousChildDef: the definition of the previously promoted child.
+  - childStatus: the status of the newly promoted child, to which the PostPromotionStatus is added.
+  - c: the client used for interacting with the Kubernetes API.
+
+Returns:
+  - An error if any issues occur during processing.
+*/
+func startPostPromotion(
+	ctx context.Context,
+	rolloutObject ProgressiveRolloutObject,
+	controller progressiveController,
+	postPromotionAnalysis *apiv1.PostPromotionAnalysis,
+	previousChildDef *unstructured.Unstructured,
+	childStatus *apiv1.UpgradingChildStatus,
+	c client.Client,
+) error {
+	numaLogger := logger.FromContext(ctx)
+
+	scaleValues, err := controller.ProcessPreviousChildPostPromotion(ctx, rolloutObject, previousChildDef, c)
+	if err != nil {
+		return err
+	}
+
+	reasonSuccess := common.LabelValueProgressiveSuccess
+	if err := ctlrcommon.UpdateUpgradeState(ctx, c, common.LabelValueUpgradeStandby, &reasonSuccess, previousChildDef); err != nil {
+		return err
+	}
+
+	windowEndTime := metav1.NewTime(time.Now().Add(postPromotionAnalysis.Window.Duration))
+	childStatus.PostPromotion = &apiv1.PostPromotionStatus{
+		PreviousChildName:        previousChildDef.GetName(),
+		PreviousChildScaleValues: scaleValues,
+		WindowEndTime:            &windowEndTime,
+		AssessmentResult:         apiv1.AssessmentResultUnknown,
+	}
+
+	numaLogger.WithValues("previous child", previousChildDef.GetName(), "windowEndTime", windowEndTime).Info("started post-promotion analysis")
+	return nil
+}
+
+/*
+processPostPromotion analyzes the promoted child during its post-promotion analysis window.
+  - If the analysis fails, the previous child is revived and promoted again, and the promoted child is recycled.
+  - If the window ends without a failure, or the Rollout spec changes during the window, the previous child is recycled.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - rolloutObject: the rollout object.
+  - controller: the progressive controller for the Kind.
+  - existingPromotedChildDef: the definition of the promoted child.
+  - promotedDifference: whether the Rollout spec differs from the promoted child.
+  - c: the client used for interacting with the Kubernetes API.
+
+Returns:
+  - A boolean indicating whether the post-promotion analysis is done.
+  - The requeue delay if it's not done.
+  - An error if any issues occur during processing.
+*/
+func processPostPromotion(
+	ctx context.Context,
+	rolloutObject ProgressiveRolloutObject,
+	controller progressiveController,
+	existingPromotedChildDef *unstructured.Unstructured,
+	promotedDifference bool,
+	c client.Client,
+) (bool, time.Duration, error) {
+	numaLogger := logger.FromContext(ctx).WithValues("promoted child", existingPromotedChildDef.GetName())
+
+	childStatus := rolloutObject.GetUpgradingChildStatus()
+	postPromotionStatus := childStatus.PostPromotion
+
+	previousChildDef, err := ctlrcommon.FindMostCurrentChildOfUpgradeState(ctx, rolloutObject, common.LabelValueUpgradeStandby, nil, true, c)
+	if err != nil {
+		return false, 0, err
+	}
+
+	// determine if the window should end now, with the previous child being recycled
+	endReason := ""
+	postPromotionAnalysis := rolloutObject.GetPostPromotionAnalysis()
+	switch {
+	case childStatus.Name != existingPromotedChildDef.GetName():
+		endReason = "the promoted child changed during the post-promotion analysis window"
+	case postPromotionAnalysis == nil:
+		endReason = "post-promotion analysis is no longer defined"
+	case promotedDifference:
+		endReason = "the rollout changed during the post-promotion analysis window"
+	case previousChildDef == nil:
+		endReason = "the previous child no longer exists"
+	case isPostPromotionWindowOver(postPromotionStatus):
+		endReason = "the post-promotion analysis window ended"
+	}
+	if endReason != "" {
+		if err := endPostPromotion(ctx, rolloutObject, previousChildDef, c); err != nil {
+			return false, 0, err
+		}
+		numaLogger.WithValues("reason", endReason).Info("post-promotion analysis completed successfully")
+		return true, 0, nil
+	}
+
+	result, failureReason, err := assessPostPromotion(ctx, *postPromotionAnalysis, existingPromotedChildDef, postPromotionStatus, c)
+	if err != nil {
+		return false, 0, err
+	}
+	if result == apiv1.AssessmentResultFailure {
+		if err := revivePreviousChild(ctx, rolloutObject, controller, existingPromotedChildDef, previousChildDef, failureReason, c); err != nil {
+			return false, 0, err
+		}
+		return true, 0, nil
+	}
+
+	rolloutObject.SetUpgradingChildStatus(childStatus)
+
+	requeueDelay := time.Until(postPromotionStatus.WindowEndTime.Time)
+	if requeueDelay > common.DefaultRequeueDelay {
+		requeueDelay = common.DefaultRequeueDelay
+	}
+	return false, requeueDelay, nil
+}
+
+// isPostPromotionWindowOver determines if the post-promotion analysis window has ended
+func isPostPromotionWindowOver(postPromotionStatus *apiv1.PostPromotionStatus) bool {
+	return postPromotionStatus.WindowEndTime == nil || !time.Now().Before(postPromotionStatus.WindowEndTime.Time)
+}
+
+/*
+assessPostPromotion assesses the promoted child during its post-promotion analysis window, using the basic resource health check
+and the Analysis. Rather than the analysisRunTimeout, the Analysis is only limited by the window, since it may keep running until the end of it.
+
+Returns:
+  - Failure if the promoted child is unhealthy or the Analysis failed; otherwise Unknown
+  - The reason for the failure
+  - An error if any issues occur during processing.
+*/
+func assessPostPromotion(
+	ctx context.Context,
+	postPromotionAnalysis apiv1.PostPromotionAnalysis,
+	existingPromotedChildDef *unstructured.Unstructured,
+	postPromotionStatus *apiv1.PostPromotionStatus,
+	c client.Client,
+) (apiv1.AssessmentResult, string, error) {
+
+	// replica counts may legitimately vary due to autoscaling, so only the phase and conditions are checked
+	result, failureReason, err := PerformResourceHealthCheckForPipelineType(ctx, existingPromotedChildDef,
+		func(*unstructured.Unstructured) (bool, string, error) { return true, "", nil })
+	if err != nil {
+		return apiv1.AssessmentResultUnknown, "", err
+	}
+	if result == apiv1.AssessmentResultFailure {
+		return result, failureReason, nil
+	}
+
+	analysis := withoutComparativeMetrics(*postPromotionAnalysis.Analysis)
+	if len(analysis.Templates) == 0 && len(analysis.Metrics) == 0 {
+		return apiv1.AssessmentResultUnknown, "", nil
+	}
+
+	analysisStatus := &postPromotionStatus.Analysis
+	if len(analysis.Templates) > 0 {
+		analysisRunName := fmt.Sprintf("%s-%s-post-promotion", strings.ToLower(existingPromotedChildDef.GetKind()), existingPromotedChildDef.GetName())
+		analysisStatus, err = performAnalysisRun(ctx, existingPromotedChildDef, analysis, analysisStatus, analysisRunName, postPromotionStatus.PreviousChildName, c)
+		if err != nil {
+			return apiv1.AssessmentResultUnknown, "", err
+		}
+	}
+	if len(analysis.Metrics) > 0 {
+		analysisStatus, err = performMetricAnalysis(ctx, existingPromotedChildDef, postPromotionStatus.PreviousChildName, analysis, analysisStatus)
+		if err != nil {
+			return apiv1.AssessmentResultUnknown, "", err
+		}
+	}
+	postPromotionStatus.Analysis = *analysisStatus
+
+	result, failureReason, err = assessAnalysisStatus(ctx, existingPromotedChildDef, analysisStatus, postPromotionAnalysis.Window.Duration)
+	if err != nil || result != apiv1.AssessmentResultFailure {
+		return apiv1.AssessmentResultUnknown, "", err
+	}
+	return result, failureReason, nil
+}
+
+// withoutComparativeMetrics removes any Metrics with a Comparison from the Analysis, since there's no running child to compare to
+func withoutComparativeMetrics(analysis apiv1.Analysis) apiv1.Analysis {
+	metrics := []apiv1.AnalysisMetric{}
+	for _, metric := range analysis.Metrics {
+		if metric.Comparison == nil {
+			metrics = append(metrics, metric)
+		}
+	}
+	analysis.Metrics = metrics
+	return analysis
+}
+
+// endPostPromotion concludes a successful post-promotion analysis: the previous child (if any) is recycled
+func endPostPromotion(
+	ctx context.Context,
+	rolloutObject ProgressiveRolloutObject,
+	previousChildDef *unstructured.Unstructured,
+	c client.Client,
+) error {
+	if previousChildDef != nil {
+		reasonSuccess := common.LabelValueProgressiveSuccess
+		if err := ctlrcommon.UpdateUpgradeState(ctx, c, common.LabelValueUpgradeRecyclable, &reasonSuccess, previousChildDef); err != nil {
+			return err
+		}
+	}
+
+	childStatus := rolloutObject.GetUpgradingChildStatus()
+	childStatus.PostPromotion.AssessmentResult = apiv1.AssessmentResultSuccess
+	rolloutObject.SetUpgradingChildStatus(childStatus)
+	return nil
+}
+
+/*
+revivePreviousChild handles a failed post-promotion analysis: the previous child is scaled back to its original scale values
+and promoted again, and the failed child is recycled. The Rollout is marked as RolledBack so that the same generation isn't attempted again.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - rolloutObject: the rollout object.
+  - controller: the progressive controller for the Kind.
+  - failedChildDef: the definition of the promoted child which failed its post-promotion analysis.
+  - previousChildDef: the definition of the previous child.
+  - failureReason: the reason for the failure.
+  - c: the client used for interacting with the Kubernetes API.
+
+Returns:
+  - An error if any issues occur during processing.
+*/
+func revivePreviousChild(
+	ctx context.Context,
+	rolloutObject ProgressiveRolloutObject,
+	controller progressiveController,
+	failedChildDef, previousChildDef *unstructured.Unstructured,
+	failureReason string,
+	c client.Client,
+) error {
+	numaLogger := logger.FromContext(ctx)
+
+	childStatus := rolloutObject.GetUpgradingChildStatus()
+
+	if err := controller.RevivePreviousChild(ctx, rolloutObject, previousChildDef, childStatus.PostPromotion.PreviousChildScaleValues, c); err != nil {
+		return err
+	}
+
+	reason := common.LabelValuePostPromotionRolledBack
+	if err := ctlrcommon.UpdateUpgradeState(ctx, c, common.LabelValueUpgradePromoted, &reason, previousChildDef); err != nil {
+		return err
+	}
+	if err := ctlrcommon.UpdateUpgradeState(ctx, c, common.LabelValueUpgradeRecyclable, &reason, failedChildDef); err != nil {
+		return err
+	}
+
+	if err := revisions.UpdateRevisionOutcome(ctx, c, rolloutObject, failedChildDef.GetName(), revisions.OutcomeFailed); err != nil {
+		numaLogger.Error(err, "failed to update revision outcome for child which failed post-promotion analysis")
+	}
+
+	failureReason = fmt.Sprintf("post-promotion analysis failed: %s", failureReason)
+	childStatus.PostPromotion.AssessmentResult = apiv1.AssessmentResultFailure
+	childStatus.PostPromotion.FailureReason = failureReason
+	childStatus.PostPromotion.RolledBack = true
+	childStatus.AssessmentResult = apiv1.AssessmentResultFailure
+	childStatus.FailureReason = failureReason
+	rolloutObject.SetUpgradingChildStatus(childStatus)
+
+	message := fmt.Sprintf("Child Object %s/%s failed its post-promotion analysis and %s was promoted again: %s",
+		failedChildDef.GetNamespace(), failedChildDef.GetName(), previousChildDef.GetName(), failureReason)
+	rolloutObject.GetRolloutStatus().MarkProgressiveUpgradeFailed(message, rolloutObject.GetRolloutObjectMeta().Generation)
+	rolloutObject.GetRolloutStatus().MarkRolledBack(message, rolloutObject.GetRolloutObjectMeta().Generation)
+
+	numaLogger.WithValues("failed child", failedChildDef.GetName(), "revived child", previousChildDef.GetName(), "reason", failureReason).
+		Info("rolled back promoted child which failed post-promotion analysis")
+	return nil
+}
@@ -0,0 +1,312 @@
+package progressive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/numaproj/numaplane/internal/common"
+	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
+	"github.com/numaproj/numaplane/internal/util/kubernetes"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_isPostPromotionWindowOver(t *testing.T) {
+	past := metav1.NewTime(time.Now().Add(-time.Minute))
+	future := metav1.NewTime(time.Now().Add(time.Minute))
+
+	assert.True(t, isPostPromotionWindowOver(&apiv1.PostPromotionStatus{}))
+	assert.True(t, isPostPromotionWindowOver(&apiv1.PostPromotionStatus{WindowEndTime: &past}))
+	assert.False(t, isPostPromotionWindowOver(&apiv1.PostPromotionStatus{WindowEndTime: &future}))
+}
+
+func Test_withoutComparativeMetrics(t *testing.T) {
+	analysis := apiv1.Analysis{Metrics: []apiv1.AnalysisMetric{
+		{Name: "success-rate"},
+		{Name: "latency", Comparison: &apiv1.MetricComparison{Direction: apiv1.ComparisonDirectionLowerIsBetter}},
+	}}
+
+	filtered := withoutComparativeMetrics(analysis)
+	assert.Len(t, filtered.Metrics, 1)
+	assert.Equal(t, "success-rate", filtered.Metrics[0].Name)
+	// the original Analysis is unchanged
+	assert.Len(t, analysis.Metrics, 2)
+}
+
+func Test_GetPostPromotionAnalysis(t *testing.T) {
+	rollout := stepsMonoVertexRollout(nil, 0)
+	assert.Nil(t, rollout.GetPostPromotionAnalysis())
+
+	rollout.Spec.Strategy.Analysis = apiv1.Analysis{Metrics: []apiv1.AnalysisMetric{{Name: "success-rate"}}}
+	rollout.Spec.Strategy.PostPromotionAnalysis = &apiv1.PostPromotionAnalysis{Window: metav1.Duration{Duration: 30 * time.Minute}}
+
+	// the Rollout's Analysis is used if the PostPromotionAnalysis doesn't define its own
+	postPromotionAnalysis := rollout.GetPostPromotionAnalysis()
+	assert.NotNil(t, postPromotionAnalysis)
+	assert.Equal(t, "success-rate", postPromotionAnalysis.Analysis.Metrics[0].Name)
+	assert.Nil(t, rollout.Spec.Strategy.PostPromotionAnalysis.Analysis)
+
+	rollout.Spec.Strategy.PostPromotionAnalysis.Analysis = &apiv1.Analysis{Metrics: []apiv1.AnalysisMetric{{Name: "memory"}}}
+	assert.Equal(t, "memory", rollout.GetPostPromotionAnalysis().Analysis.Metrics[0].Name)
+}
+
+// fakeReviveController records the revival of the previous child
+type fakeReviveController struct {
+	progressiveController
+	revivedChild       string
+	revivedScaleValues map[string]apiv1.ScaleValues
+	reviveErr          error
+}
+
+func (f *fakeReviveController) RevivePreviousChild(ctx context.Context, rolloutObject ProgressiveRolloutObject, previousChildDef *unstructured.Unstructured, scaleValues map[string]apiv1.ScaleValues, c client.Client) error {
+	if f.reviveErr != nil {
+		return f.reviveErr
+	}
+	f.revivedChild = previousChildDef.GetName()
+	f.revivedScaleValues = scaleValues
+	return nil
+}
+
+var previousChildScaleValues = map[string]apiv1.ScaleValues{"test-0": {OriginalScaleMinMax: `{"min":1,"max":3}`, ScaleTo: 0, Initial: 2}}
+
+// postPromotionMonoVertexRollout returns a MonoVertexRollout whose child test-1 was promoted, with test-0 on standby until the windowEndTime
+func postPromotionMonoVertexRollout(windowEndTime time.Time) *apiv1.MonoVertexRollout {
+	rollout := stepsMonoVertexRollout(nil, 0)
+	rollout.Name = ctlrcommon.DefaultTestMonoVertexRolloutName
+	rollout.Namespace = ctlrcommon.DefaultTestNamespace
+	rollout.Generation = 2
+	rollout.Spec.Strategy.PostPromotionAnalysis = &apiv1.PostPromotionAnalysis{Window: metav1.Duration{Duration: 10 * time.Minute}}
+	end := metav1.NewTime(windowEndTime)
+	rollout.Status.ProgressiveStatus.UpgradingMonoVertexStatus.AssessmentResult = apiv1.AssessmentResultSuccess
+	rollout.Status.ProgressiveStatus.UpgradingMonoVertexStatus.PostPromotion = &apiv1.PostPromotionStatus{
+		PreviousChildName:        "test-0",
+		PreviousChildScaleValues: previousChildScaleValues,
+		WindowEndTime:            &end,
+		AssessmentResult:         apiv1.AssessmentResultUnknown,
+	}
+	return rollout
+}
+
+// postPromotionMonoVertex returns a child MonoVertex of the given upgrade state and phase
+func postPromotionMonoVertex(name string, upgradeState common.UpgradeState, phase numaflowv1.MonoVertexPhase) *numaflowv1.MonoVertex {
+	monoVertex := createMonoVertex(name)
+	monoVertex.Labels[common.LabelKeyUpgradeState] = string(upgradeState)
+	monoVertex.Status.Phase = phase
+	return monoVertex
+}
+
+// newPostPromotionClients sets the dynamic client used for live lookups and returns a client, both of which hold the children
+func newPostPromotionClients(t *testing.T, children ...*numaflowv1.MonoVertex) client.Client {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, numaflowv1.AddToScheme(scheme))
+	assert.NoError(t, apiv1.AddToScheme(scheme))
+	assert.NoError(t, appsv1.AddToScheme(scheme))
+
+	// the children are created through the dynamic client, since it would guess the wrong resource name for them
+	monoVertexGVR := schema.GroupVersionResource{Group: "numaflow.numaproj.io", Version: "v1alpha1", Resource: "monovertices"}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{monoVertexGVR: "MonoVertexList"})
+	clientObjects := []client.Object{}
+	for _, child := range children {
+		childJSON, err := json.Marshal(child)
+		assert.NoError(t, err)
+		childDef := &unstructured.Unstructured{}
+		assert.NoError(t, childDef.UnmarshalJSON(childJSON))
+		_, err = dynamicClient.Resource(monoVertexGVR).Namespace(child.Namespace).Create(context.Background(), childDef, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		clientObjects = append(clientObjects, child.DeepCopy())
+	}
+	kubernetes.DynamicClient = dynamicClient
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(clientObjects...).Build()
+}
+
+// upgradeStateOf returns the upgrade state and reason labels of the named child
+func upgradeStateOf(t *testing.T, c client.Client, name string) (string, string) {
+	monoVertex := &numaflowv1.MonoVertex{}
+	assert.NoError(t, c.Get(context.Background(), client.ObjectKey{Namespace: ctlrcommon.DefaultTestNamespace, Name: name}, monoVertex))
+	return monoVertex.Labels[common.LabelKeyUpgradeState], monoVertex.Labels[common.LabelKeyUpgradeStateReason]
+}
+
+func Test_processPostPromotion(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		windowEndTime          time.Time
+		promotedPhase          numaflowv1.MonoVertexPhase
+		promotedDifference     bool
+		previousChildExists    bool
+		expectedDone           bool
+		expectedRequeue        bool
+		expectedResult         apiv1.AssessmentResult
+		expectedPreviousState  common.UpgradeState
+		expectedPreviousReason common.UpgradeStateReason
+		expectedPromotedState  common.UpgradeState
+		expectedRevived        bool
+	}{
+		{
+			name:                  "healthy during the window",
+			windowEndTime:         time.Now().Add(5 * time.Minute),
+			promotedPhase:         numaflowv1.MonoVertexPhaseRunning,
+			previousChildExists:   true,
+			expectedDone:          false,
+			expectedRequeue:       true,
+			expectedResult:        apiv1.AssessmentResultUnknown,
+			expectedPreviousState: common.LabelValueUpgradeStandby,
+			expectedPromotedState: common.LabelValueUpgradePromoted,
+		},
+		{
+			name:                   "window expired",
+			windowEndTime:          time.Now().Add(-time.Second),
+			promotedPhase:          numaflowv1.MonoVertexPhaseRunning,
+			previousChildExists:    true,
+			expectedDone:           true,
+			expectedResult:         apiv1.AssessmentResultSuccess,
+			expectedPreviousState:  common.LabelValueUpgradeRecyclable,
+			expectedPreviousReason: common.LabelValueProgressiveSuccess,
+			expectedPromotedState:  common.LabelValueUpgradePromoted,
+		},
+		{
+			name:                   "rollout changed during the window",
+			windowEndTime:          time.Now().Add(5 * time.Minute),
+			promotedPhase:          numaflowv1.MonoVertexPhaseRunning,
+			promotedDifference:     true,
+			previousChildExists:    true,
+			expectedDone:           true,
+			expectedResult:         apiv1.AssessmentResultSuccess,
+			expectedPreviousState:  common.LabelValueUpgradeRecyclable,
+			expectedPreviousReason: common.LabelValueProgressiveSuccess,
+			expectedPromotedState:  common.LabelValueUpgradePromoted,
+		},
+		{
+			name:                  "previous child no longer exists",
+			windowEndTime:         time.Now().Add(5 * time.Minute),
+			promotedPhase:         numaflowv1.MonoVertexPhaseFailed,
+			previousChildExists:   false,
+			expectedDone:          true,
+			expectedResult:        apiv1.AssessmentResultSuccess,
+			expectedPromotedState: common.LabelValueUpgradePromoted,
+		},
+		{
+			name:                   "promoted child failed during the window: previous child revived",
+			windowEndTime:          time.Now().Add(5 * time.Minute),
+			promotedPhase:          numaflowv1.MonoVertexPhaseFailed,
+			previousChildExists:    true,
+			expectedDone:           true,
+			expectedResult:         apiv1.AssessmentResultFailure,
+			expectedPreviousState:  common.LabelValueUpgradePromoted,
+			expectedPreviousReason: common.LabelValuePostPromotionRolledBack,
+			expectedPromotedState:  common.LabelValueUpgradeRecyclable,
+			expectedRevived:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			promotedChild := postPromotionMonoVertex("test-1", common.LabelValueUpgradePromoted, tc.promotedPhase)
+			children := []*numaflowv1.MonoVertex{promotedChild}
+			if tc.previousChildExists {
+				children = append(children, postPromotionMonoVertex("test-0", common.LabelValueUpgradeStandby, numaflowv1.MonoVertexPhaseRunning))
+			}
+			c := newPostPromotionClients(t, children...)
+			rollout := postPromotionMonoVertexRollout(tc.windowEndTime)
+			controller := &fakeReviveController{}
+
+			done, requeueDelay, err := processPostPromotion(context.Background(), rollout, controller, monoVertexToUnstruct(promotedChild), tc.promotedDifference, c)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedDone, done)
+			assert.Equal(t, tc.expectedRequeue, requeueDelay > 0)
+
+			childStatus := rollout.GetUpgradingChildStatus()
+			assert.Equal(t, tc.expectedResult, childStatus.PostPromotion.AssessmentResult)
+
+			promotedState, _ := upgradeStateOf(t, c, "test-1")
+			assert.Equal(t, string(tc.expectedPromotedState), promotedState)
+			if tc.previousChildExists {
+				previousState, previousReason := upgradeStateOf(t, c, "test-0")
+				assert.Equal(t, string(tc.expectedPreviousState), previousState)
+				if tc.expectedPreviousReason != "" {
+					assert.Equal(t, string(tc.expectedPreviousReason), previousReason)
+				}
+			}
+
+			if tc.expectedRevived {
+				assert.Equal(t, "test-0", controller.revivedChild)
+				assert.Equal(t, previousChildScaleValues, controller.revivedScaleValues)
+				assert.True(t, childStatus.PostPromotion.RolledBack)
+				assert.Equal(t, apiv1.AssessmentResultFailure, childStatus.AssessmentResult)
+				assert.Contains(t, childStatus.FailureReason, "post-promotion analysis failed")
+				assert.True(t, rollout.Status.IsRolledBack())
+			} else {
+				assert.Empty(t, controller.revivedChild)
+				assert.False(t, childStatus.PostPromotion.RolledBack)
+				assert.False(t, rollout.Status.IsRolledBack())
+			}
+		})
+	}
+}
+
+func Test_revivePreviousChild(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		reviveErr             error
+		expectError           bool
+		expectedPreviousState common.UpgradeState
+		expectedFailedState   common.UpgradeState
+	}{
+		{
+			name:                  "previous child revived and promoted again",
+			expectedPreviousState: common.LabelValueUpgradePromoted,
+			expectedFailedState:   common.LabelValueUpgradeRecyclable,
+		},
+		{
+			name:                  "previous child can't be revived",
+			reviveErr:             errors.New("failed to scale"),
+			expectError:           true,
+			expectedPreviousState: common.LabelValueUpgradeStandby,
+			expectedFailedState:   common.LabelValueUpgradePromoted,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			failedChild := postPromotionMonoVertex("test-1", common.LabelValueUpgradePromoted, numaflowv1.MonoVertexPhaseFailed)
+			previousChild := postPromotionMonoVertex("test-0", common.LabelValueUpgradeStandby, numaflowv1.MonoVertexPhaseRunning)
+			c := newPostPromotionClients(t, failedChild, previousChild)
+			rollout := postPromotionMonoVertexRollout(time.Now().Add(5 * time.Minute))
+			controller := &fakeReviveController{reviveErr: tc.reviveErr}
+
+			err := revivePreviousChild(context.Background(), rollout, controller, monoVertexToUnstruct(failedChild), monoVertexToUnstruct(previousChild), "phase is Failed", c)
+			previousState, _ := upgradeStateOf(t, c, "test-0")
+			failedState, _ := upgradeStateOf(t, c, "test-1")
+			assert.Equal(t, string(tc.expectedPreviousState), previousState)
+			assert.Equal(t, string(tc.expectedFailedState), failedState)
+
+			childStatus := rollout.GetUpgradingChildStatus()
+			if tc.expectError {
+				assert.Error(t, err)
+				assert.Equal(t, apiv1.AssessmentResultUnknown, childStatus.PostPromotion.AssessmentResult)
+				assert.False(t, rollout.Status.IsRolledBack())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, apiv1.AssessmentResultFailure, childStatus.PostPromotion.AssessmentResult)
+			assert.Equal(t, "post-promotion analysis failed: phase is Failed", childStatus.PostPromotion.FailureReason)
+			assert.True(t, childStatus.PostPromotion.RolledBack)
+			assert.True(t, rollout.Status.IsRolledBack())
+			condition := rollout.Status.GetCondition(apiv1.ConditionProgressiveUpgradeSucceeded)
+			assert.NotNil(t, condition)
+			assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		})
+	}
+}
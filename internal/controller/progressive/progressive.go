@@ -21,10 +21,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
@@ -32,6 +34,7 @@ import (
 	"github.com/numaproj/numaplane/internal/common"
 	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
 	"github.com/numaproj/numaplane/internal/controller/common/numaflowtypes"
+	"github.com/numaproj/numaplane/internal/controller/common/revisions"
 	"github.com/numaproj/numaplane/internal/controller/common/riders"
 	"github.com/numaproj/numaplane/internal/controller/config"
 	"github.com/numaproj/numaplane/internal/usde"
@@ -85,10 +88,25 @@ type progressiveController interface {
 	// ProcessUpgradingChildPostSuccess performs operations on the upgrading child after the upgrade succeeds (just the operations which are unique to this Kind)
 	ProcessUpgradingChildPostSuccess(ctx context.Context, rolloutObject ProgressiveRolloutObject, upgradingChildDef *unstructured.Unstructured, c client.Client) error
 
+	// ProcessChildrenPostStepAdvance performs operations on the promoted and upgrading children after the upgrade advances to the next Progressive Step
+	// (just the operations which are unique to this Kind)
+	// return true if requeue is needed (note this is ignored if error != nil)
+	ProcessChildrenPostStepAdvance(ctx context.Context, rolloutObject ProgressiveRolloutObject, promotedChildDef, upgradingChildDef *unstructured.Unstructured, c client.Client) (bool, error)
+
+	// ProcessPreviousChildPostPromotion performs operations on the previously promoted child when it's put on standby for the post-promotion analysis
+	// of the newly promoted child: it's scaled to zero, and its original scale values are returned so that it can be revived later
+	ProcessPreviousChildPostPromotion(ctx context.Context, rolloutObject ProgressiveRolloutObject, previousChildDef *unstructured.Unstructured, c client.Client) (map[string]apiv1.ScaleValues, error)
+
+	// RevivePreviousChild scales the previously promoted child back to its original scale values after the newly promoted child failed its post-promotion analysis
+	RevivePreviousChild(ctx context.Context, rolloutObject ProgressiveRolloutObject, previousChildDef *unstructured.Unstructured, scaleValues map[string]apiv1.ScaleValues, c client.Client) error
+
 	// ProgressiveUnsupported checks to see if Full Progressive Rollout (with assessment) is unsupported for this Rollout
 	ProgressiveUnsupported(ctx context.Context, rolloutObject ProgressiveRolloutObject) bool
 
 	UpdateProgressiveMetrics(rolloutObject ProgressiveRolloutObject, completed bool)
+
+	// GetRecorder returns the EventRecorder used to emit Events for the Rollout
+	GetRecorder() record.EventRecorder
 }
 
 // ProgressiveRolloutObject describes a Rollout instance that supports progressive upgrade
@@ -97,6 +115,12 @@ type ProgressiveRolloutObject interface {
 
 	GetProgressiveStrategy() apiv1.ProgressiveStrategy
 
+	// GetUpgradeStrategy returns the upgrade strategy selected by the Rollout, or "" if it doesn't select one
+	GetUpgradeStrategy() apiv1.UserUpgradeStrategy
+
+	// GetProgressiveSteps returns the Progressive Steps for the Rollout, or nil if none are defined or they're unsupported for this Kind
+	GetProgressiveSteps() []apiv1.ProgressiveStep
+
 	GetUpgradingChildStatus() *apiv1.UpgradingChildStatus
 
 	GetPromotedChildStatus() *apiv1.PromotedChildStatus
@@ -112,6 +136,11 @@ type ProgressiveRolloutObject interface {
 	ResetPromotedChildStatus(promotedChild *unstructured.Unstructured) error
 
 	GetChildMetadata() apiv1.Metadata
+
+	GetRevisionHistoryLimit() *int32
+
+	// GetPostPromotionAnalysis returns the PostPromotionAnalysis for the Rollout, or nil if it's not defined or unsupported for this Kind
+	GetPostPromotionAnalysis() *apiv1.PostPromotionAnalysis
 }
 
 // return:
@@ -127,6 +156,10 @@ func ProcessResource(
 	c client.Client,
 ) (bool, time.Duration, error) {
 
+	if err := ValidateProgressiveSteps(rolloutObject.GetProgressiveSteps()); err != nil {
+		return false, 0, err
+	}
+
 	// Make sure that our Promoted Child Status reflects the current promoted child
 	promotedChildStatus := rolloutObject.GetPromotedChildStatus()
 	if promotedChildStatus == nil || promotedChildStatus.Name != existingPromotedChild.GetName() {
@@ -142,8 +175,38 @@ func ProcessResource(
 		return false, 0, err
 	}
 
+	// perform any action requested by the user on the upgrade
+	stop, done, err := processUpgradeAction(ctx, rolloutObject, controller, existingPromotedChild, currentUpgradingChildDef, c)
+	if err != nil {
+		return false, 0, err
+	}
+	if done {
+		return true, 0, nil
+	}
+	if stop {
+		return false, common.DefaultRequeueDelay, nil
+	}
+
+	// if the "promoted" child is still within its post-promotion analysis window, continue to analyze it
+	if currentUpgradingChildDef == nil && rolloutObject.GetUpgradingChildStatus().IsPostPromotionInProgress() {
+		done, requeueDelay, err := processPostPromotion(ctx, rolloutObject, controller, existingPromotedChild, promotedDifference, c)
+		if err != nil || !done {
+			return false, requeueDelay, err
+		}
+		// if the previous child was revived, then the "promoted" child has changed
+		if rolloutObject.GetUpgradingChildStatus().PostPromotion.RolledBack {
+			return true, 0, nil
+		}
+	}
+
 	// if there's a difference between the desired spec and the current "promoted" child, and there isn't yet an "upgrading" definition, then create one and return
 	if promotedDifference && currentUpgradingChildDef == nil {
+		// if we already rolled back this generation of the Rollout, don't try it again: wait for the spec to change
+		if isGenerationRolledBack(rolloutObject) {
+			logger.FromContext(ctx).Debug("current generation of rollout was rolled back, not starting a new upgrade")
+			return true, 0, nil
+		}
+
 		// Create it
 		_, needRequeue, err := startUpgradeProcess(ctx, rolloutObject, existingPromotedChild, controller, c)
 		if needRequeue {
@@ -274,22 +337,56 @@ func getChildStatusAssessmentSchedule(
 		return config.AssessmentSchedule{}, fmt.Errorf("error getting default child status assessment schedule for type '%s': %w", rolloutObject.GetChildGVK().Kind, err)
 	}
 	// see if it's specified for this Rollout, and if so use that
-	rolloutSpecificSchedule := rolloutObject.GetProgressiveStrategy().AssessmentSchedule
-	if rolloutSpecificSchedule != "" {
-		rolloutSchedule, err := config.ParseAssessmentSchedule(rolloutSpecificSchedule)
+	// (an invalid schedule is replaced rather than failing the upgrade, which is surfaced with the AssessmentScheduleValid condition)
+	var invalidScheduleMessages []string
+	strategy := rolloutObject.GetProgressiveStrategy()
+	rolloutSchedule, found, err := ResolveAssessmentSchedule(strategy.Schedule, strategy.AssessmentSchedule)
+	if err != nil {
+		numaLogger.WithValues("schedule", strategy.Schedule, "scheduleString", strategy.AssessmentSchedule, "error", err.Error()).Warn("failed to parse schedule")
+		invalidScheduleMessages = append(invalidScheduleMessages,
+			fmt.Sprintf("the schedule of the Rollout is invalid, so the default schedule for %s is used: %v", rolloutObject.GetChildGVK().Kind, err))
+	} else if found {
+		schedule = rolloutSchedule
+		numaLogger.Debugf("using assessment schedule specified by rollout: %+v", schedule)
+	} else {
+		numaLogger.Debugf("using default assessment schedule for kind %q", rolloutObject.GetChildGVK().Kind)
+	}
+	// a schedule specified for the current Step takes precedence
+	if step := GetCurrentStep(rolloutObject); step != nil {
+		stepSchedule, found, err := ResolveAssessmentSchedule(step.Schedule, step.AssessmentSchedule)
 		if err != nil {
-			numaLogger.WithValues("schedule", rolloutSpecificSchedule, "error", err.Error()).Warn("failed to parse schedule")
-		} else {
-			schedule = rolloutSchedule
-			numaLogger.Debugf("using assessment schedule specified by rollout: %q", rolloutSpecificSchedule)
+			numaLogger.WithValues("schedule", step.Schedule, "scheduleString", step.AssessmentSchedule, "error", err.Error()).Warn("failed to parse step schedule")
+			invalidScheduleMessages = append(invalidScheduleMessages,
+				fmt.Sprintf("the schedule of step %d is invalid, so the schedule of the Rollout is used: %v", GetCurrentStepIndex(rolloutObject), err))
+		} else if found {
+			schedule = stepSchedule
+			numaLogger.Debugf("using assessment schedule specified by step %d: %+v", GetCurrentStepIndex(rolloutObject), schedule)
 		}
+	}
+
+	if len(invalidScheduleMessages) > 0 {
+		rolloutObject.GetRolloutStatus().MarkAssessmentScheduleInvalid(strings.Join(invalidScheduleMessages, "; "), rolloutObject.GetRolloutObjectMeta().Generation)
 	} else {
-		numaLogger.Debugf("using default assessment schedule for kind %q", rolloutObject.GetChildGVK().Kind)
+		rolloutObject.GetRolloutStatus().ClearAssessmentScheduleInvalid()
 	}
 
 	return schedule, nil
 }
 
+// ResolveAssessmentSchedule returns the schedule defined by either the structured AssessmentSchedule or otherwise
+// the deprecated comma-separated string, if either is set
+func ResolveAssessmentSchedule(scheduleSpec *apiv1.AssessmentSchedule, scheduleString string) (config.AssessmentSchedule, bool, error) {
+	if scheduleSpec != nil {
+		schedule, err := config.AssessmentScheduleFromSpec(*scheduleSpec)
+		return schedule, err == nil, err
+	}
+	if scheduleString != "" {
+		schedule, err := config.ParseAssessmentSchedule(scheduleString)
+		return schedule, err == nil, err
+	}
+	return config.AssessmentSchedule{}, false, nil
+}
+
 /*
 processUpgradingChild handles the assessment and potential update of a child resource during a progressive upgrade.
 It evaluates the current status of the upgrading child, determines if an assessment is needed, and processes the
@@ -384,6 +481,11 @@ func processUpgradingChild(
 			Debug("skipping upgrading child assessment but assessing previous child status")
 	}
 
+	// once the final step has been assessed successfully, the upgrading child may still need to be approved before it's promoted
+	if assessment == apiv1.AssessmentResultSuccess && !hasNextStep(rolloutObject) {
+		assessment, failureReason = checkManualApproval(ctx, rolloutObject, existingUpgradingChildDef.GetName())
+	}
+
 	switch assessment {
 	case apiv1.AssessmentResultFailure:
 		rolloutObject.GetRolloutStatus().MarkProgressiveUpgradeFailed(fmt.Sprintf("New Child Object %s/%s Failed", existingUpgradingChildDef.GetNamespace(), existingUpgradingChildDef.GetName()), rolloutObject.GetRolloutObjectMeta().Generation)
@@ -394,6 +496,10 @@ func processUpgradingChild(
 			status.ChildStatus.Raw = childSts
 		})
 
+		if err := revisions.UpdateRevisionOutcome(ctx, c, rolloutObject, existingUpgradingChildDef.GetName(), revisions.OutcomeFailed); err != nil {
+			numaLogger.Error(err, "failed to record revision outcome for failed child")
+		}
+
 		requeue, err := controller.ProcessPromotedChildPostFailure(ctx, rolloutObject, existingPromotedChildDef, c)
 		if err != nil {
 			return false, 0, err
@@ -409,9 +515,28 @@ func processUpgradingChild(
 			return false, common.DefaultRequeueDelay, nil
 		}
 
+		if rolloutObject.GetProgressiveStrategy().AutoRollback {
+			if err := rollBack(ctx, rolloutObject, existingUpgradingChildDef, failureReason, c); err != nil {
+				return false, 0, err
+			}
+			return true, 0, nil
+		}
+
 		return false, 0, nil
 
 	case apiv1.AssessmentResultSuccess:
+		// if there are more Steps to go through, move on to the next one rather than promoting the upgrading child
+		if hasNextStep(rolloutObject) {
+			requeue, err := advanceToNextStep(ctx, rolloutObject, controller, existingPromotedChildDef, existingUpgradingChildDef, c)
+			if err != nil {
+				return false, 0, err
+			}
+			if requeue {
+				return false, common.DefaultRequeueDelay, nil
+			}
+			return false, assessmentSchedule.Interval, nil
+		}
+
 		done, err := declareSuccess(ctx, rolloutObject, controller, existingPromotedChildDef, existingUpgradingChildDef, childStatus, c)
 		if err != nil || done {
 			return done, 0, err
@@ -447,6 +572,10 @@ func IsUpgradeReplacementRequired(
 	if err != nil {
 		return false, false, err
 	}
+	// the existing Upgrading child has the UpgradingOverrides applied, so we compare it to a definition which has them too
+	if err = applyUpgradingOverrides(rolloutObject, newUpgradingChildDef); err != nil {
+		return false, false, err
+	}
 	// we need to create an Upgrading child definition which is template-evaluated using the existing Promoted child's name so that we can effectively compare them below
 	existingPromotedChildName := existingPromotedChildDef.GetName()
 	newUpgradingChildDefUsingPromotedName, err := makeUpgradingObjectDefinition(ctx, rolloutObject, controller, c, &existingPromotedChildName)
@@ -510,6 +639,11 @@ func checkForUpgradeReplacement(
 			if err != nil {
 				return false, false, err
 			}
+			if childStatus.AssessmentResult != apiv1.AssessmentResultFailure {
+				if err := revisions.UpdateRevisionOutcome(ctx, c, rolloutObject, existingUpgradingChildDef.GetName(), revisions.OutcomeDiscontinued); err != nil {
+					numaLogger.Error(err, "failed to record revision outcome for replaced child")
+				}
+			}
 
 			// Create a new upgrading child to replace it
 
@@ -568,7 +702,11 @@ func checkForDifferences(
 	needsUpdating := false
 
 	// evaluate the Rollout child's templated metadata using the existing child name so we can effectively check whether the desired metadata is present
-	templatedMetadata, err := util.ResolveTemplatedSpec(rolloutObject.GetChildMetadata(), controller.GetTemplateArguments(existingChildDef))
+	args, err := controller.GetTemplateArguments(ctx, rolloutObject, existingChildDef)
+	if err != nil {
+		return false, err
+	}
+	templatedMetadata, err := util.ResolveTemplatedSpec(rolloutObject.GetChildMetadata(), args)
 	if err != nil {
 		return false, err
 	}
@@ -595,7 +733,7 @@ func checkForDifferences(
 func CheckRidersForDifferences(
 	ctx context.Context,
 	controller progressiveController,
-	rolloutObject ctlrcommon.RolloutObject,
+	rolloutObject ProgressiveRolloutObject,
 	// existing child whose Riders we'll check
 	existingChildDef *unstructured.Unstructured,
 	// is the existing child "Upgrading" (vs "Promoted")?
@@ -605,7 +743,7 @@ func CheckRidersForDifferences(
 
 	// Get the Riders which are desired based on the Rollout definition
 	// if newUpgradingChildDef still has unevaluated templates, then the existing child's name is used to evaluate them, so we can compare effectively
-	newRiders, err := controller.GetDesiredRiders(rolloutObject, existingChildDef.GetName(), newUpgradingChildDef)
+	newRiders, err := controller.GetDesiredRiders(ctx, rolloutObject, existingChildDef.GetName(), newUpgradingChildDef)
 	if err != nil {
 		return false, err
 	}
@@ -618,7 +756,7 @@ func CheckRidersForDifferences(
 
 	// Now compare the desired Riders with the existing Riders to see if any need updating
 	needUpdating, _, _, _, _, err := usde.RidersNeedUpdating(ctx, existingChildDef.GetNamespace(), existingChildDef.GetKind(), existingChildDef.GetName(),
-		newRiders, existingRiders)
+		newRiders, existingRiders, rolloutObject.GetUpgradeStrategy())
 	if err != nil {
 		return false, err
 	}
@@ -739,6 +877,12 @@ func declareSuccess(
 		return false, err
 	}
 
+	// the UpgradingOverrides don't apply to the promoted child
+	err = removeUpgradingOverrides(ctx, rolloutObject, controller, existingUpgradingChildDef, c)
+	if err != nil {
+		return false, err
+	}
+
 	// Label the new child as promoted and then remove the label from the old one
 	numaLogger.WithValues("old child", existingPromotedChildDef.GetName(), "new child", existingUpgradingChildDef.GetName()).Debug("replacing 'promoted' child")
 	reasonSuccess := common.LabelValueProgressiveSuccess
@@ -747,18 +891,28 @@ func declareSuccess(
 		return false, err
 	}
 
-	err = ctlrcommon.UpdateUpgradeState(ctx, c, common.LabelValueUpgradeRecyclable, &reasonSuccess, existingPromotedChildDef)
+	// if post-promotion analysis is defined, the old child is kept on standby until it's over; otherwise, it can be recycled now
+	postPromotionAnalysis := rolloutObject.GetPostPromotionAnalysis()
+	if postPromotionAnalysis != nil && !childStatus.ForcedSuccess {
+		err = startPostPromotion(ctx, rolloutObject, controller, postPromotionAnalysis, existingPromotedChildDef, childStatus, c)
+	} else {
+		err = ctlrcommon.UpdateUpgradeState(ctx, c, common.LabelValueUpgradeRecyclable, &reasonSuccess, existingPromotedChildDef)
+	}
 	if err != nil {
 		return false, err
 	}
 
+	if err := revisions.RecordRevision(ctx, c, rolloutObject, existingUpgradingChildDef.GetName(), revisions.OutcomePromoted); err != nil {
+		numaLogger.Error(err, "failed to record revision for promoted child")
+	}
+
 	rolloutObject.GetRolloutStatus().MarkProgressiveUpgradeSucceeded(fmt.Sprintf("New Child Object %s/%s Running", existingUpgradingChildDef.GetNamespace(), existingUpgradingChildDef.GetName()), rolloutObject.GetRolloutObjectMeta().Generation)
 	childStatus.AssessmentResult = apiv1.AssessmentResultSuccess
 	rolloutObject.SetUpgradingChildStatus(childStatus)
 	rolloutObject.GetRolloutStatus().MarkDeployed(rolloutObject.GetRolloutObjectMeta().Generation)
 
-	// we're done
-	return true, nil
+	// we're done, unless the post-promotion analysis has started
+	return !childStatus.IsPostPromotionInProgress(), nil
 }
 
 // startUpgradeProcess() is the process required to create a new Upgrading child as well as
@@ -793,7 +947,12 @@ func startUpgradeProcess(
 	if err != nil {
 		return newUpgradingChildDef, false, err
 	}
+	// the UpgradingOverrides only apply while the child is "in-progress": they're removed once it's promoted
+	if err = applyUpgradingOverrides(rolloutObject, newUpgradingChildDef); err != nil {
+		return newUpgradingChildDef, false, err
+	}
 	// reset the Status to reflect our new Upgrading child
+	rolloutObject.GetRolloutStatus().ClearRolledBack(rolloutObject.GetRolloutObjectMeta().Generation)
 	err = rolloutObject.ResetUpgradingChildStatus(newUpgradingChildDef)
 	if err != nil {
 		return newUpgradingChildDef, false, fmt.Errorf("processing upgrading child, failed to reset the upgrading child status for child %s/%s", newUpgradingChildDef.GetNamespace(), newUpgradingChildDef.GetName())
@@ -815,6 +974,10 @@ func startUpgradeProcess(
 		return newUpgradingChildDef, false, err
 	}
 
+	if err := revisions.RecordRevision(ctx, c, rolloutObject, newUpgradingChildDef.GetName(), revisions.OutcomeInProgress); err != nil {
+		numaLogger.Error(err, "failed to record revision for upgrading child")
+	}
+
 	// Update progressive metrics after creating the upgrading child
 	controller.UpdateProgressiveMetrics(rolloutObject, false)
 
@@ -836,7 +999,7 @@ func startPostUpgradeProcess(
 	numaLogger.Debug("starting post upgrade process")
 
 	// Create Riders for the new Upgrading child
-	newRiders, err := controller.GetDesiredRiders(rolloutObject, newUpgradingChild.GetName(), newUpgradingChild)
+	newRiders, err := controller.GetDesiredRiders(ctx, rolloutObject, newUpgradingChild.GetName(), newUpgradingChild)
 	if err != nil {
 		return false, err
 	}
@@ -980,6 +1143,9 @@ func Discontinue(ctx context.Context,
 		if err != nil {
 			return fmt.Errorf("failed to Discontinue progressive upgrade: error marking child %s recyclable: %v", child.GetName(), err)
 		}
+		if err := revisions.UpdateRevisionOutcome(ctx, c, rolloutObject, child.GetName(), revisions.OutcomeDiscontinued); err != nil {
+			logger.FromContext(ctx).Error(err, "failed to record revision outcome for discontinued child")
+		}
 	}
 
 	// We need to set our Progressive Upgrade Condition back to its default state of true since we're no longer
@@ -990,6 +1156,41 @@ func Discontinue(ctx context.Context,
 	return nil
 }
 
+// rollBack discards a failed Upgrading child, leaving the Promoted child in place, and marks the Rollout as RolledBack
+// so that the same generation of the Rollout isn't attempted again: while it's RolledBack, the Rollout controllers deploy the
+// child definition of the last promoted revision instead of the one in the spec (see revisions.ApplyAutoRollback)
+func rollBack(ctx context.Context,
+	rolloutObject ProgressiveRolloutObject,
+	existingUpgradingChildDef *unstructured.Unstructured,
+	failureReason string,
+	c client.Client,
+) error {
+	numaLogger := logger.FromContext(ctx)
+
+	reason := common.LabelValueProgressiveRolledBack
+	err := ctlrcommon.UpdateUpgradeState(ctx, c, common.LabelValueUpgradeRecyclable, &reason, existingUpgradingChildDef)
+	if err != nil {
+		return fmt.Errorf("failed to roll back progressive upgrade: error marking child %s recyclable: %v", existingUpgradingChildDef.GetName(), err)
+	}
+
+	message := fmt.Sprintf("New Child Object %s/%s Failed and was rolled back", existingUpgradingChildDef.GetNamespace(), existingUpgradingChildDef.GetName())
+	if failureReason != "" {
+		message = fmt.Sprintf("%s: %s", message, failureReason)
+	}
+	rolloutObject.GetRolloutStatus().MarkRolledBack(message, rolloutObject.GetRolloutObjectMeta().Generation)
+
+	numaLogger.WithValues("upgrading child", existingUpgradingChildDef.GetName(), "reason", failureReason).Info("rolled back failed progressive upgrade")
+
+	return nil
+}
+
+// isGenerationRolledBack determines if the current generation of the Rollout was rolled back
+func isGenerationRolledBack(rolloutObject ProgressiveRolloutObject) bool {
+	condition := rolloutObject.GetRolloutStatus().GetCondition(apiv1.ConditionRolledBack)
+	return condition != nil && condition.Status == metav1.ConditionTrue &&
+		condition.ObservedGeneration == rolloutObject.GetRolloutObjectMeta().Generation
+}
+
 func UpdateUpgradingChildStatus(rollout ProgressiveRolloutObject, f func(*apiv1.UpgradingChildStatus)) *apiv1.UpgradingChildStatus {
 	upgradingChildStatus := rollout.GetUpgradingChildStatus()
 	f(upgradingChildStatus)
@@ -14,6 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -44,7 +45,7 @@ func (fpc fakeProgressiveController) Recycle(ctx context.Context, childObject *u
 	return false, nil
 }
 
-func (fpc fakeProgressiveController) GetDesiredRiders(rolloutObject ctlrcommon.RolloutObject, name string, childDef *unstructured.Unstructured) ([]riders.Rider, error) {
+func (fpc fakeProgressiveController) GetDesiredRiders(ctx context.Context, rolloutObject ctlrcommon.RolloutObject, name string, childDef *unstructured.Unstructured) ([]riders.Rider, error) {
 	desiredRiders := []riders.Rider{}
 	return desiredRiders, nil
 }
@@ -91,6 +92,10 @@ func (fpc fakeProgressiveController) ProcessUpgradingChildPostSuccess(ctx contex
 	return nil
 }
 
+func (fpc fakeProgressiveController) ProcessChildrenPostStepAdvance(ctx context.Context, rolloutObject ProgressiveRolloutObject, promotedChildDef, upgradingChildDef *unstructured.Unstructured, c client.Client) (bool, error) {
+	return false, nil
+}
+
 func (fpc fakeProgressiveController) ProcessUpgradingChildPreUpgrade(ctx context.Context, rolloutObject ProgressiveRolloutObject, upgradingChildDef *unstructured.Unstructured, c client.Client) (bool, error) {
 	return false, nil
 }
@@ -102,12 +107,24 @@ func (fpc fakeProgressiveController) SetCurrentRiderList(ctx context.Context, ro
 
 }
 
+func (fpc fakeProgressiveController) ProcessPreviousChildPostPromotion(ctx context.Context, rolloutObject ProgressiveRolloutObject, previousChildDef *unstructured.Unstructured, c client.Client) (map[string]apiv1.ScaleValues, error) {
+	return nil, nil
+}
+
+func (fpc fakeProgressiveController) RevivePreviousChild(ctx context.Context, rolloutObject ProgressiveRolloutObject, previousChildDef *unstructured.Unstructured, scaleValues map[string]apiv1.ScaleValues, c client.Client) error {
+	return nil
+}
+
+func (fpc fakeProgressiveController) GetRecorder() record.EventRecorder {
+	return record.NewFakeRecorder(10)
+}
+
 func (fpc fakeProgressiveController) ProgressiveUnsupported(ctx context.Context, rolloutObject ProgressiveRolloutObject) bool {
 	return false
 }
 
-func (fpc fakeProgressiveController) GetTemplateArguments(pipeline *unstructured.Unstructured) map[string]interface{} {
-	return map[string]interface{}{}
+func (fpc fakeProgressiveController) GetTemplateArguments(ctx context.Context, rolloutObject ctlrcommon.RolloutObject, pipeline *unstructured.Unstructured) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
 }
 
 func Test_processUpgradingChild(t *testing.T) {
@@ -184,6 +201,34 @@ func Test_processUpgradingChild(t *testing.T) {
 			expectedRequeueDelay:      0,
 			expectedError:             nil,
 		},
+		{
+			name: "failure with auto rollback",
+			rolloutObject: setMonoVertexProgressiveStatus(
+				autoRollbackMonoVertexRollout.DeepCopy(),
+				&apiv1.UpgradingMonoVertexStatus{
+					UpgradingPipelineTypeStatus: apiv1.UpgradingPipelineTypeStatus{
+						UpgradingChildStatus: apiv1.UpgradingChildStatus{
+							Name:                     "test-rollback",
+							AssessmentResult:         apiv1.AssessmentResultFailure,
+							BasicAssessmentStartTime: &metav1.Time{Time: time.Now().Add(-1 * time.Minute)},
+							InitializationComplete:   true,
+						},
+					},
+				},
+				&apiv1.PromotedMonoVertexStatus{
+					PromotedPipelineTypeStatus: apiv1.PromotedPipelineTypeStatus{
+						PromotedChildStatus: apiv1.PromotedChildStatus{
+							Name: defaultExistingPromotedChildDef.GetName(),
+						},
+						ScaleValuesRestoredToOriginal: true,
+					},
+				},
+			),
+			existingUpgradingChildDef: createMonoVertex("test-rollback"),
+			expectedDone:              true,
+			expectedRequeueDelay:      0,
+			expectedError:             nil,
+		},
 		{
 			name: "force promote a failed progressive upgrade",
 			rolloutObject: setMonoVertexProgressiveStatus(
@@ -400,6 +445,22 @@ var forcePromoteMonoVertexRollout = &apiv1.MonoVertexRollout{
 	},
 }
 
+var autoRollbackMonoVertexRollout = &apiv1.MonoVertexRollout{
+	ObjectMeta: metav1.ObjectMeta{
+		Name:       "test",
+		Generation: 2,
+	},
+	Spec: apiv1.MonoVertexRolloutSpec{
+		Strategy: &apiv1.PipelineTypeRolloutStrategy{
+			PipelineTypeProgressiveStrategy: apiv1.PipelineTypeProgressiveStrategy{
+				Progressive: apiv1.ProgressiveStrategy{
+					AutoRollback: true,
+				},
+			},
+		},
+	},
+}
+
 var analysisTmplMonoVertexRollout = &apiv1.MonoVertexRollout{
 	ObjectMeta: metav1.ObjectMeta{
 		Name: "test",
@@ -423,6 +484,23 @@ var analysisTmplMonoVertexRollout = &apiv1.MonoVertexRollout{
 	},
 }
 
+func Test_isGenerationRolledBack(t *testing.T) {
+	rollout := autoRollbackMonoVertexRollout.DeepCopy()
+	assert.False(t, isGenerationRolledBack(rollout))
+
+	rollout.Status.MarkRolledBack("failed", rollout.Generation)
+	assert.True(t, isGenerationRolledBack(rollout))
+	assert.True(t, rollout.Status.IsRolledBack())
+
+	// a new generation of the Rollout may be attempted
+	rollout.Generation++
+	assert.False(t, isGenerationRolledBack(rollout))
+
+	rollout.Status.ClearRolledBack(rollout.Generation)
+	assert.False(t, rollout.Status.IsRolledBack())
+	assert.NotNil(t, rollout.Status.GetCondition(apiv1.ConditionRolledBack))
+}
+
 func Test_getAnalysisRunTimeout(t *testing.T) {
 
 	testCases := []struct {
@@ -476,11 +554,50 @@ func Test_getChildStatusAssessmentSchedule(t *testing.T) {
 	assert.NoError(t, err)
 
 	testCases := []struct {
-		name             string
-		rolloutSchedule  string
-		expectedSchedule config.AssessmentSchedule
-		expectedError    bool
+		name                string
+		rolloutSchedule     string
+		rolloutScheduleSpec *apiv1.AssessmentSchedule
+		expectedSchedule    config.AssessmentSchedule
+		expectedInvalid     bool
+		expectedError       bool
 	}{
+		{
+			name:            "rollout defines structured schedule, which takes precedence over the string",
+			rolloutSchedule: "300,600,200,10",
+			rolloutScheduleSpec: &apiv1.AssessmentSchedule{
+				Delay:                    metav1.Duration{Duration: 2 * time.Minute},
+				End:                      metav1.Duration{Duration: 10 * time.Minute},
+				Period:                   metav1.Duration{Duration: time.Minute},
+				Interval:                 metav1.Duration{Duration: 15 * time.Second},
+				ConsecutiveHealthyChecks: 3,
+				MaxUnhealthyChecks:       1,
+			},
+			expectedSchedule: config.AssessmentSchedule{
+				Delay:                    2 * time.Minute,
+				End:                      10 * time.Minute,
+				Period:                   time.Minute,
+				Interval:                 15 * time.Second,
+				ConsecutiveHealthyChecks: 3,
+				MaxUnhealthyChecks:       1,
+			},
+			expectedError: false,
+		},
+		{
+			name: "rollout defines invalid structured schedule, so default is used for the Kind",
+			rolloutScheduleSpec: &apiv1.AssessmentSchedule{
+				End:      metav1.Duration{Duration: time.Minute},
+				Period:   metav1.Duration{Duration: 10 * time.Minute},
+				Interval: metav1.Duration{Duration: 15 * time.Second},
+			},
+			expectedSchedule: config.AssessmentSchedule{
+				Delay:    120 * time.Second,
+				End:      360 * time.Second,
+				Period:   0 * time.Second,
+				Interval: 10 * time.Second,
+			},
+			expectedInvalid: true,
+			expectedError:   false,
+		},
 		{
 			name:            "rollout defines schedule",
 			rolloutSchedule: "300,600,200,10",
@@ -512,7 +629,8 @@ func Test_getChildStatusAssessmentSchedule(t *testing.T) {
 				Period:   0 * time.Second,
 				Interval: 10 * time.Second,
 			},
-			expectedError: false,
+			expectedInvalid: true,
+			expectedError:   false,
 		},
 	}
 
@@ -524,6 +642,12 @@ func Test_getChildStatusAssessmentSchedule(t *testing.T) {
 					Name:      "my-pipeline",
 					Namespace: "my-namespace",
 				},
+				Status: apiv1.PipelineRolloutStatus{
+					Status: apiv1.Status{
+						// a previously invalid schedule
+						Conditions: []metav1.Condition{{Type: string(apiv1.ConditionAssessmentScheduleValid), Status: metav1.ConditionFalse}},
+					},
+				},
 				Spec: apiv1.PipelineRolloutSpec{
 					Pipeline: apiv1.Pipeline{
 						// not needed for test
@@ -533,6 +657,7 @@ func Test_getChildStatusAssessmentSchedule(t *testing.T) {
 							PipelineTypeProgressiveStrategy: apiv1.PipelineTypeProgressiveStrategy{
 								Progressive: apiv1.ProgressiveStrategy{
 									AssessmentSchedule: tc.rolloutSchedule,
+									Schedule:           tc.rolloutScheduleSpec,
 								},
 							},
 						},
@@ -546,6 +671,15 @@ func Test_getChildStatusAssessmentSchedule(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tc.expectedSchedule, resultSchedule)
+				// the replacement of an invalid schedule is surfaced in the status, and otherwise cleared
+				condition := pipelineRollout.Status.GetCondition(apiv1.ConditionAssessmentScheduleValid)
+				if tc.expectedInvalid {
+					assert.NotNil(t, condition)
+					assert.Equal(t, metav1.ConditionFalse, condition.Status)
+					assert.Contains(t, condition.Message, "default schedule")
+				} else {
+					assert.Nil(t, condition)
+				}
 			}
 		})
 	}
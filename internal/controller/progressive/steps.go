@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progressive
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// DefaultUpgradingWeight is the percentage of Pods shifted to the "upgrading" child if no Steps are defined
+const DefaultUpgradingWeight int32 = 50
+
+// CalculateScaleMinMaxValuesForWeight returns the number of Pods which should remain on the "promoted" child
+// when the given percentage (weight) of its Pods are shifted to the "upgrading" child
+func CalculateScaleMinMaxValuesForWeight(podsCount int, weight int32) int64 {
+	upgradingPods := int64(math.Ceil(float64(podsCount) * float64(weight) / float64(100)))
+	return int64(podsCount) - upgradingPods
+}
+
+// ValidateProgressiveSteps verifies that each Step has a weight between 1 and 100 and that weights are strictly increasing
+func ValidateProgressiveSteps(steps []apiv1.ProgressiveStep) error {
+	previousWeight := int32(0)
+	for i, step := range steps {
+		if step.Weight < 1 || step.Weight > 100 {
+			return fmt.Errorf("invalid progressive step %d: weight %d must be between 1 and 100", i, step.Weight)
+		}
+		if step.Weight <= previousWeight {
+			return fmt.Errorf("invalid progressive step %d: weight %d must be greater than weight %d of the previous step", i, step.Weight, previousWeight)
+		}
+		previousWeight = step.Weight
+	}
+	return nil
+}
+
+// GetCurrentStepIndex returns the index of the Step that the "upgrading" child is currently being assessed for
+func GetCurrentStepIndex(rolloutObject ProgressiveRolloutObject) int {
+	childStatus := rolloutObject.GetUpgradingChildStatus()
+	if childStatus == nil {
+		return 0
+	}
+	return int(childStatus.CurrentStep)
+}
+
+// GetCurrentStep returns the Step that the "upgrading" child is currently being assessed for, or nil if there are no Steps
+func GetCurrentStep(rolloutObject ProgressiveRolloutObject) *apiv1.ProgressiveStep {
+	steps := rolloutObject.GetProgressiveSteps()
+	if len(steps) == 0 {
+		return nil
+	}
+	index := GetCurrentStepIndex(rolloutObject)
+	if index < 0 {
+		index = 0
+	} else if index >= len(steps) {
+		index = len(steps) - 1
+	}
+	return &steps[index]
+}
+
+// GetStepWeight returns the percentage of Pods which should run on the "upgrading" child for the Step at the given index
+func GetStepWeight(rolloutObject ProgressiveRolloutObject, index int) int32 {
+	steps := rolloutObject.GetProgressiveSteps()
+	if index < 0 || index >= len(steps) {
+		return DefaultUpgradingWeight
+	}
+	return steps[index].Weight
+}
+
+// GetStepAnalysis returns the Analysis for the current Step if it overrides the given Rollout-level Analysis;
+// otherwise it returns the Rollout-level Analysis
+func GetStepAnalysis(rolloutObject ProgressiveRolloutObject, rolloutAnalysis apiv1.Analysis) apiv1.Analysis {
+	step := GetCurrentStep(rolloutObject)
+	if step != nil && step.Analysis != nil {
+		return *step.Analysis
+	}
+	return rolloutAnalysis
+}
+
+// hasNextStep determines if the "upgrading" child needs to move on to another Step before it can be promoted
+func hasNextStep(rolloutObject ProgressiveRolloutObject) bool {
+	return GetCurrentStepIndex(rolloutObject)+1 < len(rolloutObject.GetProgressiveSteps())
+}
+
+/*
+advanceToNextStep moves the upgrading child on to the next Progressive Step, resetting the assessment
+so that it's performed again for the new Step.
+
+Parameters:
+- ctx: The context for managing request-scoped values, cancellation, and timeouts.
+- rolloutObject: The current rollout object.
+- controller: The progressive controller responsible for managing the upgrade process.
+- existingPromotedChildDef: The definition of the currently promoted child resource.
+- existingUpgradingChildDef: The definition of the child resource currently being upgraded.
+- c: The Kubernetes client for interacting with the cluster.
+
+Returns:
+- A boolean indicating whether we should requeue.
+- An error if any issues occur during the process.
+*/
+func advanceToNextStep(
+	ctx context.Context,
+	rolloutObject ProgressiveRolloutObject,
+	controller progressiveController,
+	existingPromotedChildDef, existingUpgradingChildDef *unstructured.Unstructured,
+	c client.Client,
+) (bool, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	previousChildStatus := rolloutObject.GetUpgradingChildStatus().DeepCopy()
+
+	childStatus := UpdateUpgradingChildStatus(rolloutObject, func(status *apiv1.UpgradingChildStatus) {
+		status.CurrentStep++
+		status.AssessmentResult = apiv1.AssessmentResultUnknown
+		status.BasicAssessmentStartTime = nil
+		status.BasicAssessmentEndTime = nil
+		status.BasicAssessmentResult = ""
+		status.TrialWindowStartTime = nil
+		status.HealthyChecks = 0
+		status.UnhealthyChecks = 0
+		status.FailureReason = ""
+	})
+
+	requeue, err := controller.ProcessChildrenPostStepAdvance(ctx, rolloutObject, existingPromotedChildDef, existingUpgradingChildDef, c)
+	if err != nil {
+		// restore the previous status so that we try to advance again next time
+		rolloutObject.SetUpgradingChildStatus(previousChildStatus)
+		return false, err
+	}
+
+	numaLogger.WithValues("step", childStatus.CurrentStep, "weight", GetStepWeight(rolloutObject, int(childStatus.CurrentStep))).
+		Debug("advanced upgrading child to next progressive step")
+
+	return requeue, nil
+}
@@ -0,0 +1,134 @@
+package progressive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	argorolloutsv1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_CalculateScaleMinMaxValuesForWeight(t *testing.T) {
+	testCases := []struct {
+		name          string
+		podsCount     int
+		weight        int32
+		expectedValue int64
+	}{
+		{name: "half of even count", podsCount: 4, weight: 50, expectedValue: 2},
+		{name: "half of odd count", podsCount: 5, weight: 50, expectedValue: 2},
+		{name: "small weight rounds up for upgrading", podsCount: 10, weight: 5, expectedValue: 9},
+		{name: "full weight", podsCount: 10, weight: 100, expectedValue: 0},
+		{name: "no pods", podsCount: 0, weight: 20, expectedValue: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedValue, CalculateScaleMinMaxValuesForWeight(tc.podsCount, tc.weight))
+		})
+	}
+
+	// the default weight must be equivalent to the original calculation
+	for pods := 0; pods < 10; pods++ {
+		assert.Equal(t, CalculateScaleMinMaxValues(pods), CalculateScaleMinMaxValuesForWeight(pods, DefaultUpgradingWeight))
+	}
+}
+
+func Test_ValidateProgressiveSteps(t *testing.T) {
+	testCases := []struct {
+		name        string
+		steps       []apiv1.ProgressiveStep
+		expectError bool
+	}{
+		{name: "no steps", steps: nil, expectError: false},
+		{name: "increasing weights", steps: []apiv1.ProgressiveStep{{Weight: 10}, {Weight: 50}, {Weight: 100}}, expectError: false},
+		{name: "weight too low", steps: []apiv1.ProgressiveStep{{Weight: 0}}, expectError: true},
+		{name: "weight too high", steps: []apiv1.ProgressiveStep{{Weight: 101}}, expectError: true},
+		{name: "weights not increasing", steps: []apiv1.ProgressiveStep{{Weight: 50}, {Weight: 50}}, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateProgressiveSteps(tc.steps)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_stepAccessors(t *testing.T) {
+	stepAnalysis := &apiv1.Analysis{Templates: []argorolloutsv1.AnalysisTemplateRef{{TemplateName: "step-template"}}}
+	rolloutAnalysis := apiv1.Analysis{Templates: []argorolloutsv1.AnalysisTemplateRef{{TemplateName: "rollout-template"}}}
+
+	rollout := stepsMonoVertexRollout([]apiv1.ProgressiveStep{
+		{Weight: 10},
+		{Weight: 50, Analysis: stepAnalysis},
+	}, 0)
+
+	assert.Equal(t, int32(10), GetStepWeight(rollout, 0))
+	assert.Equal(t, int32(50), GetStepWeight(rollout, 1))
+	assert.Equal(t, DefaultUpgradingWeight, GetStepWeight(rollout, 2))
+	assert.True(t, hasNextStep(rollout))
+	assert.Equal(t, rolloutAnalysis, GetStepAnalysis(rollout, rolloutAnalysis))
+
+	rollout.Status.ProgressiveStatus.UpgradingMonoVertexStatus.CurrentStep = 1
+	assert.False(t, hasNextStep(rollout))
+	assert.Equal(t, *stepAnalysis, GetStepAnalysis(rollout, rolloutAnalysis))
+
+	noStepsRollout := defaultMonoVertexRollout.DeepCopy()
+	assert.Nil(t, GetCurrentStep(noStepsRollout))
+	assert.False(t, hasNextStep(noStepsRollout))
+	assert.Equal(t, DefaultUpgradingWeight, GetStepWeight(noStepsRollout, 0))
+}
+
+func Test_advanceToNextStep(t *testing.T) {
+	rollout := stepsMonoVertexRollout([]apiv1.ProgressiveStep{{Weight: 10}, {Weight: 50}}, 0)
+	childStatus := rollout.GetUpgradingChildStatus()
+	childStatus.AssessmentResult = apiv1.AssessmentResultSuccess
+	childStatus.BasicAssessmentResult = apiv1.AssessmentResultSuccess
+	childStatus.BasicAssessmentEndTime = &metav1.Time{Time: time.Now()}
+	childStatus.TrialWindowStartTime = &metav1.Time{Time: time.Now()}
+
+	requeue, err := advanceToNextStep(context.Background(), rollout, fakeProgressiveController{},
+		monoVertexToUnstruct(createMonoVertex("test-0")), monoVertexToUnstruct(createMonoVertex("test-1")), nil)
+	assert.NoError(t, err)
+	assert.False(t, requeue)
+
+	childStatus = rollout.GetUpgradingChildStatus()
+	assert.Equal(t, int32(1), childStatus.CurrentStep)
+	assert.Equal(t, apiv1.AssessmentResultUnknown, childStatus.AssessmentResult)
+	assert.Equal(t, apiv1.AssessmentResult(""), childStatus.BasicAssessmentResult)
+	assert.Nil(t, childStatus.BasicAssessmentStartTime)
+	assert.Nil(t, childStatus.BasicAssessmentEndTime)
+	assert.Nil(t, childStatus.TrialWindowStartTime)
+	assert.True(t, childStatus.InitializationComplete)
+}
+
+func stepsMonoVertexRollout(steps []apiv1.ProgressiveStep, currentStep int32) *apiv1.MonoVertexRollout {
+	rollout := defaultMonoVertexRollout.DeepCopy()
+	rollout.Spec.Strategy = &apiv1.PipelineTypeRolloutStrategy{
+		PipelineTypeProgressiveStrategy: apiv1.PipelineTypeProgressiveStrategy{
+			Progressive: apiv1.ProgressiveStrategy{
+				Steps: steps,
+			},
+		},
+	}
+	return setMonoVertexProgressiveStatus(rollout,
+		&apiv1.UpgradingMonoVertexStatus{
+			UpgradingPipelineTypeStatus: apiv1.UpgradingPipelineTypeStatus{
+				UpgradingChildStatus: apiv1.UpgradingChildStatus{
+					Name:                   "test-1",
+					AssessmentResult:       apiv1.AssessmentResultUnknown,
+					InitializationComplete: true,
+					CurrentStep:            currentStep,
+				},
+			},
+		}, nil)
+}
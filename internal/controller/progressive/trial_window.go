@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progressive
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/numaproj/numaplane/internal/controller/config"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+/*
+RecordBasicHealthCheck records the result of a basic resource health check of the upgrading child in its trial window.
+
+A healthy check starts the trial window if it isn't started yet. An unhealthy check restarts the trial window, unless the
+schedule tolerates more unhealthy checks within it, in which case only the count of consecutive healthy checks is reset.
+
+Parameters:
+  - rolloutObject: the rollout object whose UpgradingChildStatus is updated.
+  - healthy: the result of the health check.
+  - schedule: the assessment schedule.
+  - currentTime: the time of the health check.
+
+Returns:
+  - Whether the trial window has passed: the upgrading child has been healthy for the schedule's Period and for
+    the required number of consecutive checks.
+*/
+func RecordBasicHealthCheck(rolloutObject ProgressiveRolloutObject, healthy bool, schedule config.AssessmentSchedule, currentTime time.Time) bool {
+	childStatus := UpdateUpgradingChildStatus(rolloutObject, func(status *apiv1.UpgradingChildStatus) {
+		status.AssessmentResult = apiv1.AssessmentResultUnknown
+		switch {
+		case healthy:
+			if !status.IsTrialWindowStartTimeSet() {
+				status.TrialWindowStartTime = &metav1.Time{Time: currentTime}
+				status.HealthyChecks = 0
+				status.UnhealthyChecks = 0
+			}
+			status.HealthyChecks++
+		case status.IsTrialWindowStartTimeSet() && status.UnhealthyChecks < schedule.MaxUnhealthyChecks:
+			// tolerate a transient failure without restarting the trial window
+			status.UnhealthyChecks++
+			status.HealthyChecks = 0
+		default:
+			status.TrialWindowStartTime = nil
+			status.HealthyChecks = 0
+			status.UnhealthyChecks = 0
+		}
+	})
+
+	return healthy && childStatus.IsTrialWindowStartTimeSet() &&
+		currentTime.Sub(childStatus.TrialWindowStartTime.Time) >= schedule.Period &&
+		childStatus.HealthyChecks >= schedule.ConsecutiveHealthyChecks
+}
@@ -0,0 +1,40 @@
+package progressive
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/numaproj/numaplane/internal/controller/config"
+)
+
+func Test_RecordBasicHealthCheck(t *testing.T) {
+	start := time.Now()
+	schedule := config.AssessmentSchedule{Period: time.Minute, Interval: 10 * time.Second, ConsecutiveHealthyChecks: 3, MaxUnhealthyChecks: 1}
+
+	rollout := stepsMonoVertexRollout(nil, 0)
+
+	// healthy check starts the trial window
+	assert.False(t, RecordBasicHealthCheck(rollout, true, schedule, start))
+	assert.Equal(t, start, rollout.GetUpgradingChildStatus().TrialWindowStartTime.Time)
+
+	// a tolerated unhealthy check resets the consecutive healthy checks but not the trial window
+	assert.False(t, RecordBasicHealthCheck(rollout, false, schedule, start.Add(10*time.Second)))
+	assert.Equal(t, start, rollout.GetUpgradingChildStatus().TrialWindowStartTime.Time)
+	assert.Equal(t, int32(0), rollout.GetUpgradingChildStatus().HealthyChecks)
+	assert.Equal(t, int32(1), rollout.GetUpgradingChildStatus().UnhealthyChecks)
+
+	// the Period has passed, but there aren't enough consecutive healthy checks yet
+	assert.False(t, RecordBasicHealthCheck(rollout, true, schedule, start.Add(time.Minute)))
+	assert.False(t, RecordBasicHealthCheck(rollout, true, schedule, start.Add(70*time.Second)))
+	assert.True(t, RecordBasicHealthCheck(rollout, true, schedule, start.Add(80*time.Second)))
+
+	// a second unhealthy check isn't tolerated, so it restarts the trial window
+	assert.False(t, RecordBasicHealthCheck(rollout, false, schedule, start.Add(90*time.Second)))
+	assert.False(t, rollout.GetUpgradingChildStatus().IsTrialWindowStartTimeSet())
+	assert.Equal(t, int32(0), rollout.GetUpgradingChildStatus().UnhealthyChecks)
+
+	// by default, a single healthy check is enough once the Period has passed
+	assert.True(t, RecordBasicHealthCheck(rollout, true, config.AssessmentSchedule{Interval: 10 * time.Second}, start.Add(100*time.Second)))
+}
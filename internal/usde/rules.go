@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usde
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	"github.com/numaproj/numaplane/internal/util/metrics"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+/*
+rulesNeedUpdating evaluates the USDE Config's custom Rules for the resource's Kind over the existing and new definitions.
+The Rules are only evaluated if the spec changed.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - newDef: the new definition of the resource.
+  - existingDef: the existing definition of the resource.
+  - rolloutStrategy: the upgrade strategy selected by the Rollout, if any.
+
+Returns:
+  - Whether any Rule classified the change.
+  - The most conservative upgrade strategy of the Rules' results.
+  - Whether any Rule requires the resource to be recreated.
+  - An error if any issues occur during processing.
+*/
+func rulesNeedUpdating(ctx context.Context, newDef, existingDef *unstructured.Unstructured, rolloutStrategy apiv1.UserUpgradeStrategy) (bool, apiv1.UpgradeStrategy, bool, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	usdeConfigMapKey := strings.ToLower(newDef.GetKind())
+	rules := config.GetConfigManagerInstance().GetEffectiveUSDEConfig(newDef.GetNamespace())[usdeConfigMapKey].Rules
+	if len(rules) == 0 || util.CompareStructNumTypeAgnostic(newDef.Object["spec"], existingDef.Object["spec"]) {
+		return false, apiv1.UpgradeStrategyNoOp, false, nil
+	}
+
+	dataLossUpgradeStrategy, err := getDataLossUpgradeStrategy(ctx, newDef.GetNamespace(), existingDef.GetKind(), rolloutStrategy)
+	if err != nil {
+		return false, apiv1.UpgradeStrategyError, false, err
+	}
+
+	needsUpdating := false
+	strategies := []apiv1.UpgradeStrategy{}
+	recreate := false
+	for _, rule := range rules {
+		changeClass, err := rule.Evaluate(existingDef.Object, newDef.Object)
+		if err != nil {
+			// a rule which fails at runtime (e.g. it references a field that isn't set without guarding it with has())
+			// is treated as not applying, so that one bad rule doesn't block every update to the resource
+			metrics.IncUSDERuleErrors(usdeConfigMapKey, rule.Name, metrics.LabelValueUSDERuleEvaluation)
+			numaLogger.WithValues("rule", rule.Name, "error", err.Error()).Warn("USDE rule couldn't be evaluated, so it doesn't apply")
+			continue
+		}
+		if changeClass == config.ChangeClassDefault {
+			continue
+		}
+
+		strategy, ruleRecreate := getChangeClassStrategy(changeClass, dataLossUpgradeStrategy)
+		numaLogger.WithValues("rule", rule.Name, "changeClass", changeClass, "strategy", strategy, "recreate", ruleRecreate).Debug("USDE rule classified change")
+		needsUpdating = true
+		strategies = append(strategies, strategy)
+		recreate = recreate || ruleRecreate
+	}
+
+	return needsUpdating, getMostConservativeStrategy(strategies), recreate, nil
+}
+
+// getChangeClassStrategy returns the upgrade strategy for a class of change, consistent with how the changes to the fields in each
+// of the USDE Config lists are treated, and whether the resource needs to be recreated
+func getChangeClassStrategy(changeClass config.USDEChangeClass, dataLossUpgradeStrategy apiv1.UpgradeStrategy) (apiv1.UpgradeStrategy, bool) {
+	switch changeClass {
+	case config.ChangeClassRecreate:
+		// a Progressive upgrade always creates a new child, so there's nothing to recreate
+		return dataLossUpgradeStrategy, dataLossUpgradeStrategy != apiv1.UpgradeStrategyProgressive
+	case config.ChangeClassDataLoss:
+		return dataLossUpgradeStrategy, false
+	case config.ChangeClassProgressive:
+		if dataLossUpgradeStrategy == apiv1.UpgradeStrategyProgressive {
+			return apiv1.UpgradeStrategyProgressive, false
+		}
+		return apiv1.UpgradeStrategyApply, false
+	default:
+		return apiv1.UpgradeStrategyApply, false
+	}
+}
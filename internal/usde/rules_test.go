@@ -0,0 +1,140 @@
+package usde
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/numaproj/numaplane/internal/controller/config"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_rulesNeedUpdating(t *testing.T) {
+	ctx := context.Background()
+
+	getwd, err := os.Getwd()
+	assert.Nil(t, err, "Failed to get working directory")
+	configPath := filepath.Join(getwd, "../../", "tests", "config")
+	configManager := config.GetConfigManagerInstance()
+	err = configManager.LoadAllConfigs(func(err error) {}, config.WithConfigsPath(configPath), config.WithConfigFileName("testconfig"))
+	assert.NoError(t, err)
+
+	makePipeline := func(vertexNames ...string) *unstructured.Unstructured {
+		vertices := []any{}
+		for _, name := range vertexNames {
+			vertices = append(vertices, map[string]any{"name": name})
+		}
+		return &unstructured.Unstructured{Object: map[string]any{
+			"kind":     "Pipeline",
+			"metadata": map[string]any{"namespace": defaultNamespace},
+			"spec":     map[string]any{"vertices": vertices},
+		}}
+	}
+
+	resourceConfig := config.USDEResourceConfig{
+		Rules: []config.ChangeRule{
+			{
+				Name:       "vertex-removed",
+				Expression: "new.spec.vertices.size() < old.spec.vertices.size() ? 'recreate' : ''",
+			},
+			{
+				Name:       "vertex-added",
+				Expression: "new.spec.vertices.size() > old.spec.vertices.size() ? 'progressive' : ''",
+			},
+		},
+	}
+	assert.NoError(t, resourceConfig.Compile())
+	configManager.UpdateUSDEConfig(config.USDEConfig{"pipeline": resourceConfig})
+	defer configManager.UnsetUSDEConfig()
+
+	testCases := []struct {
+		name                  string
+		namespaceStrategy     config.USDEUserStrategy
+		existingVertices      []string
+		newVertices           []string
+		expectedNeedsUpdating bool
+		expectedStrategy      apiv1.UpgradeStrategy
+		expectedRecreate      bool
+	}{
+		{
+			name:              "no change",
+			namespaceStrategy: config.PPNDStrategyID,
+			existingVertices:  []string{"in", "out"},
+			newVertices:       []string{"in", "out"},
+			expectedStrategy:  apiv1.UpgradeStrategyNoOp,
+		},
+		{
+			name:              "change not classified by any rule",
+			namespaceStrategy: config.PPNDStrategyID,
+			existingVertices:  []string{"in", "out"},
+			newVertices:       []string{"in", "sink"},
+			expectedStrategy:  apiv1.UpgradeStrategyNoOp,
+		},
+		{
+			name:                  "vertex removed with PPND",
+			namespaceStrategy:     config.PPNDStrategyID,
+			existingVertices:      []string{"in", "cat", "out"},
+			newVertices:           []string{"in", "out"},
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyPPND,
+			expectedRecreate:      true,
+		},
+		{
+			name:                  "vertex added with PPND",
+			namespaceStrategy:     config.PPNDStrategyID,
+			existingVertices:      []string{"in", "out"},
+			newVertices:           []string{"in", "cat", "out"},
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyApply,
+		},
+		{
+			name:                  "vertex added with Progressive",
+			namespaceStrategy:     config.ProgressiveStrategyID,
+			existingVertices:      []string{"in", "out"},
+			newVertices:           []string{"in", "cat", "out"},
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyProgressive,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configManager.UpdateNamespaceConfig(defaultNamespace, config.NamespaceConfig{UpgradeStrategy: tc.namespaceStrategy})
+			defer configManager.UnsetNamespaceConfig(defaultNamespace)
+
+			needsUpdating, strategy, recreate, err := rulesNeedUpdating(ctx, makePipeline(tc.newVertices...), makePipeline(tc.existingVertices...), "")
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedNeedsUpdating, needsUpdating)
+			assert.Equal(t, tc.expectedStrategy, strategy)
+			assert.Equal(t, tc.expectedRecreate, recreate)
+		})
+	}
+
+	t.Run("rule failing at runtime doesn't apply", func(t *testing.T) {
+		configManager.UpdateUSDEConfig(config.USDEConfig{"pipeline": config.USDEResourceConfig{
+			Rules: []config.ChangeRule{{Name: "missing-field", Expression: "new.spec.scale.max < old.spec.scale.max ? 'dataLoss' : ''"}},
+		}})
+		needsUpdating, strategy, recreate, err := rulesNeedUpdating(ctx, makePipeline("in"), makePipeline("out"), "")
+		assert.NoError(t, err)
+		assert.False(t, needsUpdating)
+		assert.Equal(t, apiv1.UpgradeStrategyNoOp, strategy)
+		assert.False(t, recreate)
+	})
+}
+
+func Test_ChangeRule_Compile(t *testing.T) {
+	for _, rules := range [][]config.ChangeRule{
+		{{Name: "syntax", Expression: "new.spec.vertices.size( < 1"}},
+		{{Name: "not-a-string", Expression: "new.spec.vertices.size() < 1"}},
+		{{Expression: "'dataLoss'"}},
+		{{Name: "duplicate", Expression: "'dataLoss'"}, {Name: "duplicate", Expression: "''"}},
+	} {
+		resourceConfig := config.USDEResourceConfig{Rules: rules}
+		var ruleErr *config.ChangeRuleCompileError
+		assert.ErrorAs(t, resourceConfig.Compile(), &ruleErr)
+	}
+}
@@ -0,0 +1,238 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usde
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util/logger"
+)
+
+type versionBump int
+
+const (
+	versionBumpNone versionBump = iota
+	versionBumpPatch
+	versionBumpMinor
+	versionBumpMajor
+	// the values couldn't be compared as semantic versions
+	versionBumpUnknown
+)
+
+func (bump versionBump) String() string {
+	switch bump {
+	case versionBumpNone:
+		return "none"
+	case versionBumpPatch:
+		return "patch"
+	case versionBumpMinor:
+		return "minor"
+	case versionBumpMajor:
+		return "major"
+	default:
+		return "unknown"
+	}
+}
+
+/*
+classifyVersionChanges reclassifies the changes to fields which have a Semver classification, based on which part of their semantic
+version changed between the existing and new definitions. Fields without a Semver classification, or whose change couldn't be
+compared as a semantic version, stay in the list they're defined in.
+
+Parameters:
+  - ctx: the context for managing request-scoped values.
+  - recreateFields, dataLossFields, progressiveFields: the USDE Config lists for the resource's Kind.
+  - newDef: the new definition of the resource.
+  - existingDef: the existing definition of the resource.
+
+Returns:
+  - the recreate, dataLoss, and progressive lists to use in comparing the two definitions: a field whose change is classified
+    as "directApply" is omitted from all of them
+  - An error if any issues occur during processing.
+*/
+func classifyVersionChanges(
+	ctx context.Context,
+	recreateFields, dataLossFields, progressiveFields []config.SpecField,
+	newDef, existingDef *unstructured.Unstructured,
+) ([]config.SpecField, []config.SpecField, []config.SpecField, error) {
+	numaLogger := logger.FromContext(ctx)
+
+	classified := map[config.USDEChangeClass][]config.SpecField{}
+	for _, list := range []struct {
+		changeClass config.USDEChangeClass
+		specFields  []config.SpecField
+	}{
+		{config.ChangeClassRecreate, recreateFields},
+		{config.ChangeClassDataLoss, dataLossFields},
+		{config.ChangeClassProgressive, progressiveFields},
+	} {
+		for _, specField := range list.specFields {
+			changeClass := list.changeClass
+			if specField.Semver != nil {
+				bump, err := getVersionBump(specField, newDef, existingDef)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+				if versionChangeClass := getSemverChangeClass(*specField.Semver, bump); versionChangeClass != config.ChangeClassDefault {
+					numaLogger.WithValues("field", specField.Path, "bump", bump.String(), "changeClass", versionChangeClass).Debug("classified version change")
+					changeClass = versionChangeClass
+				}
+			}
+			classified[changeClass] = append(classified[changeClass], specField)
+		}
+	}
+
+	return classified[config.ChangeClassRecreate], classified[config.ChangeClassDataLoss], classified[config.ChangeClassProgressive], nil
+}
+
+func getSemverChangeClass(semverClassification config.SemverClassification, bump versionBump) config.USDEChangeClass {
+	switch bump {
+	case versionBumpPatch:
+		return semverClassification.Patch
+	case versionBumpMinor:
+		return semverClassification.Minor
+	case versionBumpMajor:
+		return semverClassification.Major
+	default:
+		return config.ChangeClassDefault
+	}
+}
+
+// getVersionBump determines the largest version bump among the values selected by the field.
+// The values are compared in order: if one was added or removed, or any can't be parsed, the bump is unknown.
+func getVersionBump(specField config.SpecField, newDef, existingDef *unstructured.Unstructured) (versionBump, error) {
+	selector, err := specField.GetSelector()
+	if err != nil {
+		return versionBumpUnknown, err
+	}
+	newField, _, err := selector.Extract(newDef.Object)
+	if err != nil {
+		return versionBumpUnknown, err
+	}
+	existingField, _, err := selector.Extract(existingDef.Object)
+	if err != nil {
+		return versionBumpUnknown, err
+	}
+
+	newValues, newOK := flattenVersionValues(newField)
+	existingValues, existingOK := flattenVersionValues(existingField)
+	if !newOK || !existingOK || len(newValues) != len(existingValues) {
+		return versionBumpUnknown, nil
+	}
+
+	largestBump := versionBumpNone
+	for i := range newValues {
+		if newValues[i] == existingValues[i] {
+			continue
+		}
+		bump := compareVersions(existingValues[i], newValues[i])
+		if bump > largestBump {
+			largestBump = bump
+		}
+	}
+	return largestBump, nil
+}
+
+// flattenVersionValues returns the string values selected, in order, with "" for any which aren't present.
+// It returns false if any value selected isn't a string (e.g. a number or a boolean), since it isn't known to be a version.
+func flattenVersionValues(field any) ([]string, bool) {
+	switch typedField := field.(type) {
+	case nil:
+		return []string{""}, true
+	case string:
+		return []string{typedField}, true
+	case map[string]any:
+		// the selected maps only contain the fields along the path
+		keys := make([]string, 0, len(typedField))
+		for key := range typedField {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		values := []string{}
+		for _, key := range keys {
+			keyValues, ok := flattenVersionValues(typedField[key])
+			if !ok {
+				return nil, false
+			}
+			values = append(values, keyValues...)
+		}
+		return values, true
+	case []any:
+		values := []string{}
+		for _, element := range typedField {
+			elementValues, ok := flattenVersionValues(element)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, elementValues...)
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
+
+// compareVersions determines which part of the semantic version changed between the two values
+func compareVersions(existingValue, newValue string) versionBump {
+	existingRepository, existingVersion, existingOK := parseVersion(existingValue)
+	newRepository, newVersion, newOK := parseVersion(newValue)
+	if !existingOK || !newOK || existingRepository != newRepository {
+		return versionBumpUnknown
+	}
+
+	switch {
+	case semver.Major(existingVersion) != semver.Major(newVersion):
+		return versionBumpMajor
+	case semver.MajorMinor(existingVersion) != semver.MajorMinor(newVersion):
+		return versionBumpMinor
+	case semver.Compare(existingVersion, newVersion) != 0:
+		return versionBumpPatch
+	case existingValue != newValue:
+		// the versions are equivalent but written differently, or differ only in build metadata
+		return versionBumpUnknown
+	default:
+		return versionBumpNone
+	}
+}
+
+// parseVersion parses either a container image or a plain version, returning the image repository (if any) and the
+// semantic version (in its "v" prefixed form) of the image's tag or of the plain version
+func parseVersion(value string) (string, string, bool) {
+	repository, version := "", value
+	// ignore any digest
+	if digestIndex := strings.Index(version, "@"); digestIndex >= 0 {
+		version = version[:digestIndex]
+	}
+	// if this is an image, use its tag
+	if colonIndex := strings.LastIndex(version, ":"); colonIndex > strings.LastIndex(version, "/") {
+		repository, version = version[:colonIndex], version[colonIndex+1:]
+	}
+
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !semver.IsValid(version) {
+		return "", "", false
+	}
+	return repository, version, true
+}
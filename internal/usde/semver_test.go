@@ -0,0 +1,143 @@
+package usde
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/numaproj/numaplane/internal/controller/config"
+)
+
+func Test_compareVersions(t *testing.T) {
+	testCases := []struct {
+		existingValue string
+		newValue      string
+		expectedBump  versionBump
+	}{
+		{"quay.io/numaio/numaflow-go/map-cat:v1.2.3", "quay.io/numaio/numaflow-go/map-cat:v1.2.4", versionBumpPatch},
+		{"quay.io/numaio/numaflow-go/map-cat:v1.2.3", "quay.io/numaio/numaflow-go/map-cat:v1.3.0", versionBumpMinor},
+		{"quay.io/numaio/numaflow-go/map-cat:v1.2.3", "quay.io/numaio/numaflow-go/map-cat:v2.0.0", versionBumpMajor},
+		{"localhost:5000/map-cat:1.2.3", "localhost:5000/map-cat:1.2.4@sha256:abc", versionBumpPatch},
+		{"map-cat:v1.2.3", "map-cat:v1.2.3-rc1", versionBumpPatch},
+		{"2.10.3", "2.10.11", versionBumpPatch},
+		{"2.10.3", "2.11", versionBumpMinor},
+		// a different repository
+		{"map-cat:v1.2.3", "map-dog:v1.2.4", versionBumpUnknown},
+		// not a semantic version
+		{"map-cat:latest", "map-cat:v1.2.4", versionBumpUnknown},
+		{"localhost:5000/map-cat", "localhost:5000/map-cat:v1.2.4", versionBumpUnknown},
+		{"1.2.3", "v1.2.3", versionBumpUnknown},
+		{"v1.2.3", "v1.2.3", versionBumpNone},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expectedBump, compareVersions(tc.existingValue, tc.newValue), "%s -> %s", tc.existingValue, tc.newValue)
+	}
+}
+
+func Test_flattenVersionValues(t *testing.T) {
+	testCases := []struct {
+		name           string
+		field          any
+		expectedValues []string
+		expectedOK     bool
+	}{
+		{"not present", nil, []string{""}, true},
+		{"string", "map-cat:v1.2.3", []string{"map-cat:v1.2.3"}, true},
+		{"map, in order of keys", map[string]any{"b": "2.0.0", "a": "1.0.0"}, []string{"1.0.0", "2.0.0"}, true},
+		{"list", []any{"1.0.0", nil}, []string{"1.0.0", ""}, true},
+		{"number", int64(2), nil, false},
+		{"float", 1.5, nil, false},
+		{"boolean", true, nil, false},
+		{"number in a map", map[string]any{"a": "1.0.0", "b": int64(3)}, nil, false},
+		{"typed slice", []string{"1.0.0"}, nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values, ok := flattenVersionValues(tc.field)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedValues, values)
+		})
+	}
+}
+
+func Test_classifyVersionChanges(t *testing.T) {
+	makePipeline := func(images ...string) *unstructured.Unstructured {
+		vertices := []any{}
+		for _, image := range images {
+			vertices = append(vertices, map[string]any{"udf": map[string]any{"container": map[string]any{"image": image}}})
+		}
+		return &unstructured.Unstructured{Object: map[string]any{
+			"kind": "Pipeline",
+			"spec": map[string]any{"vertices": vertices, "edges": []any{map[string]any{"from": "in", "to": "out"}}},
+		}}
+	}
+
+	imageField := config.SpecField{
+		Path:   "spec.vertices[*].udf.container.image",
+		Semver: &config.SemverClassification{Patch: config.ChangeClassDirectApply, Major: config.ChangeClassProgressive},
+	}
+	edgesField := config.SpecField{Path: "spec.edges", IncludeSubfields: true}
+
+	testCases := []struct {
+		name                string
+		existingImages      []string
+		newImages           []string
+		expectedDataLoss    []string
+		expectedProgressive []string
+	}{
+		{
+			name:             "patch bump is a direct apply",
+			existingImages:   []string{"map-cat:v1.2.3", "map-dog:v1.0.0"},
+			newImages:        []string{"map-cat:v1.2.4", "map-dog:v1.0.0"},
+			expectedDataLoss: []string{"spec.edges"},
+		},
+		{
+			name:             "minor bump isn't classified, so it stays in its list",
+			existingImages:   []string{"map-cat:v1.2.3", "map-dog:v1.0.0"},
+			newImages:        []string{"map-cat:v1.3.0", "map-dog:v1.0.1"},
+			expectedDataLoss: []string{imageField.Path, "spec.edges"},
+		},
+		{
+			name:                "largest bump determines the classification",
+			existingImages:      []string{"map-cat:v1.2.3", "map-dog:v1.0.0"},
+			newImages:           []string{"map-cat:v1.2.4", "map-dog:v2.0.0"},
+			expectedDataLoss:    []string{"spec.edges"},
+			expectedProgressive: []string{imageField.Path},
+		},
+		{
+			name:             "unparseable version stays in its list",
+			existingImages:   []string{"map-cat:v1.2.3", "map-dog:latest"},
+			newImages:        []string{"map-cat:v1.2.4", "map-dog:v1.0.1"},
+			expectedDataLoss: []string{imageField.Path, "spec.edges"},
+		},
+		{
+			name:             "added vertex stays in its list",
+			existingImages:   []string{"map-cat:v1.2.3"},
+			newImages:        []string{"map-cat:v1.2.4", "map-dog:v1.0.1"},
+			expectedDataLoss: []string{imageField.Path, "spec.edges"},
+		},
+	}
+
+	getPaths := func(specFields []config.SpecField) []string {
+		var paths []string
+		for _, specField := range specFields {
+			paths = append(paths, specField.Path)
+		}
+		return paths
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recreateFields, dataLossFields, progressiveFields, err := classifyVersionChanges(context.Background(),
+				nil, []config.SpecField{imageField, edgesField}, nil, makePipeline(tc.newImages...), makePipeline(tc.existingImages...))
+			assert.NoError(t, err)
+			assert.Empty(t, recreateFields)
+			assert.Equal(t, tc.expectedDataLoss, getPaths(dataLossFields))
+			assert.Equal(t, tc.expectedProgressive, getPaths(progressiveFields))
+		})
+	}
+}
@@ -28,6 +28,7 @@ var (
 
 // ResourceNeedsUpdating calculates the upgrade strategy to use during the
 // resource reconciliation process based on configuration and user preference (see design doc for details).
+// rolloutStrategy is the upgrade strategy selected by the Rollout, if any, which overrides the namespace and global configuration.
 // It returns the following values:
 // - bool: Indicates whether the resource needs an update.
 // - apiv1.UpgradeStrategy: The most conservative upgrade strategy to be used for updating the resource.
@@ -40,20 +41,27 @@ func ResourceNeedsUpdating(
 	ctx context.Context,
 	newDef, existingDef *unstructured.Unstructured,
 	newRiders []riders.Rider,
-	existingRiders unstructured.UnstructuredList) (bool, apiv1.UpgradeStrategy, bool, unstructured.UnstructuredList, unstructured.UnstructuredList, unstructured.UnstructuredList, error) {
+	existingRiders unstructured.UnstructuredList,
+	rolloutStrategy apiv1.UserUpgradeStrategy) (bool, apiv1.UpgradeStrategy, bool, unstructured.UnstructuredList, unstructured.UnstructuredList, unstructured.UnstructuredList, error) {
 	numaLogger := logger.FromContext(ctx)
 
-	metadataNeedsUpdating, metadataUpgradeStrategy, err := resourceMetadataNeedsUpdating(ctx, newDef, existingDef)
+	metadataNeedsUpdating, metadataUpgradeStrategy, err := resourceMetadataNeedsUpdating(ctx, newDef, existingDef, rolloutStrategy)
 	if err != nil {
 		return false, apiv1.UpgradeStrategyError, false, unstructured.UnstructuredList{}, unstructured.UnstructuredList{}, unstructured.UnstructuredList{}, err
 	}
 
-	specNeedsUpdating, specUpgradeStrategy, recreate, err := resourceSpecNeedsUpdating(ctx, newDef, existingDef)
+	specNeedsUpdating, specUpgradeStrategy, recreate, err := resourceSpecNeedsUpdating(ctx, newDef, existingDef, rolloutStrategy)
 	if err != nil {
 		return false, apiv1.UpgradeStrategyError, false, unstructured.UnstructuredList{}, unstructured.UnstructuredList{}, unstructured.UnstructuredList{}, err
 	}
 
-	ridersNeedUpdating, ridersUpgradeStrategy, additionsRequired, modificationsRequired, deletionsRequired, err := RidersNeedUpdating(ctx, existingDef.GetNamespace(), existingDef.GetKind(), existingDef.GetName(), newRiders, existingRiders)
+	rulesNeedUpdating, rulesUpgradeStrategy, rulesRecreate, err := rulesNeedUpdating(ctx, newDef, existingDef, rolloutStrategy)
+	if err != nil {
+		return false, apiv1.UpgradeStrategyError, false, unstructured.UnstructuredList{}, unstructured.UnstructuredList{}, unstructured.UnstructuredList{}, err
+	}
+	recreate = recreate || rulesRecreate
+
+	ridersNeedUpdating, ridersUpgradeStrategy, additionsRequired, modificationsRequired, deletionsRequired, err := RidersNeedUpdating(ctx, existingDef.GetNamespace(), existingDef.GetKind(), existingDef.GetName(), newRiders, existingRiders, rolloutStrategy)
 	if err != nil {
 		return false, apiv1.UpgradeStrategyError, false, additionsRequired, modificationsRequired, deletionsRequired, err
 	}
@@ -61,14 +69,15 @@ func ResourceNeedsUpdating(
 	numaLogger.WithValues(
 		"metadataUpgradeStrategy", metadataUpgradeStrategy,
 		"specUpgradeStrategy", specUpgradeStrategy,
+		"rulesUpgradeStrategy", rulesUpgradeStrategy,
 		"ridersUpgradeStrategy", ridersUpgradeStrategy,
 	).Debug("upgrade strategies")
 
-	if !metadataNeedsUpdating && !specNeedsUpdating && !ridersNeedUpdating {
+	if !metadataNeedsUpdating && !specNeedsUpdating && !rulesNeedUpdating && !ridersNeedUpdating {
 		return false, apiv1.UpgradeStrategyNoOp, false, additionsRequired, modificationsRequired, deletionsRequired, nil
 	}
 
-	return true, getMostConservativeStrategy([]apiv1.UpgradeStrategy{metadataUpgradeStrategy, specUpgradeStrategy, ridersUpgradeStrategy}), recreate, additionsRequired, modificationsRequired, deletionsRequired, nil
+	return true, getMostConservativeStrategy([]apiv1.UpgradeStrategy{metadataUpgradeStrategy, specUpgradeStrategy, rulesUpgradeStrategy, ridersUpgradeStrategy}), recreate, additionsRequired, modificationsRequired, deletionsRequired, nil
 
 }
 
@@ -78,12 +87,12 @@ func ResourceNeedsUpdating(
 // - apiv1.UpgradeStrategy: The strategy to be used for upgrading the resource.
 // - bool: Indicates if the controller managed resources should be recreated (delete-recreate).
 // - error: Any error encountered during the function execution.
-func resourceSpecNeedsUpdating(ctx context.Context, newDef, existingDef *unstructured.Unstructured) (bool, apiv1.UpgradeStrategy, bool, error) {
+func resourceSpecNeedsUpdating(ctx context.Context, newDef, existingDef *unstructured.Unstructured, rolloutStrategy apiv1.UserUpgradeStrategy) (bool, apiv1.UpgradeStrategy, bool, error) {
 
 	numaLogger := logger.FromContext(ctx)
 
-	// Get USDE Config
-	usdeConfig := config.GetConfigManagerInstance().GetUSDEConfig()
+	// Get USDE Config (including any overrides for the namespace)
+	usdeConfig := config.GetConfigManagerInstance().GetEffectiveUSDEConfig(newDef.GetNamespace())
 
 	// Get data loss fields config based on the spec type (Pipeline, ISBS, etc.)
 	usdeConfigMapKey := strings.ToLower(newDef.GetKind())
@@ -91,15 +100,21 @@ func resourceSpecNeedsUpdating(ctx context.Context, newDef, existingDef *unstruc
 	dataLossFields := usdeConfig[usdeConfigMapKey].DataLoss
 	progressiveFields := usdeConfig[usdeConfigMapKey].Progressive
 
-	dataLossUpgradeStrategy, err := getDataLossUpgradeStrategy(ctx, newDef.GetNamespace(), existingDef.GetKind())
+	// fields which are semantic versions may be classified differently depending on the version bump
+	recreateFields, dataLossFields, progressiveFields, err := classifyVersionChanges(ctx, recreateFields, dataLossFields, progressiveFields, newDef, existingDef)
+	if err != nil {
+		return false, apiv1.UpgradeStrategyError, false, err
+	}
+
+	dataLossUpgradeStrategy, err := getDataLossUpgradeStrategy(ctx, newDef.GetNamespace(), existingDef.GetKind(), rolloutStrategy)
 	if err != nil {
 		return false, apiv1.UpgradeStrategyError, false, err
 	}
 
 	numaLogger.WithValues(
 		"dataLossUpgradeStrategy", dataLossUpgradeStrategy,
-		"newDefUnstr", kubernetes.GetLoggableResource(newDef),
-		"existingDefUnstr", kubernetes.GetLoggableResource(existingDef),
+		"newDefName", newDef.GetName(),
+		"existingDefName", existingDef.GetName(),
 	).Debug("started deriving upgrade strategy")
 
 	switch dataLossUpgradeStrategy {
@@ -189,41 +204,89 @@ func checkFieldsList(specFields []config.SpecField, newDef, existingDef *unstruc
 
 	// Loop through all the spec fields from config to see if any changes based on those fields require the specified upgrade strategy
 	for _, specField := range specFields {
-		// newDefField is a map starting with the first field specified in the path
-		// newIsMap describes the inner most element(s) described by the path
-		newDefField, newIsMap, err := util.ExtractPath(newDef.Object, strings.Split(specField.Path, "."))
+		different, err := specFieldDiffers(specField, newDef, existingDef)
 		if err != nil {
 			return false, nil, err
 		}
+		if different {
+			return true, &specField, nil
+		}
+	}
 
-		// existingDefField is a map starting with the first field specified in the path
-		// existingIsMap describes the inner most element(s) described by the path
-		existingDefField, existingIsMap, err := util.ExtractPath(existingDef.Object, strings.Split(specField.Path, "."))
+	return false, nil, nil
+}
+
+// traverse the fields passed in and return the paths of all of the ones which are different
+func findChangedFields(specFields []config.SpecField, newDef, existingDef *unstructured.Unstructured) ([]string, error) {
+	changedFields := []string{}
+	for _, specField := range specFields {
+		different, err := specFieldDiffers(specField, newDef, existingDef)
 		if err != nil {
-			return false, nil, err
+			return nil, err
 		}
-
-		if specField.IncludeSubfields {
-			// is the definition (fields + children) at all different?
-			if !util.CompareStructNumTypeAgnostic(newDefField, existingDefField) {
-				return true, &specField, nil
-			}
-		} else {
-			isMap := newIsMap || existingIsMap
-			// if it's a map, since we don't care about subfields, we just need to know if it's present in one and not the other
-			if isMap {
-				if !newIsMap || !existingIsMap { // this means that one of them is nil
-					return true, &specField, nil
-				}
-			} else {
-				if !util.CompareStructNumTypeAgnostic(newDefField, existingDefField) {
-					return true, &specField, nil
-				}
-			}
+		if different {
+			changedFields = append(changedFields, specField.Path)
 		}
 	}
+	return changedFields, nil
+}
 
-	return false, nil, nil
+// determine if the field is different between the two definitions
+func specFieldDiffers(specField config.SpecField, newDef, existingDef *unstructured.Unstructured) (bool, error) {
+	selector, err := specField.GetSelector()
+	if err != nil {
+		return false, err
+	}
+
+	// newDefField is a map starting with the first field specified in the path
+	// newIsMap describes the inner most element(s) described by the path
+	newDefField, newIsMap, err := selector.Extract(newDef.Object)
+	if err != nil {
+		return false, err
+	}
+
+	// existingDefField is a map starting with the first field specified in the path
+	// existingIsMap describes the inner most element(s) described by the path
+	existingDefField, existingIsMap, err := selector.Extract(existingDef.Object)
+	if err != nil {
+		return false, err
+	}
+
+	if specField.IncludeSubfields {
+		// is the definition (fields + children) at all different?
+		return !util.CompareStructNumTypeAgnostic(newDefField, existingDefField), nil
+	}
+	// if it's a map, since we don't care about subfields, we just need to know if it's present in one and not the other
+	if newIsMap || existingIsMap {
+		return !newIsMap || !existingIsMap, nil // this means that one of them is nil
+	}
+	return !util.CompareStructNumTypeAgnostic(newDefField, existingDefField), nil
+}
+
+// ChangedSpecFields returns the paths of the fields in each of the USDE config's "recreate", "dataLoss", and "progressive" lists
+// which differ between the new and existing definitions
+func ChangedSpecFields(newDef, existingDef *unstructured.Unstructured) ([]string, []string, []string, error) {
+	usdeConfig := config.GetConfigManagerInstance().GetEffectiveUSDEConfig(newDef.GetNamespace())
+	kindConfig := usdeConfig[strings.ToLower(newDef.GetKind())]
+
+	recreateList, dataLossList, progressiveList, err := classifyVersionChanges(context.Background(), kindConfig.Recreate, kindConfig.DataLoss, kindConfig.Progressive, newDef, existingDef)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	recreateFields, err := findChangedFields(recreateList, newDef, existingDef)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	dataLossFields, err := findChangedFields(dataLossList, newDef, existingDef)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	progressiveFields, err := findChangedFields(progressiveList, newDef, existingDef)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return recreateFields, dataLossFields, progressiveFields, nil
 }
 
 func getMostConservativeStrategy(strategies []apiv1.UpgradeStrategy) apiv1.UpgradeStrategy {
@@ -236,10 +299,10 @@ func getMostConservativeStrategy(strategies []apiv1.UpgradeStrategy) apiv1.Upgra
 	return strategy
 }
 
-func resourceMetadataNeedsUpdating(ctx context.Context, newDef, existingDef *unstructured.Unstructured) (bool, apiv1.UpgradeStrategy, error) {
+func resourceMetadataNeedsUpdating(ctx context.Context, newDef, existingDef *unstructured.Unstructured, rolloutStrategy apiv1.UserUpgradeStrategy) (bool, apiv1.UpgradeStrategy, error) {
 	numaLogger := logger.FromContext(ctx)
 
-	upgradeStrategy, err := getDataLossUpgradeStrategy(ctx, newDef.GetNamespace(), existingDef.GetKind())
+	upgradeStrategy, err := getDataLossUpgradeStrategy(ctx, newDef.GetNamespace(), existingDef.GetKind(), rolloutStrategy)
 	if err != nil {
 		return false, apiv1.UpgradeStrategyError, err
 	}
@@ -276,7 +339,7 @@ func ResourceMetadataHasDataLossRisk(ctx context.Context, newDef, existingDef *u
 }
 
 // return required upgrade strategy, list of additions, modifications, deletions required
-func RidersNeedUpdating(ctx context.Context, namespace string, childKind string, childName string, newRiders []riders.Rider, existingRiders unstructured.UnstructuredList) (bool, apiv1.UpgradeStrategy, unstructured.UnstructuredList, unstructured.UnstructuredList, unstructured.UnstructuredList, error) {
+func RidersNeedUpdating(ctx context.Context, namespace string, childKind string, childName string, newRiders []riders.Rider, existingRiders unstructured.UnstructuredList, rolloutStrategy apiv1.UserUpgradeStrategy) (bool, apiv1.UpgradeStrategy, unstructured.UnstructuredList, unstructured.UnstructuredList, unstructured.UnstructuredList, error) {
 
 	numaLogger := logger.FromContext(ctx)
 
@@ -290,7 +353,7 @@ func RidersNeedUpdating(ctx context.Context, namespace string, childKind string,
 
 	// which upgrade strategy does user prefer for this type of Child Kind? find out if it's Progressive
 	// since some Riders, if changed, can invoke a Progressive strategy
-	dataLossUpgradeStrategy, err := getDataLossUpgradeStrategy(ctx, namespace, childKind)
+	dataLossUpgradeStrategy, err := getDataLossUpgradeStrategy(ctx, namespace, childKind, rolloutStrategy)
 	if err != nil {
 		return false, apiv1.UpgradeStrategyError, additionsRequired, modificationsRequired, deletionsRequired, err
 	}
@@ -386,8 +449,8 @@ func getNamesAndKinds(ulist unstructured.UnstructuredList) string {
 }
 
 // return the upgrade strategy that represents what the user prefers to do when there's a concern for data loss
-func getDataLossUpgradeStrategy(ctx context.Context, namespace, resourceKind string) (apiv1.UpgradeStrategy, error) {
-	userUpgradeStrategy, err := GetUserStrategy(ctx, namespace, resourceKind)
+func getDataLossUpgradeStrategy(ctx context.Context, namespace, resourceKind string, rolloutStrategy apiv1.UserUpgradeStrategy) (apiv1.UpgradeStrategy, error) {
+	userUpgradeStrategy, err := GetUserStrategy(ctx, namespace, resourceKind, rolloutStrategy)
 	if err != nil {
 		return apiv1.UpgradeStrategyError, err
 	}
@@ -404,28 +467,52 @@ func getDataLossUpgradeStrategy(ctx context.Context, namespace, resourceKind str
 	}
 }
 
-func GetUserStrategy(ctx context.Context, namespace, resourceKind string) (config.USDEUserStrategy, error) {
+// GetUserStrategy returns the upgrade strategy the user prefers for the resource (see ResolveUserStrategy)
+func GetUserStrategy(ctx context.Context, namespace, resourceKind string, rolloutStrategy apiv1.UserUpgradeStrategy) (config.USDEUserStrategy, error) {
+	effectiveStrategy, err := ResolveUserStrategy(ctx, namespace, resourceKind, rolloutStrategy)
+	if err != nil {
+		return config.NoStrategyID, err
+	}
+	return config.USDEUserStrategy(effectiveStrategy.Strategy), nil
+}
+
+// ResolveUserStrategy determines the upgrade strategy the user prefers for the resource, along with where it was configured.
+// The strategy selected by the Rollout takes precedence over the one configured for the namespace, which takes precedence
+// over the global default.
+func ResolveUserStrategy(ctx context.Context, namespace, resourceKind string, rolloutStrategy apiv1.UserUpgradeStrategy) (apiv1.EffectiveUpgradeStrategy, error) {
 	numaLogger := logger.FromContext(ctx)
 
 	namespaceConfig := config.GetConfigManagerInstance().GetNamespaceConfig(namespace)
 
 	globalConfig, err := config.GetConfigManagerInstance().GetConfig()
 	if err != nil {
-		return config.NoStrategyID, fmt.Errorf("error getting the global config: %v", err)
+		return apiv1.EffectiveUpgradeStrategy{}, fmt.Errorf("error getting the global config: %v", err)
 	}
 
 	var userUpgradeStrategy config.USDEUserStrategy = globalConfig.DefaultUpgradeStrategy
 	if userUpgradeStrategy == "" {
 		userUpgradeStrategy = config.NoStrategyID
 	}
+	source := apiv1.UpgradeStrategySourceGlobal
 	if namespaceConfig != nil {
 		if !namespaceConfig.UpgradeStrategy.IsValid() {
 			numaLogger.WithValues("upgrade strategy", namespaceConfig.UpgradeStrategy).Warnf("invalid Upgrade strategy for namespace %s", namespace)
 		} else {
 			userUpgradeStrategy = namespaceConfig.UpgradeStrategy
+			source = apiv1.UpgradeStrategySourceNamespace
+		}
+	}
+	if rolloutStrategy != "" {
+		if !config.USDEUserStrategy(rolloutStrategy).IsValid() {
+			numaLogger.WithValues("upgrade strategy", rolloutStrategy).Warn("invalid Upgrade strategy selected by Rollout")
+		} else {
+			userUpgradeStrategy = config.USDEUserStrategy(rolloutStrategy)
+			source = apiv1.UpgradeStrategySourceRollout
 		}
 	}
 
+	effectiveStrategy := apiv1.EffectiveUpgradeStrategy{Source: source}
+
 	// TODO: remove when FeatureFlagDisallowProgressiveForNonMonoVertex no longer needed
 	if userUpgradeStrategy == config.ProgressiveStrategyID &&
 		resourceKind != numaflowv1.MonoVertexGroupVersionKind.Kind &&
@@ -433,7 +520,9 @@ func GetUserStrategy(ctx context.Context, namespace, resourceKind string) (confi
 
 		// Use the next most conservative strategy: PPND
 		userUpgradeStrategy = config.PPNDStrategyID
+		effectiveStrategy.Message = fmt.Sprintf("%s strategy is not allowed for %s, using %s instead", config.ProgressiveStrategyID, resourceKind, config.PPNDStrategyID)
 	}
 
-	return userUpgradeStrategy, nil
+	effectiveStrategy.Strategy = apiv1.UserUpgradeStrategy(userUpgradeStrategy)
+	return effectiveStrategy, nil
 }
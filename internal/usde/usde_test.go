@@ -182,6 +182,7 @@ func Test_ResourceNeedsUpdating(t *testing.T) {
 		existingDefinition    unstructured.Unstructured
 		usdeConfig            config.USDEConfig
 		namespaceConfig       *config.NamespaceConfig
+		rolloutStrategy       apiv1.UserUpgradeStrategy
 		expectedNeedsUpdating bool
 		expectedStrategy      apiv1.UpgradeStrategy
 	}{
@@ -324,6 +325,61 @@ func Test_ResourceNeedsUpdating(t *testing.T) {
 			expectedNeedsUpdating: true,
 			expectedStrategy:      apiv1.UpgradeStrategyPPND,
 		},
+		{
+			name:          "with changes in array deep map selected by a filter",
+			newDefinition: *pipelineDefn.DeepCopy(),
+			existingDefinition: func() unstructured.Unstructured {
+				newRPU := int64(10)
+				newPipelineDef := defaultPipelineSpec.DeepCopy()
+				newPipelineDef.Vertices[0].Source.Generator.RPU = &newRPU
+				return makePipelineDefinition(*newPipelineDef)
+			}(),
+			usdeConfig: config.USDEConfig{
+				"pipeline": config.USDEResourceConfig{
+					DataLoss: []config.SpecField{{Path: "spec.vertices[?(@.name=='in')].source", IncludeSubfields: true}},
+				},
+			},
+			namespaceConfig:       &config.NamespaceConfig{UpgradeStrategy: "pause-and-drain"},
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyPPND,
+		},
+		{
+			name:          "with changes in array deep map not selected by a filter",
+			newDefinition: *pipelineDefn.DeepCopy(),
+			existingDefinition: func() unstructured.Unstructured {
+				newRPU := int64(10)
+				newPipelineDef := defaultPipelineSpec.DeepCopy()
+				newPipelineDef.Vertices[0].Source.Generator.RPU = &newRPU
+				return makePipelineDefinition(*newPipelineDef)
+			}(),
+			usdeConfig: config.USDEConfig{
+				"pipeline": config.USDEResourceConfig{
+					DataLoss: []config.SpecField{{Path: "spec.vertices[?(@.name!='in')]", IncludeSubfields: true}},
+				},
+			},
+			namespaceConfig:       &config.NamespaceConfig{UpgradeStrategy: "pause-and-drain"},
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyApply,
+		},
+		{
+			name:          "with changes in array deep map (map field) and strategy selected by the rollout",
+			newDefinition: *pipelineDefn.DeepCopy(),
+			existingDefinition: func() unstructured.Unstructured {
+				newRPU := int64(10)
+				newPipelineDef := defaultPipelineSpec.DeepCopy()
+				newPipelineDef.Vertices[0].Source.Generator.RPU = &newRPU
+				return makePipelineDefinition(*newPipelineDef)
+			}(),
+			usdeConfig: config.USDEConfig{
+				"pipeline": config.USDEResourceConfig{
+					DataLoss: []config.SpecField{{Path: "spec.vertices.source.generator", IncludeSubfields: true}},
+				},
+			},
+			namespaceConfig:       &config.NamespaceConfig{UpgradeStrategy: "pause-and-drain"},
+			rolloutStrategy:       apiv1.UserUpgradeStrategyProgressive,
+			expectedNeedsUpdating: true,
+			expectedStrategy:      apiv1.UpgradeStrategyProgressive,
+		},
 		{
 			name:          "with changes in array deep map (primitive field)",
 			newDefinition: *pipelineDefn.DeepCopy(),
@@ -562,7 +618,7 @@ func Test_ResourceNeedsUpdating(t *testing.T) {
 
 			// TODO: add some recreate test cases
 			// TODO: test riders
-			needsUpdating, strategy, _, _, _, _, err := ResourceNeedsUpdating(ctx, &tc.newDefinition, &tc.existingDefinition, []riders.Rider{}, unstructured.UnstructuredList{})
+			needsUpdating, strategy, _, _, _, _, err := ResourceNeedsUpdating(ctx, &tc.newDefinition, &tc.existingDefinition, []riders.Rider{}, unstructured.UnstructuredList{}, tc.rolloutStrategy)
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expectedNeedsUpdating, needsUpdating)
 			assert.Equal(t, tc.expectedStrategy, strategy)
@@ -570,6 +626,73 @@ func Test_ResourceNeedsUpdating(t *testing.T) {
 	}
 }
 
+func Test_ResolveUserStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	getwd, err := os.Getwd()
+	assert.Nil(t, err, "Failed to get working directory")
+	configPath := filepath.Join(getwd, "../../", "tests", "config")
+	configManager := config.GetConfigManagerInstance()
+	err = configManager.LoadAllConfigs(func(err error) {}, config.WithConfigsPath(configPath), config.WithConfigFileName("testconfig"))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name             string
+		namespaceConfig  *config.NamespaceConfig
+		rolloutStrategy  apiv1.UserUpgradeStrategy
+		expectedStrategy apiv1.UserUpgradeStrategy
+		expectedSource   apiv1.UpgradeStrategySource
+	}{
+		{
+			name:             "global default",
+			expectedStrategy: apiv1.UserUpgradeStrategyPPND,
+			expectedSource:   apiv1.UpgradeStrategySourceGlobal,
+		},
+		{
+			name:             "namespace overrides global",
+			namespaceConfig:  &config.NamespaceConfig{UpgradeStrategy: "no-strategy"},
+			expectedStrategy: apiv1.UserUpgradeStrategyNone,
+			expectedSource:   apiv1.UpgradeStrategySourceNamespace,
+		},
+		{
+			name:             "invalid namespace strategy is ignored",
+			namespaceConfig:  &config.NamespaceConfig{UpgradeStrategy: "invalid"},
+			expectedStrategy: apiv1.UserUpgradeStrategyPPND,
+			expectedSource:   apiv1.UpgradeStrategySourceGlobal,
+		},
+		{
+			name:             "rollout overrides namespace",
+			namespaceConfig:  &config.NamespaceConfig{UpgradeStrategy: "no-strategy"},
+			rolloutStrategy:  apiv1.UserUpgradeStrategyProgressive,
+			expectedStrategy: apiv1.UserUpgradeStrategyProgressive,
+			expectedSource:   apiv1.UpgradeStrategySourceRollout,
+		},
+		{
+			name:             "invalid rollout strategy is ignored",
+			namespaceConfig:  &config.NamespaceConfig{UpgradeStrategy: "no-strategy"},
+			rolloutStrategy:  "invalid",
+			expectedStrategy: apiv1.UserUpgradeStrategyNone,
+			expectedSource:   apiv1.UpgradeStrategySourceNamespace,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.namespaceConfig != nil {
+				configManager.UpdateNamespaceConfig(defaultNamespace, *tc.namespaceConfig)
+			} else {
+				configManager.UnsetNamespaceConfig(defaultNamespace)
+			}
+			effectiveStrategy, err := ResolveUserStrategy(ctx, defaultNamespace, "Pipeline", tc.rolloutStrategy)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedStrategy, effectiveStrategy.Strategy)
+			assert.Equal(t, tc.expectedSource, effectiveStrategy.Source)
+			assert.Empty(t, effectiveStrategy.Message)
+		})
+	}
+	configManager.UnsetNamespaceConfig(defaultNamespace)
+}
+
 func TestGetMostConservativeStrategy(t *testing.T) {
 	tests := []struct {
 		name                   string
@@ -607,3 +730,26 @@ func TestGetMostConservativeStrategy(t *testing.T) {
 		})
 	}
 }
+
+func Test_findChangedFields(t *testing.T) {
+	existingDef := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"interStepBufferServiceName": "my-isbsvc",
+			"lifecycle":                  map[string]interface{}{"desiredPhase": "Running"},
+			"watermark":                  map[string]interface{}{"maxDelay": "1s"},
+		},
+	}}
+	newDef := existingDef.DeepCopy()
+	newDef.Object["spec"].(map[string]interface{})["interStepBufferServiceName"] = "my-isbsvc-2"
+	newDef.Object["spec"].(map[string]interface{})["watermark"] = map[string]interface{}{"maxDelay": "5s"}
+
+	specFields := []config.SpecField{
+		{Path: "spec.interStepBufferServiceName"},
+		{Path: "spec.lifecycle", IncludeSubfields: true},
+		{Path: "spec.watermark"}, // subfields are excluded, and the map is present in both
+		{Path: "spec.watermark.maxDelay"},
+	}
+	changedFields, err := findChangedFields(specFields, newDef, existingDef)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"spec.interStepBufferServiceName", "spec.watermark.maxDelay"}, changedFields)
+}
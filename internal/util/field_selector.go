@@ -0,0 +1,337 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type selectorTokenType int
+
+const (
+	// a field of a map
+	selectorTokenField selectorTokenType = iota
+	// every element of a list: [*]
+	selectorTokenWildcard
+	// a single element of a list: [N]
+	selectorTokenIndex
+	// the elements of a list which match a filter: [?(@.field=='value')]
+	selectorTokenFilter
+)
+
+type selectorToken struct {
+	tokenType selectorTokenType
+	field     string
+	index     int
+	filter    *selectorFilter
+}
+
+type selectorFilter struct {
+	path     []string
+	negate   bool
+	value    any
+	hasValue bool
+}
+
+// FieldSelector is a compiled JSONPath-style selector of fields within an object, such as
+// "spec.vertices[*].udf.container.image" or "spec.vertices[?(@.name=='in')].source".
+//
+// Supported syntax:
+//   - dotted field names, optionally preceded by "$."
+//   - "[*]" to select every element of a list
+//   - "[N]" to select the element of a list at index N
+//   - "[?(@.a.b=='value')]" or "[?(@.a.b!='value')]" to select the elements of a list whose field matches (or doesn't match) a
+//     string, number, or boolean literal, and "[?(@.a.b)]" to select the elements in which the field is present
+//
+// As with ExtractPath, a field name applied to a list is applied to each of its elements.
+type FieldSelector struct {
+	path   string
+	tokens []selectorToken
+}
+
+// ParseFieldSelector compiles the selector, returning an error if its syntax is invalid
+func ParseFieldSelector(path string) (*FieldSelector, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("empty field selector")
+	}
+	tokens, err := tokenizeSelector(strings.TrimPrefix(path, "$."))
+	if err != nil {
+		return nil, fmt.Errorf("invalid field selector %q: %v", path, err)
+	}
+	return &FieldSelector{path: path, tokens: tokens}, nil
+}
+
+// String returns the selector as it was written
+func (selector *FieldSelector) String() string {
+	return selector.path
+}
+
+// Extract returns the portion of the data selected, in the same form as ExtractPath: maps are reduced to the fields along the path,
+// and lists are reduced to the elements selected.
+// It also returns whether the inner most element(s) selected are maps.
+func (selector *FieldSelector) Extract(data any) (any, bool, error) {
+	return extractTokens(data, selector.tokens)
+}
+
+func extractTokens(data any, tokens []selectorToken) (any, bool, error) {
+	if len(tokens) == 0 || data == nil {
+		isMap := false
+		if data != nil {
+			isMap = reflect.TypeOf(data).Kind() == reflect.Map
+		}
+		return data, isMap, nil
+	}
+
+	v := reflect.ValueOf(data)
+	token := tokens[0]
+
+	switch v.Kind() {
+	case reflect.Map:
+		if token.tokenType != selectorTokenField {
+			// a list selector doesn't apply to a map, so nothing is selected
+			return nil, false, nil
+		}
+		value := v.MapIndex(reflect.ValueOf(token.field))
+		if !value.IsValid() {
+			return nil, false, nil
+		}
+		extracted, isMap, err := extractTokens(value.Interface(), tokens[1:])
+		if err != nil {
+			return nil, false, err
+		}
+		return map[string]any{token.field: extracted}, isMap, nil
+
+	case reflect.Slice:
+		var selected []any
+		var remaining []selectorToken
+		switch token.tokenType {
+		case selectorTokenField:
+			// apply the field to each element of the list
+			remaining = tokens
+			for i := 0; i < v.Len(); i++ {
+				selected = append(selected, v.Index(i).Interface())
+			}
+		case selectorTokenWildcard:
+			remaining = tokens[1:]
+			for i := 0; i < v.Len(); i++ {
+				selected = append(selected, v.Index(i).Interface())
+			}
+		case selectorTokenIndex:
+			if token.index >= v.Len() {
+				return nil, false, nil
+			}
+			remaining = tokens[1:]
+			selected = []any{v.Index(token.index).Interface()}
+		case selectorTokenFilter:
+			remaining = tokens[1:]
+			for i := 0; i < v.Len(); i++ {
+				if token.filter.matches(v.Index(i).Interface()) {
+					selected = append(selected, v.Index(i).Interface())
+				}
+			}
+		}
+
+		s := make([]any, len(selected))
+		atLeastOneIsMap := false
+		for i, element := range selected {
+			extracted, isMap, err := extractTokens(element, remaining)
+			if err != nil {
+				return nil, false, err
+			}
+			s[i] = extracted
+			if isMap {
+				atLeastOneIsMap = true
+			}
+		}
+		return s, atLeastOneIsMap, nil
+
+	default:
+		return nil, false, fmt.Errorf("invalid type encountered: %s", v.Kind().String())
+	}
+}
+
+func (filter *selectorFilter) matches(element any) bool {
+	value, found := element, true
+	for _, field := range filter.path {
+		m, ok := value.(map[string]any)
+		if !ok {
+			found = false
+			break
+		}
+		value, found = m[field]
+		if !found {
+			break
+		}
+	}
+
+	if !filter.hasValue {
+		return found
+	}
+	equal := found && CompareStructNumTypeAgnostic(value, filter.value)
+	return equal != filter.negate
+}
+
+// tokenizeSelector splits the selector into fields and bracketed list selectors
+func tokenizeSelector(path string) ([]selectorToken, error) {
+	tokens := []selectorToken{}
+	field := strings.Builder{}
+	// whether a field name is expected next (at the start, or after a '.')
+	expectField := true
+
+	flushField := func() error {
+		if field.Len() == 0 {
+			if expectField {
+				return fmt.Errorf("empty field name")
+			}
+			return nil
+		}
+		tokens = append(tokens, selectorToken{tokenType: selectorTokenField, field: field.String()})
+		field.Reset()
+		expectField = false
+		return nil
+	}
+
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '.':
+			if err := flushField(); err != nil {
+				return nil, err
+			}
+			expectField = true
+		case '[':
+			// a list selector follows either a field name or another list selector
+			if err := flushField(); err != nil {
+				return nil, fmt.Errorf("expected a field name before '['")
+			}
+			end, err := findClosingBracket(path, i)
+			if err != nil {
+				return nil, err
+			}
+			token, err := parseBracket(path[i+1 : end])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token)
+			expectField = false
+			i = end
+			if i+1 < len(path) && path[i+1] != '.' && path[i+1] != '[' {
+				return nil, fmt.Errorf("unexpected character %q after ']'", path[i+1])
+			}
+		case ']':
+			return nil, fmt.Errorf("unexpected ']'")
+		default:
+			field.WriteByte(path[i])
+		}
+	}
+	if err := flushField(); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// findClosingBracket returns the index of the ']' which closes the '[' at index start, ignoring any within quotes
+func findClosingBracket(path string, start int) (int, error) {
+	var quote byte
+	for i := start + 1; i < len(path); i++ {
+		switch {
+		case quote != 0:
+			if path[i] == quote {
+				quote = 0
+			}
+		case path[i] == '\'' || path[i] == '"':
+			quote = path[i]
+		case path[i] == ']':
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unterminated '['")
+}
+
+func parseBracket(contents string) (selectorToken, error) {
+	contents = strings.TrimSpace(contents)
+	switch {
+	case contents == "*":
+		return selectorToken{tokenType: selectorTokenWildcard}, nil
+	case strings.HasPrefix(contents, "?(") && strings.HasSuffix(contents, ")"):
+		filter, err := parseFilter(strings.TrimSpace(contents[2 : len(contents)-1]))
+		if err != nil {
+			return selectorToken{}, err
+		}
+		return selectorToken{tokenType: selectorTokenFilter, filter: filter}, nil
+	default:
+		index, err := strconv.Atoi(contents)
+		if err != nil || index < 0 {
+			return selectorToken{}, fmt.Errorf("unsupported list selector [%s]: expected [*], a non-negative index, or a filter", contents)
+		}
+		return selectorToken{tokenType: selectorTokenIndex, index: index}, nil
+	}
+}
+
+// parseFilter parses a filter expression of the form "@.a.b", "@.a.b==literal", or "@.a.b!=literal"
+func parseFilter(expression string) (*selectorFilter, error) {
+	filter := &selectorFilter{}
+
+	// the operand can't contain an operator, so the first one found is the filter's (any later one is within the literal)
+	operand := expression
+	equalIndex, notEqualIndex := strings.Index(expression, "=="), strings.Index(expression, "!=")
+	if notEqualIndex >= 0 && (equalIndex < 0 || notEqualIndex < equalIndex) {
+		operand = expression[:notEqualIndex]
+		filter.negate = true
+		filter.hasValue = true
+		expression = expression[notEqualIndex+2:]
+	} else if equalIndex >= 0 {
+		operand = expression[:equalIndex]
+		filter.hasValue = true
+		expression = expression[equalIndex+2:]
+	}
+
+	operand = strings.TrimSpace(operand)
+	if !strings.HasPrefix(operand, "@.") || len(operand) == 2 {
+		return nil, fmt.Errorf("invalid filter %q: expected a field of the current element, such as @.name", operand)
+	}
+	filter.path = strings.Split(operand[2:], ".")
+	for _, field := range filter.path {
+		if field == "" {
+			return nil, fmt.Errorf("invalid filter %q: empty field name", operand)
+		}
+	}
+
+	if filter.hasValue {
+		value, err := parseLiteral(strings.TrimSpace(expression))
+		if err != nil {
+			return nil, err
+		}
+		filter.value = value
+	}
+	return filter, nil
+}
+
+func parseLiteral(literal string) (any, error) {
+	if len(literal) >= 2 && (literal[0] == '\'' || literal[0] == '"') && literal[len(literal)-1] == literal[0] {
+		return literal[1 : len(literal)-1], nil
+	}
+	if b, err := strconv.ParseBool(literal); err == nil {
+		return b, nil
+	}
+	if f, err := strconv.ParseFloat(literal, 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("invalid filter value %q: expected a quoted string, number, or boolean", literal)
+}
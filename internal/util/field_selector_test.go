@@ -0,0 +1,126 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseFieldSelector(t *testing.T) {
+	validSelectors := []string{
+		"spec.interStepBufferServiceName",
+		"$.spec.vertices",
+		"spec.vertices[*].udf.container.image",
+		"spec.vertices[0].scale",
+		"spec.vertices[?(@.name=='in')].source",
+		"spec.vertices[?(@.name != \"in\")].scale.max",
+		"spec.vertices[?(@.scale.min==2)]",
+		"spec.vertices[?(@.udf)].udf.container.image",
+		"spec.vertices[?(@.name=='a.b[0]')]",
+		"spec.matrix[*][0]",
+	}
+	for _, path := range validSelectors {
+		_, err := ParseFieldSelector(path)
+		assert.NoError(t, err, path)
+	}
+
+	invalidSelectors := []string{
+		"",
+		"spec..vertices",
+		"spec.vertices.",
+		"[*].spec",
+		"spec.[*]",
+		"spec.vertices[",
+		"spec.vertices]",
+		"spec.vertices[x]",
+		"spec.vertices[-1]",
+		"spec.vertices[*]name",
+		"spec.vertices[?(name=='in')]",
+		"spec.vertices[?(@.name==in)]",
+		"spec.vertices[?(@.==1)]",
+	}
+	for _, path := range invalidSelectors {
+		_, err := ParseFieldSelector(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func Test_FieldSelector_Extract(t *testing.T) {
+	spec := map[string]any{
+		"spec": map[string]any{
+			"vertices": []any{
+				map[string]any{"name": "in", "source": map[string]any{"generator": map[string]any{"rpu": int64(5)}}, "scale": map[string]any{"min": int64(1)}},
+				map[string]any{"name": "cat", "udf": map[string]any{"container": map[string]any{"image": "cat:v1"}}, "scale": map[string]any{"min": int64(2)}},
+				map[string]any{"name": "out", "sink": map[string]any{"log": map[string]any{}}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		path          string
+		expected      any
+		expectedIsMap bool
+	}{
+		{
+			name: "dotted path applies to each list element",
+			path: "spec.vertices.name",
+			expected: map[string]any{"spec": map[string]any{"vertices": []any{
+				map[string]any{"name": "in"}, map[string]any{"name": "cat"}, map[string]any{"name": "out"},
+			}}},
+		},
+		{
+			name: "wildcard",
+			path: "spec.vertices[*].udf.container.image",
+			expected: map[string]any{"spec": map[string]any{"vertices": []any{
+				nil, map[string]any{"udf": map[string]any{"container": map[string]any{"image": "cat:v1"}}}, nil,
+			}}},
+		},
+		{
+			name: "index",
+			path: "spec.vertices[2].sink",
+			expected: map[string]any{"spec": map[string]any{"vertices": []any{
+				map[string]any{"sink": map[string]any{"log": map[string]any{}}},
+			}}},
+			expectedIsMap: true,
+		},
+		{
+			name:     "index out of range",
+			path:     "spec.vertices[3].sink",
+			expected: map[string]any{"spec": map[string]any{"vertices": nil}},
+		},
+		{
+			name: "filter by string",
+			path: "spec.vertices[?(@.name=='in')].source",
+			expected: map[string]any{"spec": map[string]any{"vertices": []any{
+				map[string]any{"source": map[string]any{"generator": map[string]any{"rpu": int64(5)}}},
+			}}},
+			expectedIsMap: true,
+		},
+		{
+			name: "filter by number, negated",
+			path: "spec.vertices[?(@.scale.min!=2)].name",
+			expected: map[string]any{"spec": map[string]any{"vertices": []any{
+				map[string]any{"name": "in"}, map[string]any{"name": "out"},
+			}}},
+		},
+		{
+			name: "filter by presence",
+			path: "$.spec.vertices[?(@.udf)].name",
+			expected: map[string]any{"spec": map[string]any{"vertices": []any{
+				map[string]any{"name": "cat"},
+			}}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			selector, err := ParseFieldSelector(tc.path)
+			assert.NoError(t, err)
+			extracted, isMap, err := selector.Extract(spec)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, extracted)
+			assert.Equal(t, tc.expectedIsMap, isMap)
+		})
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"k8s.io/client-go/rest"
 )
 
-var DynamicClient *dynamic.DynamicClient
+var DynamicClient dynamic.Interface
 var KubernetesClient *clientkube.Clientset
 var NumaplaneClient *versioned.Clientset
 
@@ -17,6 +17,7 @@ import (
 	"github.com/numaproj/numaplane/internal/controller/config"
 	"github.com/numaproj/numaplane/internal/util"
 	"github.com/numaproj/numaplane/internal/util/logger"
+	"github.com/numaproj/numaplane/internal/util/metrics"
 )
 
 // StartConfigMapWatcher will start a watcher for ConfigMaps with the given label key and value
@@ -131,6 +132,13 @@ func handleUSDEConfigMapEvent(configMap *corev1.ConfigMap, event watch.Event) er
 			if err != nil {
 				return fmt.Errorf("error unmarshalling USDE Config for '%s': %v", key, err)
 			}
+			if err := usdeResourceConfig.Compile(); err != nil {
+				var ruleErr *config.ChangeRuleCompileError
+				if errors.As(err, &ruleErr) {
+					metrics.IncUSDERuleErrors(key, ruleErr.RuleName, metrics.LabelValueUSDERuleCompile)
+				}
+				return fmt.Errorf("error in USDE Config for '%s': %v", key, err)
+			}
 
 			usdeConfig[key] = usdeResourceConfig
 		}
@@ -155,6 +163,22 @@ func handleNamespaceConfigMapEvent(configMap *corev1.ConfigMap, event watch.Even
 			return fmt.Errorf("error converting Namespace-level ConfigMap: %v", err)
 		}
 
+		if usdeOverrides, found := configMap.Data[common.NamespaceConfigMapUSDEKey]; found {
+			if err := yaml.Unmarshal([]byte(usdeOverrides), &namespaceConfig.USDE); err != nil {
+				return fmt.Errorf("error unmarshalling USDE overrides in Namespace-level ConfigMap: %v", err)
+			}
+			for kind, override := range namespaceConfig.USDE {
+				if err := override.Compile(); err != nil {
+					var ruleErr *config.ChangeRuleCompileError
+					if errors.As(err, &ruleErr) {
+						metrics.IncUSDERuleErrors(kind, ruleErr.RuleName, metrics.LabelValueUSDERuleCompile)
+					}
+					return fmt.Errorf("error in USDE overrides for '%s' in Namespace-level ConfigMap: %v", kind, err)
+				}
+				namespaceConfig.USDE[kind] = override
+			}
+		}
+
 		config.GetConfigManagerInstance().UpdateNamespaceConfig(configMap.Namespace, namespaceConfig)
 	} else if event.Type == watch.Deleted {
 		config.GetConfigManagerInstance().UnsetNamespaceConfig(configMap.Namespace)
@@ -10,6 +10,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/numaproj/numaplane/internal/common"
@@ -126,3 +127,57 @@ func Test_watchConfigMaps(t *testing.T) {
 	actualUSDEConfig = config.GetConfigManagerInstance().GetUSDEConfig()
 	assert.Equal(t, config.USDEConfig{}, actualUSDEConfig)
 }
+
+func Test_handleUSDEConfigMapEvent_invalidSelector(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "numaplane-controller-usde-config", Namespace: "default"},
+		Data: map[string]string{
+			"pipeline": "dataLoss:\n  - path: spec.vertices[?(@.name=='in')].source\n  - path: spec.vertices[x].scale\n",
+		},
+	}
+	err := handleUSDEConfigMapEvent(configMap, watch.Event{Type: watch.Added})
+	assert.ErrorContains(t, err, "spec.vertices[x].scale")
+
+	configMap.Data["pipeline"] = "dataLoss:\n  - path: spec.vertices[?(@.name=='in')].source\n  - path: spec.vertices[*].scale\n"
+	err = handleUSDEConfigMapEvent(configMap, watch.Event{Type: watch.Added})
+	assert.NoError(t, err)
+	assert.Len(t, config.GetConfigManagerInstance().GetUSDEConfig()["pipeline"].DataLoss, 2)
+}
+
+func Test_handleUSDEConfigMapEvent_invalidRule(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "numaplane-controller-usde-config", Namespace: "default"},
+		Data: map[string]string{
+			"pipeline": "rules:\n  - name: bad-rule\n    expression: \"new.spec.vertices.size() <\"\n",
+		},
+	}
+	err := handleUSDEConfigMapEvent(configMap, watch.Event{Type: watch.Added})
+	assert.ErrorContains(t, err, "bad-rule")
+
+	configMap.Data["pipeline"] = "rules:\n  - name: good-rule\n    expression: \"new.spec.vertices.size() < old.spec.vertices.size() ? 'recreate' : ''\"\n"
+	err = handleUSDEConfigMapEvent(configMap, watch.Event{Type: watch.Added})
+	assert.NoError(t, err)
+	assert.Len(t, config.GetConfigManagerInstance().GetUSDEConfig()["pipeline"].Rules, 1)
+}
+
+func Test_handleNamespaceConfigMapEvent_usdeOverrides(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "numaplane-controller-config", Namespace: "my-namespace"},
+		Data: map[string]string{
+			"upgradeStrategy": "progressive",
+			"usde":            "pipeline:\n  remove:\n    - spec.edges\n  progressive:\n    - path: spec.vertices[*].udf.container.image\n",
+		},
+	}
+	err := handleNamespaceConfigMapEvent(configMap, watch.Event{Type: watch.Added})
+	assert.NoError(t, err)
+	defer config.GetConfigManagerInstance().UnsetNamespaceConfig("my-namespace")
+
+	namespaceConfig := config.GetConfigManagerInstance().GetNamespaceConfig("my-namespace")
+	assert.Equal(t, config.ProgressiveStrategyID, namespaceConfig.UpgradeStrategy)
+	assert.Equal(t, []string{"spec.edges"}, namespaceConfig.USDE["pipeline"].Remove)
+	assert.Len(t, namespaceConfig.USDE["pipeline"].Progressive, 1)
+
+	configMap.Data["usde"] = "pipeline:\n  progressive:\n    - path: spec.vertices[\n"
+	err = handleNamespaceConfigMapEvent(configMap, watch.Event{Type: watch.Modified})
+	assert.ErrorContains(t, err, "pipeline")
+}
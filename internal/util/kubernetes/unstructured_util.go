@@ -261,7 +261,8 @@ func CalculateHash(ctx context.Context, resource unstructured.Unstructured) (str
 	}
 
 	hashVal := hex.EncodeToString(h.Sum(nil))
-	numaLogger.WithValues("resource name", resource.GetName(), "hash", hashVal).Debugf("derived hash from resource: %+v", resource)
+	// the resource itself isn't logged, since it may contain values from Secrets
+	numaLogger.WithValues("resource kind", resource.GetKind(), "resource name", resource.GetName(), "hash", hashVal).Debug("derived hash from resource")
 	return hashVal, nil
 }
 
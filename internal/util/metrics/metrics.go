@@ -3,6 +3,7 @@ package metrics
 import (
 	"strconv"
 	"sync"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -92,11 +93,16 @@ type CustomMetrics struct {
 	PipelinePausingSeconds *prometheus.GaugeVec
 	// ISBServicePausedSeconds counts the total time an ISBService requested resources be paused.
 	ISBServicePausedSeconds *prometheus.GaugeVec
+	// PipelinePauseHolderSeconds counts the total time each active pause holder has been holding a Pipeline paused.
+	PipelinePauseHolderSeconds *prometheus.GaugeVec
 
 	// Progressive Rollout Metrics
 	PipelineProgressiveResults   *prometheus.CounterVec
 	IsbSvcProgressiveResults     *prometheus.CounterVec
 	MonoVertexProgressiveResults *prometheus.CounterVec
+
+	// USDERuleErrors counts the errors compiling or evaluating USDE rules
+	USDERuleErrors *prometheus.CounterVec
 }
 
 const (
@@ -121,6 +127,15 @@ const (
 	LabelForcedSuccess             = "forcedSuccess"
 	LabelResourceHealthSuccess     = "resourceHealthSuccess"
 	LabelCompleted                 = "completed"
+	LabelKind                      = "kind"
+	LabelHolderKind                = "holder_kind"
+	LabelHolderName                = "holder_name"
+	LabelReason                    = "reason"
+	LabelGeneration                = "generation"
+
+	// values of LabelType for USDE rule errors
+	LabelValueUSDERuleCompile    = "compile"
+	LabelValueUSDERuleEvaluation = "evaluation"
 )
 
 var (
@@ -246,6 +261,13 @@ var (
 		ConstLabels: defaultLabels,
 	}, []string{LabelName, LabelNamespace})
 
+	// pipelinePauseHolderSeconds Check the total time each active pause holder has been holding a pipeline paused
+	pipelinePauseHolderSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name:        "numaflow_pipeline_pause_holder_seconds",
+		Help:        "Duration a pause holder has been holding a pipeline paused for",
+		ConstLabels: defaultLabels,
+	}, []string{LabelNamespace, LabelName, LabelHolderKind, LabelHolderName, LabelReason, LabelGeneration})
+
 	// monoVertexRolloutsRunning is the gauge for the number of MonoVertexRollouts.
 	monoVertexRolloutsRunning = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name:        "monovertex_rollouts_running",
@@ -371,6 +393,13 @@ var (
 		Help:        "The total number of monovertex progressive rollout results",
 		ConstLabels: defaultLabels,
 	}, []string{LabelNamespace, LabelName, LabelRolloutName, LabelSuccess, LabelForcedSuccess, LabelResourceHealthSuccess, LabelCompleted})
+
+	// usdeRuleErrors count the total number of errors compiling or evaluating USDE rules
+	usdeRuleErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:        "numaplane_usde_rule_errors_total",
+		Help:        "The total number of errors compiling or evaluating USDE rules",
+		ConstLabels: defaultLabels,
+	}, []string{LabelKind, LabelName, LabelType})
 )
 
 // RegisterCustomMetrics registers the custom metrics to the existing global prometheus registry for pipelines, ISB service and numaflow controller
@@ -382,8 +411,8 @@ func RegisterCustomMetrics(numaLogger *logger.NumaLogger) *CustomMetrics {
 		numaflowControllerRolloutsHealth, numaflowControllerRolloutsRunning, numaflowControllerRolloutSyncs, numaflowControllerRolloutSyncErrors, numaflowControllerRolloutPausedSeconds,
 		numaflowControllersHealth, numaflowControllerSyncs, numaflowControllerSyncErrors, numaflowControllerKubectlExecutionCounter,
 		reconciliationDuration, kubeRequestCounter, kubeResourceCacheMonitored,
-		kubeResourceCache, clusterCacheError, pipelinePausedSeconds, pipelinePausingSeconds, isbServicePausedSeconds, pipelineProgressiveResults,
-		isbSvcProgressiveResults, monoVertexProgressiveResults)
+		kubeResourceCache, clusterCacheError, pipelinePausedSeconds, pipelinePausingSeconds, isbServicePausedSeconds, pipelinePauseHolderSeconds, pipelineProgressiveResults,
+		isbSvcProgressiveResults, monoVertexProgressiveResults, usdeRuleErrors)
 
 	return &CustomMetrics{
 		NumaLogger:                                numaLogger,
@@ -421,9 +450,11 @@ func RegisterCustomMetrics(numaLogger *logger.NumaLogger) *CustomMetrics {
 		PipelinePausedSeconds:                     pipelinePausedSeconds,
 		PipelinePausingSeconds:                    pipelinePausingSeconds,
 		ISBServicePausedSeconds:                   isbServicePausedSeconds,
+		PipelinePauseHolderSeconds:                pipelinePauseHolderSeconds,
 		PipelineProgressiveResults:                pipelineProgressiveResults,
 		IsbSvcProgressiveResults:                  isbSvcProgressiveResults,
 		MonoVertexProgressiveResults:              monoVertexProgressiveResults,
+		USDERuleErrors:                            usdeRuleErrors,
 	}
 }
 
@@ -596,6 +627,17 @@ func (m *CustomMetrics) DeleteNumaflowControllersHealth(namespace, name string)
 	}
 }
 
+// SetPipelinePauseHolder sets the time the pause holder has been holding the PipelineRollout's Pipeline paused
+func (m *CustomMetrics) SetPipelinePauseHolder(namespace, name string, holder apiv1.PauseHolder) {
+	m.PipelinePauseHolderSeconds.WithLabelValues(namespace, name, holder.Kind, holder.Name, holder.Reason, strconv.FormatInt(holder.Generation, 10)).
+		Set(time.Since(holder.StartTime.Time).Seconds())
+}
+
+// DeletePipelinePauseHolder deletes the metric for a pause holder which is no longer holding the PipelineRollout's Pipeline paused
+func (m *CustomMetrics) DeletePipelinePauseHolder(namespace, name string, holder apiv1.PauseHolder) {
+	m.PipelinePauseHolderSeconds.DeleteLabelValues(namespace, name, holder.Kind, holder.Name, holder.Reason, strconv.FormatInt(holder.Generation, 10))
+}
+
 func (m *CustomMetrics) IncProgressivePipelineDrains(namespace, pipelineRolloutName, pipelineName string, drainComplete bool, drainResult LabelValueDrainResult) {
 	m.ProgressivePipelineDrains.WithLabelValues(namespace, pipelineRolloutName, pipelineName, strconv.FormatBool(drainComplete), string(drainResult)).Inc()
 }
@@ -612,6 +654,12 @@ func (m *CustomMetrics) IncMonovertexProgressiveResults(namespace, name, childNa
 	m.MonoVertexProgressiveResults.WithLabelValues(namespace, childName, name, successStatus.ToString(), strconv.FormatBool(forcedSuccess), basicAssessmentResult.ToString(), strconv.FormatBool(completed)).Inc()
 }
 
+// IncUSDERuleErrors increments the count of errors for a USDE rule.
+// This is a package-level function since USDE rules are compiled when the USDE ConfigMap is loaded, outside of any controller.
+func IncUSDERuleErrors(kind, ruleName, errorType string) {
+	usdeRuleErrors.WithLabelValues(kind, ruleName, errorType).Inc()
+}
+
 func EvaluateSuccessStatusForMetrics(assessmentResult apiv1.AssessmentResult) util.OptionalBoolStr {
 	if assessmentResult == apiv1.AssessmentResultSuccess {
 		return "true"
@@ -0,0 +1,321 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// Templated definitions are Go templates (https://pkg.go.dev/text/template) which are evaluated in every string of the
+// definition (including map keys), with the sprig functions (https://masterminds.github.io/sprig/) available.
+// The template arguments are keyed by the way they're referenced, e.g. ".pipeline-name". Since a name containing a dash
+// isn't a valid field name in a Go template, such references are rewritten as a call to the "variable" function, so
+// "{{.pipeline-name | upper}}" becomes "{{(variable "pipeline-name") | upper}}".
+//
+// To remain compatible with definitions written before templates were Go templates, a plain reference to a variable which
+// isn't one of the arguments (e.g. "{{.unknown}}") resolves to "", and a string containing "{{" which doesn't close every
+// action with "}}" isn't a template, and is left as is. An unknown variable referenced in any other way is an error.
+
+const templateVariableFunc = "variable"
+
+var templateVariableRegex = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+
+// an action in a templated string
+var templateActionRegex = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+
+// an action which is nothing but a reference to a variable, e.g. "{{ .pipeline-name }}"
+var plainVariableRegex = regexp.MustCompile(`\{\{\s*\.([a-zA-Z_][a-zA-Z0-9_]*(?:-[a-zA-Z0-9_]+)*)\s*\}\}`)
+
+// inside an action, either a string literal, which is left as is, or a reference to a variable whose name contains a dash
+var dashedVariableRegex = regexp.MustCompile("\"(?:[^\"\\\\]|\\\\.)*\"|`[^`]*`|" +
+	`([\s({|])\.([a-zA-Z_][a-zA-Z0-9_]*(?:-[a-zA-Z0-9_]+)+)`)
+
+// Templates are resolved on every reconciliation and the result is compared with the existing child, so the same arguments must
+// always resolve to the same value: these functions are excluded on top of those which sprig considers non-hermetic
+var nonDeterministicTemplateFuncs = []string{
+	"ago", "durationRound", "randInt", "shuffle", "bcrypt", "htpasswd", "encryptAES",
+	"genPrivateKey", "genCA", "genCAWithKey", "genSelfSignedCert", "genSelfSignedCertWithKey", "genSignedCert", "genSignedCertWithKey",
+}
+
+var templateFuncs = func() template.FuncMap {
+	funcs := sprig.HermeticTxtFuncMap()
+	for _, name := range nonDeterministicTemplateFuncs {
+		delete(funcs, name)
+	}
+	return funcs
+}()
+
+// removes any whitespace around the templated variables in the definition of a resource (e.g. "{{ .pipeline-name }}"),
+// so that the definition is stored the same way however it's written
+func NormalizeTemplateVariables(definition string) string {
+	return templateVariableRegex.ReplaceAllString(definition, "{{$1}}")
+}
+
+// rewrite the references to variables whose names contain a dash as calls to the "variable" function
+func rewriteDashedVariables(value string) string {
+	return templateActionRegex.ReplaceAllStringFunc(value, func(action string) string {
+		return dashedVariableRegex.ReplaceAllStringFunc(action, func(match string) string {
+			submatches := dashedVariableRegex.FindStringSubmatch(match)
+			if submatches[2] == "" {
+				return match // a string literal
+			}
+			return fmt.Sprintf("%s(%s %q)", submatches[1], templateVariableFunc, submatches[2])
+		})
+	})
+}
+
+func parseTemplatedString(value string, args map[string]interface{}) (*template.Template, error) {
+	variable := func(name string) (interface{}, error) {
+		arg, found := args["."+name]
+		if !found {
+			return nil, fmt.Errorf("unknown template variable %q", "."+name)
+		}
+		return arg, nil
+	}
+	return template.New("").Option("missingkey=error").Funcs(templateFuncs).
+		Funcs(template.FuncMap{templateVariableFunc: variable}).Parse(rewriteDashedVariables(value))
+}
+
+// whether the string is a template: it contains at least one action, and every "{{" opens an action which is closed
+func isTemplatedString(value string) bool {
+	return strings.Contains(value, "{{") && !strings.Contains(templateActionRegex.ReplaceAllString(value, ""), "{{")
+}
+
+func resolveTemplatedString(value string, args map[string]interface{}) (string, error) {
+	if !isTemplatedString(value) {
+		return value, nil
+	}
+	// a plain reference to an unknown variable resolves to ""
+	value = plainVariableRegex.ReplaceAllStringFunc(value, func(action string) string {
+		if _, found := args["."+plainVariableRegex.FindStringSubmatch(action)[1]]; !found {
+			return ""
+		}
+		return action
+	})
+	tmpl, err := parseTemplatedString(value, args)
+	if err != nil {
+		return "", err
+	}
+	data := make(map[string]interface{}, len(args))
+	for name, arg := range args {
+		data[strings.TrimPrefix(name, ".")] = arg
+	}
+	var resolved strings.Builder
+	if err := tmpl.Execute(&resolved, data); err != nil {
+		return "", err
+	}
+	return resolved.String(), nil
+}
+
+// resolve every string of the unmarshaled JSON, including map keys
+func resolveTemplatedValue(value interface{}, args map[string]interface{}) (interface{}, error) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(typedValue))
+		for key, elem := range typedValue {
+			resolvedKey, err := resolveTemplatedString(key, args)
+			if err != nil {
+				return nil, err
+			}
+			resolvedElem, err := resolveTemplatedValue(elem, args)
+			if err != nil {
+				return nil, err
+			}
+			resolved[resolvedKey] = resolvedElem
+		}
+		return resolved, nil
+	case []interface{}:
+		resolved := make([]interface{}, len(typedValue))
+		for i, elem := range typedValue {
+			resolvedElem, err := resolveTemplatedValue(elem, args)
+			if err != nil {
+				return nil, err
+			}
+			resolved[i] = resolvedElem
+		}
+		return resolved, nil
+	case string:
+		return resolveTemplatedString(typedValue, args)
+	default:
+		return value, nil
+	}
+}
+
+// call the function for every string of the unmarshaled JSON, including map keys, in a consistent order
+func forEachTemplatedString(value interface{}, f func(string) error) error {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(typedValue))
+		for key := range typedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := f(key); err != nil {
+				return err
+			}
+			if err := forEachTemplatedString(typedValue[key], f); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, elem := range typedValue {
+			if err := forEachTemplatedString(elem, f); err != nil {
+				return err
+			}
+		}
+	case string:
+		return f(typedValue)
+	}
+	return nil
+}
+
+// collect the names of the template variables referenced in the parsed template: references relative to "." are only
+// collected where "." is still the template arguments, i.e. not inside the body of a "range" or "with" action
+func collectTemplateVariables(node parse.Node, rootDot bool, found func(name string)) {
+	switch typedNode := node.(type) {
+	case *parse.ListNode:
+		if typedNode == nil {
+			return
+		}
+		for _, child := range typedNode.Nodes {
+			collectTemplateVariables(child, rootDot, found)
+		}
+	case *parse.ActionNode:
+		collectTemplateVariables(typedNode.Pipe, rootDot, found)
+	case *parse.IfNode:
+		collectTemplateVariables(typedNode.Pipe, rootDot, found)
+		collectTemplateVariables(typedNode.List, rootDot, found)
+		collectTemplateVariables(typedNode.ElseList, rootDot, found)
+	case *parse.RangeNode:
+		collectTemplateVariables(typedNode.Pipe, rootDot, found)
+		collectTemplateVariables(typedNode.List, false, found)
+		collectTemplateVariables(typedNode.ElseList, rootDot, found)
+	case *parse.WithNode:
+		collectTemplateVariables(typedNode.Pipe, rootDot, found)
+		collectTemplateVariables(typedNode.List, false, found)
+		collectTemplateVariables(typedNode.ElseList, rootDot, found)
+	case *parse.TemplateNode:
+		collectTemplateVariables(typedNode.Pipe, rootDot, found)
+	case *parse.PipeNode:
+		if typedNode == nil {
+			return
+		}
+		for _, cmd := range typedNode.Cmds {
+			collectTemplateVariables(cmd, rootDot, found)
+		}
+	case *parse.CommandNode:
+		if len(typedNode.Args) == 2 {
+			identifier, isIdentifier := typedNode.Args[0].(*parse.IdentifierNode)
+			name, isString := typedNode.Args[1].(*parse.StringNode)
+			if isIdentifier && isString && identifier.Ident == templateVariableFunc {
+				found(name.Text)
+				return
+			}
+		}
+		for _, arg := range typedNode.Args {
+			collectTemplateVariables(arg, rootDot, found)
+		}
+	case *parse.ChainNode:
+		collectTemplateVariables(typedNode.Node, rootDot, found)
+	case *parse.FieldNode:
+		if rootDot {
+			found(typedNode.Ident[0])
+		}
+	case *parse.VariableNode:
+		if typedNode.Ident[0] == "$" && len(typedNode.Ident) > 1 {
+			found(typedNode.Ident[1])
+		}
+	}
+}
+
+// returns the templated variables in the definition of a resource which aren't any of the arguments, and so wouldn't be resolved
+func GetUnresolvedTemplateVariables(data any, args map[string]interface{}) ([]string, error) {
+
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(dataBytes, &value); err != nil {
+		return nil, err
+	}
+
+	unresolved := []string{}
+	seen := map[string]struct{}{}
+	err = forEachTemplatedString(value, func(s string) error {
+		if !isTemplatedString(s) {
+			return nil
+		}
+		tmpl, err := parseTemplatedString(s, args)
+		if err != nil {
+			return err
+		}
+		collectTemplateVariables(tmpl.Root, true, func(name string) {
+			variable := "." + name
+			if _, found := args[variable]; found {
+				return
+			}
+			if _, found := seen[variable]; !found {
+				seen[variable] = struct{}{}
+				unresolved = append(unresolved, variable)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return unresolved, nil
+}
+
+// resolves templated definitions of a resource with any arguments
+func ResolveTemplatedSpec(data any, args map[string]interface{}) (map[string]interface{}, error) {
+
+	// marshal and unmarshal the data so that each of its strings can be resolved
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(dataBytes, &value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+
+	resolved, err := resolveTemplatedValue(value, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve template: %w", err)
+	}
+	resolvedTmpl, ok := resolved.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("templated definition %s is not an object", string(dataBytes))
+	}
+
+	return resolvedTmpl, nil
+}
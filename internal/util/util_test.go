@@ -413,3 +413,136 @@ func TestResolveTemplateSpec(t *testing.T) {
 	}
 
 }
+
+func TestResolveTemplatedSpecLiteralBraces(t *testing.T) {
+	args := map[string]interface{}{".monovertex-name": "my-monovertex-0"}
+	// a "{{" which isn't closed isn't a template, so the string is left as is, even alongside a templated string
+	data := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"source": map[string]interface{}{
+				"udsource": map[string]interface{}{
+					"container": map[string]interface{}{
+						"args": []interface{}{"--left-delimiter={{", "echo '{{' >> out.txt", "--name={{.monovertex-name}}"},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := ResolveTemplatedSpec(data, args)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"--left-delimiter={{", "echo '{{' >> out.txt", "--name=my-monovertex-0"},
+		result["spec"].(map[string]interface{})["source"].(map[string]interface{})["udsource"].(map[string]interface{})["container"].(map[string]interface{})["args"])
+
+	unresolved, err := GetUnresolvedTemplateVariables(data, args)
+	assert.NoError(t, err)
+	assert.Empty(t, unresolved)
+}
+
+func TestGetUnresolvedTemplateVariables(t *testing.T) {
+	data := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"configMap": map[string]interface{}{
+				"name":      "test-volume-{{.monovertex-name}}",
+				"namespace": "{{.pipeline-namespace}}",
+			},
+		},
+	}
+	args := map[string]interface{}{
+		".monovertex-name":      "my-monovertex-0",
+		".monovertex-namespace": "test-namespace",
+	}
+
+	unresolved, err := GetUnresolvedTemplateVariables(data, args)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".pipeline-namespace"}, unresolved)
+
+	unresolved, err = GetUnresolvedTemplateVariables(data["spec"].(map[string]interface{})["configMap"].(map[string]interface{})["name"], args)
+	assert.NoError(t, err)
+	assert.Empty(t, unresolved)
+
+	// variables are found inside functions and conditionals, but not where "." has been changed by "range" or "with"
+	data = map[string]interface{}{
+		"name":   `{{ .monovertex-name | trunc 10 }}-{{ if eq .upgrade-state "promoted" }}{{ .suffix }}{{ end }}`,
+		"labels": `{{ range $key, $value := .rollout-labels }}{{ .ignored }}{{ $key }}={{ $value }}{{ end }}`,
+		"volume": `{{ with .monovertex-namespace }}{{ .ignored }}{{ end }}{{ $.suffix }}`,
+	}
+	unresolved, err = GetUnresolvedTemplateVariables(data, args)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{".rollout-labels", ".upgrade-state", ".suffix"}, unresolved)
+
+	_, err = GetUnresolvedTemplateVariables(map[string]interface{}{"name": "{{ .monovertex-name | notAFunction }}"}, args)
+	assert.ErrorContains(t, err, `function "notAFunction" not defined`)
+}
+
+func TestResolveTemplatedSpecFunctions(t *testing.T) {
+	args := map[string]interface{}{
+		".pipeline-name":       "my-pipeline-2",
+		".rollout-labels":      map[string]string{"team": "payments", "app.kubernetes.io/part-of": "ledger"},
+		".rollout-annotations": map[string]string(nil),
+		".child-index":         2,
+		".upgrade-state":       "in-progress",
+	}
+	data := map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				// map keys are templated too
+				`{{ index .rollout-labels "app.kubernetes.io/part-of" }}/team`: `{{ index .rollout-labels "team" | upper }}`,
+			},
+		},
+		"spec": map[string]interface{}{
+			"name":     `{{ .pipeline-name | replace "-" "_" }}`,
+			"owner":    `{{ index .rollout-annotations "owner" | default "unknown" }}`,
+			"hash":     `{{ .pipeline-name | sha256sum | trunc 8 }}`,
+			"replicas": `{{ if eq .upgrade-state "in-progress" }}1{{ else }}3{{ end }}`,
+			"index":    `{{ add .child-index 1 }}`,
+			// a string literal which looks like a variable isn't rewritten
+			"literal": []interface{}{`{{ ".pipeline-name" }}`, 5},
+		},
+	}
+
+	result, err := ResolveTemplatedSpec(data, args)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{"ledger/team": "PAYMENTS"},
+		},
+		"spec": map[string]interface{}{
+			"name":     "my_pipeline_2",
+			"owner":    "unknown",
+			"hash":     "aed8fc6d",
+			"replicas": "1",
+			"index":    "3",
+			"literal":  []interface{}{".pipeline-name", float64(5)},
+		},
+	}, result)
+
+	// resolving is deterministic
+	again, err := ResolveTemplatedSpec(data, args)
+	assert.NoError(t, err)
+	assert.Equal(t, result, again)
+
+	// as before templates were Go templates, a plain reference to an unknown variable resolves to ""
+	result, err = ResolveTemplatedSpec(map[string]interface{}{"name": "{{ .vertex-name }}-{{.vertex}}-{{ .pipeline-name }}"}, args)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "--my-pipeline-2"}, result)
+
+	// but any other reference to an unknown variable is an error
+	_, err = ResolveTemplatedSpec(map[string]interface{}{"name": "{{ .vertex-name | upper }}"}, args)
+	assert.ErrorContains(t, err, `unknown template variable ".vertex-name"`)
+
+	_, err = ResolveTemplatedSpec(map[string]interface{}{"name": "{{ .vertex | upper }}"}, args)
+	assert.ErrorContains(t, err, `map has no entry for key "vertex"`)
+
+	// functions whose result isn't repeatable aren't available
+	for _, function := range []string{"now", "randAlpha 5", "uuidv4", "env \"HOME\""} {
+		_, err = ResolveTemplatedSpec(map[string]interface{}{"name": "{{ " + function + " }}"}, args)
+		assert.ErrorContains(t, err, "not defined")
+	}
+}
+
+func TestNormalizeTemplateVariables(t *testing.T) {
+	assert.Equal(t, `{"name":"{{.pipeline-name}}-{{.vertex-name}}","namespace":"{{.pipeline-namespace}}"}`,
+		NormalizeTemplateVariables(`{"name":"{{ .pipeline-name }}-{{.vertex-name  }}","namespace":"{{.pipeline-namespace}}"}`))
+	assert.Equal(t, `{"args":"{{ not a variable }}"}`, NormalizeTemplateVariables(`{"args":"{{ not a variable }}"}`))
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/numaproj/numaplane/internal/controller/config"
+	"github.com/numaproj/numaplane/internal/util"
+	"github.com/numaproj/numaplane/internal/util/logger"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// specDefault is the default value of a field of a child spec, which is applied if the field isn't set
+type specDefault struct {
+	path  []string
+	value string
+}
+
+// defaultChildDefinition normalizes the templated variables of a Rollout's child metadata and spec, and applies the defaults to its spec.
+// A spec which can't be parsed is left as is, to be rejected by the validating webhook.
+func defaultChildDefinition(metadata *apiv1.Metadata, spec *runtime.RawExtension, defaults []specDefault) {
+	normalizeMetadata(metadata)
+
+	spec.Raw = []byte(util.NormalizeTemplateVariables(string(spec.Raw)))
+
+	var specMap map[string]interface{}
+	if err := json.Unmarshal(spec.Raw, &specMap); err != nil || specMap == nil {
+		return
+	}
+	defaulted := false
+	for _, specDefault := range defaults {
+		value, found, err := unstructured.NestedString(specMap, specDefault.path...)
+		if err != nil || (found && value != "") {
+			continue
+		}
+		if err := unstructured.SetNestedField(specMap, specDefault.value, specDefault.path...); err == nil {
+			defaulted = true
+		}
+	}
+	if !defaulted {
+		return
+	}
+	if specBytes, err := json.Marshal(specMap); err == nil {
+		spec.Raw = specBytes
+	}
+}
+
+func normalizeMetadata(metadata *apiv1.Metadata) {
+	for key, value := range metadata.Labels {
+		metadata.Labels[key] = util.NormalizeTemplateVariables(value)
+	}
+	for key, value := range metadata.Annotations {
+		metadata.Annotations[key] = util.NormalizeTemplateVariables(value)
+	}
+}
+
+// normalizeRider normalizes the templated variables of a Rider's definition
+func normalizeRider(rider *apiv1.Rider) {
+	if rider.Definition.Raw != nil {
+		rider.Definition.Raw = []byte(util.NormalizeTemplateVariables(string(rider.Definition.Raw)))
+	}
+}
+
+// defaultAssessmentSchedule sets the strategy's Schedule to the default for the child Kind from the ProgressiveConfig, if the
+// strategy doesn't define a schedule in either format
+func defaultAssessmentSchedule(ctx context.Context, strategy *apiv1.ProgressiveStrategy, kind string) {
+	if strategy.Schedule != nil || strategy.AssessmentSchedule != "" {
+		return
+	}
+
+	numaLogger := logger.FromContext(ctx)
+	globalConfig, err := config.GetConfigManagerInstance().GetConfig()
+	if err != nil {
+		numaLogger.Error(err, "failed to get the global config for the default assessment schedule")
+		return
+	}
+	schedule, err := globalConfig.Progressive.GetChildStatusAssessmentSchedule(kind)
+	if err != nil {
+		numaLogger.WithValues("kind", kind).Warnf("not defaulting assessment schedule: %v", err)
+		return
+	}
+	scheduleSpec := schedule.ToSpec()
+	strategy.Schedule = &scheduleSpec
+}
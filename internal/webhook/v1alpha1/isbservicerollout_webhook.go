@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// SetupISBServiceRolloutWebhookWithManager registers the webhook for ISBServiceRollout in the manager
+func SetupISBServiceRolloutWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&apiv1.ISBServiceRollout{}).
+		WithValidator(&ISBServiceRolloutCustomValidator{client: mgr.GetClient()}).
+		WithDefaulter(&ISBServiceRolloutCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-numaplane-numaproj-io-v1alpha1-isbservicerollout,mutating=true,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=isbservicerollouts,verbs=create;update,versions=v1alpha1,name=misbservicerollout-v1alpha1.numaplane.numaproj.io,admissionReviewVersions=v1
+
+// ISBServiceRolloutCustomDefaulter sets the implicit defaults of an ISBServiceRollout explicitly when it's created or updated
+type ISBServiceRolloutCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &ISBServiceRolloutCustomDefaulter{}
+
+func (d *ISBServiceRolloutCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	isbServiceRollout, ok := obj.(*apiv1.ISBServiceRollout)
+	if !ok {
+		return fmt.Errorf("expected an ISBServiceRollout object but got %T", obj)
+	}
+
+	defaultChildDefinition(&isbServiceRollout.Spec.InterStepBufferService.Metadata, &isbServiceRollout.Spec.InterStepBufferService.Spec, nil)
+	for i := range isbServiceRollout.Spec.Riders {
+		normalizeRider(&isbServiceRollout.Spec.Riders[i])
+	}
+
+	if isbServiceRollout.Spec.Strategy == nil {
+		isbServiceRollout.Spec.Strategy = &apiv1.ISBServiceRolloutStrategy{}
+	}
+	defaultAssessmentSchedule(ctx, &isbServiceRollout.Spec.Strategy.Progressive, numaflowv1.ISBGroupVersionKind.Kind)
+
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-numaplane-numaproj-io-v1alpha1-isbservicerollout,mutating=false,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=isbservicerollouts,verbs=create;update,versions=v1alpha1,name=visbservicerollout-v1alpha1.numaplane.numaproj.io,admissionReviewVersions=v1
+
+// ISBServiceRolloutCustomValidator validates an ISBServiceRollout when it's created or updated
+type ISBServiceRolloutCustomValidator struct {
+	client client.Client
+}
+
+var _ webhook.CustomValidator = &ISBServiceRolloutCustomValidator{}
+
+func (v *ISBServiceRolloutCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	isbServiceRollout, ok := obj.(*apiv1.ISBServiceRollout)
+	if !ok {
+		return nil, fmt.Errorf("expected an ISBServiceRollout object but got %T", obj)
+	}
+	return nil, v.validate(ctx, isbServiceRollout)
+}
+
+func (v *ISBServiceRolloutCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldISBServiceRollout, ok := oldObj.(*apiv1.ISBServiceRollout)
+	if !ok {
+		return nil, fmt.Errorf("expected an ISBServiceRollout object for the oldObj but got %T", oldObj)
+	}
+	isbServiceRollout, ok := newObj.(*apiv1.ISBServiceRollout)
+	if !ok {
+		return nil, fmt.Errorf("expected an ISBServiceRollout object for the newObj but got %T", newObj)
+	}
+	if skipUpdateValidation(isbServiceRollout, oldISBServiceRollout.Spec, isbServiceRollout.Spec) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, isbServiceRollout)
+}
+
+func (v *ISBServiceRolloutCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *ISBServiceRolloutCustomValidator) validate(ctx context.Context, isbServiceRollout *apiv1.ISBServiceRollout) error {
+	specPath := field.NewPath("spec")
+	args := childTemplateArguments(common.TemplateISBServiceName, common.TemplateISBServiceNamespace, isbServiceRollout)
+	allErrs := addTemplateValues(ctx, v.client, specPath.Child("valuesFrom"), isbServiceRollout, args)
+
+	allErrs = append(allErrs, validateChildDefinition(specPath.Child("interStepBufferService"), isbServiceRollout.Spec.InterStepBufferService.Metadata,
+		isbServiceRollout.Spec.InterStepBufferService.Spec, args, &numaflowv1.InterStepBufferServiceSpec{}, numaflowv1.ISBGroupVersionKind.Kind)...)
+
+	for i, rider := range isbServiceRollout.Spec.Riders {
+		allErrs = append(allErrs, validateRider(specPath.Child("riders").Index(i), rider, args)...)
+	}
+
+	if isbServiceRollout.Spec.Strategy != nil {
+		allErrs = append(allErrs, validateProgressiveStrategy(ctx, v.client, isbServiceRollout.Namespace, specPath.Child("strategy", "progressive"),
+			isbServiceRollout.Spec.Strategy.Progressive)...)
+		allErrs = append(allErrs, validateNoProgressiveSteps(specPath.Child("strategy", "progressive"), isbServiceRollout.Spec.Strategy.Progressive)...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(apiv1.ISBServiceRolloutGroupVersionKind.GroupKind(), isbServiceRollout.Name, allErrs)
+}
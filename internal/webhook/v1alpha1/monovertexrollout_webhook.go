@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// SetupMonoVertexRolloutWebhookWithManager registers the webhook for MonoVertexRollout in the manager
+func SetupMonoVertexRolloutWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&apiv1.MonoVertexRollout{}).
+		WithValidator(&MonoVertexRolloutCustomValidator{client: mgr.GetClient()}).
+		WithDefaulter(&MonoVertexRolloutCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-numaplane-numaproj-io-v1alpha1-monovertexrollout,mutating=true,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=monovertexrollouts,verbs=create;update,versions=v1alpha1,name=mmonovertexrollout-v1alpha1.numaplane.numaproj.io,admissionReviewVersions=v1
+
+// MonoVertexRolloutCustomDefaulter sets the implicit defaults of a MonoVertexRollout explicitly when it's created or updated
+type MonoVertexRolloutCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &MonoVertexRolloutCustomDefaulter{}
+
+func (d *MonoVertexRolloutCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	monoVertexRollout, ok := obj.(*apiv1.MonoVertexRollout)
+	if !ok {
+		return fmt.Errorf("expected a MonoVertexRollout object but got %T", obj)
+	}
+
+	// a MonoVertex defined by a MonoVertexTemplate only has metadata here
+	if monoVertexRollout.Spec.TemplateRef != nil {
+		normalizeMetadata(&monoVertexRollout.Spec.MonoVertex.Metadata)
+	} else {
+		defaultChildDefinition(&monoVertexRollout.Spec.MonoVertex.Metadata, &monoVertexRollout.Spec.MonoVertex.Spec, []specDefault{
+			{path: []string{"lifecycle", "desiredPhase"}, value: string(numaflowv1.MonoVertexPhaseRunning)},
+		})
+	}
+	for i := range monoVertexRollout.Spec.Riders {
+		normalizeRider(&monoVertexRollout.Spec.Riders[i])
+	}
+
+	if monoVertexRollout.Spec.Strategy == nil {
+		monoVertexRollout.Spec.Strategy = &apiv1.PipelineTypeRolloutStrategy{}
+	}
+	defaultAssessmentSchedule(ctx, &monoVertexRollout.Spec.Strategy.Progressive, numaflowv1.MonoVertexGroupVersionKind.Kind)
+
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-numaplane-numaproj-io-v1alpha1-monovertexrollout,mutating=false,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=monovertexrollouts,verbs=create;update,versions=v1alpha1,name=vmonovertexrollout-v1alpha1.numaplane.numaproj.io,admissionReviewVersions=v1
+
+// MonoVertexRolloutCustomValidator validates a MonoVertexRollout when it's created or updated
+type MonoVertexRolloutCustomValidator struct {
+	client client.Client
+}
+
+var _ webhook.CustomValidator = &MonoVertexRolloutCustomValidator{}
+
+func (v *MonoVertexRolloutCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	monoVertexRollout, ok := obj.(*apiv1.MonoVertexRollout)
+	if !ok {
+		return nil, fmt.Errorf("expected a MonoVertexRollout object but got %T", obj)
+	}
+	return nil, v.validate(ctx, monoVertexRollout)
+}
+
+func (v *MonoVertexRolloutCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldMonoVertexRollout, ok := oldObj.(*apiv1.MonoVertexRollout)
+	if !ok {
+		return nil, fmt.Errorf("expected a MonoVertexRollout object for the oldObj but got %T", oldObj)
+	}
+	monoVertexRollout, ok := newObj.(*apiv1.MonoVertexRollout)
+	if !ok {
+		return nil, fmt.Errorf("expected a MonoVertexRollout object for the newObj but got %T", newObj)
+	}
+	if skipUpdateValidation(monoVertexRollout, oldMonoVertexRollout.Spec, monoVertexRollout.Spec) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, monoVertexRollout)
+}
+
+func (v *MonoVertexRolloutCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *MonoVertexRolloutCustomValidator) validate(ctx context.Context, monoVertexRollout *apiv1.MonoVertexRollout) error {
+	specPath := field.NewPath("spec")
+	args := childTemplateArguments(common.TemplateMonoVertexName, common.TemplateMonoVertexNamespace, monoVertexRollout)
+
+	valuesErrs := addTemplateValues(ctx, v.client, specPath.Child("valuesFrom"), monoVertexRollout, args)
+
+	var allErrs field.ErrorList
+	if monoVertexRollout.Spec.TemplateRef != nil {
+		// validate the MonoVertex which the MonoVertexTemplate resolves to
+		templatePath := specPath.Child("templateRef")
+		resolved := monoVertexRollout.DeepCopy()
+		allErrs = resolveTemplateRef(ctx, v.client, templatePath, resolved)
+		if len(allErrs) == 0 {
+			allErrs = validateChildDefinition(templatePath, resolved.Spec.MonoVertex.Metadata, resolved.Spec.MonoVertex.Spec,
+				args, &numaflowv1.MonoVertexSpec{}, numaflowv1.MonoVertexGroupVersionKind.Kind)
+		}
+	} else {
+		allErrs = validateChildDefinition(specPath.Child("monoVertex"), monoVertexRollout.Spec.MonoVertex.Metadata, monoVertexRollout.Spec.MonoVertex.Spec,
+			args, &numaflowv1.MonoVertexSpec{}, numaflowv1.MonoVertexGroupVersionKind.Kind)
+	}
+	allErrs = append(valuesErrs, allErrs...)
+
+	for i, rider := range monoVertexRollout.Spec.Riders {
+		allErrs = append(allErrs, validateRider(specPath.Child("riders").Index(i), rider, args)...)
+	}
+
+	allErrs = append(allErrs, validatePipelineTypeRolloutStrategy(ctx, v.client, monoVertexRollout.Namespace, specPath.Child("strategy"),
+		monoVertexRollout.Spec.Strategy)...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(apiv1.MonoVertexRolloutGroupVersionKind.GroupKind(), monoVertexRollout.Name, allErrs)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/numaproj/numaplane/internal/common"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// SetupMonoVertexTemplateWebhookWithManager registers the webhook for MonoVertexTemplate in the manager
+func SetupMonoVertexTemplateWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&apiv1.MonoVertexTemplate{}).
+		WithValidator(&MonoVertexTemplateCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-numaplane-numaproj-io-v1alpha1-monovertextemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=monovertextemplates,verbs=create;update,versions=v1alpha1,name=vmonovertextemplate-v1alpha1.numaplane.numaproj.io,admissionReviewVersions=v1
+
+// MonoVertexTemplateCustomValidator validates a MonoVertexTemplate when it's created or updated
+type MonoVertexTemplateCustomValidator struct{}
+
+var _ webhook.CustomValidator = &MonoVertexTemplateCustomValidator{}
+
+func (v *MonoVertexTemplateCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	monoVertexTemplate, ok := obj.(*apiv1.MonoVertexTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected a MonoVertexTemplate object but got %T", obj)
+	}
+	return nil, v.validate(monoVertexTemplate)
+}
+
+func (v *MonoVertexTemplateCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldMonoVertexTemplate, ok := oldObj.(*apiv1.MonoVertexTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected a MonoVertexTemplate object for the oldObj but got %T", oldObj)
+	}
+	monoVertexTemplate, ok := newObj.(*apiv1.MonoVertexTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected a MonoVertexTemplate object for the newObj but got %T", newObj)
+	}
+	if skipUpdateValidation(monoVertexTemplate, oldMonoVertexTemplate.Spec, monoVertexTemplate.Spec) {
+		return nil, nil
+	}
+	return nil, v.validate(monoVertexTemplate)
+}
+
+func (v *MonoVertexTemplateCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *MonoVertexTemplateCustomValidator) validate(monoVertexTemplate *apiv1.MonoVertexTemplate) error {
+	args := childTemplateArguments(common.TemplateMonoVertexName, common.TemplateMonoVertexNamespace, monoVertexTemplate)
+
+	allErrs := validateTemplate(field.NewPath("spec"), monoVertexTemplate, "monoVertex", args, &numaflowv1.MonoVertexSpec{}, numaflowv1.MonoVertexGroupVersionKind.Kind)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(apiv1.MonoVertexTemplateGroupVersionKind.GroupKind(), monoVertexTemplate.Name, allErrs)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/numaproj/numaplane/internal/controller/numaflowcontroller"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// SetupNumaflowControllerWebhookWithManager registers the webhook for NumaflowController in the manager
+func SetupNumaflowControllerWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&apiv1.NumaflowController{}).
+		WithValidator(&NumaflowControllerCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-numaplane-numaproj-io-v1alpha1-numaflowcontroller,mutating=false,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=numaflowcontrollers,verbs=create;update,versions=v1alpha1,name=vnumaflowcontroller-v1alpha1.numaplane.numaproj.io,admissionReviewVersions=v1
+
+// NumaflowControllerCustomValidator validates a NumaflowController when it's created or updated
+type NumaflowControllerCustomValidator struct{}
+
+var _ webhook.CustomValidator = &NumaflowControllerCustomValidator{}
+
+func (v *NumaflowControllerCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	numaflowController, ok := obj.(*apiv1.NumaflowController)
+	if !ok {
+		return nil, fmt.Errorf("expected a NumaflowController object but got %T", obj)
+	}
+	return nil, validateNumaflowController(numaflowController)
+}
+
+func (v *NumaflowControllerCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldNumaflowController, ok := oldObj.(*apiv1.NumaflowController)
+	if !ok {
+		return nil, fmt.Errorf("expected a NumaflowController object for the oldObj but got %T", oldObj)
+	}
+	numaflowController, ok := newObj.(*apiv1.NumaflowController)
+	if !ok {
+		return nil, fmt.Errorf("expected a NumaflowController object for the newObj but got %T", newObj)
+	}
+	if skipUpdateValidation(numaflowController, oldNumaflowController.Spec, numaflowController.Spec) {
+		return nil, nil
+	}
+	return nil, validateNumaflowController(numaflowController)
+}
+
+func (v *NumaflowControllerCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateNumaflowController(numaflowController *apiv1.NumaflowController) error {
+	allErrs := validateControllerDefinition(field.NewPath("spec"), numaflowController)
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(apiv1.NumaflowControllerGroupVersionKind.GroupKind(), numaflowController.Name, allErrs)
+}
+
+// validateControllerDefinition verifies that the Numaflow controller definition for the NumaflowController's version exists
+// and that its manifests resolve
+func validateControllerDefinition(path *field.Path, numaflowController *apiv1.NumaflowController) field.ErrorList {
+	if err := numaflowcontroller.ValidateControllerDefinition(numaflowController); err != nil {
+		return field.ErrorList{field.Invalid(path.Child("version"), numaflowController.Spec.Version, err.Error())}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/numaproj/numaplane/internal/common"
+	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
+	"github.com/numaproj/numaplane/internal/controller/config"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_NumaflowControllerRolloutCustomValidator(t *testing.T) {
+	ctx := context.Background()
+
+	definitions, err := ctlrcommon.GetNumaflowControllerDefinitions("../../../tests/config/controller-definitions-config.yaml")
+	assert.NoError(t, err)
+	config.GetConfigManagerInstance().GetControllerDefinitionsMgr().UpdateNumaflowControllerDefinitionConfig(*definitions, common.NumaplaneSystemNamespace)
+
+	makeNFCRollout := func(version string) *apiv1.NumaflowControllerRollout {
+		return &apiv1.NumaflowControllerRollout{
+			ObjectMeta: metav1.ObjectMeta{Name: "numaflow-controller", Namespace: defaultNamespace},
+			Spec:       apiv1.NumaflowControllerRolloutSpec{Controller: apiv1.Controller{Version: version}},
+		}
+	}
+
+	rolloutValidator := &NumaflowControllerRolloutCustomValidator{}
+	controllerValidator := &NumaflowControllerCustomValidator{}
+
+	_, err = rolloutValidator.ValidateCreate(ctx, makeNFCRollout("1.2.0"))
+	assert.NoError(t, err)
+
+	_, err = rolloutValidator.ValidateCreate(ctx, makeNFCRollout("0.0.1"))
+	assert.True(t, apierrors.IsInvalid(err), "expected an Invalid error but got %v", err)
+	assert.ErrorContains(t, err, "spec.controller.version")
+	assert.ErrorContains(t, err, "no controller definition found")
+
+	numaflowController := &apiv1.NumaflowController{
+		ObjectMeta: metav1.ObjectMeta{Name: "numaflow-controller", Namespace: defaultNamespace},
+		Spec:       apiv1.NumaflowControllerSpec{Version: "0.0.1"},
+	}
+	_, err = controllerValidator.ValidateCreate(ctx, numaflowController)
+	assert.ErrorContains(t, err, "spec.version")
+
+	numaflowController.Spec.Version = "1.2.0"
+	_, err = controllerValidator.ValidateCreate(ctx, numaflowController)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// SetupNumaflowControllerRolloutWebhookWithManager registers the webhook for NumaflowControllerRollout in the manager
+func SetupNumaflowControllerRolloutWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&apiv1.NumaflowControllerRollout{}).
+		WithValidator(&NumaflowControllerRolloutCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-numaplane-numaproj-io-v1alpha1-numaflowcontrollerrollout,mutating=false,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=numaflowcontrollerrollouts,verbs=create;update,versions=v1alpha1,name=vnumaflowcontrollerrollout-v1alpha1.numaplane.numaproj.io,admissionReviewVersions=v1
+
+// NumaflowControllerRolloutCustomValidator validates a NumaflowControllerRollout when it's created or updated
+type NumaflowControllerRolloutCustomValidator struct{}
+
+var _ webhook.CustomValidator = &NumaflowControllerRolloutCustomValidator{}
+
+func (v *NumaflowControllerRolloutCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	nfcRollout, ok := obj.(*apiv1.NumaflowControllerRollout)
+	if !ok {
+		return nil, fmt.Errorf("expected a NumaflowControllerRollout object but got %T", obj)
+	}
+	return nil, validateNumaflowControllerRollout(nfcRollout)
+}
+
+func (v *NumaflowControllerRolloutCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldNFCRollout, ok := oldObj.(*apiv1.NumaflowControllerRollout)
+	if !ok {
+		return nil, fmt.Errorf("expected a NumaflowControllerRollout object for the oldObj but got %T", oldObj)
+	}
+	nfcRollout, ok := newObj.(*apiv1.NumaflowControllerRollout)
+	if !ok {
+		return nil, fmt.Errorf("expected a NumaflowControllerRollout object for the newObj but got %T", newObj)
+	}
+	if skipUpdateValidation(nfcRollout, oldNFCRollout.Spec, nfcRollout.Spec) {
+		return nil, nil
+	}
+	return nil, validateNumaflowControllerRollout(nfcRollout)
+}
+
+func (v *NumaflowControllerRolloutCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateNumaflowControllerRollout(nfcRollout *apiv1.NumaflowControllerRollout) error {
+	// the NumaflowController child has the same name as the Rollout
+	numaflowController := &apiv1.NumaflowController{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiv1.NumaflowControllerGroupVersionKind.GroupVersion().String(),
+			Kind:       apiv1.NumaflowControllerGroupVersionKind.Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: nfcRollout.Name, Namespace: nfcRollout.Namespace},
+		Spec: apiv1.NumaflowControllerSpec{
+			InstanceID: nfcRollout.Spec.Controller.InstanceID,
+			Version:    nfcRollout.Spec.Controller.Version,
+		},
+	}
+
+	allErrs := validateControllerDefinition(field.NewPath("spec", "controller"), numaflowController)
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(apiv1.NumaflowControllerRolloutGroupVersionKind.GroupKind(), nfcRollout.Name, allErrs)
+}
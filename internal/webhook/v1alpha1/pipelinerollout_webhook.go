@@ -0,0 +1,166 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/numaproj/numaplane/internal/common"
+	"github.com/numaproj/numaplane/internal/controller/config"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// SetupPipelineRolloutWebhookWithManager registers the webhook for PipelineRollout in the manager
+func SetupPipelineRolloutWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&apiv1.PipelineRollout{}).
+		WithValidator(&PipelineRolloutCustomValidator{client: mgr.GetClient()}).
+		WithDefaulter(&PipelineRolloutCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-numaplane-numaproj-io-v1alpha1-pipelinerollout,mutating=true,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=pipelinerollouts,verbs=create;update,versions=v1alpha1,name=mpipelinerollout-v1alpha1.numaplane.numaproj.io,admissionReviewVersions=v1
+
+// PipelineRolloutCustomDefaulter sets the implicit defaults of a PipelineRollout explicitly when it's created or updated
+type PipelineRolloutCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &PipelineRolloutCustomDefaulter{}
+
+func (d *PipelineRolloutCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	pipelineRollout, ok := obj.(*apiv1.PipelineRollout)
+	if !ok {
+		return fmt.Errorf("expected a PipelineRollout object but got %T", obj)
+	}
+
+	// a Pipeline defined by a PipelineTemplate only has metadata here
+	if pipelineRollout.Spec.TemplateRef != nil {
+		normalizeMetadata(&pipelineRollout.Spec.Pipeline.Metadata)
+	} else {
+		defaultChildDefinition(&pipelineRollout.Spec.Pipeline.Metadata, &pipelineRollout.Spec.Pipeline.Spec, []specDefault{
+			{path: []string{"interStepBufferServiceName"}, value: "default"},
+			{path: []string{"lifecycle", "desiredPhase"}, value: string(numaflowv1.PipelinePhaseRunning)},
+		})
+	}
+	for i := range pipelineRollout.Spec.Riders {
+		normalizeRider(&pipelineRollout.Spec.Riders[i].Rider)
+	}
+
+	if pipelineRollout.Spec.Strategy == nil {
+		pipelineRollout.Spec.Strategy = &apiv1.PipelineStrategy{}
+	}
+	if pipelineRollout.Spec.Strategy.RecycleStrategy.ScaleFactor == nil {
+		globalConfig, err := config.GetConfigManagerInstance().GetConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get the global config: %w", err)
+		}
+		scaleFactor := globalConfig.Pipeline.GetRecycleScaleFactor()
+		pipelineRollout.Spec.Strategy.RecycleStrategy.ScaleFactor = &scaleFactor
+	}
+	defaultAssessmentSchedule(ctx, &pipelineRollout.Spec.Strategy.Progressive, numaflowv1.PipelineGroupVersionKind.Kind)
+
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-numaplane-numaproj-io-v1alpha1-pipelinerollout,mutating=false,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=pipelinerollouts,verbs=create;update,versions=v1alpha1,name=vpipelinerollout-v1alpha1.numaplane.numaproj.io,admissionReviewVersions=v1
+
+// PipelineRolloutCustomValidator validates a PipelineRollout when it's created or updated
+type PipelineRolloutCustomValidator struct {
+	client client.Client
+}
+
+var _ webhook.CustomValidator = &PipelineRolloutCustomValidator{}
+
+func (v *PipelineRolloutCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pipelineRollout, ok := obj.(*apiv1.PipelineRollout)
+	if !ok {
+		return nil, fmt.Errorf("expected a PipelineRollout object but got %T", obj)
+	}
+	return nil, v.validate(ctx, pipelineRollout)
+}
+
+func (v *PipelineRolloutCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldPipelineRollout, ok := oldObj.(*apiv1.PipelineRollout)
+	if !ok {
+		return nil, fmt.Errorf("expected a PipelineRollout object for the oldObj but got %T", oldObj)
+	}
+	pipelineRollout, ok := newObj.(*apiv1.PipelineRollout)
+	if !ok {
+		return nil, fmt.Errorf("expected a PipelineRollout object for the newObj but got %T", newObj)
+	}
+	if skipUpdateValidation(pipelineRollout, oldPipelineRollout.Spec, pipelineRollout.Spec) {
+		return nil, nil
+	}
+	return nil, v.validate(ctx, pipelineRollout)
+}
+
+func (v *PipelineRolloutCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *PipelineRolloutCustomValidator) validate(ctx context.Context, pipelineRollout *apiv1.PipelineRollout) error {
+	specPath := field.NewPath("spec")
+	args := pipelineTemplateArguments(pipelineRollout)
+
+	valuesErrs := addTemplateValues(ctx, v.client, specPath.Child("valuesFrom"), pipelineRollout, args)
+
+	var allErrs field.ErrorList
+	if pipelineRollout.Spec.TemplateRef != nil {
+		// validate the Pipeline which the PipelineTemplate resolves to
+		templatePath := specPath.Child("templateRef")
+		resolved := pipelineRollout.DeepCopy()
+		allErrs = resolveTemplateRef(ctx, v.client, templatePath, resolved)
+		if len(allErrs) == 0 {
+			allErrs = validateChildDefinition(templatePath, resolved.Spec.Pipeline.Metadata, resolved.Spec.Pipeline.Spec,
+				args, &numaflowv1.PipelineSpec{}, numaflowv1.PipelineGroupVersionKind.Kind)
+		}
+	} else {
+		allErrs = validateChildDefinition(specPath.Child("pipeline"), pipelineRollout.Spec.Pipeline.Metadata, pipelineRollout.Spec.Pipeline.Spec,
+			args, &numaflowv1.PipelineSpec{}, numaflowv1.PipelineGroupVersionKind.Kind)
+	}
+	allErrs = append(valuesErrs, allErrs...)
+
+	for i, rider := range pipelineRollout.Spec.Riders {
+		riderArgs := args
+		if rider.PerVertex {
+			riderArgs = map[string]interface{}{common.TemplateVertexName: "vertex"}
+			for name, value := range args {
+				riderArgs[name] = value
+			}
+		}
+		allErrs = append(allErrs, validateRider(specPath.Child("riders").Index(i), rider.Rider, riderArgs)...)
+	}
+
+	if pipelineRollout.Spec.Strategy != nil {
+		allErrs = append(allErrs, validatePipelineTypeRolloutStrategy(ctx, v.client, pipelineRollout.Namespace, specPath.Child("strategy"),
+			&pipelineRollout.Spec.Strategy.PipelineTypeRolloutStrategy)...)
+		allErrs = append(allErrs, validateNoProgressiveSteps(specPath.Child("strategy", "progressive"), pipelineRollout.Spec.Strategy.Progressive)...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(apiv1.PipelineRolloutGroupVersionKind.GroupKind(), pipelineRollout.Name, allErrs)
+}
@@ -0,0 +1,350 @@
+package v1alpha1
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	argorolloutsv1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/numaproj/numaplane/internal/controller/config"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+const defaultNamespace = "default"
+
+func loadTestConfig(t *testing.T) {
+	getwd, err := os.Getwd()
+	assert.NoError(t, err)
+	configPath := filepath.Join(getwd, "../../../", "tests", "config")
+	err = config.GetConfigManagerInstance().LoadAllConfigs(func(err error) {}, config.WithConfigsPath(configPath), config.WithConfigFileName("testconfig"))
+	assert.NoError(t, err)
+}
+
+func Test_PipelineRolloutCustomValidator(t *testing.T) {
+	ctx := context.Background()
+	loadTestConfig(t)
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, argorolloutsv1.AddToScheme(scheme))
+	assert.NoError(t, apiv1.AddToScheme(scheme))
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	validPipelineSpec := `{"vertices":[{"name":"in","source":{"generator":{}}},{"name":"out","sink":{"log":{}}}],"edges":[{"from":"in","to":"out"}]}`
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&argorolloutsv1.AnalysisTemplate{ObjectMeta: metav1.ObjectMeta{Name: "error-rate", Namespace: defaultNamespace}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: defaultNamespace}, Data: map[string]string{"region": "us-west-2"}},
+		&apiv1.PipelineTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-template", Namespace: defaultNamespace},
+			Spec: apiv1.PipelineTemplateSpec{
+				TemplateSpec: apiv1.TemplateSpec{Parameters: []apiv1.TemplateParameter{{Name: "vertexName"}}},
+				Pipeline: apiv1.Pipeline{Spec: runtime.RawExtension{Raw: []byte(
+					`{"vertices":[{"name":"{{.params.vertexName}}","source":{"generator":{}}}]}`)}},
+			},
+		},
+	).Build()
+	validator := &PipelineRolloutCustomValidator{client: fakeClient}
+
+	makePipelineRollout := func(pipelineSpec string, riders []apiv1.PipelineRider, strategy *apiv1.PipelineStrategy) *apiv1.PipelineRollout {
+		return &apiv1.PipelineRollout{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: defaultNamespace},
+			Spec: apiv1.PipelineRolloutSpec{
+				Pipeline: apiv1.Pipeline{
+					Metadata: apiv1.Metadata{Labels: map[string]string{"name": "{{.pipeline-name}}"}},
+					Spec:     runtime.RawExtension{Raw: []byte(pipelineSpec)},
+				},
+				Riders:   riders,
+				Strategy: strategy,
+			},
+		}
+	}
+
+	makeTemplatedPipelineRollout := func(templateName string, values map[string]string) *apiv1.PipelineRollout {
+		return &apiv1.PipelineRollout{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: defaultNamespace},
+			Spec: apiv1.PipelineRolloutSpec{
+				Pipeline:    apiv1.Pipeline{Metadata: apiv1.Metadata{Labels: map[string]string{"name": "{{.pipeline-name}}"}}},
+				TemplateRef: &apiv1.TemplateReference{Name: templateName, Values: values},
+			},
+		}
+	}
+
+	withValuesFrom := func(pipelineRollout *apiv1.PipelineRollout, configMapName string) *apiv1.PipelineRollout {
+		pipelineRollout.Spec.ValuesFrom = []apiv1.ValuesFromSource{{ConfigMapRef: &apiv1.ValuesFromReference{Name: configMapName}}}
+		return pipelineRollout
+	}
+
+	makeRider := func(definition string, perVertex bool) apiv1.PipelineRider {
+		return apiv1.PipelineRider{Rider: apiv1.Rider{Definition: runtime.RawExtension{Raw: []byte(definition)}}, PerVertex: perVertex}
+	}
+
+	makeStrategy := func(assessmentSchedule string, templateName string) *apiv1.PipelineStrategy {
+		return &apiv1.PipelineStrategy{PipelineTypeRolloutStrategy: apiv1.PipelineTypeRolloutStrategy{
+			PipelineTypeProgressiveStrategy: apiv1.PipelineTypeProgressiveStrategy{
+				Progressive: apiv1.ProgressiveStrategy{AssessmentSchedule: assessmentSchedule},
+				Analysis:    apiv1.Analysis{Templates: []argorolloutsv1.AnalysisTemplateRef{{TemplateName: templateName}}},
+			},
+		}}
+	}
+
+	testCases := []struct {
+		name            string
+		pipelineRollout *apiv1.PipelineRollout
+		expectedErrors  []string
+	}{
+		{
+			name: "valid",
+			pipelineRollout: makePipelineRollout(validPipelineSpec,
+				[]apiv1.PipelineRider{
+					makeRider(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{.pipeline-name}}"}}`, false),
+					makeRider(`{"apiVersion":"autoscaling.k8s.io/v1","kind":"VerticalPodAutoscaler","metadata":{"name":"{{.vertex-name}}"}}`, true),
+				},
+				makeStrategy("60,120,30,10", "error-rate")),
+		},
+		{
+			name:            "valid templateRef",
+			pipelineRollout: makeTemplatedPipelineRollout("my-template", map[string]string{"vertexName": "in"}),
+		},
+		{
+			name:            "template not found",
+			pipelineRollout: makeTemplatedPipelineRollout("other-template", nil),
+			expectedErrors:  []string{"spec.templateRef.name", "other-template"},
+		},
+		{
+			name:            "value missing for templateRef",
+			pipelineRollout: makeTemplatedPipelineRollout("my-template", nil),
+			expectedErrors:  []string{"spec.templateRef.values", `no value supplied for parameter "vertexName"`},
+		},
+		{
+			name: "valid functions and template variables",
+			pipelineRollout: makePipelineRollout(
+				`{"vertices":[{"name":"{{ .pipeline-name | trunc 20 }}-{{ .child-index }}","source":{"generator":{}},`+
+					`"metadata":{"labels":{"isbsvc":"{{ .isbsvc-name }}","team":"{{ index .rollout-labels \"team\" | default \"none\" }}"}}}]}`,
+				[]apiv1.PipelineRider{makeRider(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .vertex-name }}-{{ .upgrade-state }}"}}`, true)},
+				nil),
+		},
+		{
+			name: "valid valuesFrom",
+			pipelineRollout: withValuesFrom(makePipelineRollout(`{"vertices":[{"name":"in-{{ .values.region }}","source":{"generator":{}}}]}`,
+				[]apiv1.PipelineRider{makeRider(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{ .values.region }}"}}`, false)}, nil), "env"),
+		},
+		{
+			name:            "valuesFrom ConfigMap not found",
+			pipelineRollout: withValuesFrom(makePipelineRollout(validPipelineSpec, nil, nil), "other"),
+			expectedErrors:  []string{"spec.valuesFrom", "failed to get ConfigMap default/other"},
+		},
+		{
+			name:            "value not in valuesFrom",
+			pipelineRollout: withValuesFrom(makePipelineRollout(`{"vertices":[{"name":"in-{{ .values.zone }}"}]}`, nil, nil), "env"),
+			expectedErrors:  []string{"spec.pipeline.spec", `map has no entry for key "zone"`},
+		},
+		{
+			name:            "unparseable template",
+			pipelineRollout: makePipelineRollout(`{"vertices":[{"name":"{{ .pipeline-name | notAFunction }}"}]}`, nil, nil),
+			expectedErrors:  []string{"spec.pipeline.spec", `function "notAFunction" not defined`},
+		},
+		{
+			name:            "spec doesn't decode",
+			pipelineRollout: makePipelineRollout(`{"vertices":"in"}`, nil, nil),
+			expectedErrors:  []string{"spec.pipeline.spec", "not a valid Pipeline spec"},
+		},
+		{
+			name:            "unknown template variable in spec",
+			pipelineRollout: makePipelineRollout(`{"vertices":[{"name":"{{.monovertex-name}}"}]}`, nil, nil),
+			expectedErrors:  []string{"spec.pipeline.spec", "{{.monovertex-name}}", "{{.pipeline-name}}, {{.pipeline-namespace}}"},
+		},
+		{
+			name: "vertex name template variable in a Rider which isn't per-vertex",
+			pipelineRollout: makePipelineRollout(validPipelineSpec,
+				[]apiv1.PipelineRider{makeRider(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"{{.vertex-name}}"}}`, false)}, nil),
+			expectedErrors: []string{"spec.riders[0].definition", "{{.vertex-name}}"},
+		},
+		{
+			name: "unpermitted Rider kind",
+			pipelineRollout: makePipelineRollout(validPipelineSpec,
+				[]apiv1.PipelineRider{makeRider(`{"apiVersion":"v1","kind":"Secret","metadata":{"name":"secret"}}`, false)}, nil),
+			expectedErrors: []string{"spec.riders[0].definition.kind", "Secret is not a permitted Rider kind"},
+		},
+		{
+			name:            "invalid assessment schedule",
+			pipelineRollout: makePipelineRollout(validPipelineSpec, nil, makeStrategy("60,120", "error-rate")),
+			expectedErrors:  []string{"spec.strategy.progressive.assessmentSchedule"},
+		},
+		{
+			name:            "missing AnalysisTemplate",
+			pipelineRollout: makePipelineRollout(validPipelineSpec, nil, makeStrategy("", "latency")),
+			expectedErrors:  []string{"spec.strategy.analysis.templates[0].templateName", "latency (AnalysisTemplate)"},
+		},
+		{
+			name: "steps aren't supported",
+			pipelineRollout: func() *apiv1.PipelineRollout {
+				pipelineRollout := makePipelineRollout(validPipelineSpec, nil, makeStrategy("", "error-rate"))
+				pipelineRollout.Spec.Strategy.Progressive.Steps = []apiv1.ProgressiveStep{{Weight: 20}}
+				return pipelineRollout
+			}(),
+			expectedErrors: []string{"spec.strategy.progressive.steps", "only supported for MonoVertexRollout"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := validator.ValidateCreate(ctx, tc.pipelineRollout)
+			if len(tc.expectedErrors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, apierrors.IsInvalid(err), "expected an Invalid error but got %v", err)
+			for _, expectedError := range tc.expectedErrors {
+				assert.ErrorContains(t, err, expectedError)
+			}
+		})
+	}
+
+	t.Run("update which doesn't change the spec isn't validated", func(t *testing.T) {
+		oldPipelineRollout := makePipelineRollout(validPipelineSpec, nil, makeStrategy("", "latency"))
+		newPipelineRollout := oldPipelineRollout.DeepCopy()
+		newPipelineRollout.Finalizers = nil
+		_, err := validator.ValidateUpdate(ctx, oldPipelineRollout, newPipelineRollout)
+		assert.NoError(t, err)
+
+		newPipelineRollout.Spec.Strategy.Progressive.AssessmentSchedule = "60,120"
+		_, err = validator.ValidateUpdate(ctx, oldPipelineRollout, newPipelineRollout)
+		assert.Error(t, err)
+	})
+}
+
+func Test_PipelineRolloutCustomDefaulter(t *testing.T) {
+	ctx := context.Background()
+	loadTestConfig(t)
+
+	defaulter := &PipelineRolloutCustomDefaulter{}
+
+	t.Run("implicit defaults are set", func(t *testing.T) {
+		pipelineRollout := &apiv1.PipelineRollout{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: defaultNamespace},
+			Spec: apiv1.PipelineRolloutSpec{
+				Pipeline: apiv1.Pipeline{
+					Metadata: apiv1.Metadata{Annotations: map[string]string{"link": "https://example.com/{{ .pipeline-name }}"}},
+					Spec:     runtime.RawExtension{Raw: []byte(`{"vertices":[{"name":"{{ .pipeline-name}}-in"}]}`)},
+				},
+				Riders: []apiv1.PipelineRider{{Rider: apiv1.Rider{Definition: runtime.RawExtension{Raw: []byte(`{"kind":"ConfigMap","metadata":{"name":"{{ .vertex-name }}"}}`)}}}},
+			},
+		}
+		assert.NoError(t, defaulter.Default(ctx, pipelineRollout))
+
+		assert.JSONEq(t, `{"interStepBufferServiceName":"default","lifecycle":{"desiredPhase":"Running"},"vertices":[{"name":"{{.pipeline-name}}-in"}]}`,
+			string(pipelineRollout.Spec.Pipeline.Spec.Raw))
+		assert.Equal(t, "https://example.com/{{.pipeline-name}}", pipelineRollout.Spec.Pipeline.Metadata.Annotations["link"])
+		assert.JSONEq(t, `{"kind":"ConfigMap","metadata":{"name":"{{.vertex-name}}"}}`, string(pipelineRollout.Spec.Riders[0].Definition.Raw))
+
+		assert.NotNil(t, pipelineRollout.Spec.Strategy)
+		assert.Equal(t, config.DefaultRecycleScaleFactor, *pipelineRollout.Spec.Strategy.RecycleStrategy.ScaleFactor)
+		assert.Equal(t, &apiv1.AssessmentSchedule{
+			Delay:    metav1.Duration{Duration: 120 * time.Second},
+			End:      metav1.Duration{Duration: 360 * time.Second},
+			Interval: metav1.Duration{Duration: 10 * time.Second},
+		}, pipelineRollout.Spec.Strategy.Progressive.Schedule)
+	})
+
+	t.Run("explicit values are kept", func(t *testing.T) {
+		scaleFactor := int32(80)
+		pipelineSpec := `{"interStepBufferServiceName":"my-isbsvc","lifecycle":{"desiredPhase":"Paused"},"vertices":[{"name":"in"}]}`
+		pipelineRollout := &apiv1.PipelineRollout{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: defaultNamespace},
+			Spec: apiv1.PipelineRolloutSpec{
+				Pipeline: apiv1.Pipeline{Spec: runtime.RawExtension{Raw: []byte(pipelineSpec)}},
+				Strategy: &apiv1.PipelineStrategy{
+					PipelineTypeRolloutStrategy: apiv1.PipelineTypeRolloutStrategy{PipelineTypeProgressiveStrategy: apiv1.PipelineTypeProgressiveStrategy{
+						Progressive: apiv1.ProgressiveStrategy{AssessmentSchedule: "60,120,30,10"},
+					}},
+					RecycleStrategy: apiv1.RecycleStrategy{ScaleFactor: &scaleFactor},
+				},
+			},
+		}
+		assert.NoError(t, defaulter.Default(ctx, pipelineRollout))
+
+		assert.Equal(t, pipelineSpec, string(pipelineRollout.Spec.Pipeline.Spec.Raw))
+		assert.Equal(t, scaleFactor, *pipelineRollout.Spec.Strategy.RecycleStrategy.ScaleFactor)
+		assert.Nil(t, pipelineRollout.Spec.Strategy.Progressive.Schedule)
+	})
+
+	t.Run("spec defined by a template isn't defaulted", func(t *testing.T) {
+		pipelineRollout := &apiv1.PipelineRollout{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: defaultNamespace},
+			Spec: apiv1.PipelineRolloutSpec{
+				Pipeline:    apiv1.Pipeline{Metadata: apiv1.Metadata{Labels: map[string]string{"name": "{{ .pipeline-name }}"}}},
+				TemplateRef: &apiv1.TemplateReference{Name: "my-template"},
+			},
+		}
+		assert.NoError(t, defaulter.Default(ctx, pipelineRollout))
+
+		assert.Nil(t, pipelineRollout.Spec.Pipeline.Spec.Raw)
+		assert.Equal(t, "{{.pipeline-name}}", pipelineRollout.Spec.Pipeline.Metadata.Labels["name"])
+	})
+}
+
+func Test_PipelineTemplateCustomValidator(t *testing.T) {
+	ctx := context.Background()
+	validator := &PipelineTemplateCustomValidator{}
+
+	makePipelineTemplate := func(pipelineSpec string, parameters ...apiv1.TemplateParameter) *apiv1.PipelineTemplate {
+		return &apiv1.PipelineTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-template", Namespace: defaultNamespace},
+			Spec: apiv1.PipelineTemplateSpec{
+				TemplateSpec: apiv1.TemplateSpec{Parameters: parameters},
+				Pipeline:     apiv1.Pipeline{Spec: runtime.RawExtension{Raw: []byte(pipelineSpec)}},
+			},
+		}
+	}
+	replicas := "two"
+
+	testCases := []struct {
+		name             string
+		pipelineTemplate *apiv1.PipelineTemplate
+		expectedErrors   []string
+	}{
+		{
+			name: "valid",
+			pipelineTemplate: makePipelineTemplate(`{"vertices":[{"name":"{{.pipeline-name}}-{{.params.vertexName}}-{{.values.region}}","scale":{"min":"{{.params.replicas}}"}}]}`,
+				apiv1.TemplateParameter{Name: "vertexName"}, apiv1.TemplateParameter{Name: "replicas", Type: apiv1.TemplateParameterTypeInteger}),
+		},
+		{
+			name:             "undeclared parameter",
+			pipelineTemplate: makePipelineTemplate(`{"vertices":[{"name":"{{.params.vertexName}}"}]}`),
+			expectedErrors:   []string{"spec.pipeline", "{{.params.vertexName}}", "isn't declared"},
+		},
+		{
+			name: "default doesn't match the parameter type",
+			pipelineTemplate: makePipelineTemplate(`{"vertices":[{"name":"in","scale":{"min":"{{.params.replicas}}"}}]}`,
+				apiv1.TemplateParameter{Name: "replicas", Type: apiv1.TemplateParameterTypeInteger, Default: &replicas}),
+			expectedErrors: []string{"spec.parameters[0].default"},
+		},
+		{
+			name: "resolved spec doesn't decode",
+			pipelineTemplate: makePipelineTemplate(`{"vertices":[{"name":"{{.params.vertexName}}"}]}`,
+				apiv1.TemplateParameter{Name: "vertexName", Type: apiv1.TemplateParameterTypeBoolean}),
+			expectedErrors: []string{"spec.pipeline.spec", "not a valid Pipeline spec"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := validator.ValidateCreate(ctx, tc.pipelineTemplate)
+			if len(tc.expectedErrors) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, apierrors.IsInvalid(err), "expected an Invalid error but got %v", err)
+			for _, expectedError := range tc.expectedErrors {
+				assert.ErrorContains(t, err, expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// SetupPipelineTemplateWebhookWithManager registers the webhook for PipelineTemplate in the manager
+func SetupPipelineTemplateWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&apiv1.PipelineTemplate{}).
+		WithValidator(&PipelineTemplateCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-numaplane-numaproj-io-v1alpha1-pipelinetemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=numaplane.numaproj.io,resources=pipelinetemplates,verbs=create;update,versions=v1alpha1,name=vpipelinetemplate-v1alpha1.numaplane.numaproj.io,admissionReviewVersions=v1
+
+// PipelineTemplateCustomValidator validates a PipelineTemplate when it's created or updated
+type PipelineTemplateCustomValidator struct{}
+
+var _ webhook.CustomValidator = &PipelineTemplateCustomValidator{}
+
+func (v *PipelineTemplateCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pipelineTemplate, ok := obj.(*apiv1.PipelineTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected a PipelineTemplate object but got %T", obj)
+	}
+	return nil, v.validate(pipelineTemplate)
+}
+
+func (v *PipelineTemplateCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldPipelineTemplate, ok := oldObj.(*apiv1.PipelineTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected a PipelineTemplate object for the oldObj but got %T", oldObj)
+	}
+	pipelineTemplate, ok := newObj.(*apiv1.PipelineTemplate)
+	if !ok {
+		return nil, fmt.Errorf("expected a PipelineTemplate object for the newObj but got %T", newObj)
+	}
+	if skipUpdateValidation(pipelineTemplate, oldPipelineTemplate.Spec, pipelineTemplate.Spec) {
+		return nil, nil
+	}
+	return nil, v.validate(pipelineTemplate)
+}
+
+func (v *PipelineTemplateCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *PipelineTemplateCustomValidator) validate(pipelineTemplate *apiv1.PipelineTemplate) error {
+	args := pipelineTemplateArguments(pipelineTemplate)
+
+	allErrs := validateTemplate(field.NewPath("spec"), pipelineTemplate, "pipeline", args, &numaflowv1.PipelineSpec{}, numaflowv1.PipelineGroupVersionKind.Kind)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(apiv1.PipelineTemplateGroupVersionKind.GroupKind(), pipelineTemplate.Name, allErrs)
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"sort"
+	"strings"
+
+	argorolloutsv1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/numaproj/numaplane/internal/common"
+	ctlrcommon "github.com/numaproj/numaplane/internal/controller/common"
+	"github.com/numaproj/numaplane/internal/controller/common/riders"
+	"github.com/numaproj/numaplane/internal/controller/common/templates"
+	"github.com/numaproj/numaplane/internal/controller/progressive"
+	"github.com/numaproj/numaplane/internal/util"
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// skipUpdateValidation returns true if an update doesn't need to be validated: either the spec didn't change (e.g. only
+// labels, annotations or finalizers did), or the object is being deleted, in which case it mustn't be blocked
+func skipUpdateValidation(newObj client.Object, oldSpec, newSpec any) bool {
+	return newObj.GetDeletionTimestamp() != nil || apiequality.Semantic.DeepEqual(oldSpec, newSpec)
+}
+
+// childTemplateArguments returns the template arguments used to validate a Rollout's child definition, whose name
+// (and upgrade state) isn't known until it's created
+func childTemplateArguments(nameArg, namespaceArg string, rolloutObject client.Object) map[string]interface{} {
+	return map[string]interface{}{
+		nameArg:                           fmt.Sprintf("%s-0", rolloutObject.GetName()),
+		namespaceArg:                      rolloutObject.GetNamespace(),
+		common.TemplateRolloutLabels:      rolloutObject.GetLabels(),
+		common.TemplateRolloutAnnotations: rolloutObject.GetAnnotations(),
+		common.TemplateChildIndex:         0,
+		common.TemplateUpgradeState:       string(common.LabelValueUpgradePromoted),
+	}
+}
+
+// pipelineTemplateArguments returns the template arguments used to validate a Pipeline definition, which can also reference
+// the name of the InterStepBufferService it uses
+func pipelineTemplateArguments(rolloutObject client.Object) map[string]interface{} {
+	args := childTemplateArguments(common.TemplatePipelineName, common.TemplatePipelineNamespace, rolloutObject)
+	args[common.TemplateISBServiceName] = "isbsvc-0"
+	return args
+}
+
+// validateTemplateVariables verifies that every template variable in the data is one of the arguments
+func validateTemplateVariables(path *field.Path, data any, args map[string]interface{}) field.ErrorList {
+	unresolved, err := util.GetUnresolvedTemplateVariables(data, args)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, "", fmt.Sprintf("failed to parse template: %v", err))}
+	}
+
+	allErrs := field.ErrorList{}
+	for _, variable := range unresolved {
+		allErrs = append(allErrs, field.Invalid(path, fmt.Sprintf("{{%s}}", variable),
+			fmt.Sprintf("unknown template variable; supported variables are: %s", formatTemplateArguments(args))))
+	}
+	return allErrs
+}
+
+func formatTemplateArguments(args map[string]interface{}) string {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "{{" + strings.Join(names, "}}, {{") + "}}"
+}
+
+// validateChildDefinition verifies that the templated metadata and spec of a Rollout's child resolve, and that the resolved
+// spec decodes into the Numaflow type
+func validateChildDefinition(path *field.Path, metadata apiv1.Metadata, spec runtime.RawExtension, args map[string]interface{}, childSpec any, childKind string) field.ErrorList {
+	allErrs := validateTemplateVariables(path.Child("metadata"), metadata, args)
+
+	specPath := path.Child("spec")
+	specErrs := validateTemplateVariables(specPath, spec, args)
+	allErrs = append(allErrs, specErrs...)
+	if len(specErrs) > 0 {
+		return allErrs
+	}
+
+	resolvedSpec, err := util.ResolveTemplatedSpec(spec, args)
+	if err != nil {
+		return append(allErrs, field.Invalid(specPath, "", fmt.Sprintf("failed to resolve template: %v", err)))
+	}
+	specBytes, err := json.Marshal(resolvedSpec)
+	if err != nil {
+		return append(allErrs, field.InternalError(specPath, err))
+	}
+	if err := json.Unmarshal(specBytes, childSpec); err != nil {
+		return append(allErrs, field.Invalid(specPath, "", fmt.Sprintf("not a valid %s spec: %v", childKind, err)))
+	}
+	return allErrs
+}
+
+// placeholder values for template parameters without a default, used to validate that the template resolves
+var templateParameterPlaceholders = map[apiv1.TemplateParameterType]string{
+	apiv1.TemplateParameterTypeString:  "value",
+	apiv1.TemplateParameterTypeInteger: "0",
+	apiv1.TemplateParameterTypeNumber:  "0",
+	apiv1.TemplateParameterTypeBoolean: "false",
+	"":                                 "value",
+}
+
+// validateTemplate verifies a template's parameters, and that its child definition resolves with the parameters' defaults (or placeholder
+// values for the parameters without one) into a valid child definition
+func validateTemplate(path *field.Path, template templates.TemplateObject, childField string, args map[string]interface{}, childSpec any, childKind string) field.ErrorList {
+	allErrs := field.ErrorList{}
+	childPath := path.Child(childField)
+
+	values := map[string]string{}
+	declared := map[string]struct{}{}
+	for i, parameter := range template.GetTemplateSpec().Parameters {
+		declared[parameter.Name] = struct{}{}
+		if parameter.Default == nil {
+			values[parameter.Name] = templateParameterPlaceholders[parameter.Type]
+			continue
+		}
+		if _, err := templates.ParseParameterValue(parameter, *parameter.Default); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("parameters").Index(i).Child("default"), *parameter.Default, err.Error()))
+		}
+	}
+
+	references, err := templates.GetParameterReferences(template)
+	if err != nil {
+		return append(allErrs, field.InternalError(childPath, err))
+	}
+	for _, name := range references {
+		if _, found := declared[name]; !found {
+			allErrs = append(allErrs, field.Invalid(childPath, fmt.Sprintf("{{.params.%s}}", name), "parameter isn't declared in spec.parameters"))
+		}
+	}
+	if len(allErrs) > 0 {
+		return allErrs
+	}
+
+	metadata, spec, err := templates.ResolveTemplate(template, values, apiv1.Metadata{})
+	if err != nil {
+		return field.ErrorList{field.Invalid(childPath, "", fmt.Sprintf("failed to resolve template: %v", err))}
+	}
+	// the values are supplied by the ValuesFrom of each Rollout referencing the template, so placeholders are used for those referenced
+	args = maps.Clone(args)
+	args[common.TemplateValues] = templateValuePlaceholders(metadata, spec)
+	return validateChildDefinition(childPath, metadata, spec, args, childSpec, childKind)
+}
+
+// a reference to a value from a Rollout's ValuesFrom, e.g. "{{.values.region}}"
+var valueReferenceRegex = regexp.MustCompile(`\.values\.([a-zA-Z_][a-zA-Z0-9_]*)`)
+
+// templateValuePlaceholders returns a placeholder for each value referenced by the child definition
+func templateValuePlaceholders(metadata apiv1.Metadata, spec runtime.RawExtension) map[string]string {
+	placeholders := map[string]string{}
+	metadataBytes, _ := json.Marshal(metadata)
+	for _, data := range [][]byte{metadataBytes, spec.Raw} {
+		for _, match := range valueReferenceRegex.FindAllSubmatch(data, -1) {
+			placeholders[string(match[1])] = "value"
+		}
+	}
+	return placeholders
+}
+
+// addTemplateValues adds the values from the ConfigMaps and Secrets referenced by the Rollout's ValuesFrom to the template arguments,
+// verifying that those which aren't optional exist
+func addTemplateValues(ctx context.Context, c client.Client, path *field.Path, rolloutObject ctlrcommon.ValuesFromRolloutObject,
+	args map[string]interface{}) field.ErrorList {
+	values, err := ctlrcommon.GetTemplateValues(ctx, c, rolloutObject)
+	if err != nil {
+		args[common.TemplateValues] = map[string]string{}
+		return field.ErrorList{field.Invalid(path, rolloutObject.GetValuesFrom(), err.Error())}
+	}
+	args[common.TemplateValues] = values
+	return nil
+}
+
+// resolveTemplateRef resolves the template referenced by the Rollout into its in-memory child definition (so the Rollout should be a
+// copy), verifying that the template exists and that the Rollout's values are valid for it
+func resolveTemplateRef(ctx context.Context, c client.Client, path *field.Path, rolloutObject templates.TemplatedRolloutObject) field.ErrorList {
+	templateRef := rolloutObject.GetTemplateRef()
+	if err := templates.ResolveTemplateRef(ctx, c, rolloutObject); err != nil {
+		if apierrors.IsNotFound(err) {
+			return field.ErrorList{field.NotFound(path.Child("name"), templateRef.Name)}
+		}
+		return field.ErrorList{field.Invalid(path.Child("values"), templateRef.Values, err.Error())}
+	}
+	return nil
+}
+
+// validateRider verifies that a Rider's templated definition resolves and is of a permitted Kind
+func validateRider(path *field.Path, rider apiv1.Rider, args map[string]interface{}) field.ErrorList {
+	definitionPath := path.Child("definition")
+
+	definition := unstructured.Unstructured{}
+	if err := json.Unmarshal(rider.Definition.Raw, &definition.Object); err != nil {
+		return field.ErrorList{field.Invalid(definitionPath, "", fmt.Sprintf("not a valid resource definition: %v", err))}
+	}
+	if definition.GetKind() == "" || definition.GetAPIVersion() == "" {
+		return field.ErrorList{field.Required(definitionPath, "apiVersion and kind are required")}
+	}
+
+	allErrs := validateTemplateVariables(definitionPath, definition.Object, args)
+
+	unpermittedKinds, err := riders.GetUnpermittedRiderKinds([]riders.Rider{{Definition: definition}})
+	if err != nil {
+		return append(allErrs, field.InternalError(definitionPath, fmt.Errorf("failed to get permitted Rider kinds: %w", err)))
+	}
+	for _, kind := range unpermittedKinds {
+		allErrs = append(allErrs, field.Forbidden(definitionPath.Child("kind"),
+			fmt.Sprintf("%s is not a permitted Rider kind (see \"permittedRiders\" in the Numaplane controller config)", kind)))
+	}
+	return allErrs
+}
+
+// validateAssessmentSchedule verifies that the schedule, whether structured or in the deprecated comma-separated format, is valid
+func validateAssessmentSchedule(path *field.Path, schedule *apiv1.AssessmentSchedule, scheduleString string) field.ErrorList {
+	if _, _, err := progressive.ResolveAssessmentSchedule(schedule, scheduleString); err != nil {
+		if schedule != nil {
+			return field.ErrorList{field.Invalid(path.Child("schedule"), *schedule, err.Error())}
+		}
+		return field.ErrorList{field.Invalid(path.Child("assessmentSchedule"), scheduleString, err.Error())}
+	}
+	return nil
+}
+
+// validateAnalysis verifies that the AnalysisTemplates and ClusterAnalysisTemplates referenced by the Analysis exist
+func validateAnalysis(ctx context.Context, c client.Client, namespace string, path *field.Path, analysis apiv1.Analysis) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, templateRef := range analysis.Templates {
+		templatePath := path.Child("templates").Index(i).Child("templateName")
+		_, _, err := progressive.GetAnalysisTemplatesFromRefs(ctx, &[]argorolloutsv1.AnalysisTemplateRef{templateRef}, namespace, c)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				allErrs = append(allErrs, field.NotFound(templatePath, fmt.Sprintf("%s (%s)", templateRef.TemplateName, analysisTemplateKind(templateRef))))
+			} else {
+				allErrs = append(allErrs, field.InternalError(templatePath, err))
+			}
+		}
+	}
+	return allErrs
+}
+
+func analysisTemplateKind(templateRef argorolloutsv1.AnalysisTemplateRef) string {
+	if templateRef.ClusterScope {
+		return "ClusterAnalysisTemplate"
+	}
+	return "AnalysisTemplate"
+}
+
+// validateProgressiveStrategy verifies the assessment schedules of a ProgressiveStrategy and the Analysis of each of its Steps
+func validateProgressiveStrategy(ctx context.Context, c client.Client, namespace string, path *field.Path, strategy apiv1.ProgressiveStrategy) field.ErrorList {
+	allErrs := validateAssessmentSchedule(path, strategy.Schedule, strategy.AssessmentSchedule)
+	for i, step := range strategy.Steps {
+		stepPath := path.Child("steps").Index(i)
+		allErrs = append(allErrs, validateAssessmentSchedule(stepPath, step.Schedule, step.AssessmentSchedule)...)
+		if step.Analysis != nil {
+			allErrs = append(allErrs, validateAnalysis(ctx, c, namespace, stepPath.Child("analysis"), *step.Analysis)...)
+		}
+	}
+	return allErrs
+}
+
+// validateNoProgressiveSteps rejects Steps in the Progressive strategy of a Rollout which doesn't support them
+func validateNoProgressiveSteps(path *field.Path, strategy apiv1.ProgressiveStrategy) field.ErrorList {
+	if len(strategy.Steps) == 0 {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(path.Child("steps"), "steps are only supported for MonoVertexRollout")}
+}
+
+// validatePipelineTypeRolloutStrategy verifies the Progressive strategy and Analyses of a PipelineRollout or MonoVertexRollout
+func validatePipelineTypeRolloutStrategy(ctx context.Context, c client.Client, namespace string, path *field.Path, strategy *apiv1.PipelineTypeRolloutStrategy) field.ErrorList {
+	if strategy == nil {
+		return nil
+	}
+
+	allErrs := validateProgressiveStrategy(ctx, c, namespace, path.Child("progressive"), strategy.Progressive)
+	allErrs = append(allErrs, validateAnalysis(ctx, c, namespace, path.Child("analysis"), strategy.Analysis)...)
+	if strategy.PostPromotionAnalysis != nil && strategy.PostPromotionAnalysis.Analysis != nil {
+		allErrs = append(allErrs, validateAnalysis(ctx, c, namespace, path.Child("postPromotionAnalysis", "analysis"), *strategy.PostPromotionAnalysis.Analysis)...)
+	}
+	return allErrs
+}
@@ -48,6 +48,12 @@ var (
 
 	NumaflowControllerGroupVersionKind     = SchemeGroupVersion.WithKind("NumaflowController")
 	NumaflowControllerGroupVersionResource = SchemeGroupVersion.WithResource("numaflowcontrollers")
+
+	PipelineTemplateGroupVersionKind     = SchemeGroupVersion.WithKind("PipelineTemplate")
+	PipelineTemplateGroupVersionResource = SchemeGroupVersion.WithResource("pipelinetemplates")
+
+	MonoVertexTemplateGroupVersionKind     = SchemeGroupVersion.WithKind("MonoVertexTemplate")
+	MonoVertexTemplateGroupVersionResource = SchemeGroupVersion.WithResource("monovertextemplates")
 )
 
 // Resource takes an unqualified resource and returns a Group qualified GroupResource
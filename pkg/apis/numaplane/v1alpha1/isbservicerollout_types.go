@@ -32,10 +32,29 @@ type ISBServiceRolloutSpec struct {
 	InterStepBufferService InterStepBufferService     `json:"interStepBufferService"`
 	Strategy               *ISBServiceRolloutStrategy `json:"strategy,omitempty"`
 	Riders                 []Rider                    `json:"riders,omitempty"`
+
+	// ValuesFrom references ConfigMaps and Secrets whose data is available for templating the child definition and Riders as
+	// {{.values.<key>}}. Where more than one of them has the same key, the last one takes precedence.
+	// A change to any of them upgrades the child just like a change to its definition.
+	// +optional
+	ValuesFrom []ValuesFromSource `json:"valuesFrom,omitempty"`
+
+	// RevisionHistoryLimit is the maximum number of revisions of the child definition to retain (default 10)
+	// +kubebuilder:validation:Minimum=0
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
 }
 
+// +kubebuilder:validation:XValidation:rule="!has(self.progressive) || !has(self.progressive.steps)",message="progressive.steps is only supported for MonoVertexRollout"
 type ISBServiceRolloutStrategy struct {
 	Progressive ProgressiveStrategy `json:"progressive,omitempty"`
+
+	// RequireApprovalOfPlan, if set, causes the controller to publish an UpgradePlan in the Status when the Rollout changes,
+	// and to wait for it to be approved before starting to upgrade the child
+	RequireApprovalOfPlan bool `json:"requireApprovalOfPlan,omitempty"`
+
+	// UpgradeStrategy, if set, selects the strategy used to upgrade the child, overriding the namespace and global configuration
+	// +optional
+	UpgradeStrategy UserUpgradeStrategy `json:"upgradeStrategy,omitempty"`
 }
 
 // InterStepBufferService includes the spec of InterStepBufferService in Numaflow
@@ -50,6 +69,14 @@ type ISBServiceRolloutStatus struct {
 
 	PauseRequestStatus PauseStatus `json:"pauseRequestStatus,omitempty"`
 
+	// PauseRequested is the last request this Rollout made of its Pipelines as to whether they need to pause under the PPND strategy,
+	// persisted so that it can be restored when Numaplane restarts or another instance becomes the leader
+	PauseRequested *bool `json:"pauseRequested,omitempty"`
+
+	// PauseTimedOutGeneration is the generation of the Rollout whose upgrade failed because its Pipelines didn't pause within their
+	// pause timeout: the upgrade isn't attempted again until the Rollout changes
+	PauseTimedOutGeneration int64 `json:"pauseTimedOutGeneration,omitempty"`
+
 	// NameCount is used as a suffix for the name of the managed isbsvc, to uniquely
 	// identify an isbsvc.
 	NameCount *int32 `json:"nameCount,omitempty"`
@@ -80,6 +107,7 @@ type PromotedISBServiceStatus struct {
 
 // +genclient
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The current phase"
@@ -133,6 +161,22 @@ func (isbServiceRollout *ISBServiceRollout) GetRolloutStatus() *Status {
 	return &isbServiceRollout.Status.Status
 }
 
+func (isbServiceRollout *ISBServiceRollout) GetPauseTimedOutGeneration() int64 {
+	return isbServiceRollout.Status.PauseTimedOutGeneration
+}
+
+func (isbServiceRollout *ISBServiceRollout) SetPauseTimedOutGeneration(generation int64) {
+	isbServiceRollout.Status.PauseTimedOutGeneration = generation
+}
+
+// GetUpgradeStrategy returns the upgrade strategy selected by the Rollout, or "" if it doesn't select one
+func (isbServiceRollout *ISBServiceRollout) GetUpgradeStrategy() UserUpgradeStrategy {
+	if isbServiceRollout.Spec.Strategy == nil {
+		return ""
+	}
+	return isbServiceRollout.Spec.Strategy.UpgradeStrategy
+}
+
 // GetProgressiveStrategy is a function of the progressiveRolloutObject
 func (isbServiceRollout *ISBServiceRollout) GetProgressiveStrategy() ProgressiveStrategy {
 	// if the Strategy is not set, return an empty ProgressiveStrategy
@@ -143,6 +187,27 @@ func (isbServiceRollout *ISBServiceRollout) GetProgressiveStrategy() Progressive
 	return isbServiceRollout.Spec.Strategy.Progressive
 }
 
+// GetProgressiveSteps is a function of the progressiveRolloutObject
+// Steps are not currently supported for ISBServiceRollout (they're rejected by validation), so this always returns nil
+func (isbServiceRollout *ISBServiceRollout) GetProgressiveSteps() []ProgressiveStep {
+	return nil
+}
+
+// GetPostPromotionAnalysis returns nil since post-promotion analysis is not supported for ISBServiceRollout
+func (isbServiceRollout *ISBServiceRollout) GetPostPromotionAnalysis() *PostPromotionAnalysis {
+	return nil
+}
+
+// GetRevisionHistoryLimit returns the maximum number of revisions of the child definition to retain, or nil if not set
+func (isbServiceRollout *ISBServiceRollout) GetRevisionHistoryLimit() *int32 {
+	return isbServiceRollout.Spec.RevisionHistoryLimit
+}
+
+// GetValuesFrom returns the references to the ConfigMaps and Secrets which supply values for templating the child definition and Riders
+func (isbServiceRollout *ISBServiceRollout) GetValuesFrom() []ValuesFromSource {
+	return isbServiceRollout.Spec.ValuesFrom
+}
+
 // GetUpgradingChildStatus is a function of the progressiveRolloutObject
 func (isbServiceRollout *ISBServiceRollout) GetUpgradingChildStatus() *UpgradingChildStatus {
 	if isbServiceRollout.Status.ProgressiveStatus.UpgradingISBServiceStatus == nil {
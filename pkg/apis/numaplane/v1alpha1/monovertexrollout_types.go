@@ -30,16 +30,34 @@ const (
 )
 
 // MonoVertexRolloutSpec defines the desired state of MonoVertexRollout
+// +kubebuilder:validation:XValidation:rule="has(self.templateRef) != (has(self.monoVertex) && has(self.monoVertex.spec))",message="exactly one of monoVertex.spec and templateRef must be set"
 type MonoVertexRolloutSpec struct {
-	MonoVertex MonoVertex                   `json:"monoVertex"`
-	Strategy   *PipelineTypeRolloutStrategy `json:"strategy,omitempty"`
-	Riders     []Rider                      `json:"riders,omitempty"`
+	// MonoVertex defines the MonoVertex, unless it's defined by a MonoVertexTemplate: in that case only its metadata may be set,
+	// which is added to the template's
+	// +optional
+	MonoVertex MonoVertex `json:"monoVertex,omitempty"`
+	// TemplateRef references the MonoVertexTemplate which defines the MonoVertex
+	// +optional
+	TemplateRef *TemplateReference           `json:"templateRef,omitempty"`
+	Strategy    *PipelineTypeRolloutStrategy `json:"strategy,omitempty"`
+	Riders      []Rider                      `json:"riders,omitempty"`
+
+	// ValuesFrom references ConfigMaps and Secrets whose data is available for templating the child definition and Riders as
+	// {{.values.<key>}}. Where more than one of them has the same key, the last one takes precedence.
+	// A change to any of them upgrades the child just like a change to its definition.
+	// +optional
+	ValuesFrom []ValuesFromSource `json:"valuesFrom,omitempty"`
+
+	// RevisionHistoryLimit is the maximum number of revisions of the child definition to retain (default 10)
+	// +kubebuilder:validation:Minimum=0
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
 }
 
 // MonoVertex includes the spec of MonoVertex in Numaflow
 type MonoVertex struct {
 	Metadata `json:"metadata,omitempty"`
-	Spec     runtime.RawExtension `json:"spec"`
+	// +optional
+	Spec runtime.RawExtension `json:"spec"`
 }
 
 // MonoVertexRolloutStatus defines the observed state of MonoVertexRollout
@@ -55,6 +73,9 @@ type MonoVertexRolloutStatus struct {
 
 	// Riders stores the list of Riders that have been deployed along with the "promoted" MonoVertex
 	Riders []RiderStatus `json:"riders,omitempty"`
+
+	// Template describes the MonoVertexTemplate which defines the MonoVertex, if there is one
+	Template *TemplateStatus `json:"template,omitempty"`
 }
 
 type MonoVertexProgressiveStatus struct {
@@ -83,6 +104,7 @@ type PromotedMonoVertexStatus struct {
 
 // +genclient
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The current phase"
@@ -137,6 +159,14 @@ func (monoVertexRollout *MonoVertexRollout) GetRolloutStatus() *Status {
 	return &monoVertexRollout.Status.Status
 }
 
+// GetUpgradeStrategy returns the upgrade strategy selected by the Rollout, or "" if it doesn't select one
+func (monoVertexRollout *MonoVertexRollout) GetUpgradeStrategy() UserUpgradeStrategy {
+	if monoVertexRollout.Spec.Strategy == nil {
+		return ""
+	}
+	return monoVertexRollout.Spec.Strategy.UpgradeStrategy
+}
+
 // GetProgressiveStrategy is a function of the progressiveRolloutObject
 func (monoVertexRollout *MonoVertexRollout) GetProgressiveStrategy() ProgressiveStrategy {
 	// if the Strategy is not set, return an empty ProgressiveStrategy
@@ -147,6 +177,11 @@ func (monoVertexRollout *MonoVertexRollout) GetProgressiveStrategy() Progressive
 	return monoVertexRollout.Spec.Strategy.Progressive
 }
 
+// GetProgressiveSteps is a function of the progressiveRolloutObject
+func (monoVertexRollout *MonoVertexRollout) GetProgressiveSteps() []ProgressiveStep {
+	return monoVertexRollout.GetProgressiveStrategy().Steps
+}
+
 func (monoVertexRollout *MonoVertexRollout) GetAnalysis() Analysis {
 	if monoVertexRollout.Spec.Strategy == nil {
 		return Analysis{}
@@ -154,6 +189,30 @@ func (monoVertexRollout *MonoVertexRollout) GetAnalysis() Analysis {
 	return monoVertexRollout.Spec.Strategy.Analysis
 }
 
+// GetPostPromotionAnalysis returns the PostPromotionAnalysis for the Rollout, or nil if it's not defined.
+// If the PostPromotionAnalysis doesn't define its own Analysis, the Rollout's Analysis is used.
+func (monoVertexRollout *MonoVertexRollout) GetPostPromotionAnalysis() *PostPromotionAnalysis {
+	if monoVertexRollout.Spec.Strategy == nil || monoVertexRollout.Spec.Strategy.PostPromotionAnalysis == nil {
+		return nil
+	}
+	postPromotionAnalysis := *monoVertexRollout.Spec.Strategy.PostPromotionAnalysis
+	if postPromotionAnalysis.Analysis == nil {
+		analysis := monoVertexRollout.GetAnalysis()
+		postPromotionAnalysis.Analysis = &analysis
+	}
+	return &postPromotionAnalysis
+}
+
+// GetRevisionHistoryLimit returns the maximum number of revisions of the child definition to retain, or nil if not set
+func (monoVertexRollout *MonoVertexRollout) GetRevisionHistoryLimit() *int32 {
+	return monoVertexRollout.Spec.RevisionHistoryLimit
+}
+
+// GetValuesFrom returns the references to the ConfigMaps and Secrets which supply values for templating the child definition and Riders
+func (monoVertexRollout *MonoVertexRollout) GetValuesFrom() []ValuesFromSource {
+	return monoVertexRollout.Spec.ValuesFrom
+}
+
 // GetUpgradingChildStatus is a function of the progressiveRolloutObject
 func (monoVertexRollout *MonoVertexRollout) GetUpgradingChildStatus() *UpgradingChildStatus {
 	if monoVertexRollout.Status.ProgressiveStatus.UpgradingMonoVertexStatus == nil {
@@ -232,6 +291,19 @@ func (monoVertexRollout *MonoVertexRollout) SetPromotedChildStatus(status *Promo
 	monoVertexRollout.Status.ProgressiveStatus.PromotedMonoVertexStatus.PromotedPipelineTypeStatus.PromotedChildStatus = *status.DeepCopy()
 }
 
+// GetTemplateRef returns the reference to the MonoVertexTemplate which defines the MonoVertex, or nil if it's defined inline
+func (monoVertexRollout *MonoVertexRollout) GetTemplateRef() *TemplateReference {
+	return monoVertexRollout.Spec.TemplateRef
+}
+
+func (monoVertexRollout *MonoVertexRollout) GetTemplateStatus() *TemplateStatus {
+	return monoVertexRollout.Status.Template
+}
+
+func (monoVertexRollout *MonoVertexRollout) SetTemplateStatus(status *TemplateStatus) {
+	monoVertexRollout.Status.Template = status
+}
+
 func (monoVertexRollout *MonoVertexRollout) GetChildMetadata() Metadata {
 	return monoVertexRollout.Spec.MonoVertex.Metadata
 }
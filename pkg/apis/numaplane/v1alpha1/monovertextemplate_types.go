@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MonoVertexTemplateSpec defines a parameterized MonoVertex which MonoVertexRollouts can reference instead of defining the MonoVertex inline
+type MonoVertexTemplateSpec struct {
+	TemplateSpec `json:",inline"`
+
+	// MonoVertex is the templated MonoVertex, which can reference the template's parameters as well as the variables which are
+	// available to a MonoVertexRollout's MonoVertex
+	MonoVertex MonoVertex `json:"monoVertex"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Max Concurrent Upgrades",type="integer",JSONPath=".spec.maxConcurrentUpgrades"
+// MonoVertexTemplate is the Schema for the monovertextemplates API
+type MonoVertexTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MonoVertexTemplateSpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+
+// MonoVertexTemplateList contains a list of MonoVertexTemplate
+type MonoVertexTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MonoVertexTemplate `json:"items"`
+}
+
+func (monoVertexTemplate *MonoVertexTemplate) GetTemplateSpec() *TemplateSpec {
+	return &monoVertexTemplate.Spec.TemplateSpec
+}
+
+func init() {
+	SchemeBuilder.Register(&MonoVertexTemplate{}, &MonoVertexTemplateList{})
+}
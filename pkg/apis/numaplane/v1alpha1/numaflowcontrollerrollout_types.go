@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -39,10 +40,19 @@ type NumaflowControllerRolloutSpec struct {
 type NumaflowControllerRolloutStatus struct {
 	Status             `json:",inline"`
 	PauseRequestStatus PauseStatus `json:"pauseRequestStatus,omitempty"`
+
+	// PauseRequested is the last request this Rollout made of its Pipelines as to whether they need to pause under the PPND strategy,
+	// persisted so that it can be restored when Numaplane restarts or another instance becomes the leader
+	PauseRequested *bool `json:"pauseRequested,omitempty"`
+
+	// PauseTimedOutGeneration is the generation of the Rollout whose upgrade failed because its Pipelines didn't pause within their
+	// pause timeout: the upgrade isn't attempted again until the Rollout changes
+	PauseTimedOutGeneration int64 `json:"pauseTimedOutGeneration,omitempty"`
 }
 
 // +genclient
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:validation:XValidation:rule="matches(self.metadata.name, '^numaflow-controller.*')",message="The metadata name must start with 'numaflow-controller'"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
@@ -69,3 +79,43 @@ type NumaflowControllerRolloutList struct {
 func init() {
 	SchemeBuilder.Register(&NumaflowControllerRollout{}, &NumaflowControllerRolloutList{})
 }
+
+func (nfcRollout *NumaflowControllerRollout) GetRolloutGVR() metav1.GroupVersionResource {
+	return metav1.GroupVersionResource{
+		Group:    nfcRollout.TypeMeta.GroupVersionKind().Group,
+		Version:  nfcRollout.TypeMeta.GroupVersionKind().Version,
+		Resource: "numaflowcontrollerrollouts",
+	}
+}
+
+func (nfcRollout *NumaflowControllerRollout) GetRolloutGVK() schema.GroupVersionKind {
+	return nfcRollout.TypeMeta.GroupVersionKind()
+}
+
+func (nfcRollout *NumaflowControllerRollout) GetChildGVR() metav1.GroupVersionResource {
+	return metav1.GroupVersionResource{
+		Group:    NumaflowControllerGroupVersionResource.Group,
+		Version:  NumaflowControllerGroupVersionResource.Version,
+		Resource: NumaflowControllerGroupVersionResource.Resource,
+	}
+}
+
+func (nfcRollout *NumaflowControllerRollout) GetChildGVK() schema.GroupVersionKind {
+	return NumaflowControllerGroupVersionKind
+}
+
+func (nfcRollout *NumaflowControllerRollout) GetRolloutObjectMeta() *metav1.ObjectMeta {
+	return &nfcRollout.ObjectMeta
+}
+
+func (nfcRollout *NumaflowControllerRollout) GetRolloutStatus() *Status {
+	return &nfcRollout.Status.Status
+}
+
+func (nfcRollout *NumaflowControllerRollout) GetPauseTimedOutGeneration() int64 {
+	return nfcRollout.Status.PauseTimedOutGeneration
+}
+
+func (nfcRollout *NumaflowControllerRollout) SetPauseTimedOutGeneration(generation int64) {
+	nfcRollout.Status.PauseTimedOutGeneration = generation
+}
@@ -31,13 +31,43 @@ const (
 	ConditionPipelinePausingOrPaused ConditionType = "PipelinePausingOrPaused"
 )
 
+// the reasons for which a Pipeline can be held paused
+const (
+	// PauseReasonNumaflowControllerUpdate means the NumaflowControllerRollout needs the Pipeline paused to update the Numaflow Controller
+	PauseReasonNumaflowControllerUpdate = "NumaflowControllerUpdate"
+	// PauseReasonISBServiceUpdate means the ISBServiceRollout needs the Pipeline paused to update the InterStepBufferService
+	PauseReasonISBServiceUpdate = "ISBServiceUpdate"
+	// PauseReasonPipelineUpdate means the PipelineRollout needs the Pipeline paused to update it
+	PauseReasonPipelineUpdate = "PipelineUpdate"
+	// PauseReasonDesiredPhase means the PipelineRollout's spec sets the Pipeline's desiredPhase to Paused
+	PauseReasonDesiredPhase = "DesiredPhasePaused"
+)
+
 // PipelineRolloutSpec defines the desired state of PipelineRollout
+// +kubebuilder:validation:XValidation:rule="has(self.templateRef) != (has(self.pipeline) && has(self.pipeline.spec))",message="exactly one of pipeline.spec and templateRef must be set"
 type PipelineRolloutSpec struct {
-	Pipeline Pipeline          `json:"pipeline"`
-	Strategy *PipelineStrategy `json:"strategy,omitempty"`
-	Riders   []PipelineRider   `json:"riders,omitempty"`
-}
-
+	// Pipeline defines the Pipeline, unless it's defined by a PipelineTemplate: in that case only its metadata may be set, which
+	// is added to the template's
+	// +optional
+	Pipeline Pipeline `json:"pipeline,omitempty"`
+	// TemplateRef references the PipelineTemplate which defines the Pipeline
+	// +optional
+	TemplateRef *TemplateReference `json:"templateRef,omitempty"`
+	Strategy    *PipelineStrategy  `json:"strategy,omitempty"`
+	Riders      []PipelineRider    `json:"riders,omitempty"`
+
+	// ValuesFrom references ConfigMaps and Secrets whose data is available for templating the child definition and Riders as
+	// {{.values.<key>}}. Where more than one of them has the same key, the last one takes precedence.
+	// A change to any of them upgrades the child just like a change to its definition.
+	// +optional
+	ValuesFrom []ValuesFromSource `json:"valuesFrom,omitempty"`
+
+	// RevisionHistoryLimit is the maximum number of revisions of the child definition to retain (default 10)
+	// +kubebuilder:validation:Minimum=0
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.progressive) || !has(self.progressive.steps)",message="progressive.steps is only supported for MonoVertexRollout"
 type PipelineStrategy struct {
 	PipelineTypeRolloutStrategy `json:",inline"`
 
@@ -49,6 +79,37 @@ type PipelineStrategy struct {
 type PPNDStrategy struct {
 	// FastResume indicates if the Pipeline should be resumed with the number of replicas it had before it was paused.
 	FastResume bool `json:"fastResume,omitempty"`
+
+	// PauseTimeout bounds how long an ISBServiceRollout or NumaflowControllerRollout waits for the Pipeline to pause before
+	// updating its child, and defines what to do if it doesn't.
+	// If not defined, fallback to the one defined in the namespace-level ConfigMap and then the global ConfigMap
+	// +optional
+	PauseTimeout *PauseTimeout `json:"pauseTimeout,omitempty"`
+}
+
+// PauseTimeoutPolicy is what to do when a Pipeline doesn't pause within its pause timeout
+// +kubebuilder:validation:Enum=Fail;Proceed;ForceDrain
+type PauseTimeoutPolicy string
+
+const (
+	// PauseTimeoutPolicyFail fails the upgrade which needed the Pipelines paused and resumes them
+	PauseTimeoutPolicyFail PauseTimeoutPolicy = "Fail"
+	// PauseTimeoutPolicyProceed proceeds with the upgrade without waiting for the Pipeline to pause, recording the risk of data loss
+	PauseTimeoutPolicyProceed PauseTimeoutPolicy = "Proceed"
+	// PauseTimeoutPolicyForceDrain force drains the Pipeline the same way as a Pipeline being recycled which can't drain by itself
+	PauseTimeoutPolicyForceDrain PauseTimeoutPolicy = "ForceDrain"
+)
+
+// PauseTimeout defines how long to wait for a Pipeline to pause and what to do if it doesn't
+type PauseTimeout struct {
+	// Duration is how long to wait for the Pipeline to pause, measured from when the pause was requested.
+	// If not defined anywhere, wait indefinitely.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Policy is what to do when the Pipeline doesn't pause within the Duration (default Fail)
+	// +optional
+	Policy PauseTimeoutPolicy `json:"policy,omitempty"`
 }
 
 type RecycleStrategy struct {
@@ -71,6 +132,7 @@ type PipelineRider struct {
 type Pipeline struct {
 	Metadata `json:"metadata,omitempty"`
 
+	// +optional
 	Spec runtime.RawExtension `json:"spec"`
 }
 
@@ -88,6 +150,26 @@ type PipelineRolloutStatus struct {
 
 	// Riders stores the list of Riders that have been deployed along with the "promoted" Pipeline
 	Riders []RiderStatus `json:"riders,omitempty"`
+
+	// Template describes the PipelineTemplate which defines the Pipeline, if there is one
+	Template *TemplateStatus `json:"template,omitempty"`
+
+	// PauseHolders lists what is currently holding the Pipeline paused under the PPND strategy, and why
+	PauseHolders []PauseHolder `json:"pauseHolders,omitempty"`
+}
+
+// PauseHolder describes a Rollout which is holding a Pipeline paused
+type PauseHolder struct {
+	// Kind is the kind of the Rollout: NumaflowControllerRollout, ISBServiceRollout or the PipelineRollout itself
+	Kind string `json:"kind"`
+	// Name is the name of the Rollout
+	Name string `json:"name,omitempty"`
+	// Reason is why the Rollout is holding the Pipeline paused
+	Reason string `json:"reason"`
+	// StartTime is when the Rollout started holding the Pipeline paused
+	StartTime metav1.Time `json:"startTime,omitempty"`
+	// Generation is the generation of the Rollout which requires the Pipeline to be paused
+	Generation int64 `json:"generation,omitempty"`
 }
 
 type PipelineProgressiveStatus struct {
@@ -149,6 +231,7 @@ type PromotedPipelineStatus struct {
 
 // +genclient
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The current phase"
@@ -203,6 +286,14 @@ func (pipelineRollout *PipelineRollout) GetRolloutStatus() *Status {
 	return &pipelineRollout.Status.Status
 }
 
+// GetUpgradeStrategy returns the upgrade strategy selected by the Rollout, or "" if it doesn't select one
+func (pipelineRollout *PipelineRollout) GetUpgradeStrategy() UserUpgradeStrategy {
+	if pipelineRollout.Spec.Strategy == nil {
+		return ""
+	}
+	return pipelineRollout.Spec.Strategy.UpgradeStrategy
+}
+
 // GetProgressiveStrategy is a function of the progressiveRolloutObject
 func (pipelineRollout *PipelineRollout) GetProgressiveStrategy() ProgressiveStrategy {
 	// if the Strategy is not set, return an empty ProgressiveStrategy
@@ -213,6 +304,12 @@ func (pipelineRollout *PipelineRollout) GetProgressiveStrategy() ProgressiveStra
 	return pipelineRollout.Spec.Strategy.Progressive
 }
 
+// GetProgressiveSteps is a function of the progressiveRolloutObject
+// Steps are not currently supported for PipelineRollout (they're rejected by validation), so this always returns nil
+func (pipelineRollout *PipelineRollout) GetProgressiveSteps() []ProgressiveStep {
+	return nil
+}
+
 func (pipelineRollout *PipelineRollout) GetAnalysis() Analysis {
 	if pipelineRollout.Spec.Strategy == nil {
 		return Analysis{}
@@ -220,6 +317,30 @@ func (pipelineRollout *PipelineRollout) GetAnalysis() Analysis {
 	return pipelineRollout.Spec.Strategy.Analysis
 }
 
+// GetPostPromotionAnalysis returns the PostPromotionAnalysis for the Rollout, or nil if it's not defined.
+// If the PostPromotionAnalysis doesn't define its own Analysis, the Rollout's Analysis is used.
+func (pipelineRollout *PipelineRollout) GetPostPromotionAnalysis() *PostPromotionAnalysis {
+	if pipelineRollout.Spec.Strategy == nil || pipelineRollout.Spec.Strategy.PostPromotionAnalysis == nil {
+		return nil
+	}
+	postPromotionAnalysis := *pipelineRollout.Spec.Strategy.PostPromotionAnalysis
+	if postPromotionAnalysis.Analysis == nil {
+		analysis := pipelineRollout.GetAnalysis()
+		postPromotionAnalysis.Analysis = &analysis
+	}
+	return &postPromotionAnalysis
+}
+
+// GetRevisionHistoryLimit returns the maximum number of revisions of the child definition to retain, or nil if not set
+func (pipelineRollout *PipelineRollout) GetRevisionHistoryLimit() *int32 {
+	return pipelineRollout.Spec.RevisionHistoryLimit
+}
+
+// GetValuesFrom returns the references to the ConfigMaps and Secrets which supply values for templating the child definition and Riders
+func (pipelineRollout *PipelineRollout) GetValuesFrom() []ValuesFromSource {
+	return pipelineRollout.Spec.ValuesFrom
+}
+
 // GetUpgradingChildStatus is a function of the progressiveRolloutObject
 func (pipelineRollout *PipelineRollout) GetUpgradingChildStatus() *UpgradingChildStatus {
 	if pipelineRollout.Status.ProgressiveStatus.UpgradingPipelineStatus == nil {
@@ -308,6 +429,19 @@ func (pipelineRollout *PipelineRollout) SetPromotedChildStatus(status *PromotedC
 	pipelineRollout.Status.ProgressiveStatus.PromotedPipelineStatus.PromotedPipelineTypeStatus.PromotedChildStatus = *status.DeepCopy()
 }
 
+// GetTemplateRef returns the reference to the PipelineTemplate which defines the Pipeline, or nil if it's defined inline
+func (pipelineRollout *PipelineRollout) GetTemplateRef() *TemplateReference {
+	return pipelineRollout.Spec.TemplateRef
+}
+
+func (pipelineRollout *PipelineRollout) GetTemplateStatus() *TemplateStatus {
+	return pipelineRollout.Status.Template
+}
+
+func (pipelineRollout *PipelineRollout) SetTemplateStatus(status *TemplateStatus) {
+	pipelineRollout.Status.Template = status
+}
+
 func (pipelineRollout *PipelineRollout) GetChildMetadata() Metadata {
 	return pipelineRollout.Spec.Pipeline.Metadata
 }
@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineTemplateSpec defines a parameterized Pipeline which PipelineRollouts can reference instead of defining the Pipeline inline
+type PipelineTemplateSpec struct {
+	TemplateSpec `json:",inline"`
+
+	// Pipeline is the templated Pipeline, which can reference the template's parameters as well as the variables which are
+	// available to a PipelineRollout's Pipeline
+	Pipeline Pipeline `json:"pipeline"`
+}
+
+// +genclient
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Max Concurrent Upgrades",type="integer",JSONPath=".spec.maxConcurrentUpgrades"
+// PipelineTemplate is the Schema for the pipelinetemplates API
+type PipelineTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PipelineTemplateSpec `json:"spec"`
+}
+
+//+kubebuilder:object:root=true
+
+// PipelineTemplateList contains a list of PipelineTemplate
+type PipelineTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PipelineTemplate `json:"items"`
+}
+
+func (pipelineTemplate *PipelineTemplate) GetTemplateSpec() *TemplateSpec {
+	return &pipelineTemplate.Spec.TemplateSpec
+}
+
+func init() {
+	SchemeBuilder.Register(&PipelineTemplate{}, &PipelineTemplateList{})
+}
@@ -52,6 +52,12 @@ type UpgradingChildStatus struct {
 	// TrialWindowStartTime indicates the time at which the trial window starts
 	TrialWindowStartTime *metav1.Time `json:"trialWindowStartTime,omitempty"`
 
+	// HealthyChecks is the number of consecutive healthy checks of the upgrading child within the trial window
+	HealthyChecks int32 `json:"healthyChecks,omitempty"`
+
+	// UnhealthyChecks is the number of unhealthy checks of the upgrading child tolerated within the trial window
+	UnhealthyChecks int32 `json:"unhealthyChecks,omitempty"`
+
 	// ForcedSuccess indicates if this promotion was forced to complete
 	ForcedSuccess bool `json:"forcedSuccess,omitempty"`
 
@@ -71,6 +77,65 @@ type UpgradingChildStatus struct {
 
 	// Riders stores the list of Riders that have been deployed along with the "upgrading" child
 	Riders []RiderStatus `json:"riders,omitempty"`
+
+	// CurrentStep is the index of the Progressive strategy Step which the upgrading child is currently being assessed for
+	// (only applies if Steps are defined in the Progressive strategy)
+	CurrentStep int32 `json:"currentStep,omitempty"`
+
+	// Approval describes the state of the manual approval of the upgrading child
+	// (only applies if ManualApproval is defined in the Progressive strategy)
+	Approval *ApprovalStatus `json:"approval,omitempty"`
+
+	// AnalysisSkipped indicates that a user requested to skip the Analysis of the upgrading child.
+	// This is kept with the child, since the Rollout's LastUpgradeAction may be replaced by a later action.
+	AnalysisSkipped bool `json:"analysisSkipped,omitempty"`
+
+	// PostPromotion describes the state of the analysis of the child after it was promoted
+	// (only applies if PostPromotionAnalysis is defined in the strategy)
+	PostPromotion *PostPromotionStatus `json:"postPromotion,omitempty"`
+}
+
+// PostPromotionStatus describes the state of the analysis of a child after it was promoted
+type PostPromotionStatus struct {
+	// PreviousChildName is the name of the previously promoted child, which is kept scaled to zero during the window
+	PreviousChildName string `json:"previousChildName,omitempty"`
+
+	// PreviousChildScaleValues stores the original scale values of the previous child, so that they can be restored if it's revived.
+	// The keys are the names of the previous child's vertices (or the name of the MonoVertex itself).
+	PreviousChildScaleValues map[string]ScaleValues `json:"previousChildScaleValues,omitempty"`
+
+	// WindowEndTime is the time at which the post-promotion analysis window ends
+	WindowEndTime *metav1.Time `json:"windowEndTime,omitempty"`
+
+	// AssessmentResult is Unknown during the window, and otherwise indicates whether the post-promotion analysis succeeded or failed
+	AssessmentResult AssessmentResult `json:"assessmentResult,omitempty"`
+
+	// FailureReason indicates the reason for the failure
+	FailureReason string `json:"failureReason,omitempty"`
+
+	// Analysis is the status of the post-promotion analysis
+	Analysis AnalysisStatus `json:"analysis,omitempty"`
+
+	// RolledBack indicates that the post-promotion analysis failed and the previous child was revived and promoted again
+	RolledBack bool `json:"rolledBack,omitempty"`
+}
+
+// ApprovalStatus describes the state of the manual approval of an upgrading child
+type ApprovalStatus struct {
+	// WaitingSince is the time at which the upgrading child started waiting for approval
+	WaitingSince *metav1.Time `json:"waitingSince,omitempty"`
+
+	// Approved indicates that the upgrading child has been approved; a user may set this to approve it
+	Approved bool `json:"approved,omitempty"`
+
+	// ApprovedBy records who approved the upgrading child, if known
+	ApprovedBy string `json:"approvedBy,omitempty"`
+
+	// ApprovalTime is the time at which the approval was recorded
+	ApprovalTime *metav1.Time `json:"approvalTime,omitempty"`
+
+	// AutoApproved indicates that the upgrading child was approved because the approval timeout elapsed
+	AutoApproved bool `json:"autoApproved,omitempty"`
 }
 
 type UpgradingPipelineTypeStatus struct {
@@ -88,6 +153,39 @@ type AnalysisStatus struct {
 	EndTime *metav1.Time `json:"endTime,omitempty"`
 	// Phase is the phase of the AnalysisRun when completed
 	Phase argorolloutsv1.AnalysisPhase `json:"phase"`
+
+	// MetricResults are the results of the Metrics measured natively by Numaplane
+	MetricResults []MetricResult `json:"metricResults,omitempty"`
+}
+
+// MetricResult is the result of measuring an AnalysisMetric
+type MetricResult struct {
+	// Name is the name of the metric
+	Name string `json:"name"`
+	// Phase is the overall phase of the metric's measurements
+	Phase argorolloutsv1.AnalysisPhase `json:"phase"`
+	// Count is the number of measurements taken
+	Count int32 `json:"count,omitempty"`
+	// Successful is the number of successful measurements
+	Successful int32 `json:"successful,omitempty"`
+	// Failed is the number of failed measurements
+	Failed int32 `json:"failed,omitempty"`
+	// Inconclusive is the number of inconclusive measurements
+	Inconclusive int32 `json:"inconclusive,omitempty"`
+	// Error is the number of measurements which resulted in an error
+	Error int32 `json:"error,omitempty"`
+	// ConsecutiveError is the number of measurements which have resulted in an error since the last non-error measurement
+	ConsecutiveError int32 `json:"consecutiveError,omitempty"`
+	// LastValue is the value returned by the most recent measurement
+	LastValue string `json:"lastValue,omitempty"`
+	// LastMeasurementTime is the time of the most recent measurement
+	LastMeasurementTime *metav1.Time `json:"lastMeasurementTime,omitempty"`
+	// Message provides details of the most recent measurement if it didn't succeed
+	Message string `json:"message,omitempty"`
+	// BaselineSamples are the most recent values measured for the promoted child, for a comparative metric
+	BaselineSamples []string `json:"baselineSamples,omitempty"`
+	// CanarySamples are the most recent values measured for the upgrading child, for a comparative metric
+	CanarySamples []string `json:"canarySamples,omitempty"`
 }
 
 // ScaleValues stores the original scale min and max values, scaleTo value, and actual scale value of a pipeline or monovertex vertex
@@ -150,6 +248,11 @@ func (ucs *UpgradingChildStatus) BasicAssessmentEndTimeArrived() bool {
 		time.Now().After(ucs.BasicAssessmentEndTime.Time)
 }
 
+// IsPostPromotionInProgress determines if the child is still within its post-promotion analysis window
+func (ucs *UpgradingChildStatus) IsPostPromotionInProgress() bool {
+	return ucs != nil && ucs.PostPromotion != nil && ucs.PostPromotion.AssessmentResult == AssessmentResultUnknown
+}
+
 func (ucs *UpgradingChildStatus) IsFailed() bool {
 	return ucs != nil && ucs.AssessmentResult == AssessmentResultFailure
 }
@@ -20,6 +20,7 @@ package v1alpha1
 
 import (
 	argorolloutsv1 "github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -45,11 +46,39 @@ const (
 	UpgradeStrategyProgressive UpgradeStrategy = "Progressive"
 )
 
+// UserUpgradeStrategy is the strategy a user selects for upgrading the child of a Rollout when a change requires it.
+// It overrides the strategy configured for the namespace and the global default.
+// +kubebuilder:validation:Enum=progressive;pause-and-drain;no-strategy
+type UserUpgradeStrategy string
+
+const (
+	UserUpgradeStrategyProgressive UserUpgradeStrategy = "progressive"
+	UserUpgradeStrategyPPND        UserUpgradeStrategy = "pause-and-drain"
+	UserUpgradeStrategyNone        UserUpgradeStrategy = "no-strategy"
+)
+
+// UpgradeStrategySource indicates where the effective upgrade strategy of a Rollout was configured
+type UpgradeStrategySource string
+
+const (
+	UpgradeStrategySourceRollout   UpgradeStrategySource = "Rollout"
+	UpgradeStrategySourceNamespace UpgradeStrategySource = "Namespace"
+	UpgradeStrategySourceGlobal    UpgradeStrategySource = "Global"
+)
+
 // PipelineTypeRolloutStrategy specifies the Rollout Strategy for fields shared by Pipeline and MonoVertex
 type PipelineTypeRolloutStrategy struct {
 	PipelineTypeProgressiveStrategy `json:",inline"`
 
 	PauseResumeStrategy PauseResumeStrategy `json:"pauseResume,omitempty"`
+
+	// RequireApprovalOfPlan, if set, causes the controller to publish an UpgradePlan in the Status when the Rollout changes,
+	// and to wait for it to be approved before starting to upgrade the child
+	RequireApprovalOfPlan bool `json:"requireApprovalOfPlan,omitempty"`
+
+	// UpgradeStrategy, if set, selects the strategy used to upgrade the child, overriding the namespace and global configuration
+	// +optional
+	UpgradeStrategy UserUpgradeStrategy `json:"upgradeStrategy,omitempty"`
 }
 
 // PipelineTypeProgressiveStrategy specifies the Progressive Rollout Strategy for fields shared by Pipeline and MonoVertex
@@ -57,6 +86,23 @@ type PipelineTypeProgressiveStrategy struct {
 	Progressive ProgressiveStrategy `json:"progressive,omitempty"`
 
 	Analysis Analysis `json:"analysis,omitempty"`
+
+	// PostPromotionAnalysis, if set, continues to analyze a child for a window of time after it's been promoted.
+	// During the window, the previously promoted child is kept scaled to zero rather than recycled, and if the analysis fails,
+	// the previous child is revived and promoted again.
+	// +optional
+	PostPromotionAnalysis *PostPromotionAnalysis `json:"postPromotionAnalysis,omitempty"`
+}
+
+// PostPromotionAnalysis defines how a child is analyzed after it's been promoted
+type PostPromotionAnalysis struct {
+	// Window is the amount of time after promotion during which the child is analyzed and may still be rolled back
+	Window metav1.Duration `json:"window"`
+
+	// Analysis optionally overrides the Rollout's Analysis during the window.
+	// Note that Metrics with a Comparison are skipped, since the previous child isn't running to be compared to.
+	// +optional
+	Analysis *Analysis `json:"analysis,omitempty"`
 }
 
 // Analysis defines how to perform analysis of health, outside of basic resource checking
@@ -66,15 +112,242 @@ type Analysis struct {
 
 	// Templates are used to analyze the AnalysisRun
 	Templates []argorolloutsv1.AnalysisTemplateRef `json:"templates,omitempty"`
+
+	// Metrics are measured natively by Numaplane by querying Prometheus, so they don't require Argo Rollouts to be installed
+	// +optional
+	Metrics []AnalysisMetric `json:"metrics,omitempty"`
+}
+
+// AnalysisMetric defines a metric which is measured periodically and evaluated against success and failure conditions
+// +kubebuilder:validation:XValidation:rule="has(self.builtin) != (has(self.prometheus) && has(self.prometheus.query))",message="exactly one of builtin and prometheus.query must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.builtin) || has(self.comparison)",message="builtin requires comparison"
+// +kubebuilder:validation:XValidation:rule="has(self.builtin) || !has(self.comparison) || has(self.comparison.direction)",message="comparison.direction is required unless builtin is set"
+type AnalysisMetric struct {
+	// Name is the name of the metric, which must be unique within the Analysis
+	Name string `json:"name"`
+
+	// Prometheus defines the query used to measure the metric, and optionally the Prometheus server to query
+	// +optional
+	Prometheus PrometheusMetric `json:"prometheus,omitempty"`
+
+	// Builtin, if set, measures one of the metrics which Numaplane defines for the Kind of the child, in place of Prometheus.Query.
+	// It requires Comparison, whose Direction defaults to the one which suits the metric.
+	// +optional
+	Builtin BuiltinMetric `json:"builtin,omitempty"`
+
+	// SuccessCondition is an expression evaluated against the measured "result" which determines if the measurement is successful
+	// (e.g. "result[0] >= 0.95")
+	// +optional
+	SuccessCondition string `json:"successCondition,omitempty"`
+
+	// FailureCondition is an expression evaluated against the measured "result" which determines if the measurement has failed
+	// +optional
+	FailureCondition string `json:"failureCondition,omitempty"`
+
+	// Interval is the time between measurements (default: 1m)
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Count is the number of measurements to take (default: 1)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Count *int32 `json:"count,omitempty"`
+
+	// FailureLimit is the number of failed measurements tolerated before the metric fails (default: 0)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailureLimit int32 `json:"failureLimit,omitempty"`
+
+	// InconclusiveLimit is the number of inconclusive measurements tolerated before the metric is inconclusive (default: 0)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InconclusiveLimit int32 `json:"inconclusiveLimit,omitempty"`
+
+	// ConsecutiveErrorLimit is the number of consecutive errors querying Prometheus tolerated before the metric errors (default: 4)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ConsecutiveErrorLimit *int32 `json:"consecutiveErrorLimit,omitempty"`
+
+	// Comparison, if set, judges the upgrading child ("canary") relative to the promoted child ("baseline") rather than
+	// by the SuccessCondition and FailureCondition.
+	// The query is run once for each child, with "{{args.child-name}}" resolved to that child's name, and must return a single value.
+	// +optional
+	Comparison *MetricComparison `json:"comparison,omitempty"`
+}
+
+// BuiltinMetric is a metric whose query Numaplane defines for each Kind of child
+// +kubebuilder:validation:Enum=ProcessingRate;ErrorRate;AckLatency;Pending
+type BuiltinMetric string
+
+const (
+	// BuiltinMetricProcessingRate is the rate of messages read by the Source, per second per running Source pod (higher is better)
+	BuiltinMetricProcessingRate BuiltinMetric = "ProcessingRate"
+	// BuiltinMetricErrorRate is the fraction of messages read which resulted in an error or weren't acknowledged (lower is better)
+	BuiltinMetricErrorRate BuiltinMetric = "ErrorRate"
+	// BuiltinMetricAckLatency is the 95th percentile of the time taken to process and acknowledge messages (lower is better)
+	BuiltinMetricAckLatency BuiltinMetric = "AckLatency"
+	// BuiltinMetricPending is the number of messages pending, per running pod (lower is better)
+	BuiltinMetricPending BuiltinMetric = "Pending"
+)
+
+// ComparisonDirection indicates which direction of change in a metric is an improvement
+// +kubebuilder:validation:Enum=HigherIsBetter;LowerIsBetter
+type ComparisonDirection string
+
+const (
+	// e.g. processing rate
+	ComparisonDirectionHigherIsBetter ComparisonDirection = "HigherIsBetter"
+	// e.g. error rate, ack latency, pending count
+	ComparisonDirectionLowerIsBetter ComparisonDirection = "LowerIsBetter"
+)
+
+// MetricComparison defines how the upgrading child's metric is compared to the promoted child's metric.
+// Each measurement takes a sample of each child (the most recent 30 of which are retained). Once there are MinSamples of each,
+// a measurement fails if a one-sided Welch's t-test shows, with the given confidence, that the upgrading child is worse than
+// the promoted child by more than the margin.
+type MetricComparison struct {
+	// Direction indicates whether higher or lower values of the metric are better
+	// (required unless the metric is Builtin, in which case it defaults to the direction which suits the metric)
+	// +optional
+	Direction ComparisonDirection `json:"direction,omitempty"`
+
+	// MarginPercent is the tolerated degradation of the upgrading child, as a percentage of the promoted child's value (default: 10)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MarginPercent *int32 `json:"marginPercent,omitempty"`
+
+	// AbsoluteTolerance is a decimal value: a degradation no larger than this is always tolerated, which is useful when the
+	// promoted child's value is close to zero (default: "0")
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	AbsoluteTolerance string `json:"absoluteTolerance,omitempty"`
+
+	// MinSamples is the number of samples of each child required before the upgrading child may be judged worse (default: 5).
+	// The metric's Count is raised to at least this.
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=30
+	// +optional
+	MinSamples *int32 `json:"minSamples,omitempty"`
+
+	// ConfidencePercent is the confidence required that the upgrading child is worse before the measurement fails (default: 95)
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	ConfidencePercent *int32 `json:"confidencePercent,omitempty"`
+}
+
+// PrometheusMetric defines a PromQL query
+type PrometheusMetric struct {
+	// Address of the Prometheus server; if not set, the "prometheusAddress" from the Numaplane controller config is used
+	// +optional
+	Address string `json:"address,omitempty"`
+
+	// Query is the PromQL query, which may reference the Analysis arguments as "{{args.<name>}}"
+	// (required unless the metric is Builtin)
+	// +optional
+	Query string `json:"query,omitempty"`
 }
 
 type ProgressiveStrategy struct {
 	// optional string: comma-separated list consisting of:
-	// assessmentDelay, assessmentPeriod, assessmentInterval
+	// assessmentDelay, assessmentEnd, assessmentPeriod, assessmentInterval (in seconds)
+	// Deprecated: use Schedule instead, which takes precedence if both are set
 	AssessmentSchedule string `json:"assessmentSchedule,omitempty"`
 
+	// Schedule is the optional schedule for assessing the "upgrading" child
+	// (if not set, the default schedule for the Kind is used)
+	Schedule *AssessmentSchedule `json:"schedule,omitempty"`
+
 	// if ForcePromote is set, assessment will be skipped and Progressive upgrade will succeed
 	ForcePromote bool `json:"forcePromote,omitempty"`
+
+	// Steps is an optional ordered list of steps for shifting Pods from the "promoted" child to the "upgrading" child.
+	// Each step is assessed before moving on to the next one, and the "upgrading" child is promoted after the last step succeeds.
+	// If not set, half of the Pods are shifted to the "upgrading" child in a single step.
+	// Note: currently only supported for MonoVertexRollout: other Rollouts reject them
+	Steps []ProgressiveStep `json:"steps,omitempty"`
+
+	// ManualApproval, if set, requires a user to approve the "upgrading" child after its assessment has succeeded
+	// and before it's promoted
+	ManualApproval *ManualApprovalStrategy `json:"manualApproval,omitempty"`
+
+	// AutoRollback, if set, causes a failed "upgrading" child to be discarded and the "promoted" child to remain in place
+	// with its original spec. No new upgrade is attempted until the Rollout spec is changed again.
+	AutoRollback bool `json:"autoRollback,omitempty"`
+
+	// UpgradingOverrides is an optional patch, in the form of a JSON merge patch, applied to the spec of the "upgrading" child only while it's
+	// in progress (e.g. to enable verbose logging on it). The overrides are removed from the child when it's promoted.
+	// A list whose items all have a name (such as the vertices of a Pipeline, or the env of a container) is merged item by item
+	// with the list of the child, matching items by name; any other list in the patch replaces the list of the child entirely.
+	UpgradingOverrides *runtime.RawExtension `json:"upgradingOverrides,omitempty"`
+}
+
+// AssessmentSchedule defines when and how often the "upgrading" child is assessed, and what is required for its basic
+// resource health check to succeed
+type AssessmentSchedule struct {
+	// Delay is the amount of time to wait after the "upgrading" child is created before assessing it
+	Delay metav1.Duration `json:"delay,omitempty"`
+
+	// End is the amount of time after the Delay within which the basic resource health check must succeed
+	End metav1.Duration `json:"end,omitempty"`
+
+	// Period is the minimum amount of time for which the "upgrading" child must be healthy before the basic resource
+	// health check succeeds; it must not be greater than End
+	Period metav1.Duration `json:"period,omitempty"`
+
+	// Interval is how often the "upgrading" child is assessed, which must be greater than 0
+	Interval metav1.Duration `json:"interval"`
+
+	// ConsecutiveHealthyChecks is the minimum number of consecutive healthy checks required, in addition to the Period,
+	// before the basic resource health check succeeds
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ConsecutiveHealthyChecks int32 `json:"consecutiveHealthyChecks,omitempty"`
+
+	// MaxUnhealthyChecks is the number of unhealthy checks tolerated within the Period: until it's exceeded, an
+	// unhealthy check doesn't restart the Period (though it does reset the count of consecutive healthy checks)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxUnhealthyChecks int32 `json:"maxUnhealthyChecks,omitempty"`
+}
+
+type ApprovalTimeoutAction string
+
+const (
+	ApprovalTimeoutActionApprove ApprovalTimeoutAction = "Approve"
+	ApprovalTimeoutActionFail    ApprovalTimeoutAction = "Fail"
+)
+
+// ManualApprovalStrategy defines how a healthy "upgrading" child waits for approval before being promoted.
+// Approval is given either by setting the "numaplane.numaproj.io/approve-upgrade" annotation on the Rollout to the name
+// of the "upgrading" child, or by setting the Approved field of the upgrading child's ApprovalStatus.
+type ManualApprovalStrategy struct {
+	// Timeout is an optional amount of time to wait for approval, after which the TimeoutAction is taken
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// TimeoutAction is the action taken once the Timeout has elapsed: either "Approve" or "Fail" (default)
+	// +kubebuilder:validation:Enum=Approve;Fail
+	TimeoutAction ApprovalTimeoutAction `json:"timeoutAction,omitempty"`
+}
+
+// ProgressiveStep defines a single step of a multi-step Progressive upgrade
+type ProgressiveStep struct {
+	// Weight is the percentage of the "promoted" child's Pods which run on the "upgrading" child during this step.
+	// Weights must be strictly increasing from one step to the next.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+
+	// optional string: assessment schedule for this step, using the same format as the strategy's AssessmentSchedule
+	// (if not set, the strategy's schedule or otherwise the default for the Kind is used)
+	// Deprecated: use Schedule instead, which takes precedence if both are set
+	AssessmentSchedule string `json:"assessmentSchedule,omitempty"`
+
+	// Schedule is the optional schedule for assessing the "upgrading" child during this step
+	Schedule *AssessmentSchedule `json:"schedule,omitempty"`
+
+	// Analysis optionally overrides the Rollout's Analysis for this step
+	Analysis *Analysis `json:"analysis,omitempty"`
 }
 
 type PauseResumeStrategy struct {
@@ -17,10 +17,12 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
 	"reflect"
 	"sort"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -58,6 +60,22 @@ const (
 	// ConditionProgressiveUpgradeSucceeded indicates that whether the progressive upgrade succeeded.
 	ConditionProgressiveUpgradeSucceeded ConditionType = "ProgressiveUpgradeSucceeded"
 
+	// ConditionRolledBack indicates that a failed progressive upgrade was automatically rolled back to the "promoted" child,
+	// or that a progressive upgrade was aborted by a user
+	ConditionRolledBack ConditionType = "RolledBack"
+
+	// ConditionRolledBackToRevision indicates whether the revision requested by the "rollback-to-revision" annotation is deployed
+	// in place of the Rollout spec (false if the revision couldn't be applied)
+	ConditionRolledBackToRevision ConditionType = "RolledBackToRevision"
+
+	// ConditionAssessmentScheduleValid is false if the assessment schedule specified by the Rollout (or its current Step) is invalid,
+	// in which case the schedule which would otherwise apply is used in its place
+	ConditionAssessmentScheduleValid ConditionType = "AssessmentScheduleValid"
+
+	// ConditionUpgradePlanApproved indicates whether the upgrade plan for the current generation of the Rollout has been approved
+	// (only applies if approval of the plan is required)
+	ConditionUpgradePlanApproved ConditionType = "UpgradePlanApproved"
+
 	// ProgressingReasonString indicates the status condition reason as Progressing
 	ProgressingReasonString = "Progressing"
 )
@@ -84,6 +102,122 @@ type Status struct {
 
 	// UpgradeInProgress indicates the upgrade strategy currently being used and affecting the resource state or empty if no upgrade is in progress
 	UpgradeInProgress UpgradeStrategy `json:"upgradeInProgress,omitempty"`
+
+	// LastUpgradeAction acknowledges the most recent action requested by a user on a progressive upgrade
+	// +optional
+	LastUpgradeAction *UpgradeActionStatus `json:"lastUpgradeAction,omitempty"`
+
+	// UpgradePlan describes how the most recent change to the Rollout will be (or was) applied
+	// (only set if approval of the plan is required)
+	// +optional
+	UpgradePlan *UpgradePlan `json:"upgradePlan,omitempty"`
+
+	// EffectiveUpgradeStrategy is the upgrade strategy in effect for the Rollout and where it was configured
+	// +optional
+	EffectiveUpgradeStrategy *EffectiveUpgradeStrategy `json:"effectiveUpgradeStrategy,omitempty"`
+
+	// RollbackToRevision is the revision whose child definition and Riders are deployed in place of those in the Rollout spec,
+	// as requested by the "rollback-to-revision" annotation
+	// +optional
+	RollbackToRevision *int64 `json:"rollbackToRevision,omitempty"`
+}
+
+// EffectiveUpgradeStrategy describes the upgrade strategy in effect for a Rollout
+type EffectiveUpgradeStrategy struct {
+	// Strategy is the upgrade strategy in effect
+	Strategy UserUpgradeStrategy `json:"strategy"`
+	// Source is where the strategy was configured: the Rollout, the namespace, or the global configuration
+	Source UpgradeStrategySource `json:"source"`
+	// Message explains why the strategy differs from the one configured, if it does
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// UpgradePlan describes how the controller will upgrade the child of a Rollout to a new generation of the Rollout,
+// computed before anything is applied
+type UpgradePlan struct {
+	// Generation is the generation of the Rollout which the plan is for
+	Generation int64 `json:"generation"`
+
+	// DefinitionHash identifies the computed definition of the child and its Riders which the plan is for.
+	// The plan is recomputed if the definition changes, which may happen without a new generation (e.g. if a template or a
+	// value it references changes). The plan is approved by annotating the Rollout with this hash.
+	DefinitionHash string `json:"definitionHash,omitempty"`
+
+	// Strategy is the upgrade strategy which will be used
+	Strategy UpgradeStrategy `json:"strategy"`
+
+	// Recreate indicates if the child will be deleted and recreated
+	Recreate bool `json:"recreate,omitempty"`
+
+	// RecreateFields lists the fields of the USDE "recreate" list which changed
+	RecreateFields []string `json:"recreateFields,omitempty"`
+
+	// DataLossFields lists the fields of the USDE "dataLoss" list which changed
+	DataLossFields []string `json:"dataLossFields,omitempty"`
+
+	// ProgressiveFields lists the fields of the USDE "progressive" list which changed
+	ProgressiveFields []string `json:"progressiveFields,omitempty"`
+
+	// RiderAdditions lists the Riders which will be added, as "<kind>/<name>"
+	RiderAdditions []string `json:"riderAdditions,omitempty"`
+
+	// RiderModifications lists the Riders which will be modified, as "<kind>/<name>"
+	RiderModifications []string `json:"riderModifications,omitempty"`
+
+	// RiderDeletions lists the Riders which will be deleted, as "<kind>/<name>"
+	RiderDeletions []string `json:"riderDeletions,omitempty"`
+
+	// ChildrenToCreate lists the children which will be created, as "<kind>/<name>"
+	ChildrenToCreate []string `json:"childrenToCreate,omitempty"`
+
+	// ChildrenToUpdate lists the children which will be updated in place, as "<kind>/<name>"
+	ChildrenToUpdate []string `json:"childrenToUpdate,omitempty"`
+
+	// ChildrenToRecycle lists the children which will be recycled (or deleted), as "<kind>/<name>"
+	ChildrenToRecycle []string `json:"childrenToRecycle,omitempty"`
+
+	// PipelinesToPause lists the Pipelines which will be paused during the upgrade
+	PipelinesToPause []string `json:"pipelinesToPause,omitempty"`
+
+	// ComputedTime is the time at which the plan was computed
+	ComputedTime metav1.Time `json:"computedTime,omitempty"`
+
+	// Approved indicates if the plan has been approved
+	Approved bool `json:"approved,omitempty"`
+
+	// ApprovedBy records who approved the plan, if known
+	ApprovedBy string `json:"approvedBy,omitempty"`
+}
+
+// UpgradeAction is an action which a user can request on an in-flight progressive upgrade
+type UpgradeAction string
+
+const (
+	// UpgradeActionAbort discontinues the upgrade, restoring the "promoted" child
+	UpgradeActionAbort UpgradeAction = "abort"
+	// UpgradeActionRetry replaces a failed "upgrading" child with a new one of the same spec
+	UpgradeActionRetry UpgradeAction = "retry"
+	// UpgradeActionSkipAnalysis treats the Analysis of the "upgrading" child as successful, while still requiring the basic assessment to succeed
+	UpgradeActionSkipAnalysis UpgradeAction = "skip-analysis"
+)
+
+// UpgradeActionStatus describes an action requested by a user on a progressive upgrade, which has been acknowledged
+type UpgradeActionStatus struct {
+	// Action is the action which was requested
+	Action UpgradeAction `json:"action"`
+
+	// ChildName is the name of the "upgrading" child which the action was requested for
+	ChildName string `json:"childName"`
+
+	// RequestedBy records who requested the action, if known
+	RequestedBy string `json:"requestedBy,omitempty"`
+
+	// AcknowledgedTime is the time at which the action was acknowledged
+	AcknowledgedTime metav1.Time `json:"acknowledgedTime,omitempty"`
+
+	// Message describes the outcome of the action
+	Message string `json:"message,omitempty"`
 }
 
 // PauseStatus is a common structure used to communicate how long Pipelines are paused.
@@ -205,6 +339,67 @@ func (status *Status) MarkProgressiveUpgradeFailed(message string, generation in
 	status.MarkFalse(ConditionProgressiveUpgradeSucceeded, "Failed", message, generation)
 }
 
+func (status *Status) MarkRolledBack(message string, generation int64) {
+	status.MarkTrueWithReason(ConditionRolledBack, "ProgressiveUpgradeFailed", message, generation)
+}
+
+// MarkUpgradeAborted sets the RolledBack condition to true for a progressive upgrade which was aborted by a user
+func (status *Status) MarkUpgradeAborted(message string, generation int64) {
+	status.MarkTrueWithReason(ConditionRolledBack, "Aborted", message, generation)
+}
+
+// ClearRolledBack sets the RolledBack condition to false if it's currently true
+func (status *Status) ClearRolledBack(generation int64) {
+	if status.IsRolledBack() {
+		status.MarkFalse(ConditionRolledBack, "NewUpgrade", "a new upgrade has started", generation)
+	}
+}
+
+// MarkRolledBackToRevision records that the given revision is deployed in place of the Rollout spec
+func (status *Status) MarkRolledBackToRevision(revision int64, generation int64) {
+	status.RollbackToRevision = &revision
+	status.MarkTrueWithReason(ConditionRolledBackToRevision, "RollbackRequested",
+		fmt.Sprintf("revision %d is deployed in place of the spec, which is ignored until the rollback-to-revision annotation is removed", revision), generation)
+}
+
+// MarkRollbackToRevisionFailed records that the requested rollback couldn't be applied, so the Rollout spec is deployed instead
+func (status *Status) MarkRollbackToRevisionFailed(reason, message string, generation int64) {
+	status.RollbackToRevision = nil
+	status.MarkFalse(ConditionRolledBackToRevision, reason, message, generation)
+}
+
+// ClearRollbackToRevision removes the record of a rollback to a revision once it's no longer requested
+func (status *Status) ClearRollbackToRevision() {
+	status.RollbackToRevision = nil
+	meta.RemoveStatusCondition(&status.Conditions, string(ConditionRolledBackToRevision))
+}
+
+// MarkAssessmentScheduleInvalid records that the assessment schedule specified by the Rollout was replaced because it's invalid
+func (status *Status) MarkAssessmentScheduleInvalid(message string, generation int64) {
+	status.MarkFalse(ConditionAssessmentScheduleValid, "InvalidSchedule", message, generation)
+}
+
+// ClearAssessmentScheduleInvalid removes the record of an invalid assessment schedule once it's no longer replaced
+func (status *Status) ClearAssessmentScheduleInvalid() {
+	meta.RemoveStatusCondition(&status.Conditions, string(ConditionAssessmentScheduleValid))
+}
+
+// MarkUpgradePlanPendingApproval indicates that the upgrade plan for the given generation is waiting for approval
+func (status *Status) MarkUpgradePlanPendingApproval(message string, generation int64) {
+	status.markTypeStatus(ConditionUpgradePlanApproved, metav1.ConditionFalse, "PendingApproval", message, generation)
+}
+
+// MarkUpgradePlanApproved indicates that the upgrade plan for the given generation has been approved
+func (status *Status) MarkUpgradePlanApproved(message string, generation int64) {
+	status.markTypeStatus(ConditionUpgradePlanApproved, metav1.ConditionTrue, "Approved", message, generation)
+}
+
+// IsRolledBack indicates whether the RolledBack condition is currently true
+func (status *Status) IsRolledBack() bool {
+	condition := status.GetCondition(ConditionRolledBack)
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
+
 func (status *Status) SetUpgradeInProgress(upgradeStrategy UpgradeStrategy) {
 	status.UpgradeInProgress = upgradeStrategy
 }
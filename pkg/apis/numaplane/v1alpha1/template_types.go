@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// TemplateParameterType is the type of the value of a template parameter
+// +kubebuilder:validation:Enum=string;integer;number;boolean
+type TemplateParameterType string
+
+const (
+	TemplateParameterTypeString  TemplateParameterType = "string"
+	TemplateParameterTypeInteger TemplateParameterType = "integer"
+	TemplateParameterTypeNumber  TemplateParameterType = "number"
+	TemplateParameterTypeBoolean TemplateParameterType = "boolean"
+)
+
+// TemplateParameter declares a parameter of a template, whose value is supplied by each Rollout referencing the template
+type TemplateParameter struct {
+	// Name of the parameter, which is referenced in the template as {{.params.<name>}}
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z_][a-zA-Z0-9_]*$`
+	Name string `json:"name"`
+
+	// Description of the parameter
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Type of the parameter's value (default "string"). A value which isn't a string is substituted as a JSON literal
+	// if the parameter makes up an entire field of the template, e.g. `max: "{{.params.maxReplicas}}"`.
+	// +optional
+	Type TemplateParameterType `json:"type,omitempty"`
+
+	// Default is the value used if a Rollout doesn't supply one. If it's not set, every Rollout must supply a value.
+	// +optional
+	Default *string `json:"default,omitempty"`
+}
+
+// TemplateSpec holds the fields which are common to the specs of all of the templates
+type TemplateSpec struct {
+	// Parameters are the parameters of the template
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Parameters []TemplateParameter `json:"parameters,omitempty"`
+
+	// MaxConcurrentUpgrades is the maximum number of Rollouts referencing the template which are upgraded at the same time when
+	// the template changes. If not set, they're all upgraded at once.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentUpgrades *int32 `json:"maxConcurrentUpgrades,omitempty"`
+}
+
+// TemplateReference references a template in the Rollout's namespace which defines the Rollout's child
+type TemplateReference struct {
+	// Name of the template
+	Name string `json:"name"`
+
+	// Values of the template's parameters. Parameters without a value use their default.
+	// +optional
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// TemplateStatus describes the template which a Rollout's child is defined by
+type TemplateStatus struct {
+	// Name of the template
+	Name string `json:"name"`
+
+	// AppliedGeneration is the generation of the template which the Rollout's child is currently defined by
+	AppliedGeneration int64 `json:"appliedGeneration,omitempty"`
+
+	// PendingGeneration is set to a newer generation of the template while the Rollout waits to be upgraded to it, because
+	// the template's MaxConcurrentUpgrades has been reached
+	PendingGeneration int64 `json:"pendingGeneration,omitempty"`
+}
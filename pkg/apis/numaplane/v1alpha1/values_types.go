@@ -0,0 +1,40 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// ValuesFromSource references a ConfigMap or a Secret in the Rollout's namespace, whose data is made available for templating the
+// Rollout's child definition and Riders as {{.values.<key>}}. Exactly one of ConfigMapRef and SecretRef must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.configMapRef) != has(self.secretRef)",message="exactly one of configMapRef and secretRef must be set"
+type ValuesFromSource struct {
+	// ConfigMapRef references a ConfigMap whose data supplies the values
+	// +optional
+	ConfigMapRef *ValuesFromReference `json:"configMapRef,omitempty"`
+
+	// SecretRef references a Secret whose data supplies the values
+	// +optional
+	SecretRef *ValuesFromReference `json:"secretRef,omitempty"`
+}
+
+// ValuesFromReference references an object in the Rollout's namespace
+type ValuesFromReference struct {
+	// Name of the object
+	Name string `json:"name"`
+
+	// Optional, if set, allows the object not to exist, in which case it supplies no values
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+}
@@ -41,6 +41,13 @@ func (in *Analysis) DeepCopyInto(out *Analysis) {
 		*out = make([]rolloutsv1alpha1.AnalysisTemplateRef, len(*in))
 		copy(*out, *in)
 	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]AnalysisMetric, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Analysis.
@@ -53,6 +60,42 @@ func (in *Analysis) DeepCopy() *Analysis {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisMetric) DeepCopyInto(out *AnalysisMetric) {
+	*out = *in
+	out.Prometheus = in.Prometheus
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConsecutiveErrorLimit != nil {
+		in, out := &in.ConsecutiveErrorLimit, &out.ConsecutiveErrorLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Comparison != nil {
+		in, out := &in.Comparison, &out.Comparison
+		*out = new(MetricComparison)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisMetric.
+func (in *AnalysisMetric) DeepCopy() *AnalysisMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AnalysisStatus) DeepCopyInto(out *AnalysisStatus) {
 	*out = *in
@@ -64,6 +107,13 @@ func (in *AnalysisStatus) DeepCopyInto(out *AnalysisStatus) {
 		in, out := &in.EndTime, &out.EndTime
 		*out = (*in).DeepCopy()
 	}
+	if in.MetricResults != nil {
+		in, out := &in.MetricResults, &out.MetricResults
+		*out = make([]MetricResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisStatus.
@@ -76,6 +126,48 @@ func (in *AnalysisStatus) DeepCopy() *AnalysisStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalStatus) DeepCopyInto(out *ApprovalStatus) {
+	*out = *in
+	if in.WaitingSince != nil {
+		in, out := &in.WaitingSince, &out.WaitingSince
+		*out = (*in).DeepCopy()
+	}
+	if in.ApprovalTime != nil {
+		in, out := &in.ApprovalTime, &out.ApprovalTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalStatus.
+func (in *ApprovalStatus) DeepCopy() *ApprovalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AssessmentSchedule) DeepCopyInto(out *AssessmentSchedule) {
+	*out = *in
+	out.Delay = in.Delay
+	out.End = in.End
+	out.Period = in.Period
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AssessmentSchedule.
+func (in *AssessmentSchedule) DeepCopy() *AssessmentSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(AssessmentSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Controller) DeepCopyInto(out *Controller) {
 	*out = *in
@@ -106,6 +198,21 @@ func (in *ControllerDefinitions) DeepCopy() *ControllerDefinitions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EffectiveUpgradeStrategy) DeepCopyInto(out *EffectiveUpgradeStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveUpgradeStrategy.
+func (in *EffectiveUpgradeStrategy) DeepCopy() *EffectiveUpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveUpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ISBServiceProgressiveStatus) DeepCopyInto(out *ISBServiceProgressiveStatus) {
 	*out = *in
@@ -197,7 +304,7 @@ func (in *ISBServiceRolloutSpec) DeepCopyInto(out *ISBServiceRolloutSpec) {
 	if in.Strategy != nil {
 		in, out := &in.Strategy, &out.Strategy
 		*out = new(ISBServiceRolloutStrategy)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Riders != nil {
 		in, out := &in.Riders, &out.Riders
@@ -206,6 +313,18 @@ func (in *ISBServiceRolloutSpec) DeepCopyInto(out *ISBServiceRolloutSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ValuesFrom != nil {
+		in, out := &in.ValuesFrom, &out.ValuesFrom
+		*out = make([]ValuesFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ISBServiceRolloutSpec.
@@ -223,6 +342,11 @@ func (in *ISBServiceRolloutStatus) DeepCopyInto(out *ISBServiceRolloutStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
 	in.PauseRequestStatus.DeepCopyInto(&out.PauseRequestStatus)
+	if in.PauseRequested != nil {
+		in, out := &in.PauseRequested, &out.PauseRequested
+		*out = new(bool)
+		**out = **in
+	}
 	if in.NameCount != nil {
 		in, out := &in.NameCount, &out.NameCount
 		*out = new(int32)
@@ -249,7 +373,7 @@ func (in *ISBServiceRolloutStatus) DeepCopy() *ISBServiceRolloutStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ISBServiceRolloutStrategy) DeepCopyInto(out *ISBServiceRolloutStrategy) {
 	*out = *in
-	out.Progressive = in.Progressive
+	in.Progressive.DeepCopyInto(&out.Progressive)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ISBServiceRolloutStrategy.
@@ -279,6 +403,26 @@ func (in *InterStepBufferService) DeepCopy() *InterStepBufferService {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManualApprovalStrategy) DeepCopyInto(out *ManualApprovalStrategy) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManualApprovalStrategy.
+func (in *ManualApprovalStrategy) DeepCopy() *ManualApprovalStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ManualApprovalStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metadata) DeepCopyInto(out *Metadata) {
 	*out = *in
@@ -308,6 +452,65 @@ func (in *Metadata) DeepCopy() *Metadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricComparison) DeepCopyInto(out *MetricComparison) {
+	*out = *in
+	if in.MarginPercent != nil {
+		in, out := &in.MarginPercent, &out.MarginPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinSamples != nil {
+		in, out := &in.MinSamples, &out.MinSamples
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ConfidencePercent != nil {
+		in, out := &in.ConfidencePercent, &out.ConfidencePercent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricComparison.
+func (in *MetricComparison) DeepCopy() *MetricComparison {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricComparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricResult) DeepCopyInto(out *MetricResult) {
+	*out = *in
+	if in.LastMeasurementTime != nil {
+		in, out := &in.LastMeasurementTime, &out.LastMeasurementTime
+		*out = (*in).DeepCopy()
+	}
+	if in.BaselineSamples != nil {
+		in, out := &in.BaselineSamples, &out.BaselineSamples
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CanarySamples != nil {
+		in, out := &in.CanarySamples, &out.CanarySamples
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricResult.
+func (in *MetricResult) DeepCopy() *MetricResult {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonoVertex) DeepCopyInto(out *MonoVertex) {
 	*out = *in
@@ -413,6 +616,11 @@ func (in *MonoVertexRolloutList) DeepCopyObject() runtime.Object {
 func (in *MonoVertexRolloutSpec) DeepCopyInto(out *MonoVertexRolloutSpec) {
 	*out = *in
 	in.MonoVertex.DeepCopyInto(&out.MonoVertex)
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(TemplateReference)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Strategy != nil {
 		in, out := &in.Strategy, &out.Strategy
 		*out = new(PipelineTypeRolloutStrategy)
@@ -425,6 +633,18 @@ func (in *MonoVertexRolloutSpec) DeepCopyInto(out *MonoVertexRolloutSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ValuesFrom != nil {
+		in, out := &in.ValuesFrom, &out.ValuesFrom
+		*out = make([]ValuesFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonoVertexRolloutSpec.
@@ -452,6 +672,11 @@ func (in *MonoVertexRolloutStatus) DeepCopyInto(out *MonoVertexRolloutStatus) {
 		*out = make([]RiderStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(TemplateStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonoVertexRolloutStatus.
@@ -464,6 +689,81 @@ func (in *MonoVertexRolloutStatus) DeepCopy() *MonoVertexRolloutStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonoVertexTemplate) DeepCopyInto(out *MonoVertexTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonoVertexTemplate.
+func (in *MonoVertexTemplate) DeepCopy() *MonoVertexTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(MonoVertexTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MonoVertexTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonoVertexTemplateList) DeepCopyInto(out *MonoVertexTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MonoVertexTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonoVertexTemplateList.
+func (in *MonoVertexTemplateList) DeepCopy() *MonoVertexTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(MonoVertexTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MonoVertexTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonoVertexTemplateSpec) DeepCopyInto(out *MonoVertexTemplateSpec) {
+	*out = *in
+	in.TemplateSpec.DeepCopyInto(&out.TemplateSpec)
+	in.MonoVertex.DeepCopyInto(&out.MonoVertex)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonoVertexTemplateSpec.
+func (in *MonoVertexTemplateSpec) DeepCopy() *MonoVertexTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonoVertexTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NumaflowController) DeepCopyInto(out *NumaflowController) {
 	*out = *in
@@ -603,6 +903,11 @@ func (in *NumaflowControllerRolloutStatus) DeepCopyInto(out *NumaflowControllerR
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
 	in.PauseRequestStatus.DeepCopyInto(&out.PauseRequestStatus)
+	if in.PauseRequested != nil {
+		in, out := &in.PauseRequested, &out.PauseRequested
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NumaflowControllerRolloutStatus.
@@ -649,6 +954,11 @@ func (in *NumaflowControllerStatus) DeepCopy() *NumaflowControllerStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PPNDStrategy) DeepCopyInto(out *PPNDStrategy) {
 	*out = *in
+	if in.PauseTimeout != nil {
+		in, out := &in.PauseTimeout, &out.PauseTimeout
+		*out = new(PauseTimeout)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PPNDStrategy.
@@ -661,6 +971,22 @@ func (in *PPNDStrategy) DeepCopy() *PPNDStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PauseHolder) DeepCopyInto(out *PauseHolder) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PauseHolder.
+func (in *PauseHolder) DeepCopy() *PauseHolder {
+	if in == nil {
+		return nil
+	}
+	out := new(PauseHolder)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PauseResumeStrategy) DeepCopyInto(out *PauseResumeStrategy) {
 	*out = *in
@@ -694,6 +1020,26 @@ func (in *PauseStatus) DeepCopy() *PauseStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PauseTimeout) DeepCopyInto(out *PauseTimeout) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PauseTimeout.
+func (in *PauseTimeout) DeepCopy() *PauseTimeout {
+	if in == nil {
+		return nil
+	}
+	out := new(PauseTimeout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pipeline) DeepCopyInto(out *Pipeline) {
 	*out = *in
@@ -822,6 +1168,11 @@ func (in *PipelineRolloutList) DeepCopyObject() runtime.Object {
 func (in *PipelineRolloutSpec) DeepCopyInto(out *PipelineRolloutSpec) {
 	*out = *in
 	in.Pipeline.DeepCopyInto(&out.Pipeline)
+	if in.TemplateRef != nil {
+		in, out := &in.TemplateRef, &out.TemplateRef
+		*out = new(TemplateReference)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Strategy != nil {
 		in, out := &in.Strategy, &out.Strategy
 		*out = new(PipelineStrategy)
@@ -834,6 +1185,18 @@ func (in *PipelineRolloutSpec) DeepCopyInto(out *PipelineRolloutSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ValuesFrom != nil {
+		in, out := &in.ValuesFrom, &out.ValuesFrom
+		*out = make([]ValuesFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRolloutSpec.
@@ -862,6 +1225,18 @@ func (in *PipelineRolloutStatus) DeepCopyInto(out *PipelineRolloutStatus) {
 		*out = make([]RiderStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.Template != nil {
+		in, out := &in.Template, &out.Template
+		*out = new(TemplateStatus)
+		**out = **in
+	}
+	if in.PauseHolders != nil {
+		in, out := &in.PauseHolders, &out.PauseHolders
+		*out = make([]PauseHolder, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRolloutStatus.
@@ -878,7 +1253,7 @@ func (in *PipelineRolloutStatus) DeepCopy() *PipelineRolloutStatus {
 func (in *PipelineStrategy) DeepCopyInto(out *PipelineStrategy) {
 	*out = *in
 	in.PipelineTypeRolloutStrategy.DeepCopyInto(&out.PipelineTypeRolloutStrategy)
-	out.PPNDStrategy = in.PPNDStrategy
+	in.PPNDStrategy.DeepCopyInto(&out.PPNDStrategy)
 	in.RecycleStrategy.DeepCopyInto(&out.RecycleStrategy)
 }
 
@@ -892,11 +1267,91 @@ func (in *PipelineStrategy) DeepCopy() *PipelineStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineTemplate) DeepCopyInto(out *PipelineTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineTemplate.
+func (in *PipelineTemplate) DeepCopy() *PipelineTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PipelineTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineTemplateList) DeepCopyInto(out *PipelineTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PipelineTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineTemplateList.
+func (in *PipelineTemplateList) DeepCopy() *PipelineTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PipelineTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineTemplateSpec) DeepCopyInto(out *PipelineTemplateSpec) {
+	*out = *in
+	in.TemplateSpec.DeepCopyInto(&out.TemplateSpec)
+	in.Pipeline.DeepCopyInto(&out.Pipeline)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineTemplateSpec.
+func (in *PipelineTemplateSpec) DeepCopy() *PipelineTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineTypeProgressiveStrategy) DeepCopyInto(out *PipelineTypeProgressiveStrategy) {
 	*out = *in
-	out.Progressive = in.Progressive
+	in.Progressive.DeepCopyInto(&out.Progressive)
 	in.Analysis.DeepCopyInto(&out.Analysis)
+	if in.PostPromotionAnalysis != nil {
+		in, out := &in.PostPromotionAnalysis, &out.PostPromotionAnalysis
+		*out = new(PostPromotionAnalysis)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineTypeProgressiveStrategy.
@@ -926,9 +1381,104 @@ func (in *PipelineTypeRolloutStrategy) DeepCopy() *PipelineTypeRolloutStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostPromotionAnalysis) DeepCopyInto(out *PostPromotionAnalysis) {
+	*out = *in
+	out.Window = in.Window
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(Analysis)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostPromotionAnalysis.
+func (in *PostPromotionAnalysis) DeepCopy() *PostPromotionAnalysis {
+	if in == nil {
+		return nil
+	}
+	out := new(PostPromotionAnalysis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostPromotionStatus) DeepCopyInto(out *PostPromotionStatus) {
+	*out = *in
+	if in.PreviousChildScaleValues != nil {
+		in, out := &in.PreviousChildScaleValues, &out.PreviousChildScaleValues
+		*out = make(map[string]ScaleValues, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.WindowEndTime != nil {
+		in, out := &in.WindowEndTime, &out.WindowEndTime
+		*out = (*in).DeepCopy()
+	}
+	in.Analysis.DeepCopyInto(&out.Analysis)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostPromotionStatus.
+func (in *PostPromotionStatus) DeepCopy() *PostPromotionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PostPromotionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProgressiveStep) DeepCopyInto(out *ProgressiveStep) {
+	*out = *in
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(AssessmentSchedule)
+		**out = **in
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(Analysis)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProgressiveStep.
+func (in *ProgressiveStep) DeepCopy() *ProgressiveStep {
+	if in == nil {
+		return nil
+	}
+	out := new(ProgressiveStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProgressiveStrategy) DeepCopyInto(out *ProgressiveStrategy) {
 	*out = *in
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(AssessmentSchedule)
+		**out = **in
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]ProgressiveStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ManualApproval != nil {
+		in, out := &in.ManualApproval, &out.ManualApproval
+		*out = new(ManualApprovalStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradingOverrides != nil {
+		in, out := &in.UpgradingOverrides, &out.UpgradingOverrides
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProgressiveStrategy.
@@ -941,6 +1491,21 @@ func (in *ProgressiveStrategy) DeepCopy() *ProgressiveStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusMetric) DeepCopyInto(out *PrometheusMetric) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusMetric.
+func (in *PrometheusMetric) DeepCopy() *PrometheusMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PromotedChildStatus) DeepCopyInto(out *PromotedChildStatus) {
 	*out = *in
@@ -1130,6 +1695,26 @@ func (in *Status) DeepCopyInto(out *Status) {
 		}
 	}
 	in.LastFailureTime.DeepCopyInto(&out.LastFailureTime)
+	if in.LastUpgradeAction != nil {
+		in, out := &in.LastUpgradeAction, &out.LastUpgradeAction
+		*out = new(UpgradeActionStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpgradePlan != nil {
+		in, out := &in.UpgradePlan, &out.UpgradePlan
+		*out = new(UpgradePlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EffectiveUpgradeStrategy != nil {
+		in, out := &in.EffectiveUpgradeStrategy, &out.EffectiveUpgradeStrategy
+		*out = new(EffectiveUpgradeStrategy)
+		**out = **in
+	}
+	if in.RollbackToRevision != nil {
+		in, out := &in.RollbackToRevision, &out.RollbackToRevision
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Status.
@@ -1142,6 +1727,172 @@ func (in *Status) DeepCopy() *Status {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateParameter) DeepCopyInto(out *TemplateParameter) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateParameter.
+func (in *TemplateParameter) DeepCopy() *TemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateReference) DeepCopyInto(out *TemplateReference) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateReference.
+func (in *TemplateReference) DeepCopy() *TemplateReference {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateSpec) DeepCopyInto(out *TemplateSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]TemplateParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MaxConcurrentUpgrades != nil {
+		in, out := &in.MaxConcurrentUpgrades, &out.MaxConcurrentUpgrades
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateSpec.
+func (in *TemplateSpec) DeepCopy() *TemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateStatus) DeepCopyInto(out *TemplateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateStatus.
+func (in *TemplateStatus) DeepCopy() *TemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeActionStatus) DeepCopyInto(out *UpgradeActionStatus) {
+	*out = *in
+	in.AcknowledgedTime.DeepCopyInto(&out.AcknowledgedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeActionStatus.
+func (in *UpgradeActionStatus) DeepCopy() *UpgradeActionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeActionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradePlan) DeepCopyInto(out *UpgradePlan) {
+	*out = *in
+	if in.RecreateFields != nil {
+		in, out := &in.RecreateFields, &out.RecreateFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataLossFields != nil {
+		in, out := &in.DataLossFields, &out.DataLossFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ProgressiveFields != nil {
+		in, out := &in.ProgressiveFields, &out.ProgressiveFields
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RiderAdditions != nil {
+		in, out := &in.RiderAdditions, &out.RiderAdditions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RiderModifications != nil {
+		in, out := &in.RiderModifications, &out.RiderModifications
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RiderDeletions != nil {
+		in, out := &in.RiderDeletions, &out.RiderDeletions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChildrenToCreate != nil {
+		in, out := &in.ChildrenToCreate, &out.ChildrenToCreate
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChildrenToUpdate != nil {
+		in, out := &in.ChildrenToUpdate, &out.ChildrenToUpdate
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChildrenToRecycle != nil {
+		in, out := &in.ChildrenToRecycle, &out.ChildrenToRecycle
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PipelinesToPause != nil {
+		in, out := &in.PipelinesToPause, &out.PipelinesToPause
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.ComputedTime.DeepCopyInto(&out.ComputedTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradePlan.
+func (in *UpgradePlan) DeepCopy() *UpgradePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpgradingChildStatus) DeepCopyInto(out *UpgradingChildStatus) {
 	*out = *in
@@ -1163,6 +1914,16 @@ func (in *UpgradingChildStatus) DeepCopyInto(out *UpgradingChildStatus) {
 		*out = make([]RiderStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(ApprovalStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostPromotion != nil {
+		in, out := &in.PostPromotion, &out.PostPromotion
+		*out = new(PostPromotionStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradingChildStatus.
@@ -1247,6 +2008,46 @@ func (in *UpgradingPipelineTypeStatus) DeepCopy() *UpgradingPipelineTypeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValuesFromReference) DeepCopyInto(out *ValuesFromReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValuesFromReference.
+func (in *ValuesFromReference) DeepCopy() *ValuesFromReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ValuesFromReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValuesFromSource) DeepCopyInto(out *ValuesFromSource) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(ValuesFromReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(ValuesFromReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValuesFromSource.
+func (in *ValuesFromSource) DeepCopy() *ValuesFromSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ValuesFromSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VertexScaleDefinition) DeepCopyInto(out *VertexScaleDefinition) {
 	*out = *in
@@ -0,0 +1,387 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	jsonpatchgen "gomodules.xyz/jsonpatch/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// ConversionDataAnnotation holds the fields of a v1alpha1 object which can't be represented exactly in v1beta1, so that
+// converting an object to v1beta1 and back doesn't lose anything
+const ConversionDataAnnotation = "numaplane.numaproj.io/conversion-data"
+
+// maxChildSpecPatchSize limits the size of the child spec patch kept in the conversion data annotation, so that it stays well
+// within the size limit of an object's annotations
+const maxChildSpecPatchSize = 64 * 1024
+
+type conversionData struct {
+	// ChildSpecPatch is the JSON patch which restores the v1alpha1 child spec from the encoding of the typed spec, if
+	// decoding it into the typed spec loses fields (e.g. because they're unknown to this version of Numaflow, or because a
+	// field which isn't a string is templated) or adds empty ones. Only the differences are kept rather than the whole child
+	// spec, unless it doesn't decode at all.
+	ChildSpecPatch json.RawMessage `json:"childSpecPatch,omitempty"`
+	// ChildSpecHash is the hash of the encoding of the typed spec which ChildSpecPatch applies to, so that the patch is
+	// dropped if the typed spec is changed in v1beta1
+	ChildSpecHash string `json:"childSpecHash,omitempty"`
+	// PPNDFastResume and PauseResumeFastResume are the v1alpha1 "ppnd.fastResume" and "pauseResume.fastResume" of a
+	// PipelineRollout, if they differ
+	PPNDFastResume        *bool `json:"ppndFastResume,omitempty"`
+	PauseResumeFastResume *bool `json:"pauseResumeFastResume,omitempty"`
+}
+
+var (
+	_ conversion.Convertible = &PipelineRollout{}
+	_ conversion.Convertible = &MonoVertexRollout{}
+	_ conversion.Convertible = &ISBServiceRollout{}
+	_ conversion.Convertible = &NumaflowControllerRollout{}
+)
+
+// ConvertTo converts this PipelineRollout to the Hub version (v1alpha1)
+func (src *PipelineRollout) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*apiv1.PipelineRollout)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 PipelineRollout but got %T", dstRaw)
+	}
+	data, err := popConversionData(&src.ObjectMeta, &dst.ObjectMeta)
+	if err != nil {
+		return err
+	}
+	spec := src.Spec.DeepCopy()
+
+	dst.Spec.Pipeline.Metadata = spec.Pipeline.Metadata
+	if dst.Spec.Pipeline.Spec, err = childSpecToHub(spec.Pipeline.Spec, data); err != nil {
+		return err
+	}
+	dst.Spec.TemplateRef = spec.TemplateRef
+	dst.Spec.Strategy = nil
+	if spec.Strategy != nil {
+		fastResume := spec.Strategy.PauseResumeStrategy.FastResume
+		dst.Spec.Strategy = &apiv1.PipelineStrategy{
+			PipelineTypeRolloutStrategy: spec.Strategy.PipelineTypeRolloutStrategy,
+			PPNDStrategy:                apiv1.PPNDStrategy{FastResume: fastResume, PauseTimeout: spec.Strategy.PauseTimeout},
+			RecycleStrategy:             spec.Strategy.Recycle,
+		}
+		// restore the two v1alpha1 fields if they differed and the unified field wasn't changed since
+		if data.PPNDFastResume != nil && data.PauseResumeFastResume != nil &&
+			fastResume == (*data.PPNDFastResume || *data.PauseResumeFastResume) {
+			dst.Spec.Strategy.PPNDStrategy.FastResume = *data.PPNDFastResume
+			dst.Spec.Strategy.PauseResumeStrategy.FastResume = *data.PauseResumeFastResume
+		}
+	}
+	dst.Spec.Riders = spec.Riders
+	dst.Spec.ValuesFrom = spec.ValuesFrom
+	dst.Spec.RevisionHistoryLimit = spec.RevisionHistoryLimit
+
+	dst.Status = *src.Status.DeepCopy()
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) of a PipelineRollout to this version
+func (dst *PipelineRollout) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*apiv1.PipelineRollout)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 PipelineRollout but got %T", srcRaw)
+	}
+	data := conversionData{}
+	spec := src.Spec.DeepCopy()
+
+	dst.Spec.Pipeline.Metadata = spec.Pipeline.Metadata
+	var err error
+	if dst.Spec.Pipeline.Spec, err = childSpecFromHub[numaflowv1.PipelineSpec](spec.Pipeline.Spec, &data); err != nil {
+		return err
+	}
+	dst.Spec.TemplateRef = spec.TemplateRef
+	dst.Spec.Strategy = nil
+	if spec.Strategy != nil {
+		ppndFastResume := spec.Strategy.PPNDStrategy.FastResume
+		pauseResumeFastResume := spec.Strategy.PauseResumeStrategy.FastResume
+		dst.Spec.Strategy = &PipelineStrategy{
+			PipelineTypeRolloutStrategy: spec.Strategy.PipelineTypeRolloutStrategy,
+			Recycle:                     spec.Strategy.RecycleStrategy,
+			PauseTimeout:                spec.Strategy.PPNDStrategy.PauseTimeout,
+		}
+		dst.Spec.Strategy.PauseResumeStrategy.FastResume = ppndFastResume || pauseResumeFastResume
+		if ppndFastResume != pauseResumeFastResume {
+			data.PPNDFastResume = &ppndFastResume
+			data.PauseResumeFastResume = &pauseResumeFastResume
+		}
+	}
+	dst.Spec.Riders = spec.Riders
+	dst.Spec.ValuesFrom = spec.ValuesFrom
+	dst.Spec.RevisionHistoryLimit = spec.RevisionHistoryLimit
+
+	dst.Status = *src.Status.DeepCopy()
+	return pushConversionData(&src.ObjectMeta, &dst.ObjectMeta, data)
+}
+
+// ConvertTo converts this MonoVertexRollout to the Hub version (v1alpha1)
+func (src *MonoVertexRollout) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*apiv1.MonoVertexRollout)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 MonoVertexRollout but got %T", dstRaw)
+	}
+	data, err := popConversionData(&src.ObjectMeta, &dst.ObjectMeta)
+	if err != nil {
+		return err
+	}
+	spec := src.Spec.DeepCopy()
+
+	dst.Spec.MonoVertex.Metadata = spec.MonoVertex.Metadata
+	if dst.Spec.MonoVertex.Spec, err = childSpecToHub(spec.MonoVertex.Spec, data); err != nil {
+		return err
+	}
+	dst.Spec.TemplateRef = spec.TemplateRef
+	dst.Spec.Strategy = spec.Strategy
+	dst.Spec.Riders = spec.Riders
+	dst.Spec.ValuesFrom = spec.ValuesFrom
+	dst.Spec.RevisionHistoryLimit = spec.RevisionHistoryLimit
+
+	dst.Status = *src.Status.DeepCopy()
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) of a MonoVertexRollout to this version
+func (dst *MonoVertexRollout) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*apiv1.MonoVertexRollout)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 MonoVertexRollout but got %T", srcRaw)
+	}
+	data := conversionData{}
+	spec := src.Spec.DeepCopy()
+
+	dst.Spec.MonoVertex.Metadata = spec.MonoVertex.Metadata
+	var err error
+	if dst.Spec.MonoVertex.Spec, err = childSpecFromHub[numaflowv1.MonoVertexSpec](spec.MonoVertex.Spec, &data); err != nil {
+		return err
+	}
+	dst.Spec.TemplateRef = spec.TemplateRef
+	dst.Spec.Strategy = spec.Strategy
+	dst.Spec.Riders = spec.Riders
+	dst.Spec.ValuesFrom = spec.ValuesFrom
+	dst.Spec.RevisionHistoryLimit = spec.RevisionHistoryLimit
+
+	dst.Status = *src.Status.DeepCopy()
+	return pushConversionData(&src.ObjectMeta, &dst.ObjectMeta, data)
+}
+
+// ConvertTo converts this ISBServiceRollout to the Hub version (v1alpha1)
+func (src *ISBServiceRollout) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*apiv1.ISBServiceRollout)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 ISBServiceRollout but got %T", dstRaw)
+	}
+	data, err := popConversionData(&src.ObjectMeta, &dst.ObjectMeta)
+	if err != nil {
+		return err
+	}
+	spec := src.Spec.DeepCopy()
+
+	dst.Spec.InterStepBufferService.Metadata = spec.InterStepBufferService.Metadata
+	if dst.Spec.InterStepBufferService.Spec, err = childSpecToHub(&spec.InterStepBufferService.Spec, data); err != nil {
+		return err
+	}
+	dst.Spec.Strategy = spec.Strategy
+	dst.Spec.Riders = spec.Riders
+	dst.Spec.ValuesFrom = spec.ValuesFrom
+	dst.Spec.RevisionHistoryLimit = spec.RevisionHistoryLimit
+
+	dst.Status = *src.Status.DeepCopy()
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) of an ISBServiceRollout to this version
+func (dst *ISBServiceRollout) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*apiv1.ISBServiceRollout)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 ISBServiceRollout but got %T", srcRaw)
+	}
+	data := conversionData{}
+	spec := src.Spec.DeepCopy()
+
+	dst.Spec.InterStepBufferService.Metadata = spec.InterStepBufferService.Metadata
+	isbServiceSpec, err := childSpecFromHub[numaflowv1.InterStepBufferServiceSpec](spec.InterStepBufferService.Spec, &data)
+	if err != nil {
+		return err
+	}
+	if isbServiceSpec != nil {
+		dst.Spec.InterStepBufferService.Spec = *isbServiceSpec
+	}
+	dst.Spec.Strategy = spec.Strategy
+	dst.Spec.Riders = spec.Riders
+	dst.Spec.ValuesFrom = spec.ValuesFrom
+	dst.Spec.RevisionHistoryLimit = spec.RevisionHistoryLimit
+
+	dst.Status = *src.Status.DeepCopy()
+	return pushConversionData(&src.ObjectMeta, &dst.ObjectMeta, data)
+}
+
+// ConvertTo converts this NumaflowControllerRollout to the Hub version (v1alpha1)
+func (src *NumaflowControllerRollout) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*apiv1.NumaflowControllerRollout)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 NumaflowControllerRollout but got %T", dstRaw)
+	}
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = *src.Spec.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) of a NumaflowControllerRollout to this version
+func (dst *NumaflowControllerRollout) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*apiv1.NumaflowControllerRollout)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 NumaflowControllerRollout but got %T", srcRaw)
+	}
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = *src.Spec.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+	return nil
+}
+
+// childSpecFromHub decodes a v1alpha1 child spec into the typed spec, which is nil if the child spec isn't set (or doesn't decode).
+// If the typed spec doesn't represent the child spec exactly, the patch which restores it is added to the conversion data.
+func childSpecFromHub[T any](src runtime.RawExtension, data *conversionData) (*T, error) {
+	if len(src.Raw) == 0 {
+		return nil, nil
+	}
+	spec := new(T)
+	if err := json.Unmarshal(src.Raw, spec); err != nil {
+		spec = nil
+	}
+	specBytes, err := encodeChildSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	if jsonEqual(src.Raw, specBytes) {
+		return spec, nil
+	}
+	operations, err := jsonpatchgen.CreatePatch(specBytes, src.Raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the child spec patch: %w", err)
+	}
+	patch, err := json.Marshal(operations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the child spec patch: %w", err)
+	}
+	if len(patch) > maxChildSpecPatchSize {
+		return nil, fmt.Errorf("the child spec can't be represented in %s: the %d bytes which differ exceed the limit of %d bytes",
+			SchemeGroupVersion.Version, len(patch), maxChildSpecPatchSize)
+	}
+	data.ChildSpecPatch = patch
+	data.ChildSpecHash = hashChildSpec(specBytes)
+	return spec, nil
+}
+
+// childSpecToHub encodes the typed spec as a v1alpha1 child spec. The child spec patch from the conversion data is applied
+// if the typed spec hasn't changed since it was decoded.
+func childSpecToHub[T any](spec *T, data conversionData) (runtime.RawExtension, error) {
+	specBytes, err := encodeChildSpec(spec)
+	if err != nil {
+		return runtime.RawExtension{}, err
+	}
+	if len(data.ChildSpecPatch) > 0 && data.ChildSpecHash == hashChildSpec(specBytes) {
+		patch, err := jsonpatch.DecodePatch(data.ChildSpecPatch)
+		if err != nil {
+			return runtime.RawExtension{}, fmt.Errorf("failed to decode the child spec patch: %w", err)
+		}
+		childSpecBytes, err := patch.Apply(specBytes)
+		if err != nil {
+			return runtime.RawExtension{}, fmt.Errorf("failed to apply the child spec patch: %w", err)
+		}
+		return runtime.RawExtension{Raw: childSpecBytes}, nil
+	}
+	if spec == nil {
+		return runtime.RawExtension{}, nil
+	}
+	return runtime.RawExtension{Raw: specBytes}, nil
+}
+
+// encodeChildSpec encodes the typed spec, or an empty object if it's nil
+func encodeChildSpec[T any](spec *T) ([]byte, error) {
+	if spec == nil {
+		return []byte("{}"), nil
+	}
+	specBytes, err := json.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal child spec: %w", err)
+	}
+	return specBytes, nil
+}
+
+func hashChildSpec(specBytes []byte) string {
+	sum := sha256.Sum256(specBytes)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func jsonEqual(a, b []byte) bool {
+	var aObj, bObj interface{}
+	if json.Unmarshal(a, &aObj) != nil || json.Unmarshal(b, &bObj) != nil {
+		return false
+	}
+	return reflect.DeepEqual(aObj, bObj)
+}
+
+// pushConversionData copies the v1alpha1 ObjectMeta to the v1beta1 one, adding the conversion data annotation if there's
+// any data
+func pushConversionData(src, dst *metav1.ObjectMeta, data conversionData) error {
+	*dst = *src.DeepCopy()
+	if reflect.DeepEqual(data, conversionData{}) {
+		return nil
+	}
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conversion data: %w", err)
+	}
+	if dst.Annotations == nil {
+		dst.Annotations = map[string]string{}
+	}
+	dst.Annotations[ConversionDataAnnotation] = string(dataBytes)
+	return nil
+}
+
+// popConversionData copies the v1beta1 ObjectMeta to the v1alpha1 one without the conversion data annotation, and returns
+// the conversion data
+func popConversionData(src, dst *metav1.ObjectMeta) (conversionData, error) {
+	*dst = *src.DeepCopy()
+	data := conversionData{}
+	dataString, found := dst.Annotations[ConversionDataAnnotation]
+	if !found {
+		return data, nil
+	}
+	delete(dst.Annotations, ConversionDataAnnotation)
+	if len(dst.Annotations) == 0 {
+		dst.Annotations = nil
+	}
+	if err := json.Unmarshal([]byte(dataString), &data); err != nil {
+		return data, fmt.Errorf("failed to unmarshal %q annotation: %w", ConversionDataAnnotation, err)
+	}
+	return data, nil
+}
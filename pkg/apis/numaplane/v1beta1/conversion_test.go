@@ -0,0 +1,183 @@
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+func Test_IsConvertible(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, apiv1.AddToScheme(scheme))
+	assert.NoError(t, AddToScheme(scheme))
+
+	for _, obj := range []runtime.Object{&apiv1.PipelineRollout{}, &apiv1.MonoVertexRollout{}, &apiv1.ISBServiceRollout{}, &apiv1.NumaflowControllerRollout{}} {
+		convertible, err := conversion.IsConvertible(scheme, obj)
+		assert.NoError(t, err)
+		assert.True(t, convertible, "%T", obj)
+	}
+}
+
+func Test_PipelineRolloutConversion(t *testing.T) {
+	nameCount := int32(2)
+	scaleFactor := int32(80)
+	makeHubPipelineRollout := func(pipelineSpec string, ppndFastResume, pauseResumeFastResume bool) *apiv1.PipelineRollout {
+		return &apiv1.PipelineRollout{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pipeline", Namespace: "default", Annotations: map[string]string{"key": "value"}},
+			Spec: apiv1.PipelineRolloutSpec{
+				Pipeline: apiv1.Pipeline{
+					Metadata: apiv1.Metadata{Labels: map[string]string{"name": "{{.pipeline-name}}"}},
+					Spec:     runtime.RawExtension{Raw: []byte(pipelineSpec)},
+				},
+				Strategy: &apiv1.PipelineStrategy{
+					PipelineTypeRolloutStrategy: apiv1.PipelineTypeRolloutStrategy{
+						PauseResumeStrategy: apiv1.PauseResumeStrategy{FastResume: pauseResumeFastResume},
+						UpgradeStrategy:     apiv1.UserUpgradeStrategyPPND,
+					},
+					PPNDStrategy: apiv1.PPNDStrategy{FastResume: ppndFastResume,
+						PauseTimeout: &apiv1.PauseTimeout{Duration: &metav1.Duration{Duration: 10 * time.Minute}, Policy: apiv1.PauseTimeoutPolicyProceed}},
+					RecycleStrategy: apiv1.RecycleStrategy{ScaleFactor: &scaleFactor},
+				},
+				Riders: []apiv1.PipelineRider{{Rider: apiv1.Rider{Definition: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap"}`)}}, PerVertex: true}},
+			},
+			Status: apiv1.PipelineRolloutStatus{NameCount: &nameCount},
+		}
+	}
+
+	t.Run("round trip of a spec which isn't represented exactly by the typed spec", func(t *testing.T) {
+		hubPipelineRollout := makeHubPipelineRollout(`{"vertices":[{"name":"in","source":{"generator":{}}},{"name":"out","sink":{"log":{}}}],"edges":[{"from":"in","to":"out"}],"newField":"x"}`, true, false)
+
+		pipelineRollout := &PipelineRollout{}
+		assert.NoError(t, pipelineRollout.ConvertFrom(hubPipelineRollout))
+		assert.Len(t, pipelineRollout.Spec.Pipeline.Spec.Vertices, 2)
+		assert.Equal(t, "out", pipelineRollout.Spec.Pipeline.Spec.Edges[0].To)
+		assert.True(t, pipelineRollout.Spec.Strategy.PauseResumeStrategy.FastResume)
+		assert.Equal(t, scaleFactor, *pipelineRollout.Spec.Strategy.Recycle.ScaleFactor)
+		assert.Equal(t, apiv1.PauseTimeoutPolicyProceed, pipelineRollout.Spec.Strategy.PauseTimeout.Policy)
+		assert.Equal(t, nameCount, *pipelineRollout.Status.NameCount)
+		assert.Contains(t, pipelineRollout.Annotations, ConversionDataAnnotation)
+
+		convertedHubPipelineRollout := &apiv1.PipelineRollout{}
+		assert.NoError(t, pipelineRollout.ConvertTo(convertedHubPipelineRollout))
+		normalizeJSON(t, &hubPipelineRollout.Spec.Pipeline.Spec)
+		normalizeJSON(t, &convertedHubPipelineRollout.Spec.Pipeline.Spec)
+		assert.Equal(t, hubPipelineRollout, convertedHubPipelineRollout)
+	})
+
+	t.Run("round trip of a spec which is represented exactly by the typed spec", func(t *testing.T) {
+		hubPipelineRollout := makeHubPipelineRollout(`{"interStepBufferServiceName":"my-isbsvc","lifecycle":{"desiredPhase":"Paused"},"watermark":{"disabled":true}}`, true, true)
+
+		pipelineRollout := &PipelineRollout{}
+		assert.NoError(t, pipelineRollout.ConvertFrom(hubPipelineRollout))
+		assert.Equal(t, "my-isbsvc", pipelineRollout.Spec.Pipeline.Spec.InterStepBufferServiceName)
+		assert.NotContains(t, pipelineRollout.Annotations, ConversionDataAnnotation)
+
+		convertedHubPipelineRollout := &apiv1.PipelineRollout{}
+		assert.NoError(t, pipelineRollout.ConvertTo(convertedHubPipelineRollout))
+		normalizeJSON(t, &hubPipelineRollout.Spec.Pipeline.Spec)
+		normalizeJSON(t, &convertedHubPipelineRollout.Spec.Pipeline.Spec)
+		assert.Equal(t, hubPipelineRollout, convertedHubPipelineRollout)
+	})
+
+	t.Run("changes made in v1beta1 take precedence over the conversion data", func(t *testing.T) {
+		hubPipelineRollout := makeHubPipelineRollout(`{"vertices":[{"name":"in"}],"newField":"x"}`, false, true)
+
+		pipelineRollout := &PipelineRollout{}
+		assert.NoError(t, pipelineRollout.ConvertFrom(hubPipelineRollout))
+		pipelineRollout.Spec.Pipeline.Spec.Vertices[0].Name = "{{.pipeline-name}}-in"
+		pipelineRollout.Spec.Strategy.PauseResumeStrategy.FastResume = false
+
+		convertedHubPipelineRollout := &apiv1.PipelineRollout{}
+		assert.NoError(t, pipelineRollout.ConvertTo(convertedHubPipelineRollout))
+		convertedSpec := numaflowv1.PipelineSpec{}
+		assert.NoError(t, json.Unmarshal(convertedHubPipelineRollout.Spec.Pipeline.Spec.Raw, &convertedSpec))
+		assert.Equal(t, "{{.pipeline-name}}-in", convertedSpec.Vertices[0].Name)
+		assert.NotContains(t, string(convertedHubPipelineRollout.Spec.Pipeline.Spec.Raw), "newField")
+		assert.False(t, convertedHubPipelineRollout.Spec.Strategy.PPNDStrategy.FastResume)
+		assert.False(t, convertedHubPipelineRollout.Spec.Strategy.PauseResumeStrategy.FastResume)
+		assert.Equal(t, map[string]string{"key": "value"}, convertedHubPipelineRollout.Annotations)
+	})
+
+	t.Run("only the fields which the typed spec doesn't represent are kept in the conversion data", func(t *testing.T) {
+		hubPipelineRollout := makeHubPipelineRollout(`{"vertices":[{"name":"in","source":{"generator":{}}}],"newField":"x"}`, false, false)
+
+		pipelineRollout := &PipelineRollout{}
+		assert.NoError(t, pipelineRollout.ConvertFrom(hubPipelineRollout))
+		assert.Contains(t, pipelineRollout.Annotations[ConversionDataAnnotation], "newField")
+		assert.NotContains(t, pipelineRollout.Annotations[ConversionDataAnnotation], "generator")
+	})
+
+	t.Run("a spec which differs too much from the typed spec can't be converted", func(t *testing.T) {
+		hubPipelineRollout := makeHubPipelineRollout(fmt.Sprintf(`{"newField":%q}`, strings.Repeat("x", maxChildSpecPatchSize)), false, false)
+
+		pipelineRollout := &PipelineRollout{}
+		assert.ErrorContains(t, pipelineRollout.ConvertFrom(hubPipelineRollout), "exceed the limit")
+	})
+
+	t.Run("a spec which doesn't decode into the typed spec is kept", func(t *testing.T) {
+		hubPipelineRollout := makeHubPipelineRollout(`{"vertices":[{"name":"in","scale":{"min":"{{.min}}"}}]}`, false, false)
+
+		pipelineRollout := &PipelineRollout{}
+		assert.NoError(t, pipelineRollout.ConvertFrom(hubPipelineRollout))
+		assert.Nil(t, pipelineRollout.Spec.Pipeline.Spec)
+
+		convertedHubPipelineRollout := &apiv1.PipelineRollout{}
+		assert.NoError(t, pipelineRollout.ConvertTo(convertedHubPipelineRollout))
+		normalizeJSON(t, &hubPipelineRollout.Spec.Pipeline.Spec)
+		normalizeJSON(t, &convertedHubPipelineRollout.Spec.Pipeline.Spec)
+		assert.Equal(t, hubPipelineRollout, convertedHubPipelineRollout)
+	})
+}
+
+func Test_MonoVertexAndISBServiceRolloutConversion(t *testing.T) {
+	hubMonoVertexRollout := &apiv1.MonoVertexRollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-monovertex", Namespace: "default"},
+		Spec: apiv1.MonoVertexRolloutSpec{
+			MonoVertex: apiv1.MonoVertex{Spec: runtime.RawExtension{Raw: []byte(`{"source":{"udsource":{"container":{"image":"my-source"}}},"sink":{"log":{}}}`)}},
+			Strategy:   &apiv1.PipelineTypeRolloutStrategy{PipelineTypeProgressiveStrategy: apiv1.PipelineTypeProgressiveStrategy{Progressive: apiv1.ProgressiveStrategy{AssessmentSchedule: "60,120,10"}}},
+		},
+	}
+	monoVertexRollout := &MonoVertexRollout{}
+	assert.NoError(t, monoVertexRollout.ConvertFrom(hubMonoVertexRollout))
+	assert.Equal(t, "my-source", monoVertexRollout.Spec.MonoVertex.Spec.Source.UDSource.Container.Image)
+	convertedHubMonoVertexRollout := &apiv1.MonoVertexRollout{}
+	assert.NoError(t, monoVertexRollout.ConvertTo(convertedHubMonoVertexRollout))
+	normalizeJSON(t, &hubMonoVertexRollout.Spec.MonoVertex.Spec)
+	normalizeJSON(t, &convertedHubMonoVertexRollout.Spec.MonoVertex.Spec)
+	assert.Equal(t, hubMonoVertexRollout, convertedHubMonoVertexRollout)
+
+	hubISBServiceRollout := &apiv1.ISBServiceRollout{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-isbsvc", Namespace: "default"},
+		Spec: apiv1.ISBServiceRolloutSpec{
+			InterStepBufferService: apiv1.InterStepBufferService{Spec: runtime.RawExtension{Raw: []byte(`{"jetstream":{"version":"latest","persistence":{"volumeSize":"1Gi"}}}`)}},
+		},
+	}
+	isbServiceRollout := &ISBServiceRollout{}
+	assert.NoError(t, isbServiceRollout.ConvertFrom(hubISBServiceRollout))
+	assert.Equal(t, "latest", isbServiceRollout.Spec.InterStepBufferService.Spec.JetStream.Version)
+	assert.NotContains(t, isbServiceRollout.Annotations, ConversionDataAnnotation)
+	convertedHubISBServiceRollout := &apiv1.ISBServiceRollout{}
+	assert.NoError(t, isbServiceRollout.ConvertTo(convertedHubISBServiceRollout))
+	normalizeJSON(t, &hubISBServiceRollout.Spec.InterStepBufferService.Spec)
+	normalizeJSON(t, &convertedHubISBServiceRollout.Spec.InterStepBufferService.Spec)
+	assert.Equal(t, hubISBServiceRollout, convertedHubISBServiceRollout)
+}
+
+// normalizeJSON re-encodes a child spec, since the conversion only preserves its content rather than its encoding
+func normalizeJSON(t *testing.T, raw *runtime.RawExtension) {
+	var obj any
+	assert.NoError(t, json.Unmarshal(raw.Raw, &obj))
+	normalized, err := json.Marshal(obj)
+	assert.NoError(t, err)
+	raw.Raw = normalized
+}
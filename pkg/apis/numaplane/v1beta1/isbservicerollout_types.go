@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// ISBServiceRolloutSpec defines the desired state of ISBServiceRollout
+type ISBServiceRolloutSpec struct {
+	InterStepBufferService InterStepBufferService           `json:"interStepBufferService"`
+	Strategy               *apiv1.ISBServiceRolloutStrategy `json:"strategy,omitempty"`
+	Riders                 []apiv1.Rider                    `json:"riders,omitempty"`
+
+	// ValuesFrom references ConfigMaps and Secrets whose data is available for templating the child definition and Riders as
+	// {{.values.<key>}}. Where more than one of them has the same key, the last one takes precedence.
+	// A change to any of them upgrades the child just like a change to its definition.
+	// +optional
+	ValuesFrom []apiv1.ValuesFromSource `json:"valuesFrom,omitempty"`
+
+	// RevisionHistoryLimit is the maximum number of revisions of the child definition to retain (default 10)
+	// +kubebuilder:validation:Minimum=0
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+// InterStepBufferService includes the spec of InterStepBufferService in Numaflow
+type InterStepBufferService struct {
+	apiv1.Metadata `json:"metadata,omitempty"`
+
+	// Spec is the spec of the InterStepBufferService, whose string fields may use template variables.
+	// Its full schema would exceed the size limit of the CRD, so only a trimmed schema of it is published (see config/crd/patches);
+	// it's fully verified by the validating webhook.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Spec numaflowv1.InterStepBufferServiceSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:unservedversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The current phase"
+// ISBServiceRollout is the Schema for the isbservicerollouts API
+type ISBServiceRollout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ISBServiceRolloutSpec         `json:"spec"`
+	Status apiv1.ISBServiceRolloutStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ISBServiceRolloutList contains a list of ISBServiceRollout
+type ISBServiceRolloutList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ISBServiceRollout `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ISBServiceRollout{}, &ISBServiceRolloutList{})
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	numaflowv1 "github.com/numaproj/numaflow/pkg/apis/numaflow/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+)
+
+// PipelineRolloutSpec defines the desired state of PipelineRollout
+// +kubebuilder:validation:XValidation:rule="has(self.templateRef) != (has(self.pipeline) && has(self.pipeline.spec))",message="exactly one of pipeline.spec and templateRef must be set"
+type PipelineRolloutSpec struct {
+	// Pipeline defines the Pipeline, unless it's defined by a PipelineTemplate: in that case only its metadata may be set, which
+	// is added to the template's
+	// +optional
+	Pipeline Pipeline `json:"pipeline,omitempty"`
+	// TemplateRef references the PipelineTemplate which defines the Pipeline
+	// +optional
+	TemplateRef *apiv1.TemplateReference `json:"templateRef,omitempty"`
+	Strategy    *PipelineStrategy        `json:"strategy,omitempty"`
+	Riders      []apiv1.PipelineRider    `json:"riders,omitempty"`
+
+	// ValuesFrom references ConfigMaps and Secrets whose data is available for templating the child definition and Riders as
+	// {{.values.<key>}}. Where more than one of them has the same key, the last one takes precedence.
+	// A change to any of them upgrades the child just like a change to its definition.
+	// +optional
+	ValuesFrom []apiv1.ValuesFromSource `json:"valuesFrom,omitempty"`
+
+	// RevisionHistoryLimit is the maximum number of revisions of the child definition to retain (default 10)
+	// +kubebuilder:validation:Minimum=0
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+// PipelineStrategy specifies the Rollout Strategy of a PipelineRollout.
+// Whether the Pipeline is resumed fast is defined once by PauseResumeStrategy, both for when the user resumes it and for
+// when it's resumed after being paused for the "pause-and-drain" strategy.
+// +kubebuilder:validation:XValidation:rule="!has(self.progressive) || !has(self.progressive.steps)",message="progressive.steps is only supported for MonoVertexRollout"
+type PipelineStrategy struct {
+	apiv1.PipelineTypeRolloutStrategy `json:",inline"`
+
+	// Recycle defines how a Pipeline is drained before it's deleted
+	Recycle apiv1.RecycleStrategy `json:"recycle,omitempty"`
+
+	// PauseTimeout bounds how long an ISBServiceRollout or NumaflowControllerRollout waits for the Pipeline to pause for the
+	// "pause-and-drain" strategy, and defines what to do if it doesn't
+	// +optional
+	PauseTimeout *apiv1.PauseTimeout `json:"pauseTimeout,omitempty"`
+}
+
+// Pipeline includes the spec of Pipeline in Numaflow
+type Pipeline struct {
+	apiv1.Metadata `json:"metadata,omitempty"`
+
+	// Spec is the spec of the Pipeline, whose string fields may use template variables.
+	// Its full schema would exceed the size limit of the CRD, so only a trimmed schema of it is published (see config/crd/patches);
+	// it's fully verified by the validating webhook.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Spec *numaflowv1.PipelineSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:unservedversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The current phase"
+// +kubebuilder:printcolumn:name="Upgrade In Progress",type="string",JSONPath=".status.upgradeInProgress",description="The upgrade strategy currently prosessing the PipelineRollout. No upgrade in progress if empty"
+// PipelineRollout is the Schema for the pipelinerollouts API
+type PipelineRollout struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PipelineRolloutSpec         `json:"spec"`
+	Status apiv1.PipelineRolloutStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// PipelineRolloutList contains a list of PipelineRollout
+type PipelineRolloutList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PipelineRollout `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PipelineRollout{}, &PipelineRolloutList{})
+}
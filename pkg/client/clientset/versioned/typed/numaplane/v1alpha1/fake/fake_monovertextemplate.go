@@ -0,0 +1,133 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeMonoVertexTemplates implements MonoVertexTemplateInterface
+type FakeMonoVertexTemplates struct {
+	Fake *FakeNumaplaneV1alpha1
+	ns   string
+}
+
+var monovertextemplatesResource = v1alpha1.SchemeGroupVersion.WithResource("monovertextemplates")
+
+var monovertextemplatesKind = v1alpha1.SchemeGroupVersion.WithKind("MonoVertexTemplate")
+
+// Get takes name of the monoVertexTemplate, and returns the corresponding monoVertexTemplate object, and an error if there is any.
+func (c *FakeMonoVertexTemplates) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.MonoVertexTemplate, err error) {
+	emptyResult := &v1alpha1.MonoVertexTemplate{}
+	obj, err := c.Fake.
+		Invokes(testing.NewGetActionWithOptions(monovertextemplatesResource, c.ns, name, options), emptyResult)
+
+	if obj == nil {
+		return emptyResult, err
+	}
+	return obj.(*v1alpha1.MonoVertexTemplate), err
+}
+
+// List takes label and field selectors, and returns the list of MonoVertexTemplates that match those selectors.
+func (c *FakeMonoVertexTemplates) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.MonoVertexTemplateList, err error) {
+	emptyResult := &v1alpha1.MonoVertexTemplateList{}
+	obj, err := c.Fake.
+		Invokes(testing.NewListActionWithOptions(monovertextemplatesResource, monovertextemplatesKind, c.ns, opts), emptyResult)
+
+	if obj == nil {
+		return emptyResult, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.MonoVertexTemplateList{ListMeta: obj.(*v1alpha1.MonoVertexTemplateList).ListMeta}
+	for _, item := range obj.(*v1alpha1.MonoVertexTemplateList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested monoVertexTemplates.
+func (c *FakeMonoVertexTemplates) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchActionWithOptions(monovertextemplatesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a monoVertexTemplate and creates it.  Returns the server's representation of the monoVertexTemplate, and an error, if there is any.
+func (c *FakeMonoVertexTemplates) Create(ctx context.Context, monoVertexTemplate *v1alpha1.MonoVertexTemplate, opts v1.CreateOptions) (result *v1alpha1.MonoVertexTemplate, err error) {
+	emptyResult := &v1alpha1.MonoVertexTemplate{}
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateActionWithOptions(monovertextemplatesResource, c.ns, monoVertexTemplate, opts), emptyResult)
+
+	if obj == nil {
+		return emptyResult, err
+	}
+	return obj.(*v1alpha1.MonoVertexTemplate), err
+}
+
+// Update takes the representation of a monoVertexTemplate and updates it. Returns the server's representation of the monoVertexTemplate, and an error, if there is any.
+func (c *FakeMonoVertexTemplates) Update(ctx context.Context, monoVertexTemplate *v1alpha1.MonoVertexTemplate, opts v1.UpdateOptions) (result *v1alpha1.MonoVertexTemplate, err error) {
+	emptyResult := &v1alpha1.MonoVertexTemplate{}
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateActionWithOptions(monovertextemplatesResource, c.ns, monoVertexTemplate, opts), emptyResult)
+
+	if obj == nil {
+		return emptyResult, err
+	}
+	return obj.(*v1alpha1.MonoVertexTemplate), err
+}
+
+// Delete takes name of the monoVertexTemplate and deletes it. Returns an error if one occurs.
+func (c *FakeMonoVertexTemplates) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(monovertextemplatesResource, c.ns, name, opts), &v1alpha1.MonoVertexTemplate{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeMonoVertexTemplates) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionActionWithOptions(monovertextemplatesResource, c.ns, opts, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.MonoVertexTemplateList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched monoVertexTemplate.
+func (c *FakeMonoVertexTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.MonoVertexTemplate, err error) {
+	emptyResult := &v1alpha1.MonoVertexTemplate{}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceActionWithOptions(monovertextemplatesResource, c.ns, name, pt, data, opts, subresources...), emptyResult)
+
+	if obj == nil {
+		return emptyResult, err
+	}
+	return obj.(*v1alpha1.MonoVertexTemplate), err
+}
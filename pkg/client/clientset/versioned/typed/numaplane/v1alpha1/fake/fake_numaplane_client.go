@@ -35,6 +35,10 @@ func (c *FakeNumaplaneV1alpha1) MonoVertexRollouts(namespace string) v1alpha1.Mo
 	return &FakeMonoVertexRollouts{c, namespace}
 }
 
+func (c *FakeNumaplaneV1alpha1) MonoVertexTemplates(namespace string) v1alpha1.MonoVertexTemplateInterface {
+	return &FakeMonoVertexTemplates{c, namespace}
+}
+
 func (c *FakeNumaplaneV1alpha1) NumaflowControllers(namespace string) v1alpha1.NumaflowControllerInterface {
 	return &FakeNumaflowControllers{c, namespace}
 }
@@ -47,6 +51,10 @@ func (c *FakeNumaplaneV1alpha1) PipelineRollouts(namespace string) v1alpha1.Pipe
 	return &FakePipelineRollouts{c, namespace}
 }
 
+func (c *FakeNumaplaneV1alpha1) PipelineTemplates(namespace string) v1alpha1.PipelineTemplateInterface {
+	return &FakePipelineTemplates{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeNumaplaneV1alpha1) RESTClient() rest.Interface {
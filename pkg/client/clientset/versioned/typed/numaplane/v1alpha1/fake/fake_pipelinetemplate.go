@@ -0,0 +1,133 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakePipelineTemplates implements PipelineTemplateInterface
+type FakePipelineTemplates struct {
+	Fake *FakeNumaplaneV1alpha1
+	ns   string
+}
+
+var pipelinetemplatesResource = v1alpha1.SchemeGroupVersion.WithResource("pipelinetemplates")
+
+var pipelinetemplatesKind = v1alpha1.SchemeGroupVersion.WithKind("PipelineTemplate")
+
+// Get takes name of the pipelineTemplate, and returns the corresponding pipelineTemplate object, and an error if there is any.
+func (c *FakePipelineTemplates) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.PipelineTemplate, err error) {
+	emptyResult := &v1alpha1.PipelineTemplate{}
+	obj, err := c.Fake.
+		Invokes(testing.NewGetActionWithOptions(pipelinetemplatesResource, c.ns, name, options), emptyResult)
+
+	if obj == nil {
+		return emptyResult, err
+	}
+	return obj.(*v1alpha1.PipelineTemplate), err
+}
+
+// List takes label and field selectors, and returns the list of PipelineTemplates that match those selectors.
+func (c *FakePipelineTemplates) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.PipelineTemplateList, err error) {
+	emptyResult := &v1alpha1.PipelineTemplateList{}
+	obj, err := c.Fake.
+		Invokes(testing.NewListActionWithOptions(pipelinetemplatesResource, pipelinetemplatesKind, c.ns, opts), emptyResult)
+
+	if obj == nil {
+		return emptyResult, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.PipelineTemplateList{ListMeta: obj.(*v1alpha1.PipelineTemplateList).ListMeta}
+	for _, item := range obj.(*v1alpha1.PipelineTemplateList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested pipelineTemplates.
+func (c *FakePipelineTemplates) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchActionWithOptions(pipelinetemplatesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a pipelineTemplate and creates it.  Returns the server's representation of the pipelineTemplate, and an error, if there is any.
+func (c *FakePipelineTemplates) Create(ctx context.Context, pipelineTemplate *v1alpha1.PipelineTemplate, opts v1.CreateOptions) (result *v1alpha1.PipelineTemplate, err error) {
+	emptyResult := &v1alpha1.PipelineTemplate{}
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateActionWithOptions(pipelinetemplatesResource, c.ns, pipelineTemplate, opts), emptyResult)
+
+	if obj == nil {
+		return emptyResult, err
+	}
+	return obj.(*v1alpha1.PipelineTemplate), err
+}
+
+// Update takes the representation of a pipelineTemplate and updates it. Returns the server's representation of the pipelineTemplate, and an error, if there is any.
+func (c *FakePipelineTemplates) Update(ctx context.Context, pipelineTemplate *v1alpha1.PipelineTemplate, opts v1.UpdateOptions) (result *v1alpha1.PipelineTemplate, err error) {
+	emptyResult := &v1alpha1.PipelineTemplate{}
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateActionWithOptions(pipelinetemplatesResource, c.ns, pipelineTemplate, opts), emptyResult)
+
+	if obj == nil {
+		return emptyResult, err
+	}
+	return obj.(*v1alpha1.PipelineTemplate), err
+}
+
+// Delete takes name of the pipelineTemplate and deletes it. Returns an error if one occurs.
+func (c *FakePipelineTemplates) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(pipelinetemplatesResource, c.ns, name, opts), &v1alpha1.PipelineTemplate{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakePipelineTemplates) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionActionWithOptions(pipelinetemplatesResource, c.ns, opts, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.PipelineTemplateList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched pipelineTemplate.
+func (c *FakePipelineTemplates) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.PipelineTemplate, err error) {
+	emptyResult := &v1alpha1.PipelineTemplate{}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceActionWithOptions(pipelinetemplatesResource, c.ns, name, pt, data, opts, subresources...), emptyResult)
+
+	if obj == nil {
+		return emptyResult, err
+	}
+	return obj.(*v1alpha1.PipelineTemplate), err
+}
@@ -21,8 +21,12 @@ type ISBServiceRolloutExpansion interface{}
 
 type MonoVertexRolloutExpansion interface{}
 
+type MonoVertexTemplateExpansion interface{}
+
 type NumaflowControllerExpansion interface{}
 
 type NumaflowControllerRolloutExpansion interface{}
 
 type PipelineRolloutExpansion interface{}
+
+type PipelineTemplateExpansion interface{}
@@ -29,9 +29,11 @@ type NumaplaneV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	ISBServiceRolloutsGetter
 	MonoVertexRolloutsGetter
+	MonoVertexTemplatesGetter
 	NumaflowControllersGetter
 	NumaflowControllerRolloutsGetter
 	PipelineRolloutsGetter
+	PipelineTemplatesGetter
 }
 
 // NumaplaneV1alpha1Client is used to interact with features provided by the numaplane group.
@@ -47,6 +49,10 @@ func (c *NumaplaneV1alpha1Client) MonoVertexRollouts(namespace string) MonoVerte
 	return newMonoVertexRollouts(c, namespace)
 }
 
+func (c *NumaplaneV1alpha1Client) MonoVertexTemplates(namespace string) MonoVertexTemplateInterface {
+	return newMonoVertexTemplates(c, namespace)
+}
+
 func (c *NumaplaneV1alpha1Client) NumaflowControllers(namespace string) NumaflowControllerInterface {
 	return newNumaflowControllers(c, namespace)
 }
@@ -59,6 +65,10 @@ func (c *NumaplaneV1alpha1Client) PipelineRollouts(namespace string) PipelineRol
 	return newPipelineRollouts(c, namespace)
 }
 
+func (c *NumaplaneV1alpha1Client) PipelineTemplates(namespace string) PipelineTemplateInterface {
+	return newPipelineTemplates(c, namespace)
+}
+
 // NewForConfig creates a new NumaplaneV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
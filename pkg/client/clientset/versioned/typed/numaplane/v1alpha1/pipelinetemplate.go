@@ -0,0 +1,66 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+
+	v1alpha1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	scheme "github.com/numaproj/numaplane/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	gentype "k8s.io/client-go/gentype"
+)
+
+// PipelineTemplatesGetter has a method to return a PipelineTemplateInterface.
+// A group's client should implement this interface.
+type PipelineTemplatesGetter interface {
+	PipelineTemplates(namespace string) PipelineTemplateInterface
+}
+
+// PipelineTemplateInterface has methods to work with PipelineTemplate resources.
+type PipelineTemplateInterface interface {
+	Create(ctx context.Context, pipelineTemplate *v1alpha1.PipelineTemplate, opts v1.CreateOptions) (*v1alpha1.PipelineTemplate, error)
+	Update(ctx context.Context, pipelineTemplate *v1alpha1.PipelineTemplate, opts v1.UpdateOptions) (*v1alpha1.PipelineTemplate, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.PipelineTemplate, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.PipelineTemplateList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.PipelineTemplate, err error)
+	PipelineTemplateExpansion
+}
+
+// pipelineTemplates implements PipelineTemplateInterface
+type pipelineTemplates struct {
+	*gentype.ClientWithList[*v1alpha1.PipelineTemplate, *v1alpha1.PipelineTemplateList]
+}
+
+// newPipelineTemplates returns a PipelineTemplates
+func newPipelineTemplates(c *NumaplaneV1alpha1Client, namespace string) *pipelineTemplates {
+	return &pipelineTemplates{
+		gentype.NewClientWithList[*v1alpha1.PipelineTemplate, *v1alpha1.PipelineTemplateList](
+			"pipelinetemplates",
+			c.RESTClient(),
+			scheme.ParameterCodec,
+			namespace,
+			func() *v1alpha1.PipelineTemplate { return &v1alpha1.PipelineTemplate{} },
+			func() *v1alpha1.PipelineTemplateList { return &v1alpha1.PipelineTemplateList{} }),
+	}
+}
@@ -27,12 +27,16 @@ type Interface interface {
 	ISBServiceRollouts() ISBServiceRolloutInformer
 	// MonoVertexRollouts returns a MonoVertexRolloutInformer.
 	MonoVertexRollouts() MonoVertexRolloutInformer
+	// MonoVertexTemplates returns a MonoVertexTemplateInformer.
+	MonoVertexTemplates() MonoVertexTemplateInformer
 	// NumaflowControllers returns a NumaflowControllerInformer.
 	NumaflowControllers() NumaflowControllerInformer
 	// NumaflowControllerRollouts returns a NumaflowControllerRolloutInformer.
 	NumaflowControllerRollouts() NumaflowControllerRolloutInformer
 	// PipelineRollouts returns a PipelineRolloutInformer.
 	PipelineRollouts() PipelineRolloutInformer
+	// PipelineTemplates returns a PipelineTemplateInformer.
+	PipelineTemplates() PipelineTemplateInformer
 }
 
 type version struct {
@@ -56,6 +60,11 @@ func (v *version) MonoVertexRollouts() MonoVertexRolloutInformer {
 	return &monoVertexRolloutInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// MonoVertexTemplates returns a MonoVertexTemplateInformer.
+func (v *version) MonoVertexTemplates() MonoVertexTemplateInformer {
+	return &monoVertexTemplateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // NumaflowControllers returns a NumaflowControllerInformer.
 func (v *version) NumaflowControllers() NumaflowControllerInformer {
 	return &numaflowControllerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -70,3 +79,8 @@ func (v *version) NumaflowControllerRollouts() NumaflowControllerRolloutInformer
 func (v *version) PipelineRollouts() PipelineRolloutInformer {
 	return &pipelineRolloutInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// PipelineTemplates returns a PipelineTemplateInformer.
+func (v *version) PipelineTemplates() PipelineTemplateInformer {
+	return &pipelineTemplateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
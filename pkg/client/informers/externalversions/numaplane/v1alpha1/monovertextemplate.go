@@ -0,0 +1,89 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	time "time"
+
+	numaplanev1alpha1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	versioned "github.com/numaproj/numaplane/pkg/client/clientset/versioned"
+	internalinterfaces "github.com/numaproj/numaplane/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "github.com/numaproj/numaplane/pkg/client/listers/numaplane/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// MonoVertexTemplateInformer provides access to a shared informer and lister for
+// MonoVertexTemplates.
+type MonoVertexTemplateInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.MonoVertexTemplateLister
+}
+
+type monoVertexTemplateInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+	namespace        string
+}
+
+// NewMonoVertexTemplateInformer constructs a new informer for MonoVertexTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewMonoVertexTemplateInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredMonoVertexTemplateInformer(client, namespace, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredMonoVertexTemplateInformer constructs a new informer for MonoVertexTemplate type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredMonoVertexTemplateInformer(client versioned.Interface, namespace string, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.NumaplaneV1alpha1().MonoVertexTemplates(namespace).List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.NumaplaneV1alpha1().MonoVertexTemplates(namespace).Watch(context.TODO(), options)
+			},
+		},
+		&numaplanev1alpha1.MonoVertexTemplate{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *monoVertexTemplateInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredMonoVertexTemplateInformer(client, f.namespace, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *monoVertexTemplateInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&numaplanev1alpha1.MonoVertexTemplate{}, f.defaultInformer)
+}
+
+func (f *monoVertexTemplateInformer) Lister() v1alpha1.MonoVertexTemplateLister {
+	return v1alpha1.NewMonoVertexTemplateLister(f.Informer().GetIndexer())
+}
@@ -33,6 +33,14 @@ type MonoVertexRolloutListerExpansion interface{}
 // MonoVertexRolloutNamespaceLister.
 type MonoVertexRolloutNamespaceListerExpansion interface{}
 
+// MonoVertexTemplateListerExpansion allows custom methods to be added to
+// MonoVertexTemplateLister.
+type MonoVertexTemplateListerExpansion interface{}
+
+// MonoVertexTemplateNamespaceListerExpansion allows custom methods to be added to
+// MonoVertexTemplateNamespaceLister.
+type MonoVertexTemplateNamespaceListerExpansion interface{}
+
 // NumaflowControllerListerExpansion allows custom methods to be added to
 // NumaflowControllerLister.
 type NumaflowControllerListerExpansion interface{}
@@ -56,3 +64,11 @@ type PipelineRolloutListerExpansion interface{}
 // PipelineRolloutNamespaceListerExpansion allows custom methods to be added to
 // PipelineRolloutNamespaceLister.
 type PipelineRolloutNamespaceListerExpansion interface{}
+
+// PipelineTemplateListerExpansion allows custom methods to be added to
+// PipelineTemplateLister.
+type PipelineTemplateListerExpansion interface{}
+
+// PipelineTemplateNamespaceListerExpansion allows custom methods to be added to
+// PipelineTemplateNamespaceLister.
+type PipelineTemplateNamespaceListerExpansion interface{}
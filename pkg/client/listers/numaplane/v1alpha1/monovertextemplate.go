@@ -0,0 +1,69 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/listers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// MonoVertexTemplateLister helps list MonoVertexTemplates.
+// All objects returned here must be treated as read-only.
+type MonoVertexTemplateLister interface {
+	// List lists all MonoVertexTemplates in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.MonoVertexTemplate, err error)
+	// MonoVertexTemplates returns an object that can list and get MonoVertexTemplates.
+	MonoVertexTemplates(namespace string) MonoVertexTemplateNamespaceLister
+	MonoVertexTemplateListerExpansion
+}
+
+// monoVertexTemplateLister implements the MonoVertexTemplateLister interface.
+type monoVertexTemplateLister struct {
+	listers.ResourceIndexer[*v1alpha1.MonoVertexTemplate]
+}
+
+// NewMonoVertexTemplateLister returns a new MonoVertexTemplateLister.
+func NewMonoVertexTemplateLister(indexer cache.Indexer) MonoVertexTemplateLister {
+	return &monoVertexTemplateLister{listers.New[*v1alpha1.MonoVertexTemplate](indexer, v1alpha1.Resource("monovertextemplate"))}
+}
+
+// MonoVertexTemplates returns an object that can list and get MonoVertexTemplates.
+func (s *monoVertexTemplateLister) MonoVertexTemplates(namespace string) MonoVertexTemplateNamespaceLister {
+	return monoVertexTemplateNamespaceLister{listers.NewNamespaced[*v1alpha1.MonoVertexTemplate](s.ResourceIndexer, namespace)}
+}
+
+// MonoVertexTemplateNamespaceLister helps list and get MonoVertexTemplates.
+// All objects returned here must be treated as read-only.
+type MonoVertexTemplateNamespaceLister interface {
+	// List lists all MonoVertexTemplates in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.MonoVertexTemplate, err error)
+	// Get retrieves the MonoVertexTemplate from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.MonoVertexTemplate, error)
+	MonoVertexTemplateNamespaceListerExpansion
+}
+
+// monoVertexTemplateNamespaceLister implements the MonoVertexTemplateNamespaceLister
+// interface.
+type monoVertexTemplateNamespaceLister struct {
+	listers.ResourceIndexer[*v1alpha1.MonoVertexTemplate]
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/numaproj/numaplane/pkg/apis/numaplane/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/listers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// PipelineTemplateLister helps list PipelineTemplates.
+// All objects returned here must be treated as read-only.
+type PipelineTemplateLister interface {
+	// List lists all PipelineTemplates in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.PipelineTemplate, err error)
+	// PipelineTemplates returns an object that can list and get PipelineTemplates.
+	PipelineTemplates(namespace string) PipelineTemplateNamespaceLister
+	PipelineTemplateListerExpansion
+}
+
+// pipelineTemplateLister implements the PipelineTemplateLister interface.
+type pipelineTemplateLister struct {
+	listers.ResourceIndexer[*v1alpha1.PipelineTemplate]
+}
+
+// NewPipelineTemplateLister returns a new PipelineTemplateLister.
+func NewPipelineTemplateLister(indexer cache.Indexer) PipelineTemplateLister {
+	return &pipelineTemplateLister{listers.New[*v1alpha1.PipelineTemplate](indexer, v1alpha1.Resource("pipelinetemplate"))}
+}
+
+// PipelineTemplates returns an object that can list and get PipelineTemplates.
+func (s *pipelineTemplateLister) PipelineTemplates(namespace string) PipelineTemplateNamespaceLister {
+	return pipelineTemplateNamespaceLister{listers.NewNamespaced[*v1alpha1.PipelineTemplate](s.ResourceIndexer, namespace)}
+}
+
+// PipelineTemplateNamespaceLister helps list and get PipelineTemplates.
+// All objects returned here must be treated as read-only.
+type PipelineTemplateNamespaceLister interface {
+	// List lists all PipelineTemplates in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.PipelineTemplate, err error)
+	// Get retrieves the PipelineTemplate from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.PipelineTemplate, error)
+	PipelineTemplateNamespaceListerExpansion
+}
+
+// pipelineTemplateNamespaceLister implements the PipelineTemplateNamespaceLister
+// interface.
+type pipelineTemplateNamespaceLister struct {
+	listers.ResourceIndexer[*v1alpha1.PipelineTemplate]
+}